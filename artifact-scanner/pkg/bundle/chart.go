@@ -0,0 +1,304 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/config"
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/models"
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"knative.dev/pkg/logging"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+const (
+	// chartReleaseName is the release name used when rendering charts
+	chartReleaseName = "artifact-scanner"
+	// chartLayerMediaType is the media type of the chart content layer of an OCI chart
+	chartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// loadChart loads a chart from the local chart directory or pulls it from the registry
+// ctx: The context for the operation
+// image: The chart image to load
+// Returns the loaded chart and any error that occurred
+func loadChart(ctx context.Context, image models.Image) (*chart.Chart, error) {
+	logger := logging.FromContext(ctx).With("chart", image)
+
+	var chartCfg config.Chart
+	if cfg, ok := ctx.Value(config.ContextKeyConfig).(*config.Config); ok {
+		chartCfg = cfg.Chart
+	}
+
+	if chartCfg.LocalDir != "" {
+		tarball, err := findChartTarball(chartCfg.LocalDir, image)
+		if err != nil {
+			return nil, err
+		}
+		if tarball != "" {
+			logger.Debugw("load chart from local tarball", "path", tarball)
+			return loader.LoadFile(tarball)
+		}
+	}
+
+	logger.Debugw("pull chart from registry")
+
+	data, err := pullChart(ctx, image, chartCfg.PlainHTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadArchive(bytes.NewReader(data))
+}
+
+// findChartTarball looks up the tarball of a chart in a local directory
+// the tarball should be named as <name>-<tag>.tgz, the "v" prefix of the tag is optional
+// dir: The directory containing chart tarballs
+// image: The chart image to look up
+// Returns the path of the tarball, or an empty string if not found
+func findChartTarball(dir string, image models.Image) (string, error) {
+	name := path.Base(image.Repository)
+	candidates := []string{
+		fmt.Sprintf("%s-%s.tgz", name, image.Tag),
+		fmt.Sprintf("%s-%s.tgz", name, strings.TrimPrefix(image.Tag, "v")),
+	}
+
+	for _, candidate := range candidates {
+		tarball := filepath.Join(dir, candidate)
+		_, err := os.Stat(tarball)
+		if err == nil {
+			return tarball, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to stat chart tarball %s: %w", tarball, err)
+		}
+	}
+
+	return "", nil
+}
+
+// pullChart pulls a chart from an OCI registry
+// ctx: The context for the operation
+// image: The chart image to pull
+// plainHTTP: Whether to use plain HTTP to access the registry
+// Returns the chart archive content and any error that occurred
+func pullChart(ctx context.Context, image models.Image, plainHTTP bool) ([]byte, error) {
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", image.Registry, image.Repository))
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart reference %s: %w", image.URL(), err)
+	}
+	repo.PlainHTTP = plainHTTP
+
+	client := &auth.Client{
+		Client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				// keep consistent with the bundle extraction which skips TLS verification
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			},
+		},
+		Cache: auth.NewCache(),
+	}
+	if store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{}); err == nil {
+		client.Credential = credentials.Credential(store)
+	}
+	repo.Client = client
+
+	imageURL := image.URL()
+	_, manifestContent, err := oras.FetchBytes(ctx, repo, image.Tag, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error pulling chart %s: %w", imageURL, err)
+	}
+
+	manifest := ocispec.Manifest{}
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest of chart %s: %w", imageURL, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != chartLayerMediaType {
+			continue
+		}
+		data, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, fmt.Errorf("error pulling chart content %s: %w", imageURL, err)
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("no chart content found in %s", imageURL)
+}
+
+// renderChart renders the chart templates with the default values
+// chrt: The chart to render
+// Returns a map of template name to rendered manifest and any error that occurred
+func renderChart(chrt *chart.Chart) (map[string]string, error) {
+	if err := chartutil.ProcessDependenciesWithMerge(chrt, map[string]interface{}{}); err != nil {
+		return nil, fmt.Errorf("failed to process chart dependencies: %w", err)
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      chartReleaseName,
+		Namespace: chartReleaseName,
+		Revision:  1,
+		IsInstall: true,
+	}
+	values, err := chartutil.ToRenderValues(chrt, map[string]interface{}{}, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose chart values: %w", err)
+	}
+
+	// lint mode prevents "required" values from failing the rendering with default values
+	manifests, err := engine.Engine{LintMode: true}.Render(chrt, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	return manifests, nil
+}
+
+// imagesFromManifests collects all image references in the rendered manifests
+// it collects every string value of the "image" field, regardless of the kind of the resource
+// manifests: The rendered manifests
+// Returns a sorted and de-duplicated list of image references and any error that occurred
+func imagesFromManifests(manifests map[string]string) ([]string, error) {
+	found := map[string]struct{}{}
+
+	for name, manifest := range manifests {
+		ext := filepath.Ext(name)
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		decoder := yaml.NewDecoder(strings.NewReader(manifest))
+		for {
+			node := &yaml.Node{}
+			err := decoder.Decode(node)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode manifest %s: %w", name, err)
+			}
+			collectImages(node, found)
+		}
+	}
+
+	images := make([]string, 0, len(found))
+	for image := range found {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+
+	return images, nil
+}
+
+// collectImages walks a yaml node and records the values of "image" fields
+func collectImages(node *yaml.Node, found map[string]struct{}) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "image" && value.Kind == yaml.ScalarNode {
+				if image := strings.TrimSpace(value.Value); image != "" {
+					found[image] = struct{}{}
+				}
+				continue
+			}
+			collectImages(value, found)
+		}
+		return
+	}
+
+	for _, child := range node.Content {
+		collectImages(child, found)
+	}
+}
+
+// getChartImages retrieves all images referenced by the manifests of a chart
+// ctx: The context for the operation
+// chartImage: The chart image to analyze
+// Returns a list of images and any error that occurred
+func getChartImages(ctx context.Context, chartImage models.Image) ([]models.Image, error) {
+	logger := logging.FromContext(ctx).With("chart", chartImage)
+
+	chrt, err := loadChart(ctx, chartImage)
+	if err != nil {
+		logger.Errorw("failed to load chart", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debugw("render chart with default values")
+
+	manifests, err := renderChart(chrt)
+	if err != nil {
+		logger.Errorw("failed to render chart", zap.Error(err))
+		return nil, err
+	}
+
+	refs, err := imagesFromManifests(manifests)
+	if err != nil {
+		logger.Errorw("failed to collect images", zap.Error(err))
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no images found in chart")
+	}
+
+	results := make([]models.Image, 0, len(refs))
+	for _, ref := range refs {
+		named, err := reference.ParseNormalizedNamed(ref)
+		if err != nil {
+			logger.Warnw("skip invalid image reference", "image", ref, zap.Error(err))
+			continue
+		}
+
+		image, err := models.ImageFromURL(named.String())
+		if err != nil {
+			return nil, err
+		}
+		image.Owner = chartImage.Owner
+		results = append(results, image)
+	}
+
+	logger.Debugw("found chart images", "images", results)
+
+	return results, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/config"
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/models"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const testDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: {{ .Values.global.registry }}/devops/init@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef
+      containers:
+      - name: controller
+        image: {{ .Values.global.registry }}/devops/controller:{{ .Chart.AppVersion }}
+      - name: sidecar
+        image: {{ .Values.global.registry }}/devops/controller:{{ .Chart.AppVersion }}
+`
+
+const testCustomResource = `apiVersion: example.io/v1
+kind: Config
+metadata:
+  name: config
+spec:
+  components:
+    proxy:
+      image: {{ .Values.global.registry }}/devops/proxy:v2.0.0
+{{- if .Values.optional.enabled }}
+    optional:
+      image: {{ .Values.global.registry }}/devops/optional:v3.0.0
+{{- end }}
+`
+
+const testValues = `global:
+  registry: registry.example.com
+optional:
+  enabled: false
+`
+
+func newTestChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "chart-demo",
+			Version:    "1.0.0",
+			AppVersion: "v1.0.0",
+		},
+		Values: map[string]interface{}{
+			"global": map[string]interface{}{
+				"registry": "registry.example.com",
+			},
+			"optional": map[string]interface{}{
+				"enabled": false,
+			},
+		},
+		Raw: []*chart.File{
+			{Name: chartutil.ValuesfileName, Data: []byte(testValues)},
+		},
+		Templates: []*chart.File{
+			{Name: "templates/deployment.yaml", Data: []byte(testDeployment)},
+			{Name: "templates/config.yaml", Data: []byte(testCustomResource)},
+			{Name: "templates/NOTES.txt", Data: []byte("image: not-an-image")},
+		},
+	}
+}
+
+func TestRenderChartImages(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	manifests, err := renderChart(newTestChart())
+	g.Expect(err).To(BeNil())
+
+	images, err := imagesFromManifests(manifests)
+	g.Expect(err).To(BeNil())
+	g.Expect(images).To(Equal([]string{
+		"registry.example.com/devops/controller:v1.0.0",
+		"registry.example.com/devops/init@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		"registry.example.com/devops/proxy:v2.0.0",
+	}))
+}
+
+func TestGetChartImagesFromLocalTarball(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	_, err := chartutil.Save(newTestChart(), dir)
+	g.Expect(err).To(BeNil())
+
+	cfg := &config.Config{Chart: config.Chart{LocalDir: dir}}
+	ctx := cfg.InjectContext(context.Background())
+
+	owner := models.Owner{Team: "DEVOPS", JiraUser: "user1"}
+	images, err := getChartImages(ctx, models.Image{
+		Repository: "devops/chart-demo",
+		Tag:        "v1.0.0",
+		Type:       models.ImageTypeChart,
+		Owner:      owner,
+	})
+	g.Expect(err).To(BeNil())
+	g.Expect(images).To(HaveLen(3))
+	for _, image := range images {
+		g.Expect(image.Registry).To(Equal("registry.example.com"))
+		g.Expect(image.Type).To(Equal(models.ImageTypeImage))
+		g.Expect(image.Owner).To(Equal(owner))
+	}
+	g.Expect(images[0].Repository).To(Equal("devops/controller"))
+	g.Expect(images[0].Tag).To(Equal("v1.0.0"))
+	// digest pinned images keep their digest instead of defaulting to latest
+	g.Expect(images[1].Repository).To(Equal("devops/init"))
+	g.Expect(images[1].Tag).To(BeEmpty())
+	g.Expect(images[1].Digest).To(Equal("sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+}
+
+func TestFindChartTarballNotFound(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tarball, err := findChartTarball(t.TempDir(), models.Image{Repository: "devops/chart-demo", Tag: "v1.0.0"})
+	g.Expect(err).To(BeNil())
+	g.Expect(tarball).To(BeEmpty())
+}
@@ -22,26 +22,46 @@ import (
 	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/models"
 
 	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/ops"
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/vex"
 	"knative.dev/pkg/logging"
 )
 
 // Scanner represents a scanner for analyzing bundle images and their related images
 // opsClient: The OPS API client for vulnerability scanning
+// suppressor: Filters vulnerabilities that are not exploitable out of the scan results
 type Scanner struct {
-	opsClient *ops.Client
+	opsClient  *ops.Client
+	suppressor *vex.Suppressor
+}
+
+// ScannerOption represents a function that can modify a scanner
+type ScannerOption func(scanner *Scanner)
+
+// WithSuppressor sets the suppressor used to filter the scan results
+// suppressor: The suppressor to use
+func WithSuppressor(suppressor *vex.Suppressor) ScannerOption {
+	return func(scanner *Scanner) {
+		scanner.suppressor = suppressor
+	}
 }
 
 // NewScanner creates a new scanner instance
 // client: The OPS API client to use for scanning
-func NewScanner(client *ops.Client) *Scanner {
+// options: Options to customize the scanner
+func NewScanner(client *ops.Client, options ...ScannerOption) *Scanner {
 	scanner := &Scanner{
 		opsClient: client,
 	}
 
+	for _, option := range options {
+		option(scanner)
+	}
+
 	return scanner
 }
 
 // Scan performs vulnerability scanning on an image and its related images
+// related images of a bundle come from the CSV, related images of a chart come from its rendered manifests
 // ctx: The context for the operation
 // image: The image to scan
 // Returns a map of scan results for each image and any error that occurred
@@ -49,9 +69,17 @@ func (s *Scanner) Scan(ctx context.Context, image models.Image) (models.ScanResu
 	logger := logging.FromContext(ctx)
 
 	var images []models.Image
-	if image.Type == models.ImageTypeImage {
+	switch image.Type {
+	case models.ImageTypeImage:
 		images = append(images, image)
-	} else {
+	case models.ImageTypeChart:
+		chartImages, err := getChartImages(ctx, image)
+		if err != nil {
+			logger.Errorf("failed to get chart images: %w", err)
+			return nil, err
+		}
+		images = chartImages
+	default:
 		relatedImages, err := getRelatedImages(ctx, image)
 		if err != nil {
 			logger.Errorf("failed to get related images: %w", err)
@@ -72,11 +100,13 @@ func (s *Scanner) Scan(ctx context.Context, image models.Image) (models.ScanResu
 			return nil, err
 		}
 
+		result = s.suppressor.Apply(image.Plugin, relatedImage, result)
+
 		count := result.TotalVulnerabilities()
 		if count > 0 {
 			results[relatedImage] = result
 		}
-		logger.Infof("scan image completed, vulnerability count: %d", count)
+		logger.Infof("scan image completed, vulnerability count: %d, suppressed count: %d", count, len(result.Suppressed))
 	}
 
 	return results, nil
@@ -27,6 +27,7 @@ import (
 	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/config"
 	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/jira"
 	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/ops"
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/vex"
 	"github.com/ankitpokhrel/jira-cli/pkg/jql"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -93,8 +94,13 @@ func (s *Options) run(ctx context.Context) error {
 		return err
 	}
 
+	suppressor, err := vex.NewSuppressor(cfg.Suppression)
+	if err != nil {
+		return fmt.Errorf("failed to load suppressions: %w", err)
+	}
+
 	opsClient := ops.NewClient(cfg.Ops.BaseURL)
-	scanner := bundle.NewScanner(opsClient)
+	scanner := bundle.NewScanner(opsClient, bundle.WithSuppressor(suppressor))
 
 	jiraClient, err := jira.NewClient(cfg.Jira.BaseURL, cfg.Jira.Username, cfg.Jira.Password)
 	if err != nil {
@@ -103,12 +109,12 @@ func (s *Options) run(ctx context.Context) error {
 
 	for _, image := range images {
 
-		if image.Type != models.ImageTypeBundle {
-			// todo: only handle bundles currently
+		if image.Type != models.ImageTypeBundle && image.Type != models.ImageTypeChart {
+			// todo: only handle bundles and charts currently
 			continue
 		}
 
-		logger.Infof("==== start to scan bundle:%s ====", image.URL())
+		logger.Infof("==== start to scan %s:%s ====", strings.ToLower(string(image.Type)), image.URL())
 
 		owner, err := opsClient.GetOwner(image.URL())
 		if err != nil {
@@ -141,18 +147,18 @@ func (s *Options) run(ctx context.Context) error {
 			jira.WithProject(owner.Team),
 			jira.WithSummary(fmt.Sprintf("漏洞 - %s", image.Repository)),
 			jira.WithPriority(results.Priority()),
-			jira.WithLabels(s.branch, image.Repository, image.Tag),
+			jira.WithLabels(s.branch, image.Repository, image.Version()),
 			jira.WithType(jira.IssueTypeJob),
 		}
 
-		logger.Infof("creating jira issue for bundle")
+		logger.Infof("creating jira issue for %s", strings.ToLower(string(image.Type)))
 		query := s.searchJQL(owner.Team, []string{s.branch, image.Repository})
 		parentIssue, err := jiraClient.FindOrCreateIssue(ctx, query, parentOptions...)
 		if err != nil {
 			logger.Errorf("failed to create jira issue: %s", err.Error())
 			return err
 		}
-		logger.Infof("bundle issue created: %s", parentIssue.Key)
+		logger.Infof("%s issue created: %s", strings.ToLower(string(image.Type)), parentIssue.Key)
 
 		for relatedImage, result := range results {
 			description, err := jira.RenderVulnerabilityTable(result)
@@ -168,7 +174,7 @@ func (s *Options) run(ctx context.Context) error {
 				jira.WithSummary(fmt.Sprintf("漏洞 - %s", relatedImage.Repository)),
 				jira.WithDescription(description),
 				jira.WithPriority(result.Priority()),
-				jira.WithLabels(s.branch, relatedImage.Repository, relatedImage.Tag),
+				jira.WithLabels(s.branch, relatedImage.Repository, relatedImage.Version()),
 				jira.WithType(jira.IssueTypeVulnerability),
 				jira.WithAffectsVersion(version),
 				jira.WithCustomField(map[string]interface{}{
@@ -177,7 +183,7 @@ func (s *Options) run(ctx context.Context) error {
 				}),
 			}
 
-			query := s.searchJQL(owner.Team, []string{s.branch, relatedImage.Repository, relatedImage.Tag})
+			query := s.searchJQL(owner.Team, []string{s.branch, relatedImage.Repository, relatedImage.Version()})
 			childIssue, err := jiraClient.CreateOrUpdateIssue(ctx, query, childOptions...)
 			if err != nil {
 				logger.Infof("failed to create jira issue: %s", err.Error())
@@ -27,11 +27,15 @@ import (
 // Config represents the application configuration
 // Jira: Jira configuration settings
 // Ops: OPS API configuration settings
+// Chart: Helm chart configuration settings
+// Suppression: Vulnerability suppression settings
 type Config struct {
-	Jira     Jira     `json:"jira" yaml:"jira"`
-	Ops      Ops      `json:"ops" yaml:"ops"`
-	Users    []User   `json:"users" yaml:"users"`
-	Registry Registry `json:"registry" yaml:"registry"`
+	Jira        Jira        `json:"jira" yaml:"jira"`
+	Ops         Ops         `json:"ops" yaml:"ops"`
+	Users       []User      `json:"users" yaml:"users"`
+	Registry    Registry    `json:"registry" yaml:"registry"`
+	Chart       Chart       `json:"chart" yaml:"chart"`
+	Suppression Suppression `json:"suppression" yaml:"suppression"`
 }
 
 // Jira represents Jira configuration settings
@@ -55,6 +59,23 @@ type Registry struct {
 	Address string `json:"address" yaml:"address"`
 }
 
+// Chart represents the settings used to load helm charts
+// LocalDir: Directory containing chart tarballs, a tarball found here is used instead of pulling the chart from the registry
+// PlainHTTP: Use plain HTTP instead of HTTPS when pulling charts from the registry
+type Chart struct {
+	LocalDir  string `json:"localDir" yaml:"localDir"`
+	PlainHTTP bool   `json:"plainHTTP" yaml:"plainHTTP"`
+}
+
+// Suppression represents the files used to suppress vulnerabilities that are not exploitable
+// each file is either an OpenVEX document or a suppression YAML file
+// Files: Files applied to the images of all plugins
+// Plugins: Files applied to the images of a single plugin, keyed by plugin name
+type Suppression struct {
+	Files   []string            `json:"files" yaml:"files"`
+	Plugins map[string][]string `json:"plugins" yaml:"plugins"`
+}
+
 type User struct {
 	Email string   `json:"email" yaml:"email"`
 	Jira  JiraUser `json:"jira" yaml:"jira"`
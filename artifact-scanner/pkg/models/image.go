@@ -44,23 +44,42 @@ const (
 // Tag: The tag of the image
 // Owner: The ownership information of the image
 // Registry: The registry where the image is stored (not serialized)
+// Digest: The digest pinning the image, empty when it is referenced by tag only (not serialized)
 // Type: The type of the image (bundle, chart, image)
+// Plugin: The name of the plugin that the image belongs to (not serialized)
 type Image struct {
 	Repository string `json:"repository" yaml:"repository"`
 	Tag        string `json:"tag" yaml:"tag"`
 	Owner      Owner  `json:"owner" yaml:"owner"`
 
 	Registry string    `json:"-" yaml:"-"`
+	Digest   string    `json:"-" yaml:"-"`
 	Type     ImageType `json:"-" yaml:"-"`
+	Plugin   string    `json:"-" yaml:"-"`
 }
 
-// URL returns the full URL of the image including registry, repository and tag
+// URL returns the full URL of the image including registry, repository, tag and digest
 func (i *Image) URL() string {
-	return fmt.Sprintf("%s/%s:%s", i.Registry, i.Repository, i.Tag)
+	url := fmt.Sprintf("%s/%s", i.Registry, i.Repository)
+	if i.Tag != "" {
+		url += ":" + i.Tag
+	}
+	if i.Digest != "" {
+		url += "@" + i.Digest
+	}
+	return url
+}
+
+// Version returns the tag of the image, or its digest when the image is pinned by digest only
+func (i *Image) Version() string {
+	if i.Tag == "" {
+		return i.Digest
+	}
+	return i.Tag
 }
 
 // ComponentName extracts the component name from the repository
-// Removes the registry prefix and "-bundle" suffix if present
+// Removes the registry prefix, "-bundle" suffix and "chart-" prefix if present
 func (i *Image) ComponentName() string {
 	repository := i.Repository
 	lastIndex := strings.LastIndex(repository, "/")
@@ -68,7 +87,8 @@ func (i *Image) ComponentName() string {
 		repository = repository[lastIndex+1:]
 	}
 
-	return strings.TrimSuffix(repository, "-bundle")
+	repository = strings.TrimSuffix(repository, "-bundle")
+	return strings.TrimPrefix(repository, "chart-")
 }
 
 // ImageFromURL creates an Image struct from a URL string
@@ -84,9 +104,15 @@ func ImageFromURL(url string) (Image, error) {
 
 	repository := reference.Path(ref)
 
-	tag := "latest"
+	// an image pinned by digest only has no tag, it does not default to latest
+	var tag, digest string
+	if digested, ok := ref.(reference.Digested); ok {
+		digest = digested.Digest().String()
+	}
 	if tagged, ok := ref.(reference.Tagged); ok {
 		tag = tagged.Tag()
+	} else if digest == "" {
+		tag = "latest"
 	}
 
 	var imageType = ImageTypeImage
@@ -99,6 +125,7 @@ func ImageFromURL(url string) (Image, error) {
 	image := Image{
 		Repository: repository,
 		Tag:        tag,
+		Digest:     digest,
 		Registry:   registry,
 		Type:       imageType,
 	}
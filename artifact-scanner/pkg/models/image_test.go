@@ -46,6 +46,25 @@ func TestImage(t *testing.T) {
 			},
 			expected: "myregistry.com/app/backend:v1.0.0",
 		},
+		{
+			name: "image pinned by digest",
+			image: Image{
+				Registry:   "myregistry.com",
+				Repository: "app/backend",
+				Digest:     "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			expected: "myregistry.com/app/backend@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
+		{
+			name: "image with tag and digest",
+			image: Image{
+				Registry:   "myregistry.com",
+				Repository: "app/backend",
+				Tag:        "v1.0.0",
+				Digest:     "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			expected: "myregistry.com/app/backend:v1.0.0@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		},
 	}
 
 	for _, tt := range tests {
@@ -83,6 +102,13 @@ func TestComponentName(t *testing.T) {
 			},
 			expected: "backend",
 		},
+		{
+			name: "chart repository",
+			image: Image{
+				Repository: "devops/chart-harbor-robot-gen",
+			},
+			expected: "harbor-robot-gen",
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,6 +159,29 @@ func TestImageFromURL(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "image pinned by digest",
+			url:  "myregistry.com/app/backend@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			expected: Image{
+				Registry:   "myregistry.com",
+				Repository: "app/backend",
+				Digest:     "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Type:       ImageTypeImage,
+			},
+			expectError: false,
+		},
+		{
+			name: "image with tag and digest",
+			url:  "myregistry.com/app/backend:v1.0.0@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			expected: Image{
+				Registry:   "myregistry.com",
+				Repository: "app/backend",
+				Tag:        "v1.0.0",
+				Digest:     "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Type:       ImageTypeImage,
+			},
+			expectError: false,
+		},
 		{
 			name:        "invalid image URL",
 			url:         "invalid@url",
@@ -153,6 +202,7 @@ func TestImageFromURL(t *testing.T) {
 				g.Expect(result.Registry).To(Equal(tt.expected.Registry))
 				g.Expect(result.Repository).To(Equal(tt.expected.Repository))
 				g.Expect(result.Tag).To(Equal(tt.expected.Tag))
+				g.Expect(result.Digest).To(Equal(tt.expected.Digest))
 				g.Expect(result.Type).To(Equal(tt.expected.Type))
 			}
 		})
@@ -80,11 +80,14 @@ func (s SeverityList) HighestPriority() string {
 // Lang: Language-specific vulnerabilities
 // Secret: Secret scanning results
 // OSImage: The base OS image used
+// Suppressed: Vulnerabilities filtered out by VEX statements or suppression rules (not serialized)
 type ScanResult struct {
 	OS      []Vulnerability `json:"os"`
 	Lang    []Vulnerability `json:"lang"`
 	Secret  []interface{}   `json:"secret"`
 	OSImage string          `json:"os_image"`
+
+	Suppressed []SuppressedVulnerability `json:"-"`
 }
 
 // Vulnerability represents a single vulnerability finding
@@ -107,6 +110,15 @@ type Vulnerability struct {
 	Description      string `json:"Description"`
 }
 
+// SuppressedVulnerability represents a vulnerability that is not exploitable in the image
+// Status: The VEX status of the vulnerability, e.g. not_affected or fixed
+// Reason: The reason why the vulnerability is suppressed
+type SuppressedVulnerability struct {
+	Vulnerability
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
 // Priority returns the Jira priority corresponding to the highest severity in the scan result
 func (s *ScanResult) Priority() string {
 	return SeverityToPriority[s.Severity()]
@@ -64,7 +64,7 @@ func (v *ValuesSource) GetImages(ctx context.Context) ([]Image, error) {
 	}
 
 	images := make([]Image, 0)
-	for _, item := range values.Global.Images {
+	for name, item := range values.Global.Images {
 
 		imageType := ImageTypeImage
 		if strings.HasSuffix(item.Repository, "bundle") {
@@ -80,6 +80,7 @@ func (v *ValuesSource) GetImages(ctx context.Context) ([]Image, error) {
 			Owner:      item.Owner,
 			Registry:   values.Global.Registry.Address,
 			Type:       imageType,
+			Plugin:     name,
 		}
 
 		if v.bundle == "" || (v.bundle != "" && v.bundle == item.Repository) {
@@ -171,6 +172,7 @@ func (d *DirSource) GetImages(ctx context.Context) ([]Image, error) {
 				Tag:        artifact.Tag,
 				Type:       ImageType(artifact.Type),
 				Registry:   cfg.Registry.Address,
+				Plugin:     pluginName,
 				Owner: Owner{
 					Team:     jiraUser.Team,
 					JiraUser: jiraUser.User,
@@ -161,6 +161,7 @@ func TestDirSource_GetImages(t *testing.T) {
 			Repository: "devops/connectors-operator-bundle",
 			Tag:        "v1.1.0-beta.126.gf70d7e4",
 			Type:       ImageTypeBundle,
+			Plugin:     "connectors-operator",
 			Owner: Owner{
 				Team:     "DEVOPS",
 				JiraUser: "user1",
@@ -170,6 +171,7 @@ func TestDirSource_GetImages(t *testing.T) {
 			Repository: "devops/gitlab-ce-operator-bundle",
 			Tag:        "v17.12.0-beta.21.g5e337e0",
 			Type:       ImageTypeBundle,
+			Plugin:     "gitlab-ce-operator",
 			Owner: Owner{
 				Team:     "DEVOPS",
 				JiraUser: "user2",
@@ -179,6 +181,7 @@ func TestDirSource_GetImages(t *testing.T) {
 			Repository: "devops/chart-harbor-robot-gen",
 			Tag:        "v0.13.0-gb3a73ed",
 			Type:       ImageTypeChart,
+			Plugin:     "harbor-robot-gen",
 			Owner: Owner{
 				Team:     "DEVOPS",
 				JiraUser: "user1",
@@ -196,6 +199,7 @@ func TestDirSource_GetImages(t *testing.T) {
 			Repository: "devops/gitlab-ce-operator-bundle",
 			Tag:        "v17.12.0-beta.21.g5e337e0",
 			Type:       ImageTypeBundle,
+			Plugin:     "gitlab-ce-operator",
 			Owner: Owner{
 				Team:     "DEVOPS",
 				JiraUser: "user2",
@@ -205,6 +209,7 @@ func TestDirSource_GetImages(t *testing.T) {
 			Repository: "devops/chart-harbor-robot-gen",
 			Tag:        "v0.13.0-gb3a73ed",
 			Type:       ImageTypeChart,
+			Plugin:     "harbor-robot-gen",
 			Owner: Owner{
 				Team:     "DEVOPS",
 				JiraUser: "user1",
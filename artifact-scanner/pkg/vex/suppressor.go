@@ -0,0 +1,389 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/config"
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// rule is the normalized form of an OpenVEX statement or a suppression rule
+// vulnerabilities: The vulnerability ID and its aliases
+// products: Image references or package URLs of the images, empty means all images
+// packages: Package names or package URLs, empty means all packages
+type rule struct {
+	vulnerabilities []string
+	products        []string
+	packages        []string
+	status          Status
+	reason          string
+	timestamp       time.Time
+}
+
+// Suppressor filters suppressed vulnerabilities out of scan results
+// global: Rules applied to the images of all plugins
+// plugins: Rules applied to the images of a single plugin, keyed by plugin name
+type Suppressor struct {
+	global  []rule
+	plugins map[string][]rule
+}
+
+// NewSuppressor creates a new suppressor from the suppression settings
+// cfg: The suppression settings
+// Returns the suppressor and any error that occurred
+func NewSuppressor(cfg config.Suppression) (*Suppressor, error) {
+	suppressor := &Suppressor{
+		plugins: make(map[string][]rule),
+	}
+
+	for _, file := range cfg.Files {
+		rules, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		suppressor.global = append(suppressor.global, rules...)
+	}
+
+	for plugin, files := range cfg.Plugins {
+		for _, file := range files {
+			rules, err := loadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			suppressor.plugins[plugin] = append(suppressor.plugins[plugin], rules...)
+		}
+	}
+
+	return suppressor, nil
+}
+
+// loadFile loads the rules from an OpenVEX document or a suppression file
+// filePath: The path of the file
+// Returns the rules and any error that occurred
+func loadFile(filePath string) ([]rule, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suppression file %s: %w", filePath, err)
+	}
+
+	rules, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse suppression file %s: %w", filePath, err)
+	}
+
+	return rules, nil
+}
+
+// parse parses an OpenVEX document or a suppression file
+// a JSON document with an OpenVEX "@context" is an OpenVEX document, otherwise it is a suppression file
+func parse(data []byte) ([]rule, error) {
+	if json.Valid(data) && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		document := &Document{}
+		if err := json.Unmarshal(data, document); err != nil {
+			return nil, err
+		}
+		if strings.Contains(document.Context, "openvex") {
+			return documentRules(document), nil
+		}
+	}
+
+	file := &SuppressionFile{}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, err
+	}
+
+	return suppressionRules(file)
+}
+
+// documentRules converts the statements of an OpenVEX document to rules
+func documentRules(document *Document) []rule {
+	rules := make([]rule, 0, len(document.Statements))
+	for _, statement := range document.Statements {
+		r := rule{
+			vulnerabilities: append([]string{statement.Vulnerability.Name}, statement.Vulnerability.Aliases...),
+			status:          statement.Status,
+			reason:          statementReason(statement),
+		}
+
+		if statement.Timestamp != nil {
+			r.timestamp = *statement.Timestamp
+		} else if document.Timestamp != nil {
+			r.timestamp = *document.Timestamp
+		}
+
+		for _, product := range statement.Products {
+			r.products = append(r.products, product.ID)
+			for _, subcomponent := range product.Subcomponents {
+				r.packages = append(r.packages, subcomponent.ID)
+			}
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules
+}
+
+// statementReason builds a human readable reason from an OpenVEX statement
+func statementReason(statement Statement) string {
+	reasons := []string{}
+	for _, reason := range []string{statement.Justification, statement.ImpactStatement, statement.StatusNotes} {
+		if reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	return strings.Join(reasons, ": ")
+}
+
+// suppressionRules converts the entries of a suppression file to rules
+func suppressionRules(file *SuppressionFile) ([]rule, error) {
+	rules := make([]rule, 0, len(file.Suppressions))
+	for i, suppression := range file.Suppressions {
+		if suppression.Vulnerability == "" {
+			return nil, fmt.Errorf("suppression %d has no vulnerability", i)
+		}
+
+		status := suppression.Status
+		if status == "" {
+			status = StatusNotAffected
+		}
+
+		rules = append(rules, rule{
+			vulnerabilities: []string{suppression.Vulnerability},
+			products:        suppression.Images,
+			packages:        suppression.Packages,
+			status:          status,
+			reason:          suppression.Reason,
+		})
+	}
+
+	return rules, nil
+}
+
+// Apply filters the suppressed vulnerabilities out of a scan result
+// the latest matching rule decides the status of a vulnerability, plugin rules take precedence over global rules
+// plugin: The name of the plugin that the image belongs to
+// image: The scanned image
+// result: The scan result of the image
+// Returns a new scan result with the suppressed vulnerabilities moved to Suppressed
+func (s *Suppressor) Apply(plugin string, image models.Image, result *models.ScanResult) *models.ScanResult {
+	if s == nil || result == nil {
+		return result
+	}
+
+	rules := make([]rule, 0, len(s.global)+len(s.plugins[plugin]))
+	rules = append(rules, sortByTimestamp(s.global)...)
+	rules = append(rules, sortByTimestamp(s.plugins[plugin])...)
+
+	filtered := &models.ScanResult{
+		Secret:     result.Secret,
+		OSImage:    result.OSImage,
+		Suppressed: append([]models.SuppressedVulnerability{}, result.Suppressed...),
+	}
+
+	filter := func(vulns []models.Vulnerability) []models.Vulnerability {
+		kept := make([]models.Vulnerability, 0, len(vulns))
+		for _, vuln := range vulns {
+			matched := lastMatch(rules, image, vuln)
+			if matched == nil || !matched.status.Suppresses() {
+				kept = append(kept, vuln)
+				continue
+			}
+
+			filtered.Suppressed = append(filtered.Suppressed, models.SuppressedVulnerability{
+				Vulnerability: vuln,
+				Status:        string(matched.status),
+				Reason:        sanitizeReason(matched.reason),
+			})
+		}
+		return kept
+	}
+
+	filtered.OS = filter(result.OS)
+	filtered.Lang = filter(result.Lang)
+
+	sort.SliceStable(filtered.Suppressed, func(i, j int) bool {
+		if filtered.Suppressed[i].PkgName != filtered.Suppressed[j].PkgName {
+			return filtered.Suppressed[i].PkgName < filtered.Suppressed[j].PkgName
+		}
+		return filtered.Suppressed[i].VulnerabilityID < filtered.Suppressed[j].VulnerabilityID
+	})
+
+	return filtered
+}
+
+// sortByTimestamp returns a copy of the rules sorted by timestamp, rules without timestamp keep their order first
+func sortByTimestamp(rules []rule) []rule {
+	sorted := append([]rule{}, rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].timestamp.Before(sorted[j].timestamp)
+	})
+	return sorted
+}
+
+// lastMatch returns the last rule that matches the vulnerability of the image
+func lastMatch(rules []rule, image models.Image, vuln models.Vulnerability) *rule {
+	var matched *rule
+	for i := range rules {
+		if rules[i].matches(image, vuln) {
+			matched = &rules[i]
+		}
+	}
+	return matched
+}
+
+// matches returns true if the rule applies to the vulnerability of the image
+func (r *rule) matches(image models.Image, vuln models.Vulnerability) bool {
+	if !containsFold(r.vulnerabilities, vuln.VulnerabilityID) {
+		return false
+	}
+
+	if len(r.products) > 0 {
+		matched := false
+		for _, product := range r.products {
+			if matchProduct(product, image) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.packages) > 0 {
+		matched := false
+		for _, pkg := range r.packages {
+			if matchPackage(pkg, vuln.PkgName) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchProduct returns true if the product identifies the image
+// product is either an OCI package URL, e.g. pkg:oci/controller@sha256:...?repository_url=registry.example.com/devops/controller&tag=v1.0.0
+// or an image reference without registry, e.g. devops/controller:v1.0.0
+func matchProduct(product string, image models.Image) bool {
+	if strings.HasPrefix(product, "pkg:oci/") {
+		name, version, qualifiers := parsePURL(product)
+
+		if repositoryURL := qualifiers.Get("repository_url"); repositoryURL != "" {
+			if repositoryURL != image.Repository && !strings.HasSuffix(repositoryURL, "/"+image.Repository) {
+				return false
+			}
+		} else if path.Base(name) != path.Base(image.Repository) {
+			return false
+		}
+
+		if tag := qualifiers.Get("tag"); tag != "" && tag != image.Tag {
+			return false
+		}
+		if version != "" && !strings.Contains(version, ":") && version != image.Tag {
+			return false
+		}
+		return true
+	}
+
+	repository, tag := product, ""
+	if index := strings.LastIndex(product, ":"); index > strings.LastIndex(product, "/") {
+		repository, tag = product[:index], product[index+1:]
+	}
+
+	if repository != image.Repository && !strings.HasSuffix(repository, "/"+image.Repository) {
+		return false
+	}
+
+	return tag == "" || tag == image.Tag
+}
+
+// matchPackage returns true if the package identifies the package name reported by the scanner
+// pkg is either a package name or a package URL, e.g. pkg:golang/golang.org/x/net@v0.1.0
+func matchPackage(pkg string, pkgName string) bool {
+	if !strings.HasPrefix(pkg, "pkg:") {
+		return pkg == pkgName
+	}
+
+	name, _, _ := parsePURL(pkg)
+	return name == pkgName || path.Base(name) == pkgName
+}
+
+// parsePURL parses a package URL into the namespace/name, version and qualifiers, the type is dropped
+func parsePURL(purl string) (string, string, url.Values) {
+	purl = strings.TrimPrefix(purl, "pkg:")
+	if index := strings.Index(purl, "#"); index != -1 {
+		purl = purl[:index]
+	}
+
+	qualifiers := url.Values{}
+	if index := strings.Index(purl, "?"); index != -1 {
+		qualifiers, _ = url.ParseQuery(purl[index+1:])
+		purl = purl[:index]
+	}
+
+	version := ""
+	if index := strings.LastIndex(purl, "@"); index != -1 {
+		version, _ = url.PathUnescape(purl[index+1:])
+		purl = purl[:index]
+	}
+
+	// drop the package type
+	if index := strings.Index(purl, "/"); index != -1 {
+		purl = purl[index+1:]
+	}
+
+	name, err := url.PathUnescape(purl)
+	if err != nil {
+		name = purl
+	}
+
+	return name, version, qualifiers
+}
+
+// containsFold returns true if the list contains the value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeReason makes the reason safe to render in a Jira table cell
+func sanitizeReason(reason string) string {
+	reason = strings.ReplaceAll(reason, "|", "/")
+	return strings.Join(strings.Fields(reason), " ")
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vex
+
+import (
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/config"
+	"github.com/AlaudaDevops/toolbox/artifact-scanner/pkg/models"
+	. "github.com/onsi/gomega"
+)
+
+var testImage = models.Image{
+	Registry:   "registry.example.com",
+	Repository: "devops/gitlab-ce-operator",
+	Tag:        "v1.0.0",
+}
+
+func vulnerabilityIDs(vulns []models.Vulnerability) []string {
+	ids := []string{}
+	for _, vuln := range vulns {
+		ids = append(ids, vuln.VulnerabilityID)
+	}
+	return ids
+}
+
+func TestSuppressor_ApplyOpenVEX(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	suppressor, err := NewSuppressor(config.Suppression{
+		Plugins: map[string][]string{
+			"gitlab-ce-operator": {"testdata/openvex.json"},
+		},
+	})
+	g.Expect(err).To(BeNil())
+
+	result := &models.ScanResult{
+		OS: []models.Vulnerability{
+			{VulnerabilityID: "CVE-2024-0002", PkgName: "openssl", Severity: models.SeverityCritical},
+			{VulnerabilityID: "CVE-2024-0003", PkgName: "zlib", Severity: models.SeverityLow},
+		},
+		Lang: []models.Vulnerability{
+			{VulnerabilityID: "CVE-2024-0001", PkgName: "golang.org/x/net", Severity: models.SeverityHigh},
+			{VulnerabilityID: "CVE-2024-0001", PkgName: "golang.org/x/crypto", Severity: models.SeverityHigh},
+		},
+	}
+
+	filtered := suppressor.Apply("gitlab-ce-operator", testImage, result)
+	g.Expect(vulnerabilityIDs(filtered.OS)).To(Equal([]string{"CVE-2024-0003"}))
+	g.Expect(vulnerabilityIDs(filtered.Lang)).To(Equal([]string{"CVE-2024-0001"}))
+	g.Expect(filtered.Lang[0].PkgName).To(Equal("golang.org/x/crypto"))
+	g.Expect(filtered.Priority()).To(Equal(models.PriorityHigh))
+
+	g.Expect(filtered.Suppressed).To(HaveLen(2))
+	g.Expect(filtered.Suppressed[0].VulnerabilityID).To(Equal("CVE-2024-0001"))
+	g.Expect(filtered.Suppressed[0].Status).To(Equal(string(StatusNotAffected)))
+	g.Expect(filtered.Suppressed[0].Reason).To(Equal("vulnerable_code_not_in_execute_path: the http2 server is not used"))
+	g.Expect(filtered.Suppressed[1].VulnerabilityID).To(Equal("CVE-2024-0002"))
+	g.Expect(filtered.Suppressed[1].Status).To(Equal(string(StatusFixed)))
+
+	// the original result is untouched
+	g.Expect(result.TotalVulnerabilities()).To(Equal(4))
+
+	// plugin documents do not apply to other plugins
+	other := suppressor.Apply("connectors-operator", testImage, result)
+	g.Expect(other.TotalVulnerabilities()).To(Equal(4))
+	g.Expect(other.Suppressed).To(BeEmpty())
+}
+
+func TestSuppressor_ApplySuppressionFile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	suppressor, err := NewSuppressor(config.Suppression{
+		Files: []string{"testdata/suppressions.yaml"},
+	})
+	g.Expect(err).To(BeNil())
+
+	result := &models.ScanResult{
+		OS: []models.Vulnerability{
+			{VulnerabilityID: "CVE-2024-1000", PkgName: "bash"},
+			{VulnerabilityID: "CVE-2024-2000", PkgName: "bash"},
+			{VulnerabilityID: "CVE-2024-3000", PkgName: "openssl"},
+			{VulnerabilityID: "CVE-2024-3000", PkgName: "libssl"},
+		},
+	}
+
+	filtered := suppressor.Apply("", testImage, result)
+	g.Expect(vulnerabilityIDs(filtered.OS)).To(Equal([]string{"CVE-2024-2000", "CVE-2024-3000"}))
+	g.Expect(filtered.Suppressed).To(HaveLen(2))
+	g.Expect(filtered.Suppressed[0].Reason).To(Equal("only exploitable on windows"))
+	g.Expect(filtered.Suppressed[0].Status).To(Equal(string(StatusNotAffected)))
+	g.Expect(filtered.Suppressed[1].Reason).To(Equal("backported / verified"))
+	g.Expect(filtered.Suppressed[1].Status).To(Equal(string(StatusFixed)))
+}
+
+func TestNewSuppressor_MissingFile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := NewSuppressor(config.Suppression{Files: []string{"testdata/not-found.yaml"}})
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestMatchProduct(t *testing.T) {
+	tests := []struct {
+		name     string
+		product  string
+		expected bool
+	}{
+		{name: "repository", product: "devops/gitlab-ce-operator", expected: true},
+		{name: "repository with registry", product: "registry.example.com/devops/gitlab-ce-operator", expected: true},
+		{name: "repository with tag", product: "devops/gitlab-ce-operator:v1.0.0", expected: true},
+		{name: "repository with other tag", product: "devops/gitlab-ce-operator:v2.0.0", expected: false},
+		{name: "other repository", product: "devops/gitlab-ce-operator-bundle", expected: false},
+		{name: "purl by name", product: "pkg:oci/gitlab-ce-operator@sha256%3A1234", expected: true},
+		{name: "purl with tag", product: "pkg:oci/gitlab-ce-operator?tag=v1.0.0", expected: true},
+		{name: "purl with other tag", product: "pkg:oci/gitlab-ce-operator?tag=v2.0.0", expected: false},
+		{name: "purl with other repository url", product: "pkg:oci/gitlab-ce-operator?repository_url=registry.example.com/other/gitlab-ce-operator", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(matchProduct(tt.product, testImage)).To(Equal(tt.expected))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vex
+
+import "time"
+
+// Status represents the VEX status of a vulnerability
+type Status string
+
+const (
+	StatusNotAffected        Status = "not_affected"
+	StatusAffected           Status = "affected"
+	StatusFixed              Status = "fixed"
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Suppresses returns true if the vulnerability should be filtered out of the scan result
+func (s Status) Suppresses() bool {
+	return s == StatusNotAffected || s == StatusFixed
+}
+
+// Document represents an OpenVEX document
+// OpenVEX documents are always JSON, see https://github.com/openvex/spec/blob/main/OPENVEX-SPEC.md
+type Document struct {
+	Context    string      `json:"@context"`
+	ID         string      `json:"@id"`
+	Author     string      `json:"author"`
+	Timestamp  *time.Time  `json:"timestamp"`
+	Statements []Statement `json:"statements"`
+}
+
+// Statement represents a statement of an OpenVEX document
+type Statement struct {
+	Vulnerability   Vulnerability `json:"vulnerability"`
+	Products        []Product     `json:"products"`
+	Status          Status        `json:"status"`
+	Timestamp       *time.Time    `json:"timestamp"`
+	StatusNotes     string        `json:"status_notes"`
+	Justification   string        `json:"justification"`
+	ImpactStatement string        `json:"impact_statement"`
+}
+
+// Vulnerability represents the vulnerability of an OpenVEX statement
+type Vulnerability struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// Component represents a product or subcomponent of an OpenVEX statement
+// ID is an image reference or a package URL
+type Component struct {
+	ID string `json:"@id"`
+}
+
+// Product represents a product of an OpenVEX statement
+type Product struct {
+	Component
+	Subcomponents []Component `json:"subcomponents"`
+}
+
+// SuppressionFile represents a simple suppression file
+//
+//	suppressions:
+//	- vulnerability: CVE-2024-0001
+//	  images: [devops/gitlab-ce-operator]
+//	  packages: [golang.org/x/net]
+//	  status: not_affected
+//	  reason: the vulnerable code is not called
+type SuppressionFile struct {
+	Suppressions []Suppression `json:"suppressions" yaml:"suppressions"`
+}
+
+// Suppression represents a single suppression rule
+// Vulnerability: The vulnerability ID to suppress
+// Images: Image references the rule applies to, empty means all images
+// Packages: Package names the rule applies to, empty means all packages
+// Status: The VEX status, defaults to not_affected
+// Reason: The reason why the vulnerability is suppressed
+type Suppression struct {
+	Vulnerability string   `json:"vulnerability" yaml:"vulnerability"`
+	Images        []string `json:"images" yaml:"images"`
+	Packages      []string `json:"packages" yaml:"packages"`
+	Status        Status   `json:"status" yaml:"status"`
+	Reason        string   `json:"reason" yaml:"reason"`
+}
@@ -3,66 +3,224 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/aggregate"
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/compare"
 	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
 	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/report"
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/threshold"
 	"github.com/spf13/cobra"
 )
 
 var (
-	inputFile  string
-	outputFile string
-	format     string
+	inputFile    string
+	outputFile   string
+	format       string
+	baselineFile string
+	nodeInputs   []string
+	outputFormat string
+	failOn       []string
 )
 
+// ThresholdError is returned when the benchmark data exceeds a --fail-on threshold
+// the report is still written, so pipelines can publish it before failing
+type ThresholdError struct {
+	Violations []string
+}
+
+func (e *ThresholdError) Error() string {
+	return fmt.Sprintf("fail-on thresholds exceeded: %s", strings.Join(e.Violations, "; "))
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "kube-bench-report",
 	Short: "Generate HTML reports from kube-bench output",
 	Long: `kube-bench-report is a CLI tool that generates user-readable, self-contained HTML reports
 from kube-bench output files. It can process text, JSON, and JUnit XML output formats from kube-bench.
+Reports can also be generated as normalized JSON, Markdown, CSV or SARIF, and --fail-on thresholds
+make the command exit with code 2 so pipelines can be blocked.
 
 Example usage:
   kube-bench-report --input kube-bench.txt --output report.html
   kube-bench-report --input kube-bench.json --output report.html
-  kube-bench-report --input kube-bench-junit.xml --output report.html`,
+  kube-bench-report --input kube-bench-junit.xml --output report.html
+  kube-bench-report --input after.json --baseline before.txt --output report.html
+  kube-bench-report --input ./results/ --output report.html
+  kube-bench-report --node master-1=master.json --node worker-1=worker.txt --output report.html
+  kube-bench-report --input kube-bench.json --output-format markdown --output -
+  kube-bench-report --input kube-bench.json --output-format sarif --fail-on fail:1 --fail-on "warn>10"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		rules, err := threshold.ParseAll(failOn)
+		if err != nil {
+			return err
+		}
+
+		// Use the extension of the output format when the output file is not set
+		output := outputFile
+		if !cmd.Flags().Changed("output") && outputFormat != report.FormatHTML {
+			output = "kube-bench-report." + report.Extension(outputFormat)
+		}
+
+		// Labeled node inputs are always aggregated
+		if len(nodeInputs) > 0 {
+			return runAggregated(cmd, output, rules)
+		}
+
+		if inputFile == "" {
+			return fmt.Errorf("either --input or --node is required")
+		}
+
 		// Validate input file exists
-		if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		info, err := os.Stat(inputFile)
+		if os.IsNotExist(err) {
 			return fmt.Errorf("input file does not exist: %s", inputFile)
 		}
 
+		// A directory contains the outputs of multiple nodes
+		if err == nil && info.IsDir() {
+			return runAggregated(cmd, output, rules)
+		}
+
 		// Parse the input file
 		benchData, err := parser.ParseFile(inputFile)
 		if err != nil {
 			return fmt.Errorf("failed to parse input file: %v", err)
 		}
 
-		// Generate the HTML report
-		htmlContent, err := report.GenerateHTML(benchData)
+		var opts []report.Option
+		if baselineFile != "" {
+			baselineData, err := parser.ParseFile(baselineFile)
+			if err != nil {
+				return fmt.Errorf("failed to parse baseline file: %v", err)
+			}
+			opts = append(opts, report.WithComparison(compare.Compare(benchData, baselineData)))
+		}
+
+		// Generate the report
+		content, err := report.Generate(outputFormat, benchData, opts...)
 		if err != nil {
-			return fmt.Errorf("failed to generate HTML report: %v", err)
+			return fmt.Errorf("failed to generate report: %v", err)
 		}
 
-		// Write the HTML report to the output file
-		if err := os.WriteFile(outputFile, []byte(htmlContent), 0644); err != nil {
-			return fmt.Errorf("failed to write output file: %v", err)
+		if err := writeReport(output, content); err != nil {
+			return err
 		}
+		// The usage is not helpful once the report is written
+		cmd.SilenceUsage = true
 
-		fmt.Printf("Report successfully generated: %s\n", outputFile)
-		return nil
+		violations := []string{}
+		for _, violation := range threshold.Evaluate(benchData, rules) {
+			violations = append(violations, violation.String())
+		}
+		return thresholdError(violations)
 	},
 }
 
+// runAggregated generates a single report from the outputs of multiple nodes
+func runAggregated(cmd *cobra.Command, output string, rules []threshold.Rule) error {
+	if baselineFile != "" {
+		return fmt.Errorf("--baseline is not supported with multiple nodes")
+	}
+	if outputFormat != report.FormatHTML {
+		return fmt.Errorf("--output-format %s is not supported with multiple nodes", outputFormat)
+	}
+
+	results := []aggregate.NodeResult{}
+	if inputFile != "" {
+		info, err := os.Stat(inputFile)
+		if err != nil {
+			return fmt.Errorf("failed to stat input: %v", err)
+		}
+
+		if info.IsDir() {
+			dirResults, err := aggregate.LoadDir(inputFile)
+			if err != nil {
+				return err
+			}
+			results = append(results, dirResults...)
+		} else {
+			data, err := parser.ParseFile(inputFile)
+			if err != nil {
+				return fmt.Errorf("failed to parse input file: %v", err)
+			}
+			results = append(results, aggregate.NodeResult{Name: aggregate.NodeNameFromPath(inputFile), Data: data})
+		}
+	}
+
+	for _, nodeInput := range nodeInputs {
+		name, path, err := aggregate.ParseNodeArg(nodeInput)
+		if err != nil {
+			return err
+		}
+
+		data, err := parser.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse input file of node %s: %v", name, err)
+		}
+		results = append(results, aggregate.NodeResult{Name: name, Data: data})
+	}
+
+	aggregated, err := aggregate.Aggregate(results)
+	if err != nil {
+		return err
+	}
+
+	htmlContent, err := report.GenerateAggregatedHTML(aggregated)
+	if err != nil {
+		return fmt.Errorf("failed to generate HTML report: %v", err)
+	}
+
+	if err := writeReport(output, htmlContent); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	// Thresholds apply to each node, so a single failing node blocks the pipeline
+	violations := []string{}
+	for _, result := range results {
+		for _, violation := range threshold.Evaluate(result.Data, rules) {
+			violations = append(violations, fmt.Sprintf("node %s: %s", result.Name, violation))
+		}
+	}
+	return thresholdError(violations)
+}
+
+// thresholdError returns a ThresholdError if there are violations, nil otherwise
+func thresholdError(violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ThresholdError{Violations: violations}
+}
+
+// writeReport writes the report to the output file, or to stdout if the output is "-"
+func writeReport(output, content string) error {
+	if output == "-" {
+		fmt.Print(content)
+		return nil
+	}
+
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+
+	fmt.Printf("Report successfully generated: %s\n", output)
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&inputFile, "input", "i", "", "Input file containing kube-bench output (required)")
-	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "kube-bench-report.html", "Output HTML report file")
+	rootCmd.Flags().StringVarP(&inputFile, "input", "i", "", "Input file containing kube-bench output, or a directory containing one output file per node")
+	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "kube-bench-report.html", "Output report file, - for stdout")
 	rootCmd.Flags().StringVarP(&format, "format", "f", "auto", "Input format (auto, text, json, junit)")
-
-	rootCmd.MarkFlagRequired("input")
+	rootCmd.Flags().StringVarP(&baselineFile, "baseline", "b", "", "Baseline kube-bench output to compare against (optional)")
+	rootCmd.Flags().StringArrayVarP(&nodeInputs, "node", "n", nil, "kube-bench output of a node as <node>=<file>, can be repeated to aggregate multiple nodes")
+	rootCmd.Flags().StringVar(&outputFormat, "output-format", report.FormatHTML, "Output format ("+strings.Join(report.Formats, ", ")+")")
+	rootCmd.Flags().StringArrayVar(&failOn, "fail-on", nil, "Exit with code 2 when a threshold is exceeded, as <state>[:<check id prefix>][>|>=<count>], e.g. fail:1 or warn>10, can be repeated")
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -118,4 +119,126 @@ func TestRootCmd(t *testing.T) {
 			t.Error("Expected error for invalid input file")
 		}
 	})
+	t.Run("WithBaseline", func(t *testing.T) {
+		defer func() { baselineFile = "" }()
+
+		baselineContent := `[INFO] 4 Worker Node Security Configuration
+[INFO] 4.1 Worker Node Configuration Files
+[FAIL] 4.1.1 Test check
+
+== Summary total ==
+0 checks PASS
+1 checks FAIL
+0 checks WARN
+0 checks INFO
+`
+		xmlContent := `<testsuites>
+	<testsuite name="4 Test Control" tests="1" failures="0" errors="0" time="0">
+		<testcase name="4.1.1 Test check" classname="4.1 Test Group" time="0">
+			<system-out>{"test_number":"4.1.1","status":"PASS"}</system-out>
+		</testcase>
+	</testsuite>
+</testsuites>`
+
+		baseline := filepath.Join(tmpDir, "baseline.txt")
+		input := filepath.Join(tmpDir, "current.xml")
+		output := filepath.Join(tmpDir, "output-baseline.html")
+
+		if err := os.WriteFile(baseline, []byte(baselineContent), 0644); err != nil {
+			t.Fatalf("Failed to write baseline file: %v", err)
+		}
+		if err := os.WriteFile(input, []byte(xmlContent), 0644); err != nil {
+			t.Fatalf("Failed to write input file: %v", err)
+		}
+
+		rootCmd.SetArgs([]string{"--input", input, "--baseline", baseline, "--output", output})
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Expected successful execution, got error: %v", err)
+		}
+
+		content, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if !strings.Contains(string(content), "Newly passing") {
+			t.Error("Expected output to mark the check as newly passing")
+		}
+	})
+
+	t.Run("WithNodeDirectory", func(t *testing.T) {
+		nodeDir := filepath.Join(tmpDir, "nodes")
+		if err := os.Mkdir(nodeDir, 0755); err != nil {
+			t.Fatalf("Failed to create node directory: %v", err)
+		}
+
+		for node, state := range map[string]string{"worker-1": "FAIL", "worker-2": "PASS"} {
+			content := "[INFO] 4 Worker Node Security Configuration\n[INFO] 4.1 Worker Node Configuration Files\n[" + state + "] 4.1.1 Test check\n"
+			if err := os.WriteFile(filepath.Join(nodeDir, node+".txt"), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write input file: %v", err)
+			}
+		}
+
+		output := filepath.Join(tmpDir, "output-nodes.html")
+		rootCmd.SetArgs([]string{"--input", nodeDir, "--output", output})
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Expected successful execution, got error: %v", err)
+		}
+
+		content, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if !strings.Contains(string(content), "<th>worker-1</th>") || !strings.Contains(string(content), "<th>worker-2</th>") {
+			t.Error("Expected output to contain a column per node")
+		}
+	})
+	t.Run("WithOutputFormatAndFailOn", func(t *testing.T) {
+		defer func() {
+			outputFormat = "html"
+			failOn = nil
+		}()
+
+		content := "[INFO] 1 Control Plane Security Configuration\n[INFO] 1.1 Control Plane Node Configuration Files\n[FAIL] 1.1.1 Test check\n[WARN] 1.1.2 Other check\n"
+		input := filepath.Join(tmpDir, "gate.txt")
+		if err := os.WriteFile(input, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write input file: %v", err)
+		}
+
+		output := filepath.Join(tmpDir, "output.csv")
+		rootCmd.SetArgs([]string{"--input", input, "--output", output, "--output-format", "csv", "--fail-on", "fail:4", "--fail-on", "warn>1"})
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Expected thresholds not to be exceeded, got error: %v", err)
+		}
+
+		report, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if !strings.HasPrefix(string(report), "Control ID,") {
+			t.Errorf("Expected CSV output, got %s", report)
+		}
+
+		rootCmd.SetArgs([]string{"--input", input, "--output", output, "--output-format", "csv", "--fail-on", "fail:1"})
+		err = rootCmd.Execute()
+
+		var thresholdErr *ThresholdError
+		if !errors.As(err, &thresholdErr) {
+			t.Fatalf("Expected threshold error, got %v", err)
+		}
+		if len(thresholdErr.Violations) != 1 {
+			t.Errorf("Expected 1 violation, got %v", thresholdErr.Violations)
+		}
+		if _, err := os.Stat(output); err != nil {
+			t.Error("Expected report to be written before failing")
+		}
+	})
 }
\ No newline at end of file
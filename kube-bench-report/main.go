@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -10,6 +11,12 @@ import (
 func main() {
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+		// Threshold violations use a dedicated exit code to distinguish them from errors
+		var thresholdErr *cmd.ThresholdError
+		if errors.As(err, &thresholdErr) {
+			os.Exit(2)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,241 @@
+package aggregate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// NodeResult represents the kube-bench output of a single node
+type NodeResult struct {
+	Name string                `json:"name"`
+	Data *parser.BenchmarkData `json:"data"`
+}
+
+// Report represents the aggregated results of multiple nodes
+type Report struct {
+	// Nodes contains the node names in input order
+	Nodes []string `json:"nodes"`
+	// NodeTotals contains the totals of each node in input order
+	NodeTotals []NodeTotals `json:"nodeTotals"`
+	// Totals is the sum of the totals of all nodes
+	Totals parser.Totals `json:"totals"`
+	// Controls contains the union of the controls of all nodes
+	Controls []Control `json:"controls"`
+}
+
+// NodeTotals represents the totals of a single node
+type NodeTotals struct {
+	Node   string        `json:"node"`
+	Totals parser.Totals `json:"totals"`
+}
+
+// Control represents a control merged across nodes
+type Control struct {
+	ID     string  `json:"id"`
+	Text   string  `json:"text"`
+	Groups []Group `json:"groups"`
+}
+
+// Group represents a group merged across nodes
+type Group struct {
+	ID     string  `json:"id"`
+	Text   string  `json:"text"`
+	Checks []Check `json:"checks"`
+}
+
+// Check represents a check merged across nodes
+type Check struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	// States maps node names to the state of the check on that node, nodes that didn't run the check are absent
+	States map[string]string `json:"states"`
+	// Remediations contains the distinct remediation texts reported by the nodes
+	Remediations []string `json:"remediations,omitempty"`
+	// FailingNodes contains the nodes on which the check failed, in input order
+	FailingNodes []string `json:"failingNodes,omitempty"`
+}
+
+// State returns the state of the check on a node, empty if the node didn't run the check
+func (c Check) State(node string) string {
+	return c.States[node]
+}
+
+// ParseNodeArg parses a "name=path" argument into a node name and a file path
+// if the name is omitted, the file name without extension is used as node name
+func ParseNodeArg(arg string) (string, string, error) {
+	name, path, found := strings.Cut(arg, "=")
+	if !found {
+		return NodeNameFromPath(arg), arg, nil
+	}
+
+	name = strings.TrimSpace(name)
+	path = strings.TrimSpace(path)
+	if name == "" || path == "" {
+		return "", "", fmt.Errorf("invalid node input %q, expected <node>=<file>", arg)
+	}
+
+	return name, path, nil
+}
+
+// NodeNameFromPath returns the file name without extension
+func NodeNameFromPath(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// LoadDir parses every regular file of a directory as the kube-bench output of a node
+// the file name without extension is used as node name, hidden files are ignored
+func LoadDir(dir string) ([]NodeResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %v", err)
+	}
+
+	results := []NodeResult{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := parser.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		results = append(results, NodeResult{Name: NodeNameFromPath(path), Data: data})
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no kube-bench output found in directory: %s", dir)
+	}
+
+	return results, nil
+}
+
+// Aggregate merges the results of multiple nodes into a single report
+// controls, groups and checks are matched by ID and keep the order of their first appearance
+func Aggregate(results []NodeResult) (*Report, error) {
+	report := &Report{
+		Nodes:      []string{},
+		NodeTotals: []NodeTotals{},
+		Controls:   []Control{},
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if seen[result.Name] {
+			return nil, fmt.Errorf("duplicate node name: %s", result.Name)
+		}
+		seen[result.Name] = true
+
+		report.Nodes = append(report.Nodes, result.Name)
+		report.NodeTotals = append(report.NodeTotals, NodeTotals{Node: result.Name, Totals: result.Data.Totals})
+		report.Totals.Pass += result.Data.Totals.Pass
+		report.Totals.Fail += result.Data.Totals.Fail
+		report.Totals.Warn += result.Data.Totals.Warn
+		report.Totals.Info += result.Data.Totals.Info
+
+		for _, control := range result.Data.Controls {
+			merged := report.control(control)
+			for _, group := range control.Groups {
+				mergedGroup := merged.group(group)
+				for _, check := range group.Checks {
+					mergedGroup.addCheck(result.Name, check)
+				}
+			}
+		}
+	}
+
+	for c := range report.Controls {
+		for g := range report.Controls[c].Groups {
+			for i := range report.Controls[c].Groups[g].Checks {
+				check := &report.Controls[c].Groups[g].Checks[i]
+				for _, node := range report.Nodes {
+					if check.States[node] == "fail" {
+						check.FailingNodes = append(check.FailingNodes, node)
+					}
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// control returns the merged control with the ID of the given control, adding it if missing
+func (r *Report) control(control parser.Control) *Control {
+	for i := range r.Controls {
+		if r.Controls[i].ID == control.ID {
+			return &r.Controls[i]
+		}
+	}
+
+	r.Controls = append(r.Controls, Control{ID: control.ID, Text: control.Text, Groups: []Group{}})
+	return &r.Controls[len(r.Controls)-1]
+}
+
+// group returns the merged group with the ID of the given group, adding it if missing
+func (c *Control) group(group parser.Group) *Group {
+	for i := range c.Groups {
+		if c.Groups[i].ID == group.ID {
+			return &c.Groups[i]
+		}
+	}
+
+	c.Groups = append(c.Groups, Group{ID: group.ID, Text: group.Text, Checks: []Check{}})
+	return &c.Groups[len(c.Groups)-1]
+}
+
+// addCheck records the state and remediation of a check on a node
+func (g *Group) addCheck(node string, check parser.Check) {
+	var merged *Check
+	for i := range g.Checks {
+		if g.Checks[i].ID == check.ID {
+			merged = &g.Checks[i]
+			break
+		}
+	}
+
+	if merged == nil {
+		g.Checks = append(g.Checks, Check{ID: check.ID, Text: check.Text, States: make(map[string]string)})
+		merged = &g.Checks[len(g.Checks)-1]
+	}
+
+	merged.States[node] = check.State
+
+	remediation := strings.TrimSpace(check.Remediation)
+	if remediation != "" && !contains(merged.Remediations, remediation) {
+		merged.Remediations = append(merged.Remediations, remediation)
+	}
+}
+
+// FailingChecks returns the IDs of the checks failing on at least one node, sorted by ID
+func (r *Report) FailingChecks() []string {
+	ids := []string{}
+	for _, control := range r.Controls {
+		for _, group := range control.Groups {
+			for _, check := range group.Checks {
+				if len(check.FailingNodes) > 0 {
+					ids = append(ids, check.ID)
+				}
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// contains returns true if the list contains the value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package aggregate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+func newNodeData(controlID string, checks ...parser.Check) *parser.BenchmarkData {
+	data := &parser.BenchmarkData{
+		Controls: []parser.Control{
+			{
+				ID:   controlID,
+				Text: "Control " + controlID,
+				Groups: []parser.Group{
+					{ID: controlID + ".1", Text: "Group " + controlID + ".1", Checks: checks},
+				},
+			},
+		},
+	}
+	for _, check := range checks {
+		switch check.State {
+		case "pass":
+			data.Totals.Pass++
+		case "fail":
+			data.Totals.Fail++
+		case "warn":
+			data.Totals.Warn++
+		}
+	}
+	return data
+}
+
+func TestAggregate(t *testing.T) {
+	results := []NodeResult{
+		{Name: "master-1", Data: newNodeData("1",
+			parser.Check{ID: "1.1.1", State: "fail", Remediation: "chmod 600 /etc/kubernetes/manifests/kube-apiserver.yaml"},
+			parser.Check{ID: "1.1.2", State: "pass"},
+		)},
+		{Name: "worker-1", Data: newNodeData("4",
+			parser.Check{ID: "4.1.1", State: "fail", Remediation: "chmod 600 kubelet.service"},
+		)},
+		{Name: "worker-2", Data: newNodeData("4",
+			parser.Check{ID: "4.1.1", State: "fail", Remediation: "chmod 600 kubelet.service\n"},
+			parser.Check{ID: "4.1.2", State: "warn"},
+		)},
+	}
+
+	report, err := Aggregate(results)
+	if err != nil {
+		t.Fatalf("Expected successful aggregation, got error: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.Nodes, []string{"master-1", "worker-1", "worker-2"}) {
+		t.Errorf("Unexpected nodes: %v", report.Nodes)
+	}
+
+	expectedTotals := parser.Totals{Pass: 1, Fail: 3, Warn: 1}
+	if report.Totals != expectedTotals {
+		t.Errorf("Expected totals %+v, got %+v", expectedTotals, report.Totals)
+	}
+	if report.NodeTotals[2].Node != "worker-2" || report.NodeTotals[2].Totals.Warn != 1 {
+		t.Errorf("Unexpected node totals: %+v", report.NodeTotals)
+	}
+
+	if len(report.Controls) != 2 {
+		t.Fatalf("Expected 2 controls, got %d", len(report.Controls))
+	}
+
+	workerChecks := report.Controls[1].Groups[0].Checks
+	if len(workerChecks) != 2 {
+		t.Fatalf("Expected 2 worker checks, got %d", len(workerChecks))
+	}
+
+	check := workerChecks[0]
+	if !reflect.DeepEqual(check.FailingNodes, []string{"worker-1", "worker-2"}) {
+		t.Errorf("Unexpected failing nodes: %v", check.FailingNodes)
+	}
+	if len(check.Remediations) != 1 {
+		t.Errorf("Expected remediation to be de-duplicated, got %v", check.Remediations)
+	}
+	if check.State("master-1") != "" {
+		t.Errorf("Expected no state for a node that didn't run the check, got %q", check.State("master-1"))
+	}
+	if workerChecks[1].State("worker-2") != "warn" {
+		t.Errorf("Expected warn state, got %q", workerChecks[1].State("worker-2"))
+	}
+
+	if !reflect.DeepEqual(report.FailingChecks(), []string{"1.1.1", "4.1.1"}) {
+		t.Errorf("Unexpected failing checks: %v", report.FailingChecks())
+	}
+}
+
+func TestAggregateDuplicateNode(t *testing.T) {
+	results := []NodeResult{
+		{Name: "node", Data: newNodeData("4")},
+		{Name: "node", Data: newNodeData("4")},
+	}
+
+	if _, err := Aggregate(results); err == nil {
+		t.Error("Expected error for duplicate node names")
+	}
+}
+
+func TestParseNodeArg(t *testing.T) {
+	tests := []struct {
+		arg          string
+		expectedName string
+		expectedPath string
+		expectError  bool
+	}{
+		{arg: "master-1=out/master.json", expectedName: "master-1", expectedPath: "out/master.json"},
+		{arg: "out/worker-1.txt", expectedName: "worker-1", expectedPath: "out/worker-1.txt"},
+		{arg: "=out/worker-1.txt", expectError: true},
+		{arg: "worker-1=", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			name, path, err := ParseNodeArg(tt.arg)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if name != tt.expectedName || path != tt.expectedPath {
+				t.Errorf("Expected %s=%s, got %s=%s", tt.expectedName, tt.expectedPath, name, path)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+
+	master := `[INFO] 1 Control Plane Security Configuration
+[INFO] 1.1 Control Plane Node Configuration Files
+[FAIL] 1.1.1 Ensure that the API server pod specification file permissions are set to 600 or more restrictive (Automated)
+`
+	worker := `{"controls":[{"id":"4","text":"Worker Node Security Configuration","groups":[{"id":"4.1","text":"Worker Node Configuration Files","checks":[{"id":"4.1.1","text":"Ensure that the kubelet service file permissions are set to 600","state":"pass"}]}]}],"totals":{"pass":1}}`
+
+	if err := os.WriteFile(filepath.Join(dir, "master.txt"), []byte(master), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "worker.json"), []byte(worker), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".hidden"), []byte("ignored"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	results, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("Expected successful load, got error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "master" || results[1].Name != "worker" {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+	if results[0].Data.Totals.Fail != 1 || results[1].Data.Totals.Pass != 1 {
+		t.Errorf("Unexpected totals: %+v, %+v", results[0].Data.Totals, results[1].Data.Totals)
+	}
+
+	if _, err := LoadDir(t.TempDir()); err == nil {
+		t.Error("Expected error for empty directory")
+	}
+}
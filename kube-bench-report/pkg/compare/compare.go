@@ -0,0 +1,150 @@
+package compare
+
+import (
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// Change describes how the state of a check changed compared to the baseline
+type Change string
+
+const (
+	// NewlyFailing means the check fails now but did not fail in the baseline
+	NewlyFailing Change = "newly-failing"
+	// NewlyPassing means the check passes now but did not pass in the baseline
+	NewlyPassing Change = "newly-passing"
+	// Unchanged means the check has the same state as in the baseline
+	Unchanged Change = "unchanged"
+	// Changed means the state changed without newly failing or passing (e.g. fail -> warn)
+	Changed Change = "changed"
+	// Added means the check does not exist in the baseline
+	Added Change = "added"
+)
+
+// CheckChange represents the comparison result of a single check
+type CheckChange struct {
+	Change        Change `json:"change"`
+	BaselineState string `json:"baselineState,omitempty"`
+}
+
+// Label returns a human readable label of the change
+func (c CheckChange) Label() string {
+	switch c.Change {
+	case NewlyFailing:
+		return "Newly failing"
+	case NewlyPassing:
+		return "Newly passing"
+	case Changed:
+		return "Changed from " + c.BaselineState
+	case Added:
+		return "New check"
+	default:
+		return "Unchanged"
+	}
+}
+
+// Comparison represents the comparison of a benchmark run against a baseline run
+type Comparison struct {
+	// Checks maps check IDs of the current run to their change
+	Checks map[string]CheckChange `json:"checks"`
+	// Removed contains the checks that only exist in the baseline
+	Removed []parser.Check `json:"removed"`
+	// Baseline is the totals of the baseline run
+	Baseline parser.Totals `json:"baseline"`
+	// Delta is the difference of the totals (current - baseline)
+	Delta parser.Totals `json:"delta"`
+	// Counts is the number of checks per change
+	Counts map[Change]int `json:"counts"`
+}
+
+// Compare compares the current benchmark data against the baseline
+// checks are matched by ID, so any mix of text, JSON and JUnit input can be compared
+func Compare(current, baseline *parser.BenchmarkData) *Comparison {
+	comparison := &Comparison{
+		Checks:   make(map[string]CheckChange),
+		Removed:  []parser.Check{},
+		Baseline: baseline.Totals,
+		Delta: parser.Totals{
+			Pass: current.Totals.Pass - baseline.Totals.Pass,
+			Fail: current.Totals.Fail - baseline.Totals.Fail,
+			Warn: current.Totals.Warn - baseline.Totals.Warn,
+			Info: current.Totals.Info - baseline.Totals.Info,
+		},
+		Counts: make(map[Change]int),
+	}
+
+	baselineChecks := checksByID(baseline)
+	currentChecks := checksByID(current)
+
+	for _, check := range allChecks(current) {
+		change := compareCheck(check, baselineChecks)
+		comparison.Checks[check.ID] = change
+		comparison.Counts[change.Change]++
+	}
+
+	for _, check := range allChecks(baseline) {
+		if _, exists := currentChecks[check.ID]; !exists {
+			comparison.Removed = append(comparison.Removed, check)
+		}
+	}
+
+	return comparison
+}
+
+// compareCheck compares a single check against the baseline checks
+func compareCheck(check parser.Check, baselineChecks map[string]parser.Check) CheckChange {
+	baselineCheck, exists := baselineChecks[check.ID]
+	if !exists {
+		return CheckChange{Change: Added}
+	}
+
+	change := CheckChange{BaselineState: baselineCheck.State}
+	switch {
+	case check.State == baselineCheck.State:
+		change.Change = Unchanged
+	case check.State == "fail":
+		change.Change = NewlyFailing
+	case check.State == "pass":
+		change.Change = NewlyPassing
+	default:
+		change.Change = Changed
+	}
+
+	return change
+}
+
+// allChecks returns all checks of the benchmark data in report order
+func allChecks(data *parser.BenchmarkData) []parser.Check {
+	checks := []parser.Check{}
+	for _, control := range data.Controls {
+		for _, group := range control.Groups {
+			checks = append(checks, group.Checks...)
+		}
+	}
+	return checks
+}
+
+// checksByID returns the checks of the benchmark data keyed by ID
+func checksByID(data *parser.BenchmarkData) map[string]parser.Check {
+	checks := make(map[string]parser.Check)
+	for _, check := range allChecks(data) {
+		checks[check.ID] = check
+	}
+	return checks
+}
+
+// Count returns the number of checks with the given change
+func (c *Comparison) Count(change Change) int {
+	return c.Counts[change]
+}
+
+// Get returns the change of a check, nil if the comparison or the check doesn't exist
+func (c *Comparison) Get(checkID string) *CheckChange {
+	if c == nil {
+		return nil
+	}
+	change, exists := c.Checks[checkID]
+	if !exists {
+		return nil
+	}
+	return &change
+}
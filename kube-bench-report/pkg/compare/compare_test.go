@@ -0,0 +1,101 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+func newBenchmarkData(checks ...parser.Check) *parser.BenchmarkData {
+	data := &parser.BenchmarkData{
+		Controls: []parser.Control{
+			{
+				ID:   "1",
+				Text: "Control Plane Security Configuration",
+				Groups: []parser.Group{
+					{ID: "1.1", Text: "Control Plane Node Configuration Files", Checks: checks},
+				},
+			},
+		},
+	}
+
+	for _, check := range checks {
+		switch check.State {
+		case "pass":
+			data.Totals.Pass++
+		case "fail":
+			data.Totals.Fail++
+		case "warn":
+			data.Totals.Warn++
+		case "info":
+			data.Totals.Info++
+		}
+	}
+
+	return data
+}
+
+func TestCompare(t *testing.T) {
+	baseline := newBenchmarkData(
+		parser.Check{ID: "1.1.1", State: "fail"},
+		parser.Check{ID: "1.1.2", State: "pass"},
+		parser.Check{ID: "1.1.3", State: "fail"},
+		parser.Check{ID: "1.1.4", State: "warn"},
+		parser.Check{ID: "1.1.5", State: "pass", Text: "Removed check"},
+	)
+	current := newBenchmarkData(
+		parser.Check{ID: "1.1.1", State: "pass"},
+		parser.Check{ID: "1.1.2", State: "fail"},
+		parser.Check{ID: "1.1.3", State: "warn"},
+		parser.Check{ID: "1.1.4", State: "warn"},
+		parser.Check{ID: "1.1.6", State: "info"},
+	)
+
+	comparison := Compare(current, baseline)
+
+	expected := map[string]Change{
+		"1.1.1": NewlyPassing,
+		"1.1.2": NewlyFailing,
+		"1.1.3": Changed,
+		"1.1.4": Unchanged,
+		"1.1.6": Added,
+	}
+	for id, change := range expected {
+		got := comparison.Get(id)
+		if got == nil {
+			t.Fatalf("Expected change for check %s", id)
+		}
+		if got.Change != change {
+			t.Errorf("Expected check %s to be %s, got %s", id, change, got.Change)
+		}
+	}
+
+	if got := comparison.Get("1.1.3").Label(); got != "Changed from fail" {
+		t.Errorf("Expected label 'Changed from fail', got %q", got)
+	}
+
+	if len(comparison.Removed) != 1 || comparison.Removed[0].ID != "1.1.5" {
+		t.Errorf("Expected check 1.1.5 to be removed, got %v", comparison.Removed)
+	}
+
+	expectedDelta := parser.Totals{Pass: -1, Fail: -1, Warn: 1, Info: 1}
+	if comparison.Delta != expectedDelta {
+		t.Errorf("Expected delta %+v, got %+v", expectedDelta, comparison.Delta)
+	}
+
+	if comparison.Count(Unchanged) != 1 || comparison.Count(NewlyFailing) != 1 {
+		t.Errorf("Unexpected counts: %v", comparison.Counts)
+	}
+}
+
+func TestComparisonGetNil(t *testing.T) {
+	var comparison *Comparison
+	if comparison.Get("1.1.1") != nil {
+		t.Error("Expected nil change for nil comparison")
+	}
+
+	comparison = Compare(newBenchmarkData(), newBenchmarkData())
+	if comparison.Get("1.1.1") != nil {
+		t.Error("Expected nil change for unknown check")
+	}
+}
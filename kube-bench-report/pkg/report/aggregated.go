@@ -0,0 +1,202 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/aggregate"
+)
+
+// GenerateAggregatedHTML generates an HTML report with a node × check matrix from the aggregated results of multiple nodes
+func GenerateAggregatedHTML(report *aggregate.Report) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("aggregated report is nil")
+	}
+
+	tmpl, err := template.New("aggregated-report").Funcs(templateFuncs()).Parse(aggregatedHTMLTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML template: %v", err)
+	}
+
+	templateData := struct {
+		Report    *aggregate.Report
+		Timestamp string
+	}{
+		Report:    report,
+		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
+		return "", fmt.Errorf("failed to execute template: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+// aggregatedHTMLTemplate is the HTML template for the multi-node report
+const aggregatedHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Kube-Bench Security Report</title>
+    <style>
+` + reportStyles + `
+        .node-totals, .matrix {
+            width: 100%;
+            border-collapse: collapse;
+            margin: 15px 0;
+            background-color: white;
+        }
+
+        .node-totals th, .node-totals td, .matrix th, .matrix td {
+            border: 1px solid var(--border-color);
+            padding: 6px 10px;
+            text-align: center;
+        }
+
+        .matrix td.check-cell {
+            text-align: left;
+        }
+
+        .matrix td.state {
+            color: white;
+            font-weight: bold;
+            text-transform: uppercase;
+            font-size: 0.8em;
+        }
+
+        .matrix td.state.none {
+            color: #999;
+            background-color: white;
+        }
+
+        .matrix .failing-nodes {
+            color: var(--fail-color);
+            font-weight: bold;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <header>
+            <h1>Kubernetes Security Benchmark Report</h1>
+            <p>Based on CIS Kubernetes Benchmark, aggregated from {{ len .Report.Nodes }} nodes</p>
+        </header>
+
+        <div class="summary">
+            <div class="summary-card pass">
+                <h2>{{ .Report.Totals.Pass }}</h2>
+                <p>PASSED</p>
+            </div>
+            <div class="summary-card fail">
+                <h2>{{ .Report.Totals.Fail }}</h2>
+                <p>FAILED</p>
+            </div>
+            <div class="summary-card warn">
+                <h2>{{ .Report.Totals.Warn }}</h2>
+                <p>SKIPPED</p>
+            </div>
+            <div class="summary-card info">
+                <h2>{{ .Report.Totals.Info }}</h2>
+                <p>INFO</p>
+            </div>
+        </div>
+
+        <div class="comparison">
+            <h3>Per-Node Totals</h3>
+            <table class="node-totals">
+                <tr>
+                    <th>Node</th>
+                    <th>Pass</th>
+                    <th>Fail</th>
+                    <th>Warn</th>
+                    <th>Info</th>
+                </tr>
+                {{ range .Report.NodeTotals }}
+                <tr>
+                    <td>{{ .Node }}</td>
+                    <td>{{ .Totals.Pass }}</td>
+                    <td>{{ .Totals.Fail }}</td>
+                    <td>{{ .Totals.Warn }}</td>
+                    <td>{{ .Totals.Info }}</td>
+                </tr>
+                {{ end }}
+            </table>
+            <p>Checks failing on at least one node: {{ len .Report.FailingChecks }}</p>
+        </div>
+
+        <div class="controls">
+            {{ range .Report.Controls }}
+            <div class="control">
+                <div class="control-header" onclick="toggleSection(this.parentElement)">
+                    <h2>{{ .ID }}. {{ .Text }}</h2>
+                    <span class="toggle-icon">▼</span>
+                </div>
+                <div class="control-content">
+                    {{ range .Groups }}
+                    <div class="group">
+                        <div class="group-header" onclick="toggleSection(this.parentElement)">
+                            <h3>{{ .ID }} {{ .Text }}</h3>
+                            <span class="toggle-icon">▼</span>
+                        </div>
+                        <div class="group-content">
+                            <table class="matrix">
+                                <tr>
+                                    <th>Check</th>
+                                    {{ range $.Report.Nodes }}<th>{{ . }}</th>{{ end }}
+                                    <th>Failing Nodes</th>
+                                </tr>
+                                {{ range .Checks }}
+                                {{ $check := . }}
+                                <tr>
+                                    <td class="check-cell"><span class="check-id">{{ .ID }}</span> {{ .Text }}</td>
+                                    {{ range $.Report.Nodes }}
+                                    {{ with $check.State . }}<td class="state {{ . }}">{{ . }}</td>{{ else }}<td class="state none">-</td>{{ end }}
+                                    {{ end }}
+                                    <td class="failing-nodes">{{ len .FailingNodes }}</td>
+                                </tr>
+                                {{ end }}
+                            </table>
+                            {{ range .Checks }}
+                            {{ if .Remediations }}
+                            <div class="check {{ if .FailingNodes }}fail{{ else }}warn{{ end }}">
+                                <div class="check-header">
+                                    <div>
+                                        <span class="check-id">{{ .ID }}</span>
+                                        {{ if .FailingNodes }}failing on: {{ range $i, $node := .FailingNodes }}{{ if $i }}, {{ end }}{{ $node }}{{ end }}{{ end }}
+                                    </div>
+                                </div>
+                                {{ range .Remediations }}
+                                <div class="remediation">
+                                    <strong>Remediation:</strong>
+                                    {{ . }}
+                                </div>
+                                {{ end }}
+                            </div>
+                            {{ end }}
+                            {{ end }}
+                        </div>
+                    </div>
+                    {{ end }}
+                </div>
+            </div>
+            {{ end }}
+        </div>
+
+        <div class="timestamp">
+            Report generated: {{ .Timestamp }}
+        </div>
+
+        <footer>
+            <p>Generated by kube-bench-report</p>
+        </footer>
+    </div>
+
+    <script>
+` + reportScript + `    </script>
+</body>
+</html>`
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// csvHeader is the header row of the CSV report
+var csvHeader = []string{"Control ID", "Control", "Group ID", "Group", "Check ID", "Check", "State", "Audit", "Remediation"}
+
+// GenerateCSV generates a CSV report with one row per check
+func GenerateCSV(data *parser.BenchmarkData) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for _, control := range data.Controls {
+		for _, group := range control.Groups {
+			for _, check := range group.Checks {
+				record := []string{
+					control.ID, control.Text,
+					group.ID, group.Text,
+					check.ID, check.Text, check.State,
+					check.Audit, check.Remediation,
+				}
+				if err := writer.Write(record); err != nil {
+					return "", fmt.Errorf("failed to write CSV record: %v", err)
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV report: %v", err)
+	}
+
+	return buf.String(), nil
+}
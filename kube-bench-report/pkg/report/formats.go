@@ -0,0 +1,50 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// Output formats supported by Generate
+const (
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatSARIF    = "sarif"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatHTML, FormatJSON, FormatMarkdown, FormatCSV, FormatSARIF}
+
+// Generate generates a report in the given output format from the benchmark data
+func Generate(format string, data *parser.BenchmarkData, opts ...Option) (string, error) {
+	switch strings.ToLower(format) {
+	case FormatHTML:
+		return GenerateHTML(data, opts...)
+	case FormatJSON:
+		return GenerateJSON(data)
+	case FormatMarkdown, "md":
+		return GenerateMarkdown(data, opts...)
+	case FormatCSV:
+		return GenerateCSV(data)
+	case FormatSARIF:
+		return GenerateSARIF(data)
+	default:
+		return "", fmt.Errorf("unsupported output format: %s (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Extension returns the file extension of the given output format
+func Extension(format string) string {
+	switch strings.ToLower(format) {
+	case FormatMarkdown, "md":
+		return "md"
+	case FormatSARIF:
+		return "sarif"
+	default:
+		return strings.ToLower(format)
+	}
+}
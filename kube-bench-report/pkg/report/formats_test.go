@@ -0,0 +1,125 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/compare"
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+func newFormatTestData() *parser.BenchmarkData {
+	return &parser.BenchmarkData{
+		Controls: []parser.Control{
+			{
+				ID:   "1",
+				Text: "Control Plane Security Configuration",
+				Groups: []parser.Group{
+					{
+						ID:   "1.1",
+						Text: "Control Plane Node Configuration Files",
+						Checks: []parser.Check{
+							{ID: "1.1.1", Text: "Ensure permissions | 600", State: "fail", Remediation: "chmod 600 /etc/kubernetes/manifests/kube-apiserver.yaml"},
+							{ID: "1.1.2", Text: "Ensure ownership", State: "pass"},
+							{ID: "1.1.3", Text: "Ensure audit, logging", State: "warn", Remediation: "Enable audit logging"},
+						},
+					},
+				},
+			},
+		},
+		Totals: parser.Totals{Pass: 1, Fail: 1, Warn: 1},
+	}
+}
+
+func TestGenerateJSON(t *testing.T) {
+	content, err := Generate(FormatJSON, newFormatTestData())
+	if err != nil {
+		t.Fatalf("Expected successful generation, got error: %v", err)
+	}
+
+	var data parser.BenchmarkData
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if data.Totals.Fail != 1 || data.Controls[0].Groups[0].Checks[2].ID != "1.1.3" {
+		t.Errorf("Unexpected JSON data: %+v", data)
+	}
+}
+
+func TestGenerateCSV(t *testing.T) {
+	content, err := Generate(FormatCSV, newFormatTestData())
+	if err != nil {
+		t.Fatalf("Expected successful generation, got error: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got error: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected header and 3 records, got %d", len(records))
+	}
+	if records[3][4] != "1.1.3" || records[3][5] != "Ensure audit, logging" || records[3][6] != "warn" {
+		t.Errorf("Unexpected record: %v", records[3])
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	data := newFormatTestData()
+	baseline := newFormatTestData()
+	baseline.Controls[0].Groups[0].Checks[0].State = "pass"
+	baseline.Totals = parser.Totals{Pass: 2, Warn: 1}
+
+	content, err := Generate(FormatMarkdown, data, WithComparison(compare.Compare(data, baseline)))
+	if err != nil {
+		t.Fatalf("Expected successful generation, got error: %v", err)
+	}
+
+	expected := []string{
+		"| 1 (-1) | 1 (+1) | 1 (0) | 0 (0) |",
+		"- Newly failing: 1",
+		"### Failed Checks (1)",
+		"| 1.1.1 | Ensure permissions \\| 600 | Newly failing |",
+		"### Warnings (1)",
+		"chmod 600 /etc/kubernetes/manifests/kube-apiserver.yaml",
+	}
+	for _, element := range expected {
+		if !strings.Contains(content, element) {
+			t.Errorf("Expected Markdown to contain '%s'", element)
+		}
+	}
+	if strings.Contains(content, "1.1.2") {
+		t.Error("Expected passed checks not to be listed")
+	}
+}
+
+func TestGenerateSARIF(t *testing.T) {
+	content, err := Generate(FormatSARIF, newFormatTestData())
+	if err != nil {
+		t.Fatalf("Expected successful generation, got error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(content), &log); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 3 {
+		t.Errorf("Expected a rule per check, got %d", len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 2 || run.Results[0].Level != "error" || run.Results[1].Level != "warning" {
+		t.Errorf("Unexpected results: %+v", run.Results)
+	}
+}
+
+func TestGenerateUnsupportedFormat(t *testing.T) {
+	if _, err := Generate("pdf", newFormatTestData()); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
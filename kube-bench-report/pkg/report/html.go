@@ -6,25 +6,43 @@ import (
 	"html/template"
 	"time"
 
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/compare"
 	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
 )
 
+// reportData is the data passed to the HTML template
+type reportData struct {
+	Data       *parser.BenchmarkData
+	Comparison *compare.Comparison
+	Timestamp  string
+}
+
+// Option customizes the generated report
+type Option func(*reportData)
+
+// WithComparison marks each check with its change against a baseline run
+func WithComparison(comparison *compare.Comparison) Option {
+	return func(data *reportData) {
+		data.Comparison = comparison
+	}
+}
+
 // GenerateHTML generates an HTML report from the benchmark data
-func GenerateHTML(data *parser.BenchmarkData) (string, error) {
+func GenerateHTML(data *parser.BenchmarkData, opts ...Option) (string, error) {
 	// Create a template
-	tmpl, err := template.New("report").Parse(htmlTemplate)
+	tmpl, err := template.New("report").Funcs(templateFuncs()).Parse(htmlTemplate)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse HTML template: %v", err)
 	}
 
 	// Prepare template data
-	templateData := struct {
-		Data      *parser.BenchmarkData
-		Timestamp string
-	}{
+	templateData := &reportData{
 		Data:      data,
 		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
 	}
+	for _, opt := range opts {
+		opt(templateData)
+	}
 
 	// Execute the template
 	var buf bytes.Buffer
@@ -35,6 +53,14 @@ func GenerateHTML(data *parser.BenchmarkData) (string, error) {
 	return buf.String(), nil
 }
 
+// templateFuncs returns the functions available in the HTML template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// delta formats a difference with an explicit sign
+		"delta": formatDelta,
+	}
+}
+
 // htmlTemplate is the HTML template for the report
 const htmlTemplate = `<!DOCTYPE html>
 <html lang="en">
@@ -43,7 +69,117 @@ const htmlTemplate = `<!DOCTYPE html>
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>Kube-Bench Security Report</title>
     <style>
-        :root {
+` + reportStyles + `    </style>
+</head>
+<body>
+    <div class="container">
+        <header>
+            <h1>Kubernetes Security Benchmark Report</h1>
+            <p>Based on CIS Kubernetes Benchmark</p>
+        </header>
+
+        <div class="summary">
+            <div class="summary-card pass">
+                <h2>{{ .Data.Totals.Pass }}</h2>
+                <p>PASSED</p>
+                {{ if .Comparison }}<p class="delta">{{ delta .Comparison.Delta.Pass }} vs baseline ({{ .Comparison.Baseline.Pass }})</p>{{ end }}
+            </div>
+            <div class="summary-card fail">
+                <h2>{{ .Data.Totals.Fail }}</h2>
+                <p>FAILED</p>
+                {{ if .Comparison }}<p class="delta">{{ delta .Comparison.Delta.Fail }} vs baseline ({{ .Comparison.Baseline.Fail }})</p>{{ end }}
+            </div>
+            <div class="summary-card warn">
+                <h2>{{ .Data.Totals.Warn }}</h2>
+                <p>SKIPPED</p>
+                {{ if .Comparison }}<p class="delta">{{ delta .Comparison.Delta.Warn }} vs baseline ({{ .Comparison.Baseline.Warn }})</p>{{ end }}
+            </div>
+            <div class="summary-card info">
+                <h2>{{ .Data.Totals.Info }}</h2>
+                <p>INFO</p>
+                {{ if .Comparison }}<p class="delta">{{ delta .Comparison.Delta.Info }} vs baseline ({{ .Comparison.Baseline.Info }})</p>{{ end }}
+            </div>
+        </div>
+
+        {{ if .Comparison }}
+        <div class="comparison">
+            <h3>Baseline Comparison</h3>
+            <ul>
+                <li>Newly failing: {{ .Comparison.Count "newly-failing" }}</li>
+                <li>Newly passing: {{ .Comparison.Count "newly-passing" }}</li>
+                <li>Changed: {{ .Comparison.Count "changed" }}</li>
+                <li>Unchanged: {{ .Comparison.Count "unchanged" }}</li>
+                <li>New checks: {{ .Comparison.Count "added" }}</li>
+                <li>Removed checks: {{ len .Comparison.Removed }}</li>
+            </ul>
+            {{ if .Comparison.Removed }}
+            <h4>Checks only in baseline</h4>
+            <ul>
+                {{ range .Comparison.Removed }}
+                <li>{{ .ID }} {{ .Text }} ({{ .State }})</li>
+                {{ end }}
+            </ul>
+            {{ end }}
+        </div>
+        {{ end }}
+
+        <div class="controls">
+            {{ range .Data.Controls }}
+            <div class="control">
+                <div class="control-header" onclick="toggleSection(this.parentElement)">
+                    <h2>{{ .ID }}. {{ .Text }}</h2>
+                    <span class="toggle-icon">▼</span>
+                </div>
+                <div class="control-content">
+                    {{ range .Groups }}
+                    <div class="group">
+                        <div class="group-header" onclick="toggleSection(this.parentElement)">
+                            <h3>{{ .ID }} {{ .Text }}</h3>
+                            <span class="toggle-icon">▼</span>
+                        </div>
+                        <div class="group-content">
+                            {{ range .Checks }}
+                            <div class="check {{ .State }}">
+                                <div class="check-header">
+                                    <div>
+                                        <span class="check-id">{{ .ID }}</span>
+                                        {{ if $.Comparison }}{{ with $.Comparison.Get .ID }}<span class="check-change {{ .Change }}">{{ .Label }}</span>{{ end }}{{ end }}
+                                    </div>
+                                    <span class="check-status {{ .State }}">{{ .State }}</span>
+                                </div>
+                                <div class="check-text">{{ .Text }}</div>
+                                {{ if .Remediation }}
+                                <div class="remediation">
+                                    <strong>Remediation:</strong>
+                                    {{ .Remediation }}
+                                </div>
+                                {{ end }}
+                            </div>
+                            {{ end }}
+                        </div>
+                    </div>
+                    {{ end }}
+                </div>
+            </div>
+            {{ end }}
+        </div>
+
+        <div class="timestamp">
+            Report generated: {{ .Timestamp }}
+        </div>
+
+        <footer>
+            <p>Generated by kube-bench-report</p>
+        </footer>
+    </div>
+
+    <script>
+` + reportScript + `    </script>
+</body>
+</html>`
+
+// reportStyles is the CSS shared by all HTML reports
+const reportStyles = `        :root {
             --pass-color: #4caf50;
             --fail-color: #f44336;
             --warn-color: #ff9800;
@@ -222,6 +358,51 @@ const htmlTemplate = `<!DOCTYPE html>
             font-family: monospace;
         }
 
+        .delta {
+            margin: 0;
+            font-size: 0.9em;
+            opacity: 0.9;
+        }
+
+        .comparison {
+            margin: 20px 10px;
+            padding: 15px 20px;
+            border: 1px solid var(--border-color);
+            border-radius: 5px;
+            background-color: white;
+        }
+
+        .comparison ul {
+            margin: 0;
+            padding-left: 20px;
+        }
+
+        .check-change {
+            padding: 3px 8px;
+            margin-right: 10px;
+            border-radius: 3px;
+            font-size: 0.8em;
+            border: 1px solid var(--border-color);
+            background-color: white;
+        }
+
+        .check-change.newly-failing {
+            color: white;
+            border-color: var(--fail-color);
+            background-color: var(--fail-color);
+        }
+
+        .check-change.newly-passing {
+            color: white;
+            border-color: var(--pass-color);
+            background-color: var(--pass-color);
+        }
+
+        .check-change.changed,
+        .check-change.added {
+            border-color: var(--warn-color);
+        }
+
         .timestamp {
             text-align: right;
             margin-top: 20px;
@@ -251,85 +432,10 @@ const htmlTemplate = `<!DOCTYPE html>
                 min-width: 100%;
             }
         }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <header>
-            <h1>Kubernetes Security Benchmark Report</h1>
-            <p>Based on CIS Kubernetes Benchmark</p>
-        </header>
+`
 
-        <div class="summary">
-            <div class="summary-card pass">
-                <h2>{{ .Data.Totals.Pass }}</h2>
-                <p>PASSED</p>
-            </div>
-            <div class="summary-card fail">
-                <h2>{{ .Data.Totals.Fail }}</h2>
-                <p>FAILED</p>
-            </div>
-            <div class="summary-card warn">
-                <h2>{{ .Data.Totals.Warn }}</h2>
-                <p>SKIPPED</p>
-            </div>
-            <div class="summary-card info">
-                <h2>{{ .Data.Totals.Info }}</h2>
-                <p>INFO</p>
-            </div>
-        </div>
-
-        <div class="controls">
-            {{ range .Data.Controls }}
-            <div class="control">
-                <div class="control-header" onclick="toggleSection(this.parentElement)">
-                    <h2>{{ .ID }}. {{ .Text }}</h2>
-                    <span class="toggle-icon">▼</span>
-                </div>
-                <div class="control-content">
-                    {{ range .Groups }}
-                    <div class="group">
-                        <div class="group-header" onclick="toggleSection(this.parentElement)">
-                            <h3>{{ .ID }} {{ .Text }}</h3>
-                            <span class="toggle-icon">▼</span>
-                        </div>
-                        <div class="group-content">
-                            {{ range .Checks }}
-                            <div class="check {{ .State }}">
-                                <div class="check-header">
-                                    <div>
-                                        <span class="check-id">{{ .ID }}</span>
-                                    </div>
-                                    <span class="check-status {{ .State }}">{{ .State }}</span>
-                                </div>
-                                <div class="check-text">{{ .Text }}</div>
-                                {{ if .Remediation }}
-                                <div class="remediation">
-                                    <strong>Remediation:</strong>
-                                    {{ .Remediation }}
-                                </div>
-                                {{ end }}
-                            </div>
-                            {{ end }}
-                        </div>
-                    </div>
-                    {{ end }}
-                </div>
-            </div>
-            {{ end }}
-        </div>
-
-        <div class="timestamp">
-            Report generated: {{ .Timestamp }}
-        </div>
-
-        <footer>
-            <p>Generated by kube-bench-report</p>
-        </footer>
-    </div>
-
-    <script>
-        function toggleSection(element) {
+// reportScript is the JavaScript shared by all HTML reports
+const reportScript = `        function toggleSection(element) {
             const content = element.querySelector('.control-content, .group-content');
             const icon = element.querySelector('.toggle-icon');
 
@@ -350,6 +456,4 @@ const htmlTemplate = `<!DOCTYPE html>
                 content.style.display = 'block';
             });
         });
-    </script>
-</body>
-</html>`
+`
@@ -1,10 +1,13 @@
 package report
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	htmltemplate "html/template"
 
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/aggregate"
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/compare"
 	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
 )
 
@@ -370,10 +373,125 @@ func TestGenerateHTML(t *testing.T) {
 	})
 }
 
+func TestGenerateHTMLWithComparison(t *testing.T) {
+	newData := func(states ...string) *parser.BenchmarkData {
+		data := &parser.BenchmarkData{
+			Controls: []parser.Control{
+				{
+					ID:   "1",
+					Text: "Control Plane Security Configuration",
+					Groups: []parser.Group{
+						{ID: "1.1", Text: "Control Plane Node Configuration Files"},
+					},
+				},
+			},
+		}
+		for i, state := range states {
+			check := parser.Check{ID: fmt.Sprintf("1.1.%d", i+1), Text: "Check", State: state}
+			data.Controls[0].Groups[0].Checks = append(data.Controls[0].Groups[0].Checks, check)
+			switch state {
+			case "pass":
+				data.Totals.Pass++
+			case "fail":
+				data.Totals.Fail++
+			}
+		}
+		return data
+	}
+
+	baseline := newData("fail", "pass", "pass")
+	current := newData("pass", "fail")
+
+	html, err := GenerateHTML(current, WithComparison(compare.Compare(current, baseline)))
+	if err != nil {
+		t.Fatalf("Expected successful HTML generation, got error: %v", err)
+	}
+
+	expected := []string{
+		"Baseline Comparison",
+		`<span class="check-change newly-passing">Newly passing</span>`,
+		`<span class="check-change newly-failing">Newly failing</span>`,
+		"Newly failing: 1",
+		"Removed checks: 1",
+		"-1 vs baseline (2)",
+		"0 vs baseline (1)",
+	}
+	for _, element := range expected {
+		if !strings.Contains(html, element) {
+			t.Errorf("Expected HTML to contain '%s'", element)
+		}
+	}
+
+	html, err = GenerateHTML(current)
+	if err != nil {
+		t.Fatalf("Expected successful HTML generation, got error: %v", err)
+	}
+	if strings.Contains(html, "Baseline Comparison") || strings.Contains(html, "vs baseline") {
+		t.Error("Expected HTML without baseline to omit the comparison")
+	}
+}
+
+func TestGenerateAggregatedHTML(t *testing.T) {
+	node := func(state string) *parser.BenchmarkData {
+		return &parser.BenchmarkData{
+			Controls: []parser.Control{
+				{
+					ID:   "4",
+					Text: "Worker Node Security Configuration",
+					Groups: []parser.Group{
+						{
+							ID:   "4.1",
+							Text: "Worker Node Configuration Files",
+							Checks: []parser.Check{
+								{ID: "4.1.1", Text: "Worker check", State: state, Remediation: "chmod 600 kubelet.service"},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	aggregated, err := aggregate.Aggregate([]aggregate.NodeResult{
+		{Name: "worker-1", Data: node("fail")},
+		{Name: "worker-2", Data: node("pass")},
+	})
+	if err != nil {
+		t.Fatalf("Expected successful aggregation, got error: %v", err)
+	}
+
+	html, err := GenerateAggregatedHTML(aggregated)
+	if err != nil {
+		t.Fatalf("Expected successful HTML generation, got error: %v", err)
+	}
+
+	expected := []string{
+		"<th>worker-1</th>",
+		"<th>worker-2</th>",
+		`<td class="state fail">fail</td>`,
+		`<td class="state pass">pass</td>`,
+		"failing on: worker-1",
+		"Per-Node Totals",
+	}
+	for _, element := range expected {
+		if !strings.Contains(html, element) {
+			t.Errorf("Expected HTML to contain '%s'", element)
+		}
+	}
+
+	if strings.Count(html, "chmod 600 kubelet.service") != 1 {
+		t.Error("Expected remediation to be rendered once")
+	}
+
+	if _, err := GenerateAggregatedHTML(nil); err == nil {
+		t.Error("Expected error for nil report")
+	}
+}
+
 func TestHTMLTemplate(t *testing.T) {
 	t.Run("TemplateCompilation", func(t *testing.T) {
 		// Test that the template can be parsed without data
-		_, err := htmltemplate.New("test").Parse(htmlTemplate)
+		_, err := htmltemplate.New("test").Funcs(templateFuncs()).Parse(htmlTemplate)
 		if err != nil {
 			t.Fatalf("HTML template should be valid, got parse error: %v", err)
 		}
@@ -0,0 +1,19 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// GenerateJSON generates the normalized benchmark data as JSON
+// the output is the same regardless of the input format, so it can be consumed by other tools
+func GenerateJSON(data *parser.BenchmarkData) (string, error) {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON report: %v", err)
+	}
+
+	return string(content) + "\n", nil
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/compare"
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// GenerateMarkdown generates a Markdown report, suitable for pull request comments
+// only failed and warned checks are listed to keep the comment short
+func GenerateMarkdown(data *parser.BenchmarkData, opts ...Option) (string, error) {
+	reportData := &reportData{Data: data}
+	for _, opt := range opts {
+		opt(reportData)
+	}
+	comparison := reportData.Comparison
+
+	var sb strings.Builder
+	sb.WriteString("## Kubernetes Security Benchmark Report\n\n")
+
+	sb.WriteString("| Pass | Fail | Warn | Info |\n")
+	sb.WriteString("|------|------|------|------|\n")
+	if comparison != nil {
+		fmt.Fprintf(&sb, "| %d (%s) | %d (%s) | %d (%s) | %d (%s) |\n",
+			data.Totals.Pass, formatDelta(comparison.Delta.Pass),
+			data.Totals.Fail, formatDelta(comparison.Delta.Fail),
+			data.Totals.Warn, formatDelta(comparison.Delta.Warn),
+			data.Totals.Info, formatDelta(comparison.Delta.Info))
+	} else {
+		fmt.Fprintf(&sb, "| %d | %d | %d | %d |\n", data.Totals.Pass, data.Totals.Fail, data.Totals.Warn, data.Totals.Info)
+	}
+
+	if comparison != nil {
+		sb.WriteString("\n### Baseline Comparison\n\n")
+		fmt.Fprintf(&sb, "- Newly failing: %d\n", comparison.Count(compare.NewlyFailing))
+		fmt.Fprintf(&sb, "- Newly passing: %d\n", comparison.Count(compare.NewlyPassing))
+		fmt.Fprintf(&sb, "- Changed: %d\n", comparison.Count(compare.Changed))
+		fmt.Fprintf(&sb, "- Unchanged: %d\n", comparison.Count(compare.Unchanged))
+		fmt.Fprintf(&sb, "- New checks: %d\n", comparison.Count(compare.Added))
+		fmt.Fprintf(&sb, "- Removed checks: %d\n", len(comparison.Removed))
+	}
+
+	failed := checksWithState(data, "fail")
+	warned := checksWithState(data, "warn")
+
+	writeMarkdownChecks(&sb, "Failed Checks", failed, reportData)
+	writeMarkdownChecks(&sb, "Warnings", warned, reportData)
+
+	remediations := []parser.Check{}
+	for _, check := range append(failed, warned...) {
+		if strings.TrimSpace(check.Remediation) != "" {
+			remediations = append(remediations, check)
+		}
+	}
+	if len(remediations) > 0 {
+		sb.WriteString("\n<details>\n<summary>Remediation</summary>\n\n")
+		for _, check := range remediations {
+			fmt.Fprintf(&sb, "**%s** %s\n\n```\n%s\n```\n\n", check.ID, escapeMarkdownCell(check.Text), strings.TrimSpace(check.Remediation))
+		}
+		sb.WriteString("</details>\n")
+	}
+
+	return sb.String(), nil
+}
+
+// writeMarkdownChecks writes a table of checks under the given title
+func writeMarkdownChecks(sb *strings.Builder, title string, checks []parser.Check, data *reportData) {
+	fmt.Fprintf(sb, "\n### %s (%d)\n\n", title, len(checks))
+	if len(checks) == 0 {
+		sb.WriteString("None\n")
+		return
+	}
+
+	if data.Comparison != nil {
+		sb.WriteString("| ID | Check | Change |\n")
+		sb.WriteString("|----|-------|--------|\n")
+	} else {
+		sb.WriteString("| ID | Check |\n")
+		sb.WriteString("|----|-------|\n")
+	}
+
+	for _, check := range checks {
+		if data.Comparison != nil {
+			label := ""
+			if change := data.Comparison.Get(check.ID); change != nil {
+				label = change.Label()
+			}
+			fmt.Fprintf(sb, "| %s | %s | %s |\n", check.ID, escapeMarkdownCell(check.Text), label)
+		} else {
+			fmt.Fprintf(sb, "| %s | %s |\n", check.ID, escapeMarkdownCell(check.Text))
+		}
+	}
+}
+
+// checksWithState returns the checks with the given state in report order
+func checksWithState(data *parser.BenchmarkData, state string) []parser.Check {
+	checks := []parser.Check{}
+	for _, control := range data.Controls {
+		for _, group := range control.Groups {
+			for _, check := range group.Checks {
+				if check.State == state {
+					checks = append(checks, check)
+				}
+			}
+		}
+	}
+	return checks
+}
+
+// escapeMarkdownCell makes a text safe to use in a single Markdown table cell
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// formatDelta formats a difference with an explicit sign
+func formatDelta(value int) string {
+	if value > 0 {
+		return fmt.Sprintf("+%d", value)
+	}
+	return fmt.Sprintf("%d", value)
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// sarifLog is the root object of a SARIF 2.1.0 log
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string         `json:"id"`
+	ShortDescription sarifMessage   `json:"shortDescription"`
+	Help             *sarifMessage  `json:"help,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID  string       `json:"ruleId"`
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// GenerateSARIF generates a SARIF 2.1.0 log with a rule per check and a result per failed or warned check
+func GenerateSARIF(data *parser.BenchmarkData) (string, error) {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "kube-bench",
+				InformationURI: "https://github.com/aquasecurity/kube-bench",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	for _, control := range data.Controls {
+		for _, group := range control.Groups {
+			for _, check := range group.Checks {
+				rule := sarifRule{
+					ID:               check.ID,
+					ShortDescription: sarifMessage{Text: check.Text},
+					Properties: map[string]any{
+						"control": control.ID + " " + control.Text,
+						"group":   group.ID + " " + group.Text,
+					},
+				}
+				if remediation := strings.TrimSpace(check.Remediation); remediation != "" {
+					rule.Help = &sarifMessage{Text: remediation}
+				}
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+				level := sarifLevel(check.State)
+				if level == "" {
+					continue
+				}
+				run.Results = append(run.Results, sarifResult{
+					RuleID:  check.ID,
+					Level:   level,
+					Message: sarifMessage{Text: fmt.Sprintf("[%s] %s %s", strings.ToUpper(check.State), check.ID, check.Text)},
+				})
+			}
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	content, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF report: %v", err)
+	}
+
+	return string(content) + "\n", nil
+}
+
+// sarifLevel maps a check state to a SARIF result level, empty if the check should not be reported
+func sarifLevel(state string) string {
+	switch state {
+	case "fail":
+		return "error"
+	case "warn":
+		return "warning"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,135 @@
+package threshold
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+// validStates contains the check states a rule can count
+var validStates = map[string]bool{"pass": true, "fail": true, "warn": true, "info": true}
+
+// Rule is a threshold on the number of checks in a state
+// it is written as <state>[:<check id prefix>][<operator><count>], e.g. "fail", "fail:1", "warn>10" or "fail:4.2>=3"
+// when the operator is omitted the rule is violated by any matching check
+type Rule struct {
+	// State is the check state to count
+	State string
+	// Scope restricts the rule to a control, group or check ID, empty for all checks
+	Scope string
+	// Operator is either ">" or ">="
+	Operator string
+	// Count is the number the matching checks are compared to
+	Count int
+	// raw is the rule as written by the user
+	raw string
+}
+
+// String returns the rule as written by the user
+func (r Rule) String() string {
+	return r.raw
+}
+
+// Parse parses a single rule
+func Parse(rule string) (Rule, error) {
+	raw := strings.TrimSpace(rule)
+	parsed := Rule{Operator: ">", Count: 0, raw: raw}
+
+	selector := raw
+	if index := strings.IndexAny(raw, "><"); index >= 0 {
+		selector = raw[:index]
+		expression := raw[index:]
+
+		switch {
+		case strings.HasPrefix(expression, ">="):
+			parsed.Operator = ">="
+		case strings.HasPrefix(expression, ">"):
+			parsed.Operator = ">"
+		default:
+			return Rule{}, fmt.Errorf("invalid rule %q: only > and >= are supported", raw)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(expression, parsed.Operator)))
+		if err != nil || count < 0 {
+			return Rule{}, fmt.Errorf("invalid rule %q: count must be a non-negative number", raw)
+		}
+		parsed.Count = count
+	}
+
+	state, scope, _ := strings.Cut(selector, ":")
+	parsed.State = strings.ToLower(strings.TrimSpace(state))
+	parsed.Scope = strings.TrimSpace(scope)
+	if !validStates[parsed.State] {
+		return Rule{}, fmt.Errorf("invalid rule %q: unknown state %q", raw, state)
+	}
+
+	return parsed, nil
+}
+
+// ParseAll parses a list of rules, each entry may contain multiple comma separated rules
+func ParseAll(values []string) ([]Rule, error) {
+	rules := []Rule{}
+	for _, value := range values {
+		for _, rule := range strings.Split(value, ",") {
+			if strings.TrimSpace(rule) == "" {
+				continue
+			}
+			parsed, err := Parse(rule)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, parsed)
+		}
+	}
+	return rules, nil
+}
+
+// Matches returns true if the check is counted by the rule
+func (r Rule) Matches(check parser.Check) bool {
+	if check.State != r.State {
+		return false
+	}
+	if r.Scope == "" {
+		return true
+	}
+	return check.ID == r.Scope || strings.HasPrefix(check.ID, r.Scope+".")
+}
+
+// Violation represents a rule exceeded by the benchmark data
+type Violation struct {
+	Rule   Rule
+	Actual int
+}
+
+// String returns a human readable description of the violation
+func (v Violation) String() string {
+	scope := "all checks"
+	if v.Rule.Scope != "" {
+		scope = v.Rule.Scope
+	}
+	return fmt.Sprintf("%s: %d %s checks in %s", v.Rule, v.Actual, v.Rule.State, scope)
+}
+
+// Evaluate returns the violations of the rules by the benchmark data
+func Evaluate(data *parser.BenchmarkData, rules []Rule) []Violation {
+	violations := []Violation{}
+	for _, rule := range rules {
+		count := 0
+		for _, control := range data.Controls {
+			for _, group := range control.Groups {
+				for _, check := range group.Checks {
+					if rule.Matches(check) {
+						count++
+					}
+				}
+			}
+		}
+
+		if (rule.Operator == ">=" && count >= rule.Count) || (rule.Operator == ">" && count > rule.Count) {
+			violations = append(violations, Violation{Rule: rule, Actual: count})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,105 @@
+package threshold
+
+import (
+	"testing"
+
+	"github.com/alaudadevops/toolbox/kube-bench-report/pkg/parser"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule        string
+		expected    Rule
+		expectError bool
+	}{
+		{rule: "fail", expected: Rule{State: "fail", Operator: ">", Count: 0}},
+		{rule: "FAIL:1", expected: Rule{State: "fail", Scope: "1", Operator: ">", Count: 0}},
+		{rule: "warn>10", expected: Rule{State: "warn", Operator: ">", Count: 10}},
+		{rule: "fail:4.2>=3", expected: Rule{State: "fail", Scope: "4.2", Operator: ">=", Count: 3}},
+		{rule: "skipped", expectError: true},
+		{rule: "fail<3", expectError: true},
+		{rule: "fail>many", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			tt.expected.raw = tt.rule
+			if rule != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, rule)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	data := &parser.BenchmarkData{
+		Controls: []parser.Control{
+			{
+				ID: "1",
+				Groups: []parser.Group{
+					{ID: "1.1", Checks: []parser.Check{
+						{ID: "1.1.1", State: "pass"},
+						{ID: "1.1.2", State: "warn"},
+					}},
+				},
+			},
+			{
+				ID: "4",
+				Groups: []parser.Group{
+					{ID: "4.1", Checks: []parser.Check{
+						{ID: "4.1.1", State: "fail"},
+						{ID: "4.1.2", State: "warn"},
+					}},
+				},
+			},
+			{
+				ID: "10",
+				Groups: []parser.Group{
+					{ID: "10.1", Checks: []parser.Check{
+						{ID: "10.1.1", State: "fail"},
+					}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		rules    []string
+		violated []string
+	}{
+		{rules: []string{"fail:1"}, violated: []string{}},
+		{rules: []string{"fail:4"}, violated: []string{"fail:4"}},
+		{rules: []string{"fail>2"}, violated: []string{}},
+		{rules: []string{"fail>=2", "warn>1"}, violated: []string{"fail>=2", "warn>1"}},
+		{rules: []string{"warn:1>0,fail:10.1.1"}, violated: []string{"warn:1>0", "fail:10.1.1"}},
+	}
+
+	for _, tt := range tests {
+		rules, err := ParseAll(tt.rules)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		violations := Evaluate(data, rules)
+		if len(violations) != len(tt.violated) {
+			t.Errorf("Rules %v: expected %d violations, got %v", tt.rules, len(tt.violated), violations)
+			continue
+		}
+		for i, violation := range violations {
+			if violation.Rule.String() != tt.violated[i] {
+				t.Errorf("Rules %v: expected violation of %s, got %s", tt.rules, tt.violated[i], violation.Rule)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jira
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// CheckAction is the outcome of the release check of a plugin
+type CheckAction string
+
+const (
+	// CheckActionCreated means a new release-check issue was created
+	CheckActionCreated CheckAction = "created"
+	// CheckActionSkipped means no issue was needed
+	CheckActionSkipped CheckAction = "skipped"
+	// CheckActionErrored means the check failed
+	CheckActionErrored CheckAction = "errored"
+)
+
+// CheckResult is the result of the release check of a plugin
+type CheckResult struct {
+	Plugin string
+	Action CheckAction
+	// Issue is the key of the created issue or of the issue that caused the skip
+	Issue string
+	// Detail explains the action
+	Detail string
+}
+
+// ShouldCreateIssue decides if a new release-check issue is needed based on the latest existing one
+// latest: the latest release-check issue of the plugin, nil if there is none
+// completed: whether the latest issue is done or cancelled
+// interval: minimum time between two issues, zero to only wait for the latest issue to be completed
+// Returns whether an issue should be created and the reason
+func ShouldCreateIssue(latest *gojira.Issue, completed bool, interval time.Duration, now time.Time) (bool, string) {
+	if latest == nil {
+		return true, "no previous release-check issue"
+	}
+	if !completed {
+		return false, fmt.Sprintf("issue %s is still open", latest.Key)
+	}
+
+	created := time.Time{}
+	if latest.Fields != nil {
+		created = time.Time(latest.Fields.Created)
+	}
+	if next := created.Add(interval); interval > 0 && now.Before(next) {
+		return false, fmt.Sprintf("issue %s was created on %s, next check after %s", latest.Key, created.Format(time.DateOnly), next.Format(time.DateOnly))
+	}
+	return true, fmt.Sprintf("issue %s is completed", latest.Key)
+}
+
+// PrintCheckResults prints the results as a table followed by a summary line
+func PrintCheckResults(out io.Writer, results []CheckResult) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLUGIN\tRESULT\tISSUE\tDETAIL")
+	counts := map[CheckAction]int{}
+	for _, result := range results {
+		issue := result.Issue
+		if issue == "" {
+			issue = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Plugin, result.Action, issue, result.Detail)
+		counts[result.Action]++
+	}
+	w.Flush()
+	fmt.Fprintf(out, "%d created, %d skipped, %d errored\n", counts[CheckActionCreated], counts[CheckActionSkipped], counts[CheckActionErrored])
+}
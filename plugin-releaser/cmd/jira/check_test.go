@@ -0,0 +1,223 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jira_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gojira "github.com/andygrunwald/go-jira"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/cmd/jira"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/types"
+)
+
+var _ = Describe("ShouldCreateIssue", func() {
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	issueCreatedAt := func(created time.Time) *gojira.Issue {
+		return &gojira.Issue{Key: "DEVOPS-1", Fields: &gojira.IssueFields{Created: gojira.Time(created)}}
+	}
+
+	It("should create an issue when there is no previous issue", func() {
+		create, _ := jira.ShouldCreateIssue(nil, false, 0, now)
+		Expect(create).To(BeTrue())
+	})
+
+	It("should skip when the latest issue is still open", func() {
+		create, reason := jira.ShouldCreateIssue(issueCreatedAt(now.AddDate(0, -3, 0)), false, 24*time.Hour, now)
+		Expect(create).To(BeFalse())
+		Expect(reason).To(ContainSubstring("DEVOPS-1 is still open"))
+	})
+
+	It("should skip when the latest issue is newer than the interval", func() {
+		create, reason := jira.ShouldCreateIssue(issueCreatedAt(now.AddDate(0, 0, -10)), true, 30*24*time.Hour, now)
+		Expect(create).To(BeFalse())
+		Expect(reason).To(ContainSubstring("next check after 2025-07-05"))
+	})
+
+	It("should create an issue when the latest issue is completed and older than the interval", func() {
+		create, _ := jira.ShouldCreateIssue(issueCreatedAt(now.AddDate(0, 0, -31)), true, 30*24*time.Hour, now)
+		Expect(create).To(BeTrue())
+	})
+
+	It("should create an issue when the latest issue is completed and there is no interval", func() {
+		create, _ := jira.ShouldCreateIssue(issueCreatedAt(now), true, 0, now)
+		Expect(create).To(BeTrue())
+	})
+})
+
+var _ = Describe("ParseUpstreamVersions", func() {
+	It("should parse plugin versions", func() {
+		versions, err := jira.ParseUpstreamVersions([]string{"gitlab=v17.1.0", "harbor=v2.13.0"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(Equal(map[string]string{"gitlab": "v17.1.0", "harbor": "v2.13.0"}))
+	})
+
+	It("should reject invalid arguments", func() {
+		_, err := jira.ParseUpstreamVersions([]string{"gitlab"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ReleaseCheckJQL", func() {
+	It("should match the release-check label and the monthly label", func() {
+		query := jira.ReleaseCheckJQL("DEVOPS", "gitlab").String()
+		Expect(query).To(ContainSubstring(`labels="created-by-release-bot"`))
+		Expect(query).To(MatchRegexp(`labels IN \("gitlab-release-check", "gitlab-\d{6}"\)`))
+	})
+})
+
+var _ = Describe("PrintCheckResults", func() {
+	It("should print a table and a summary", func() {
+		out := &bytes.Buffer{}
+		jira.PrintCheckResults(out, []jira.CheckResult{
+			{Plugin: "gitlab", Action: jira.CheckActionCreated, Issue: "DEVOPS-2", Detail: "no previous release-check issue"},
+			{Plugin: "harbor", Action: jira.CheckActionSkipped, Issue: "DEVOPS-1", Detail: "issue DEVOPS-1 is still open"},
+			{Plugin: "sonarqube", Action: jira.CheckActionErrored, Detail: "failed to find issue"},
+		})
+
+		Expect(out.String()).To(ContainSubstring("PLUGIN     RESULT   ISSUE     DETAIL"))
+		Expect(out.String()).To(ContainSubstring("sonarqube  errored  -         failed to find issue"))
+		Expect(out.String()).To(HaveSuffix("1 created, 1 skipped, 1 errored\n"))
+	})
+})
+
+var _ = Describe("State", func() {
+	It("should return an empty state when the file does not exist", func() {
+		state, err := jira.LoadState(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.Plugins).To(BeEmpty())
+	})
+
+	It("should save and load the state", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "state.yaml")
+		created := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		state := &jira.State{Plugins: map[string]jira.PluginState{
+			"gitlab": {LastIssue: "DEVOPS-1", LastIssueCreated: created, UpstreamVersion: "v17.1.0"},
+		}}
+		Expect(state.Save(path)).To(Succeed())
+
+		loaded, err := jira.LoadState(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Plugins["gitlab"].LastIssue).To(Equal("DEVOPS-1"))
+		Expect(loaded.Plugins["gitlab"].LastIssueCreated.Equal(created)).To(BeTrue())
+		Expect(loaded.Plugins["gitlab"].UpstreamVersion).To(Equal("v17.1.0"))
+	})
+})
+
+var _ = Describe("ParseDuration", func() {
+	DescribeTable("should parse durations",
+		func(value string, expected time.Duration) {
+			duration, err := types.ParseDuration(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(time.Duration(duration)).To(Equal(expected))
+		},
+		Entry("days", "30d", 30*24*time.Hour),
+		Entry("weeks", "2w", 14*24*time.Hour),
+		Entry("go duration", "36h", 36*time.Hour),
+		Entry("empty", "", time.Duration(0)),
+	)
+
+	It("should reject invalid durations", func() {
+		_, err := types.ParseDuration("monthly")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("create-release-check-jira command", func() {
+	var (
+		server  *httptest.Server
+		created []string
+		dir     string
+	)
+
+	BeforeEach(func() {
+		created = nil
+		dir = GinkgoT().TempDir()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+				query := r.URL.Query().Get("jql")
+				switch {
+				case strings.Contains(query, "harbor-release-check"):
+					fmt.Fprintln(w, `{"issues":[{"id":"10000","key":"DEVOPS-1","fields":{"created":"2025-01-01T00:00:00.000+0000","status":{"statusCategory":{"key":"indeterminate"}}}}]}`)
+				case strings.Contains(query, "sonarqube-release-check"):
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintln(w, `{"errorMessages":["search failed"]}`)
+				default:
+					fmt.Fprintln(w, `{"issues":[]}`)
+				}
+			case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+				created = append(created, r.URL.Path)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintln(w, `{"id":"10001","key":"DEVOPS-2"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create, skip and report errors per plugin and record the state", func() {
+		config := fmt.Sprintf(`jira:
+  baseURL: %s
+  username: user
+  password: pass
+  project: DEVOPS
+  issueType: Job
+  summary: "Check {{.plugin}} {{.upstreamVersion}}"
+  interval: 30d
+plugins:
+  gitlab:
+    owner: alice
+  harbor:
+    interval: 2w
+  sonarqube: {}
+`, server.URL)
+		configPath := filepath.Join(dir, "config.yaml")
+		statePath := filepath.Join(dir, "state.yaml")
+		Expect(os.WriteFile(configPath, []byte(config), 0644)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		cmd := jira.NewJiraCmd()
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"--config", configPath, "--state", statePath, "--upstream-version", "gitlab=v17.1.0"})
+		Expect(cmd.Execute()).To(MatchError(ContainSubstring("1 of 3 plugins failed")))
+
+		Expect(created).To(HaveLen(1))
+		Expect(out.String()).To(MatchRegexp(`gitlab\s+created\s+DEVOPS-2`))
+		Expect(out.String()).To(MatchRegexp(`harbor\s+skipped\s+DEVOPS-1\s+issue DEVOPS-1 is still open`))
+		Expect(out.String()).To(MatchRegexp(`sonarqube\s+errored\s+-`))
+
+		state, err := jira.LoadState(statePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.Plugins["gitlab"].LastIssue).To(Equal("DEVOPS-2"))
+		Expect(state.Plugins["gitlab"].UpstreamVersion).To(Equal("v17.1.0"))
+		Expect(state.Plugins["harbor"].LastIssue).To(Equal("DEVOPS-1"))
+	})
+})
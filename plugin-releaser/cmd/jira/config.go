@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/types"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/upstream"
 	"github.com/spf13/cast"
 	"gopkg.in/yaml.v3"
 )
@@ -16,6 +17,8 @@ import (
 type JiraConfig struct {
 	types.JiraConfig `yaml:",inline"`
 	IssueMeta        `yaml:",inline"`
+	// Default minimum time between two release-check issues of a plugin
+	Interval types.Duration `yaml:"interval"`
 }
 
 // IssueMeta contains metadata for creating Jira issues
@@ -85,6 +88,10 @@ type PluginIssueMeta struct {
 	IssueMeta `yaml:",inline"`
 	// Username of the person responsible for this plugin
 	Owner string `yaml:"owner"`
+	// Minimum time between two release-check issues, defaults to the jira interval
+	Interval types.Duration `yaml:"interval"`
+	// Upstream source used to detect new releases, issues are only created when upstream is ahead
+	Upstream *upstream.Config `yaml:"upstream,omitempty"`
 }
 
 // Merge combines this PluginIssueMeta with another IssueMeta, filling in missing values
@@ -127,6 +134,8 @@ type Config struct {
 	Jira JiraConfig `yaml:"jira"`
 	// Plugin-specific configurations indexed by plugin name
 	Plugins map[string]PluginIssueMeta `yaml:"plugins"`
+	// Artifacts repository directory containing <plugin>/versions.yaml, used to compare upstream versions
+	ArtifactsDir string `yaml:"artifactsDir"`
 }
 
 // LoadConfig loads configuration from a YAML file
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"html/template"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/jira"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/upstream"
 	"github.com/ankitpokhrel/jira-cli/pkg/jql"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -74,16 +76,55 @@ func NewJiraCmd() *cobra.Command {
 			}
 			logger.Debug("Jira client created successfully")
 
+			statePath, _ := cmd.Flags().GetString("state")
+			state, err := LoadState(statePath)
+			if err != nil {
+				logger.WithError(err).WithField("state", statePath).Error("Failed to load state")
+				return fmt.Errorf("failed to load state: %w", err)
+			}
+
+			artifactsDir, _ := cmd.Flags().GetString("artifacts-dir")
+			if artifactsDir == "" {
+				artifactsDir = cfg.ArtifactsDir
+			}
+
+			upstreamArgs, _ := cmd.Flags().GetStringArray("upstream-version")
+			upstreamVersions, err := ParseUpstreamVersions(upstreamArgs)
+			if err != nil {
+				return err
+			}
+
 			logger.WithField("pluginCount", len(cfg.Plugins)).Info("Processing plugins")
 
-			for pluginName, issueMeta := range cfg.Plugins {
+			pluginNames := make([]string, 0, len(cfg.Plugins))
+			for pluginName := range cfg.Plugins {
+				pluginNames = append(pluginNames, pluginName)
+			}
+			sort.Strings(pluginNames)
+
+			results := make([]CheckResult, 0, len(pluginNames))
+			failed := 0
+			for _, pluginName := range pluginNames {
 				logger.WithField("plugin", pluginName).Info("Processing plugin")
-				issueMeta := issueMeta.Merge(&cfg.Jira.IssueMeta)
-				if err := createPluginIssue(ctx, client, issueMeta, pluginName); err != nil {
-					logger.WithError(err).WithField("plugin", pluginName).Error("Failed to process plugin")
-					return err
+				issueMeta := cfg.Plugins[pluginName]
+				if issueMeta.Interval == 0 {
+					issueMeta.Interval = cfg.Jira.Interval
+				}
+				result := checkPlugin(ctx, client, state, issueMeta.Merge(&cfg.Jira.IssueMeta), pluginName, upstreamVersions[pluginName], artifactsDir)
+				if result.Action == CheckActionErrored {
+					failed++
 				}
-				logger.WithField("plugin", pluginName).Info("Plugin processed successfully")
+				results = append(results, result)
+			}
+
+			if err := state.Save(statePath); err != nil {
+				logger.WithError(err).WithField("state", statePath).Error("Failed to save state")
+				return fmt.Errorf("failed to save state: %w", err)
+			}
+
+			PrintCheckResults(cmd.OutOrStdout(), results)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d plugins failed", failed, len(results))
 			}
 
 			logger.Info("All plugins processed successfully")
@@ -92,6 +133,9 @@ func NewJiraCmd() *cobra.Command {
 	}
 
 	cmd.Flags().String("config", "", "Config file path (default: ./config.yaml)")
+	cmd.Flags().String("state", "release-check-state.yaml", "State file recording the last issue and upstream version of each plugin")
+	cmd.Flags().StringArray("upstream-version", nil, "Observed upstream version of a plugin as <plugin>=<version>, skips the upstream detection, can be repeated")
+	cmd.Flags().String("artifacts-dir", "", "Artifacts repository directory containing <plugin>/versions.yaml (default: artifactsDir of the config)")
 	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose logging")
 
 	return cmd
@@ -111,19 +155,80 @@ func templateFuncMap(jiraClient *jira.Client) template.FuncMap {
 	}
 }
 
-func createPluginIssue(ctx context.Context, jiraClient *jira.Client, issueMeta *PluginIssueMeta, pluginName string) error {
+// checkPlugin creates a release-check issue for a plugin unless upstream is not ahead of the shipped version,
+// its latest issue is still open or was created less than the plugin interval ago, and records the outcome in the state
+// upstream releases are detected when the plugin has an upstream source and no upstream version was provided
+func checkPlugin(ctx context.Context, jiraClient *jira.Client, state *State, issueMeta *PluginIssueMeta, pluginName, upstreamVersion, artifactsDir string) CheckResult {
 	logger := logger.WithField("plugin", pluginName)
+	result := CheckResult{Plugin: pluginName}
+	pluginState := state.Plugins[pluginName]
+	defer func() {
+		pluginState.LastCheck = time.Now()
+		if upstreamVersion != "" {
+			pluginState.UpstreamVersion = upstreamVersion
+		}
+		state.Plugins[pluginName] = pluginState
+	}()
+
+	var gap *upstream.Gap
+	if issueMeta.Upstream != nil && upstreamVersion == "" {
+		var err error
+		gap, err = DetectUpstream(ctx, artifactsDir, pluginName, *issueMeta.Upstream)
+		if err != nil {
+			logger.WithError(err).Error("Failed to detect upstream releases")
+			result.Action, result.Detail = CheckActionErrored, err.Error()
+			return result
+		}
+		upstreamVersion = gap.Latest.Tag
+		logger.WithField("gap", gap.String()).Info("Upstream releases detected")
+		if !gap.Ahead() {
+			result.Action, result.Detail = CheckActionSkipped, fmt.Sprintf("upstream %s is not ahead of %s", gap.Latest.Tag, gap.Current)
+			return result
+		}
+	}
+
+	botLabels := GenerateIssueTags(pluginName)
+	jqlQuery := ReleaseCheckJQL(issueMeta.Project, pluginName)
+	logger.WithField("jql", jqlQuery.String()).Debug("Searching latest release-check issue")
+	latest, err := jiraClient.FindLatestIssue(ctx, jqlQuery)
+	if err != nil {
+		logger.WithError(err).Error("Failed to find latest release-check issue")
+		result.Action, result.Detail = CheckActionErrored, err.Error()
+		return result
+	}
+
+	completed := latest != nil && jiraClient.IsCompletedIssue(*latest)
+	create, reason := ShouldCreateIssue(latest, completed, time.Duration(issueMeta.Interval), time.Now())
+	if !create {
+		logger.WithField("reason", reason).Info("Skipping plugin")
+		result.Action, result.Issue, result.Detail = CheckActionSkipped, latest.Key, reason
+		pluginState.LastIssue = latest.Key
+		return result
+	}
 
 	logger.Debug("Creating template data for issue")
 	templateData := map[string]interface{}{
-		"plugin": pluginName,
-		"issue":  issueMeta,
+		"plugin":              pluginName,
+		"issue":               issueMeta,
+		"upstreamVersion":     upstreamVersion,
+		"lastUpstreamVersion": pluginState.UpstreamVersion,
+	}
+	if gap != nil {
+		templateData["currentVersion"] = gap.Current
+		templateData["versionGap"] = gap
+		templateData["changelogs"] = gap.Changelogs()
 	}
 
 	logger.Debug("Rendering template for issue metadata")
-	if err := RenderStructTemplate(issueMeta, templateData, templateFuncMap(jiraClient)); err != nil {
+	// upstream settings are not templated and may contain characters special to templates
+	upstreamConfig := issueMeta.Upstream
+	issueMeta.Upstream = nil
+	err = RenderStructTemplate(issueMeta, templateData, templateFuncMap(jiraClient))
+	issueMeta.Upstream = upstreamConfig
+	if err != nil {
 		logger.WithError(err).Error("Failed to render template")
-		return fmt.Errorf("failed to render template: %w", err)
+		result.Action, result.Detail = CheckActionErrored, fmt.Sprintf("failed to render template: %s", err)
+		return result
 	}
 	logger.Debug("Template rendered successfully")
 
@@ -142,8 +247,8 @@ func createPluginIssue(ctx context.Context, jiraClient *jira.Client, issueMeta *
 		logger.WithField("assignee", issueMeta.Owner).Debug("Issue assignee set")
 	}
 
-	botLabels := GenerateIssueTags(pluginName)
 	labels := append(issueMeta.Labels, botLabels...)
+	labels = append(labels, ReleaseCheckLabel(pluginName))
 	opts = append(opts, jira.WithLabels(labels...))
 	logger.WithField("labels", labels).Debug("Issue labels configured")
 
@@ -152,32 +257,53 @@ func createPluginIssue(ctx context.Context, jiraClient *jira.Client, issueMeta *
 		logger.WithField("customField", issueMeta.GetCustomFields()).Debug("Custom fields configured")
 	}
 
-	// Build JQL query to find existing issues
-	jqlQuery := jql.NewJQL(issueMeta.Project)
-	for _, label := range botLabels {
-		jqlQuery = jqlQuery.And(func() {
-			jqlQuery.FilterBy("labels", label)
-		})
-	}
-
 	logger.WithFields(logrus.Fields{
 		"project": issueMeta.Project,
 		"summary": issueMeta.Summary,
-		"labels":  botLabels,
-	}).Info("Creating or finding Jira issue")
+		"reason":  reason,
+	}).Info("Creating Jira issue")
 
-	issue, err := jiraClient.FindOrCreateIssue(ctx, jqlQuery, opts...)
+	issue, err := jiraClient.CreateIssue(ctx, opts...)
 	if err != nil {
-		logger.WithError(err).Error("Failed to create or find Jira issue")
-		return fmt.Errorf("failed to create or find jira issue: %w", err)
+		logger.WithError(err).Error("Failed to create Jira issue")
+		result.Action, result.Detail = CheckActionErrored, err.Error()
+		return result
 	}
 
-	logger.WithFields(logrus.Fields{
-		"issueKey": issue.Key,
-		"plugin":   pluginName,
-	}).Info("Jira issue created or found successfully")
+	logger.WithField("issueKey", issue.Key).Info("Jira issue created successfully")
+	pluginState.LastIssue = issue.Key
+	pluginState.LastIssueCreated = time.Now()
+	result.Action, result.Issue, result.Detail = CheckActionCreated, issue.Key, reason
+	return result
+}
 
-	return nil
+// ReleaseCheckLabel returns the label shared by all release-check issues of a plugin
+func ReleaseCheckLabel(pluginName string) string {
+	return pluginName + "-release-check"
+}
+
+// ReleaseCheckJQL returns the query matching the release-check issues of a plugin
+// issues created before the release-check label was introduced are matched by the label of the current month
+func ReleaseCheckJQL(project, pluginName string) *jql.JQL {
+	tags := GenerateIssueTags(pluginName)
+	jqlQuery := jql.NewJQL(project)
+	return jqlQuery.And(func() {
+		jqlQuery.FilterBy("labels", tags[0])
+		jqlQuery.In("labels", ReleaseCheckLabel(pluginName), tags[1])
+	})
+}
+
+// ParseUpstreamVersions parses <plugin>=<version> arguments into a map of versions indexed by plugin name
+func ParseUpstreamVersions(args []string) (map[string]string, error) {
+	versions := make(map[string]string, len(args))
+	for _, arg := range args {
+		pluginName, version, found := strings.Cut(arg, "=")
+		if !found || pluginName == "" || version == "" {
+			return nil, fmt.Errorf("invalid upstream version %q, expected <plugin>=<version>", arg)
+		}
+		versions[pluginName] = version
+	}
+	return versions, nil
 }
 
 // GenerateIssueTags returns a tag with the format "bot-<pluginName>-YYYYMM"
@@ -0,0 +1,73 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jira
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// State records the outcome of the previous release checks so later runs can compare against it
+type State struct {
+	// Plugins contains the state of each plugin indexed by plugin name
+	Plugins map[string]PluginState `yaml:"plugins"`
+}
+
+// PluginState is the state of the release check of a plugin
+type PluginState struct {
+	// Key of the latest release-check issue
+	LastIssue string `yaml:"lastIssue,omitempty"`
+	// Time the latest release-check issue was created
+	LastIssueCreated time.Time `yaml:"lastIssueCreated,omitempty"`
+	// Time of the last check
+	LastCheck time.Time `yaml:"lastCheck,omitempty"`
+	// Last observed upstream version
+	UpstreamVersion string `yaml:"upstreamVersion,omitempty"`
+}
+
+// LoadState loads the state from a YAML file
+// Returns an empty state if the file does not exist
+func LoadState(path string) (*State, error) {
+	state := &State{Plugins: map[string]PluginState{}}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(content, state); err != nil {
+		return nil, err
+	}
+	if state.Plugins == nil {
+		state.Plugins = map[string]PluginState{}
+	}
+	return state, nil
+}
+
+// Save writes the state to a YAML file
+func (s *State) Save(path string) error {
+	content, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,48 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jira
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/artifacts"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/upstream"
+)
+
+// DetectUpstream compares the upstream releases of a plugin with the version shipped in versions.yaml
+// artifactsDir: the artifacts repository directory containing <plugin>/versions.yaml
+func DetectUpstream(ctx context.Context, artifactsDir, pluginName string, cfg upstream.Config) (*upstream.Gap, error) {
+	if artifactsDir == "" {
+		return nil, fmt.Errorf("artifacts directory is required to compare upstream versions")
+	}
+
+	versions, err := artifacts.LoadVersions(artifactsDir, pluginName)
+	if err != nil {
+		return nil, err
+	}
+	current, err := upstream.CurrentVersion(versions, cfg.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := upstream.NewSource(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return upstream.Detect(ctx, source, cfg, current)
+}
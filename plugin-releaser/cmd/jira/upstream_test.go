@@ -0,0 +1,143 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jira_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/cmd/jira"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/upstream"
+)
+
+var _ = Describe("Upstream release detection", func() {
+	var (
+		github       *httptest.Server
+		jiraServer   *httptest.Server
+		artifactsDir string
+		descriptions []string
+	)
+
+	BeforeEach(func() {
+		descriptions = nil
+		artifactsDir = GinkgoT().TempDir()
+		for plugin, versions := range map[string]string{
+			"harbor": "stable: v2.12.0-beta.3.gabcdef\nalpha: v2.12.1\n",
+			"tekton": "stable: v0.60.0\n",
+		} {
+			Expect(os.MkdirAll(filepath.Join(artifactsDir, plugin), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(artifactsDir, plugin, "versions.yaml"), []byte(versions), 0644)).To(Succeed())
+		}
+
+		github = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/repos/goharbor/harbor/releases":
+				fmt.Fprintln(w, `[{"tag_name":"v2.13.0","html_url":"https://github.com/goharbor/harbor/releases/tag/v2.13.0"},{"tag_name":"v2.12.2","html_url":"https://github.com/goharbor/harbor/releases/tag/v2.12.2"},{"tag_name":"v2.12.0"}]`)
+			case "/repos/tektoncd/pipeline/releases":
+				fmt.Fprintln(w, `[{"tag_name":"v0.60.0"}]`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		jiraServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+				fmt.Fprintln(w, `{"issues":[]}`)
+			case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+				var issue struct {
+					Fields struct {
+						Description string `json:"description"`
+					} `json:"fields"`
+				}
+				Expect(json.NewDecoder(r.Body).Decode(&issue)).To(Succeed())
+				descriptions = append(descriptions, issue.Fields.Description)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintln(w, `{"id":"10001","key":"DEVOPS-2"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		github.Close()
+		jiraServer.Close()
+	})
+
+	It("should compare upstream releases with versions.yaml", func() {
+		gap, err := jira.DetectUpstream(context.Background(), artifactsDir, "harbor", upstream.Config{Type: upstream.TypeGitHub, URL: github.URL, Repository: "goharbor/harbor", Channel: "stable"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gap.Current).To(Equal("v2.12.0-beta.3.gabcdef"))
+		Expect(gap.String()).To(Equal("v2.12.0-beta.3.gabcdef -> v2.13.0 (2 releases)"))
+
+		_, err = jira.DetectUpstream(context.Background(), "", "harbor", upstream.Config{Type: upstream.TypeGitHub})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only create issues for plugins whose upstream is ahead", func() {
+		config := fmt.Sprintf(`jira:
+  baseURL: %[1]s
+  username: user
+  password: pass
+  project: DEVOPS
+  issueType: Job
+  summary: "Release {{.plugin}} {{.upstreamVersion}}"
+  description: |-
+    Shipped {{.currentVersion}}, upstream {{.upstreamVersion}}
+    {{range .versionGap.Releases}}* {{.Tag}} {{.URL}}
+    {{end}}
+artifactsDir: %[3]s
+plugins:
+  harbor:
+    upstream:
+      type: github
+      url: %[2]s
+      repository: goharbor/harbor
+      channel: stable
+  tekton:
+    upstream:
+      type: github
+      url: %[2]s
+      repository: tektoncd/pipeline
+`, jiraServer.URL, github.URL, artifactsDir)
+		configPath := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(configPath, []byte(config), 0644)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		cmd := jira.NewJiraCmd()
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"--config", configPath, "--state", filepath.Join(GinkgoT().TempDir(), "state.yaml")})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(out.String()).To(MatchRegexp(`harbor\s+created\s+DEVOPS-2`))
+		Expect(out.String()).To(MatchRegexp(`tekton\s+skipped\s+-\s+upstream v0.60.0 is not ahead of v0.60.0`))
+		Expect(descriptions).To(HaveLen(1))
+		Expect(descriptions[0]).To(ContainSubstring("Shipped v2.12.0-beta.3.gabcdef, upstream v2.13.0"))
+		Expect(descriptions[0]).To(ContainSubstring("* v2.12.2 https://github.com/goharbor/harbor/releases/tag/v2.12.2"))
+		Expect(descriptions[0]).To(ContainSubstring("* v2.13.0 https://github.com/goharbor/harbor/releases/tag/v2.13.0"))
+	})
+})
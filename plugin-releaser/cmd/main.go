@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/AlaudaDevops/toolbox/plugin-releaser/cmd/jira"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/cmd/release"
 	"github.com/spf13/cobra"
 	"os"
 )
@@ -18,6 +19,7 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(jira.NewJiraCmd())
+	rootCmd.AddCommand(release.NewReleaseCmd())
 }
 
 func main() {
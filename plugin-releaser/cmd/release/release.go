@@ -0,0 +1,234 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package release provides the commands carrying out a plugin release from a plan
+package release
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	jiracmd "github.com/AlaudaDevops/toolbox/plugin-releaser/cmd/jira"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/github"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/jira"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/release"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// logger is the package-level logger instance
+var logger = logrus.New()
+
+func init() {
+	logger.SetFormatter(&logrus.TextFormatter{
+		ForceColors:     true,
+		TimestampFormat: "15:04:05",
+	})
+	logger.SetLevel(logrus.InfoLevel)
+	logger.SetOutput(os.Stdout)
+}
+
+// NewReleaseCmd creates the release command and its subcommands
+func NewReleaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Carry out a plugin release from a plan",
+	}
+	cmd.PersistentFlags().String("plan", "", "Release plan file path (required)")
+	cmd.PersistentFlags().String("record", "", "Record file of the release progress (default: <plan>.record.yaml)")
+	_ = cmd.MarkPersistentFlagRequired("plan")
+
+	cmd.AddCommand(newRunCmd(), newStatusCmd())
+	return cmd
+}
+
+// newRunCmd creates the release run subcommand
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the steps of a release plan, resuming after the last succeeded step",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if verbose {
+				logger.SetLevel(logrus.DebugLevel)
+			}
+
+			plan, recordPath, record, err := loadRelease(cmd)
+			if err != nil {
+				return err
+			}
+
+			restart, _ := cmd.Flags().GetBool("restart")
+			if restart {
+				logger.WithField("record", recordPath).Info("Restarting the release from the first step")
+				record = &release.Record{}
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			deps, err := newDependencies(plan, configPath)
+			if err != nil {
+				return err
+			}
+
+			steps := release.Steps(plan, deps)
+			if err := record.Prepare(plan, steps); err != nil {
+				return fmt.Errorf("%w, use --restart to discard %s", err, recordPath)
+			}
+
+			logger.WithFields(logrus.Fields{
+				"plugin":  plan.Plugin,
+				"version": plan.Version,
+				"steps":   len(steps),
+			}).Info("Running release")
+
+			runner := &release.Runner{
+				Steps:  steps,
+				Record: record,
+				Save: func(r *release.Record) error {
+					return r.Save(recordPath)
+				},
+			}
+			runErr := runner.Run(ctx)
+
+			PrintRecord(cmd.OutOrStdout(), steps, record)
+			if runErr != nil {
+				logger.WithError(runErr).WithField("record", recordPath).Error("Release failed, run again to resume")
+				return runErr
+			}
+
+			logger.Info("Release completed successfully")
+			return nil
+		},
+	}
+
+	cmd.Flags().String("config", "", "Config file with the jira connection, required if the plan has jira transitions (default: ./config.yaml)")
+	cmd.Flags().Bool("restart", false, "Discard the record and run all steps again")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose logging")
+	return cmd
+}
+
+// newStatusCmd creates the release status subcommand
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Print the progress of a release plan",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, recordPath, record, err := loadRelease(cmd)
+			if err != nil {
+				return err
+			}
+
+			steps := release.Steps(plan, release.Dependencies{})
+			if err := record.Prepare(plan, steps); err != nil {
+				return fmt.Errorf("%w, see %s", err, recordPath)
+			}
+			PrintRecord(cmd.OutOrStdout(), steps, record)
+			return nil
+		},
+	}
+}
+
+// loadRelease loads the plan and the record of the release
+func loadRelease(cmd *cobra.Command) (*release.Plan, string, *release.Record, error) {
+	planPath, _ := cmd.Flags().GetString("plan")
+	plan, err := release.LoadPlan(planPath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	recordPath, _ := cmd.Flags().GetString("record")
+	if recordPath == "" {
+		recordPath = RecordPath(planPath)
+	}
+	record, err := release.LoadRecord(recordPath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return plan, recordPath, record, nil
+}
+
+// RecordPath returns the default record path of a plan, e.g. plan.yaml -> plan.record.yaml
+func RecordPath(planPath string) string {
+	return strings.TrimSuffix(planPath, filepath.Ext(planPath)) + ".record.yaml"
+}
+
+// newDependencies creates the clients needed by the plan
+func newDependencies(plan *release.Plan, configPath string) (release.Dependencies, error) {
+	deps := release.Dependencies{}
+
+	if pr := plan.PullRequest; pr != nil {
+		token := os.Getenv(pr.TokenEnv)
+		if token == "" {
+			logger.WithField("env", pr.TokenEnv).Warn("GitHub token is empty")
+		}
+		deps.GitHub = &github.Client{BaseURL: pr.URL, Token: token}
+	}
+
+	if plan.Jira != nil {
+		if configPath == "" {
+			configPath = "config.yaml"
+		}
+		cfg, err := jiracmd.LoadConfig(configPath)
+		if err != nil {
+			return deps, fmt.Errorf("failed to load config: %w", err)
+		}
+		client, err := jira.NewClientWithConfig(&cfg.Jira.JiraConfig)
+		if err != nil {
+			return deps, fmt.Errorf("failed to create jira client: %w", err)
+		}
+		deps.Jira = client
+	}
+	return deps, nil
+}
+
+// PrintRecord prints the status of each step of a release as a table
+func PrintRecord(out io.Writer, steps []release.Step, record *release.Record) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tSTATUS\tDETAIL")
+	counts := map[release.StepStatus]int{}
+	for _, step := range steps {
+		stepRecord := record.Step(step.Name)
+		detail := step.Description
+		switch {
+		case stepRecord.Status == release.StepStatusFailed:
+			detail = stepRecord.Error
+		case len(stepRecord.Outputs) > 0:
+			detail = formatOutputs(stepRecord.Outputs)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", step.Name, stepRecord.Status, detail)
+		counts[stepRecord.Status]++
+	}
+	w.Flush()
+	fmt.Fprintf(out, "%d succeeded, %d failed, %d pending\n", counts[release.StepStatusSucceeded], counts[release.StepStatusFailed], counts[release.StepStatusPending])
+}
+
+// formatOutputs formats step outputs as sorted key=value pairs
+func formatOutputs(outputs map[string]string) string {
+	pairs := make([]string, 0, len(outputs))
+	for key, value := range outputs {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright 2025 AlaudaDevops authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package release_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/cmd/release"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Release command", func() {
+	var dir, planPath string
+
+	run := func(args ...string) (string, error) {
+		cmd := release.NewReleaseCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		cmd.SetArgs(append(args, "--plan", planPath))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		planPath = filepath.Join(dir, "plan.yaml")
+		Expect(os.MkdirAll(filepath.Join(dir, "artifacts", "harbor"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "artifacts", "harbor", "versions.yaml"), []byte("stable: v2.12.2\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(planPath, []byte("plugin: harbor\nversion: v2.13.0\nartifactsDir: artifacts\nchannels: [stable]\n"), 0644)).To(Succeed())
+	})
+
+	It("derives the record path from the plan path", func() {
+		Expect(release.RecordPath("plans/harbor.yaml")).To(Equal("plans/harbor.record.yaml"))
+	})
+
+	It("runs the plan and records the progress", func() {
+		out, err := run("status")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("bump-versions  pending"))
+		Expect(out).To(ContainSubstring("0 succeeded, 0 failed, 1 pending"))
+
+		out, err = run("run")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("bump-versions  succeeded  changed=true"))
+
+		data, err := os.ReadFile(filepath.Join(dir, "artifacts", "harbor", "versions.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("stable: v2.13.0\n"))
+		Expect(filepath.Join(dir, "plan.record.yaml")).To(BeARegularFile())
+
+		// resuming does not run succeeded steps again
+		Expect(os.WriteFile(filepath.Join(dir, "artifacts", "harbor", "versions.yaml"), []byte("stable: v2.12.2\n"), 0644)).To(Succeed())
+		_, err = run("run")
+		Expect(err).ToNot(HaveOccurred())
+		data, err = os.ReadFile(filepath.Join(dir, "artifacts", "harbor", "versions.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("stable: v2.12.2\n"))
+
+		_, err = run("run", "--restart")
+		Expect(err).ToNot(HaveOccurred())
+		data, err = os.ReadFile(filepath.Join(dir, "artifacts", "harbor", "versions.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("stable: v2.13.0\n"))
+	})
+
+	It("records the failed step and its error", func() {
+		Expect(os.Remove(filepath.Join(dir, "artifacts", "harbor", "versions.yaml"))).To(Succeed())
+
+		out, err := run("run")
+		Expect(err).To(MatchError(ContainSubstring("step bump-versions failed")))
+		Expect(out).To(ContainSubstring("bump-versions  failed"))
+		Expect(out).To(ContainSubstring("0 succeeded, 1 failed, 0 pending"))
+	})
+})
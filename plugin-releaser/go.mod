@@ -3,6 +3,7 @@ module github.com/AlaudaDevops/toolbox/plugin-releaser
 go 1.25.4
 
 require (
+	github.com/Masterminds/semver/v3 v3.3.1
 	github.com/andygrunwald/go-jira v1.16.1
 	github.com/ankitpokhrel/jira-cli v1.5.2
 	github.com/onsi/ginkgo/v2 v2.23.4
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifacts
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ArtifactsFile is the file containing the artifacts published in each channel
+const ArtifactsFile = "artifacts.yaml"
+
+// ArtifactUpdate is the new tag and digest of an artifact of a channel in artifacts.yaml
+type ArtifactUpdate struct {
+	// Channel of the artifact
+	Channel string `yaml:"channel"`
+	// Repository of the artifact, e.g. devops/harbor-bundle
+	Repository string `yaml:"repository"`
+	// Tag is the new tag, defaults to the release version
+	Tag string `yaml:"tag,omitempty"`
+	// Digest is the new digest, e.g. sha256:...
+	Digest string `yaml:"digest"`
+}
+
+// SetVersions sets the version of the channels in versions.yaml, keeping the order and comments of the file
+// Returns true if the file changed
+func SetVersions(dir, plugin, version string, channels ...string) (bool, error) {
+	path := filepath.Join(dir, plugin, VersionsFile)
+	doc, err := loadNode(path)
+	if err != nil {
+		return false, err
+	}
+
+	root := doc.Content[0]
+	changed := false
+	for _, channel := range channels {
+		changed = setScalar(root, channel, version) || changed
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, saveNode(path, doc)
+}
+
+// UpdateArtifacts sets the version of the channels and the tag and digest of the matching artifacts in artifacts.yaml
+// keeping the order and comments of the file
+// Returns true if the file changed
+func UpdateArtifacts(dir, plugin, version string, updates ...ArtifactUpdate) (bool, error) {
+	path := filepath.Join(dir, plugin, ArtifactsFile)
+	doc, err := loadNode(path)
+	if err != nil {
+		return false, err
+	}
+
+	channels := mappingValue(doc.Content[0], "channels")
+	if channels == nil || channels.Kind != yaml.SequenceNode {
+		return false, fmt.Errorf("%s has no channels", path)
+	}
+
+	changed := false
+	for _, update := range updates {
+		tag := update.Tag
+		if tag == "" {
+			tag = version
+		}
+
+		channel := findItem(channels, "channel", update.Channel)
+		if channel == nil {
+			return false, fmt.Errorf("channel %s not found in %s", update.Channel, path)
+		}
+		changed = setScalar(channel, "version", version) || changed
+
+		artifact := findItem(mappingValue(channel, "artifacts"), "repository", update.Repository)
+		if artifact == nil {
+			return false, fmt.Errorf("artifact %s not found in channel %s of %s", update.Repository, update.Channel, path)
+		}
+		changed = setScalar(artifact, "tag", tag) || changed
+		if update.Digest != "" {
+			changed = setScalar(artifact, "digest", update.Digest) || changed
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, saveNode(path, doc)
+}
+
+// loadNode loads a YAML file whose root is a mapping
+func loadNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a mapping", path)
+	}
+	return doc, nil
+}
+
+// saveNode writes a YAML document with an indentation of 2 spaces
+func saveNode(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// mappingValue returns the value of a key in a mapping, nil if missing
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setScalar sets a string value in a mapping, adding the key if missing
+// Returns true if the value changed
+func setScalar(mapping *yaml.Node, key, value string) bool {
+	if node := mappingValue(mapping, key); node != nil {
+		if node.Kind == yaml.ScalarNode && node.Value == value {
+			return false
+		}
+		*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, LineComment: node.LineComment}
+		return true
+	}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	)
+	return true
+}
+
+// findItem returns the mapping of a sequence whose key has the given value, nil if missing
+func findItem(sequence *yaml.Node, key, value string) *yaml.Node {
+	if sequence == nil || sequence.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, item := range sequence.Content {
+		if node := mappingValue(item, key); node != nil && node.Value == value {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func writePluginFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "harbor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "harbor", name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetVersions(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	writePluginFile(t, dir, VersionsFile, "# shipped versions\nstable: v2.12.2\nalpha: v2.12.2 # preview\n")
+
+	changed, err := SetVersions(dir, "harbor", "v2.13.0", "alpha", "stable")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).To(BeTrue())
+
+	data, err := os.ReadFile(filepath.Join(dir, "harbor", VersionsFile))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal("# shipped versions\nstable: v2.13.0\nalpha: v2.13.0 # preview\n"))
+
+	changed, err = SetVersions(dir, "harbor", "v2.13.0", "stable")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).To(BeFalse())
+
+	_, err = SetVersions(dir, "missing", "v2.13.0", "stable")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestUpdateArtifacts(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	writePluginFile(t, dir, ArtifactsFile, `channels:
+  - channel: stable
+    version: v2.12.2
+    artifacts:
+      - repository: devops/harbor-bundle
+        tag: v2.12.2
+        digest: sha256:old
+        type: Bundle
+      - repository: devops/harbor-chart
+        tag: v2.12.2
+        digest: sha256:chart
+        type: Chart
+`)
+
+	changed, err := UpdateArtifacts(dir, "harbor", "v2.13.0", ArtifactUpdate{
+		Channel:    "stable",
+		Repository: "devops/harbor-bundle",
+		Digest:     "sha256:new",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changed).To(BeTrue())
+
+	data, err := os.ReadFile(filepath.Join(dir, "harbor", ArtifactsFile))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal(`channels:
+  - channel: stable
+    version: v2.13.0
+    artifacts:
+      - repository: devops/harbor-bundle
+        tag: v2.13.0
+        digest: sha256:new
+        type: Bundle
+      - repository: devops/harbor-chart
+        tag: v2.12.2
+        digest: sha256:chart
+        type: Chart
+`))
+
+	_, err = UpdateArtifacts(dir, "harbor", "v2.13.0", ArtifactUpdate{Channel: "alpha", Repository: "devops/harbor-bundle"})
+	g.Expect(err).To(MatchError(ContainSubstring("channel alpha not found")))
+
+	_, err = UpdateArtifacts(dir, "harbor", "v2.13.0", ArtifactUpdate{Channel: "stable", Repository: "devops/missing"})
+	g.Expect(err).To(MatchError(ContainSubstring("artifact devops/missing not found")))
+}
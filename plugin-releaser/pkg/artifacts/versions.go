@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package artifacts reads and writes the plugin files of an artifacts repository
+// each plugin is a folder named after the plugin containing versions.yaml and artifacts.yaml
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VersionsFile is the file containing the version shipped in each channel
+const VersionsFile = "versions.yaml"
+
+// Versions is the content of versions.yaml, a map of channel name to version
+type Versions map[string]string
+
+// LoadVersions loads the versions.yaml of a plugin
+// dir: the artifacts repository directory
+// plugin: the plugin name
+func LoadVersions(dir, plugin string) (Versions, error) {
+	path := filepath.Join(dir, plugin, VersionsFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	versions := Versions{}
+	if err := yaml.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	return versions, nil
+}
+
+// Channels returns the channel names sorted alphabetically
+func (v Versions) Channels() []string {
+	channels := make([]string, 0, len(v))
+	for channel := range v {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
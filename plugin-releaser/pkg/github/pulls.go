@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package github opens pull requests with the GitHub REST API
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultURL is the GitHub API URL
+const DefaultURL = "https://api.github.com"
+
+// Client is a minimal GitHub API client
+type Client struct {
+	Client *http.Client
+	// BaseURL is the API URL, defaults to DefaultURL
+	BaseURL string
+	// Token is the API token
+	Token string
+}
+
+// PullRequest is a pull request returned by the GitHub API
+type PullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+}
+
+// NewPullRequest is the payload used to open a pull request
+type NewPullRequest struct {
+	// Title of the pull request
+	Title string `json:"title"`
+	// Head is the branch containing the changes
+	Head string `json:"head"`
+	// Base is the branch the changes are merged into
+	Base string `json:"base"`
+	// Body is the description of the pull request
+	Body string `json:"body,omitempty"`
+}
+
+// CreatePullRequest opens a pull request on the repository
+// Returns the open pull request of the head branch if one already exists
+// repo: the repository as owner/repo
+func (c *Client) CreatePullRequest(ctx context.Context, repo string, pr NewPullRequest) (*PullRequest, error) {
+	if existing, err := c.FindPullRequest(ctx, repo, pr.Head); err != nil || existing != nil {
+		return existing, err
+	}
+
+	created := &PullRequest{}
+	if err := c.do(ctx, http.MethodPost, c.repoURL(repo, "pulls"), pr, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// FindPullRequest returns the open pull request of a branch of the repository, nil if none exists
+func (c *Client) FindPullRequest(ctx context.Context, repo, branch string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(repo, "/")
+	query := url.Values{"state": {"open"}, "head": {owner + ":" + branch}}
+
+	var pulls []PullRequest
+	if err := c.do(ctx, http.MethodGet, c.repoURL(repo, "pulls")+"?"+query.Encode(), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return &pulls[0], nil
+}
+
+// repoURL returns the API URL of a repository path
+func (c *Client) repoURL(repo, path string) string {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	return fmt.Sprintf("%s/repos/%s/%s", strings.TrimSuffix(baseURL, "/"), (&url.URL{Path: repo}).EscapedPath(), path)
+}
+
+// do sends a JSON request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, endpoint string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to %s %s: status %d: %s", method, endpoint, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       string
+		createStatus   int
+		expectedNumber int
+		expectCreate   bool
+		wantErr        bool
+	}{
+		{
+			name:           "opens a pull request",
+			existing:       `[]`,
+			createStatus:   http.StatusCreated,
+			expectedNumber: 7,
+			expectCreate:   true,
+		},
+		{
+			name:           "returns the open pull request of the branch",
+			existing:       `[{"number":3,"html_url":"https://github.com/org/artifacts/pull/3","state":"open"}]`,
+			expectedNumber: 3,
+		},
+		{
+			name:         "create error",
+			existing:     `[]`,
+			createStatus: http.StatusUnprocessableEntity,
+			expectCreate: true,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var head, auth string
+			var created *NewPullRequest
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.URL.Path).To(Equal("/repos/org/artifacts/pulls"))
+				auth = r.Header.Get("Authorization")
+				switch r.Method {
+				case http.MethodGet:
+					head = r.URL.Query().Get("head")
+					fmt.Fprint(w, tt.existing)
+				case http.MethodPost:
+					created = &NewPullRequest{}
+					g.Expect(json.NewDecoder(r.Body).Decode(created)).To(Succeed())
+					w.WriteHeader(tt.createStatus)
+					fmt.Fprint(w, `{"number":7,"html_url":"https://github.com/org/artifacts/pull/7","state":"open"}`)
+				}
+			}))
+			defer ts.Close()
+
+			client := &Client{Client: ts.Client(), BaseURL: ts.URL, Token: "token"}
+			pr, err := client.CreatePullRequest(context.Background(), "org/artifacts", NewPullRequest{
+				Title: "release harbor v2.13.0",
+				Head:  "release/harbor-v2.13.0",
+				Base:  "main",
+			})
+
+			g.Expect(head).To(Equal("org:release/harbor-v2.13.0"))
+			g.Expect(auth).To(Equal("Bearer token"))
+			g.Expect(created != nil).To(Equal(tt.expectCreate))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pr.Number).To(Equal(tt.expectedNumber))
+			if created != nil {
+				g.Expect(created.Base).To(Equal("main"))
+				g.Expect(created.Title).To(Equal("release harbor v2.13.0"))
+			}
+		})
+	}
+}
@@ -19,8 +19,8 @@ package jira
 import (
 	"context"
 	"fmt"
-
 	"io"
+	"strings"
 
 	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/types"
 	"github.com/andygrunwald/go-jira"
@@ -102,6 +102,74 @@ func (c *Client) FindOrCreateIssue(ctx context.Context, jql *jql.JQL, options ..
 	return issue, nil
 }
 
+// FindLatestIssue returns the most recently created issue matching the JQL query
+// Returns nil if no issue matches
+func (c *Client) FindLatestIssue(ctx context.Context, jql *jql.JQL) (*jira.Issue, error) {
+	searchOptions := &jira.SearchOptions{
+		Fields:     []string{"status", "created", "summary"},
+		MaxResults: 1,
+	}
+	issues, resp, err := c.inner.Issue.SearchWithContext(ctx, jql.OrderBy("created", "DESC").String(), searchOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issue: %s", c.handleError(resp, err))
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	return &issues[0], nil
+}
+
+// CreateIssue creates a new issue with the given options
+func (c *Client) CreateIssue(ctx context.Context, options ...IssueOption) (*jira.Issue, error) {
+	issue, resp, err := c.inner.Issue.CreateWithContext(ctx, newIssue(options...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %s", c.handleError(resp, err))
+	}
+
+	return issue, nil
+}
+
+// TransitionIssue moves an issue through the transition matching the name
+// name can be either the transition name or the name of the target status
+// Does nothing if the issue already has the target status of the transition
+func (c *Client) TransitionIssue(ctx context.Context, key, name string) error {
+	issue, resp, err := c.inner.Issue.GetWithContext(ctx, key, &jira.GetQueryOptions{Fields: "status"})
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %s", key, c.handleError(resp, err))
+	}
+	status := ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		status = issue.Fields.Status.Name
+	}
+
+	transitions, resp, err := c.inner.Issue.GetTransitionsWithContext(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get transitions of issue %s: %s", key, c.handleError(resp, err))
+	}
+
+	for _, transition := range transitions {
+		if !strings.EqualFold(transition.Name, name) && !strings.EqualFold(transition.To.Name, name) {
+			continue
+		}
+		// name may be the transition name, the issue status is compared with the status it leads to
+		if strings.EqualFold(status, transition.To.Name) {
+			return nil
+		}
+		resp, err := c.inner.Issue.DoTransitionWithContext(ctx, key, transition.ID)
+		if err != nil {
+			return fmt.Errorf("failed to transition issue %s to %s: %s", key, name, c.handleError(resp, err))
+		}
+		return nil
+	}
+
+	// transitions to the current status are usually not offered
+	if strings.EqualFold(status, name) {
+		return nil
+	}
+	return fmt.Errorf("transition %s is not available for issue %s", name, key)
+}
+
 func (c *Client) GetActiveSprint(ctx context.Context, boardID int) (*jira.Sprint, error) {
 	sprints, resp, err := c.inner.Board.GetAllSprintsWithOptionsWithContext(ctx, boardID, &jira.GetAllSprintsOptions{
 		State: "active",
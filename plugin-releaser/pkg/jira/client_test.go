@@ -18,10 +18,12 @@ package jira
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
 	"github.com/ankitpokhrel/jira-cli/pkg/jql"
@@ -199,6 +201,181 @@ func TestClient_CreateOrUpdateIssue(t *testing.T) {
 	}
 }
 
+func TestClient_FindLatestIssue(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name             string
+		mockSearchResp   string
+		mockSearchStatus int
+		expectedIssueKey string
+		wantErr          bool
+	}{
+		{
+			name:             "returns the latest issue",
+			mockSearchResp:   `{"issues":[{"id":"10001","key":"TEST-2","fields":{"created":"2025-06-01T10:00:00.000+0000","status":{"statusCategory":{"key":"done"}}}}]}`,
+			mockSearchStatus: http.StatusOK,
+			expectedIssueKey: "TEST-2",
+		},
+		{
+			name:             "returns nil when no issue matches",
+			mockSearchResp:   `{"issues":[]}`,
+			mockSearchStatus: http.StatusOK,
+		},
+		{
+			name:             "search error",
+			mockSearchResp:   `{"errorMessages":["Error searching for issues"],"errors":{}}`,
+			mockSearchStatus: http.StatusBadRequest,
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query, maxResults string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query().Get("jql")
+				maxResults = r.URL.Query().Get("maxResults")
+				w.WriteHeader(tt.mockSearchStatus)
+				fmt.Fprintln(w, tt.mockSearchResp)
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL, "user", "pass")
+			g.Expect(err).ToNot(HaveOccurred())
+
+			issue, err := client.FindLatestIssue(context.Background(), jql.NewJQL("TEST").FilterBy("labels", "gitlab-release-check"))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(query).To(HaveSuffix("ORDER BY created DESC"))
+			g.Expect(maxResults).To(Equal("1"))
+			if tt.expectedIssueKey == "" {
+				g.Expect(issue).To(BeNil())
+				return
+			}
+			g.Expect(issue.Key).To(Equal(tt.expectedIssueKey))
+			g.Expect(time.Time(issue.Fields.Created).Year()).To(Equal(2025))
+		})
+	}
+}
+
+// workflow are the transitions offered from each status, like a Jira workflow
+var workflow = map[string][]jira.Transition{
+	"To Do":       {{ID: "11", Name: "Start Progress", To: jira.Status{Name: "In Progress"}}},
+	"In Progress": {{ID: "21", Name: "Resolve", To: jira.Status{Name: "Done"}}, {ID: "31", Name: "Stop Progress", To: jira.Status{Name: "To Do"}}},
+	"Done":        {{ID: "41", Name: "Reopen", To: jira.Status{Name: "To Do"}}},
+}
+
+func TestClient_TransitionIssue(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name               string
+		status             string
+		transition         string
+		expectedTransition string
+		expectedStatus     string
+		wantErr            bool
+	}{
+		{
+			name:               "matches the transition name",
+			status:             "To Do",
+			transition:         "start progress",
+			expectedTransition: "11",
+			expectedStatus:     "In Progress",
+		},
+		{
+			name:               "matches the target status",
+			status:             "In Progress",
+			transition:         "Done",
+			expectedTransition: "21",
+			expectedStatus:     "Done",
+		},
+		{
+			name:           "does nothing when the issue already has the status",
+			status:         "Done",
+			transition:     "Done",
+			expectedStatus: "Done",
+		},
+		{
+			name:       "transition not offered from the status",
+			status:     "In Progress",
+			transition: "Start Progress",
+			wantErr:    true,
+		},
+		{
+			name:       "transition not available",
+			status:     "To Do",
+			transition: "Closed",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transitioned string
+			status := tt.status
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/rest/api/2/issue/TEST-1":
+					fmt.Fprintf(w, `{"id":"10000","key":"TEST-1","fields":{"status":{"name":%q}}}`, status)
+				case r.URL.Path == "/rest/api/2/issue/TEST-1/transitions" && r.Method == http.MethodGet:
+					g.Expect(json.NewEncoder(w).Encode(map[string]any{"transitions": workflow[status]})).To(Succeed())
+				case r.URL.Path == "/rest/api/2/issue/TEST-1/transitions" && r.Method == http.MethodPost:
+					var payload jira.CreateTransitionPayload
+					g.Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+					for _, transition := range workflow[status] {
+						if transition.ID == payload.Transition.ID {
+							transitioned, status = transition.ID, transition.To.Name
+							w.WriteHeader(http.StatusNoContent)
+							return
+						}
+					}
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL, "user", "pass")
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = client.TransitionIssue(context.Background(), "TEST-1", tt.transition)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(transitioned).To(Equal(tt.expectedTransition))
+			g.Expect(status).To(Equal(tt.expectedStatus))
+		})
+	}
+}
+
+func TestClient_CreateIssue(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.Method).To(Equal(http.MethodPost))
+		g.Expect(r.URL.Path).To(Equal("/rest/api/2/issue"))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintln(w, `{"id":"10001","key":"TEST-2"}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, "user", "pass")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	issue, err := client.CreateIssue(context.Background(), WithProject("TEST"), WithSummary("Test Issue"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(issue.Key).To(Equal("TEST-2"))
+}
+
 func TestClient_IsCompletedIssue(t *testing.T) {
 	g := NewWithT(t)
 
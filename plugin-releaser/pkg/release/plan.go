@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package release carries out a plugin release from a declarative plan
+// each step of the plan is recorded so that a failed release can be resumed
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/artifacts"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan describes a plugin release
+type Plan struct {
+	// Plugin is the plugin name, also the folder of the plugin in the artifacts repository
+	Plugin string `yaml:"plugin"`
+	// Version is the released version
+	Version string `yaml:"version"`
+	// ArtifactsDir is the local clone of the artifacts repository
+	// relative paths are resolved against the folder of the plan
+	ArtifactsDir string `yaml:"artifactsDir"`
+	// Channels are the channels of versions.yaml bumped to the version
+	Channels []string `yaml:"channels"`
+	// Artifacts are the artifacts of artifacts.yaml updated with a new tag and digest
+	Artifacts []artifacts.ArtifactUpdate `yaml:"artifacts"`
+	// PullRequest opens a pull request with the changes on the artifacts repository
+	PullRequest *PullRequestPlan `yaml:"pullRequest"`
+	// Jira moves the release issue through transitions
+	Jira *JiraPlan `yaml:"jira"`
+}
+
+// PullRequestPlan describes the pull request opened on the artifacts repository
+type PullRequestPlan struct {
+	// Repository is the GitHub repository as owner/repo
+	Repository string `yaml:"repository"`
+	// URL is the GitHub API URL, defaults to https://api.github.com
+	URL string `yaml:"url"`
+	// TokenEnv is the environment variable containing the GitHub token, defaults to GITHUB_TOKEN
+	TokenEnv string `yaml:"tokenEnv"`
+	// Remote is the git remote the branch is pushed to, defaults to origin
+	Remote string `yaml:"remote"`
+	// Base is the branch the pull request is merged into, defaults to main
+	Base string `yaml:"base"`
+	// Branch is the branch of the changes, defaults to release/<plugin>-<version>
+	Branch string `yaml:"branch"`
+	// Title of the commit and pull request, defaults to "chore(<plugin>): release <version>"
+	Title string `yaml:"title"`
+	// Body of the pull request
+	Body string `yaml:"body"`
+}
+
+// JiraPlan describes the transitions of the release issue
+type JiraPlan struct {
+	// Issue is the issue key, e.g. DEVOPS-123
+	Issue string `yaml:"issue"`
+	// Transitions are applied in order, each one is a transition name or a target status name
+	Transitions []string `yaml:"transitions"`
+}
+
+// LoadPlan loads a release plan and sets its defaults
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan %s: %w", path, err)
+	}
+
+	plan := &Plan{}
+	if err := yaml.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal plan %s: %w", path, err)
+	}
+	if plan.ArtifactsDir != "" && !filepath.IsAbs(plan.ArtifactsDir) {
+		plan.ArtifactsDir = filepath.Join(filepath.Dir(path), plan.ArtifactsDir)
+	}
+
+	plan.SetDefaults()
+	if err := plan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+// SetDefaults sets the default values of the pull request
+func (p *Plan) SetDefaults() {
+	if p.PullRequest == nil {
+		return
+	}
+	pr := p.PullRequest
+	if pr.TokenEnv == "" {
+		pr.TokenEnv = "GITHUB_TOKEN"
+	}
+	if pr.Remote == "" {
+		pr.Remote = "origin"
+	}
+	if pr.Base == "" {
+		pr.Base = "main"
+	}
+	if pr.Branch == "" {
+		pr.Branch = fmt.Sprintf("release/%s-%s", p.Plugin, p.Version)
+	}
+	if pr.Title == "" {
+		pr.Title = fmt.Sprintf("chore(%s): release %s", p.Plugin, p.Version)
+	}
+	if pr.Body == "" {
+		pr.Body = fmt.Sprintf("Release %s %s", p.Plugin, p.Version)
+		if p.Jira != nil && p.Jira.Issue != "" {
+			pr.Body += fmt.Sprintf("\n\nJira: %s", p.Jira.Issue)
+		}
+	}
+}
+
+// Validate checks the plan is complete
+func (p *Plan) Validate() error {
+	if p.Plugin == "" {
+		return fmt.Errorf("plugin is required")
+	}
+	if p.Version == "" {
+		return fmt.Errorf("version is required")
+	}
+	if (len(p.Channels) > 0 || len(p.Artifacts) > 0 || p.PullRequest != nil) && p.ArtifactsDir == "" {
+		return fmt.Errorf("artifactsDir is required to update the artifacts repository")
+	}
+	for i, artifact := range p.Artifacts {
+		if artifact.Channel == "" || artifact.Repository == "" {
+			return fmt.Errorf("artifacts[%d]: channel and repository are required", i)
+		}
+	}
+	if p.PullRequest != nil && p.PullRequest.Repository == "" {
+		return fmt.Errorf("pullRequest.repository is required")
+	}
+	if p.Jira != nil && (p.Jira.Issue == "" || len(p.Jira.Transitions) == 0) {
+		return fmt.Errorf("jira.issue and jira.transitions are required")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StepStatus is the status of a step of the release
+type StepStatus string
+
+const (
+	// StepStatusPending means the step did not run yet
+	StepStatusPending StepStatus = "pending"
+	// StepStatusSucceeded means the step completed and is skipped when the release is resumed
+	StepStatusSucceeded StepStatus = "succeeded"
+	// StepStatusFailed means the step failed and runs again when the release is resumed
+	StepStatusFailed StepStatus = "failed"
+)
+
+// Record records the progress of a release
+type Record struct {
+	Plugin  string       `yaml:"plugin"`
+	Version string       `yaml:"version"`
+	Steps   []StepRecord `yaml:"steps"`
+}
+
+// StepRecord records the last run of a step
+type StepRecord struct {
+	Name       string            `yaml:"name"`
+	Status     StepStatus        `yaml:"status"`
+	StartedAt  time.Time         `yaml:"startedAt,omitempty"`
+	FinishedAt time.Time         `yaml:"finishedAt,omitempty"`
+	Outputs    map[string]string `yaml:"outputs,omitempty"`
+	Error      string            `yaml:"error,omitempty"`
+}
+
+// LoadRecord loads a release record, returns an empty record if the file does not exist
+func LoadRecord(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record %s: %w", path, err)
+	}
+
+	record := &Record{}
+	if err := yaml.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record %s: %w", path, err)
+	}
+	return record, nil
+}
+
+// Save writes the record to a file
+func (r *Record) Save(path string) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write record %s: %w", path, err)
+	}
+	return nil
+}
+
+// Step returns the record of a step, adding a pending record if missing
+func (r *Record) Step(name string) *StepRecord {
+	for i := range r.Steps {
+		if r.Steps[i].Name == name {
+			return &r.Steps[i]
+		}
+	}
+	r.Steps = append(r.Steps, StepRecord{Name: name, Status: StepStatusPending})
+	return &r.Steps[len(r.Steps)-1]
+}
+
+// Prepare binds the record to a release and adds a pending record for each step
+// Returns an error if the record belongs to another release
+func (r *Record) Prepare(plan *Plan, steps []Step) error {
+	if r.Plugin == "" && r.Version == "" {
+		r.Plugin, r.Version = plan.Plugin, plan.Version
+	}
+	if r.Plugin != plan.Plugin || r.Version != plan.Version {
+		return fmt.Errorf("record belongs to the release of %s %s, not %s %s", r.Plugin, r.Version, plan.Plugin, plan.Version)
+	}
+	for _, step := range steps {
+		r.Step(step.Name)
+	}
+	return nil
+}
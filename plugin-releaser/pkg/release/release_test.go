@@ -0,0 +1,274 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/artifacts"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/github"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/jira"
+	. "github.com/onsi/gomega"
+)
+
+func TestLoadPlan(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plan.yaml")
+	g.Expect(os.WriteFile(path, []byte(`plugin: harbor
+version: v2.13.0
+artifactsDir: artifacts
+channels: [stable]
+pullRequest:
+  repository: org/artifacts
+jira:
+  issue: DEVOPS-1
+  transitions: [Done]
+`), 0644)).To(Succeed())
+
+	plan, err := LoadPlan(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(plan.ArtifactsDir).To(Equal(filepath.Join(dir, "artifacts")))
+	g.Expect(*plan.PullRequest).To(Equal(PullRequestPlan{
+		Repository: "org/artifacts",
+		TokenEnv:   "GITHUB_TOKEN",
+		Remote:     "origin",
+		Base:       "main",
+		Branch:     "release/harbor-v2.13.0",
+		Title:      "chore(harbor): release v2.13.0",
+		Body:       "Release harbor v2.13.0\n\nJira: DEVOPS-1",
+	}))
+
+	g.Expect(os.WriteFile(path, []byte("plugin: harbor\nversion: v2.13.0\nchannels: [stable]\n"), 0644)).To(Succeed())
+	_, err = LoadPlan(path)
+	g.Expect(err).To(MatchError(ContainSubstring("artifactsDir is required")))
+}
+
+func TestRunner_Resume(t *testing.T) {
+	g := NewWithT(t)
+
+	runs := map[string]int{}
+	failing := true
+	steps := []Step{
+		{Name: "first", Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+			runs["first"]++
+			return map[string]string{"value": "1"}, nil
+		}},
+		{Name: "second", Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+			runs["second"]++
+			if failing {
+				return nil, fmt.Errorf("boom")
+			}
+			return nil, nil
+		}},
+		{Name: "third", Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+			runs["third"]++
+			return nil, nil
+		}},
+	}
+
+	path := filepath.Join(t.TempDir(), "record.yaml")
+	plan := &Plan{Plugin: "harbor", Version: "v2.13.0"}
+	run := func() error {
+		record, err := LoadRecord(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(record.Prepare(plan, steps)).To(Succeed())
+		runner := &Runner{Steps: steps, Record: record, Save: func(r *Record) error { return r.Save(path) }}
+		return runner.Run(context.Background())
+	}
+
+	g.Expect(run()).To(MatchError("step second failed: boom"))
+	record, err := LoadRecord(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(record.Step("first").Status).To(Equal(StepStatusSucceeded))
+	g.Expect(record.Step("first").Outputs).To(Equal(map[string]string{"value": "1"}))
+	g.Expect(record.Step("second").Status).To(Equal(StepStatusFailed))
+	g.Expect(record.Step("second").Error).To(Equal("boom"))
+	g.Expect(record.Step("third").Status).To(Equal(StepStatusPending))
+
+	failing = false
+	g.Expect(run()).To(Succeed())
+	g.Expect(runs).To(Equal(map[string]int{"first": 1, "second": 2, "third": 1}))
+
+	record, err = LoadRecord(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(record.Step("second").Error).To(BeEmpty())
+	g.Expect(record.Prepare(&Plan{Plugin: "harbor", Version: "v2.14.0"}, steps)).To(MatchError(ContainSubstring("record belongs to the release of harbor v2.13.0")))
+}
+
+type fakeTransitioner struct {
+	transitions []string
+}
+
+func (f *fakeTransitioner) TransitionIssue(ctx context.Context, key, name string) error {
+	f.transitions = append(f.transitions, key+":"+name)
+	return nil
+}
+
+func TestSteps(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	root := t.TempDir()
+
+	git := func(dir string, args ...string) string {
+		out, err := ExecGit(ctx, dir, args...)
+		g.Expect(err).ToNot(HaveOccurred())
+		return out
+	}
+	remote := filepath.Join(root, "remote.git")
+	dir := filepath.Join(root, "artifacts")
+	git(root, "init", "--bare", "-b", "main", remote)
+	git(root, "clone", remote, dir)
+	git(dir, "config", "user.name", "release-bot")
+	git(dir, "config", "user.email", "release-bot@example.com")
+	g.Expect(os.MkdirAll(filepath.Join(dir, "harbor"), 0755)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "harbor", artifacts.VersionsFile), []byte("stable: v2.12.2\n"), 0644)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "harbor", artifacts.ArtifactsFile), []byte(`channels:
+  - channel: stable
+    version: v2.12.2
+    artifacts:
+      - repository: devops/harbor-bundle
+        tag: v2.12.2
+        digest: sha256:old
+`), 0644)).To(Succeed())
+	git(dir, "add", ".")
+	git(dir, "commit", "-m", "init")
+	git(dir, "push", "origin", "main")
+	// a local commit which is not pushed must not be released
+	g.Expect(os.WriteFile(filepath.Join(dir, "local.txt"), []byte("local\n"), 0644)).To(Succeed())
+	git(dir, "add", "local.txt")
+	git(dir, "commit", "-m", "local")
+
+	var created bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		created = true
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":12,"html_url":"https://github.com/org/artifacts/pull/12"}`)
+	}))
+	defer ts.Close()
+
+	plan := &Plan{
+		Plugin:       "harbor",
+		Version:      "v2.13.0",
+		ArtifactsDir: dir,
+		Channels:     []string{"stable"},
+		Artifacts:    []artifacts.ArtifactUpdate{{Channel: "stable", Repository: "devops/harbor-bundle", Digest: "sha256:new"}},
+		PullRequest:  &PullRequestPlan{Repository: "org/artifacts"},
+		Jira:         &JiraPlan{Issue: "DEVOPS-1", Transitions: []string{"In Progress", "Done"}},
+	}
+	plan.SetDefaults()
+	g.Expect(plan.Validate()).To(Succeed())
+
+	jira := &fakeTransitioner{}
+	steps := Steps(plan, Dependencies{GitHub: &github.Client{Client: ts.Client(), BaseURL: ts.URL}, Jira: jira})
+	names := make([]string, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	g.Expect(names).To(Equal([]string{StepBumpVersions, StepUpdateArtifacts, StepPushBranch, StepOpenPullRequest, StepTransitionJira}))
+
+	record := &Record{}
+	g.Expect(record.Prepare(plan, steps)).To(Succeed())
+	g.Expect((&Runner{Steps: steps, Record: record}).Run(ctx)).To(Succeed())
+
+	g.Expect(created).To(BeTrue())
+	g.Expect(jira.transitions).To(Equal([]string{"DEVOPS-1:In Progress", "DEVOPS-1:Done"}))
+	g.Expect(record.Step(StepOpenPullRequest).Outputs).To(Equal(map[string]string{"number": "12", "url": "https://github.com/org/artifacts/pull/12"}))
+
+	pushed := git(dir, "rev-parse", "origin/release/harbor-v2.13.0")
+	g.Expect(record.Step(StepPushBranch).Outputs["commit"]).To(Equal(pushed))
+	g.Expect(git(dir, "show", pushed+":harbor/versions.yaml")).To(Equal("stable: v2.13.0"))
+	g.Expect(git(dir, "log", "-1", "--format=%s", pushed)).To(Equal("chore(harbor): release v2.13.0"))
+	g.Expect(git(dir, "rev-parse", pushed+"^")).To(Equal(git(dir, "rev-parse", "origin/main")))
+
+	// pushing again without changes does not create an empty commit
+	_, err := pushBranch(ctx, ExecGit, plan)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(git(dir, "rev-parse", "HEAD")).To(Equal(pushed))
+}
+
+func TestSteps_ResumeJiraTransitions(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	// the fake Jira only offers the transitions of the current status, and fails the first Resolve
+	transitions := map[string]string{
+		"To Do":       `[{"id":"11","name":"Start Progress","to":{"name":"In Progress"}}]`,
+		"In Progress": `[{"id":"21","name":"Resolve","to":{"name":"Done"}}]`,
+		"Done":        `[]`,
+	}
+	targets := map[string]string{"11": "In Progress", "21": "Done"}
+	status := "To Do"
+	failResolve := true
+	var applied []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/2/issue/DEVOPS-1":
+			fmt.Fprintf(w, `{"id":"10000","key":"DEVOPS-1","fields":{"status":{"name":%q}}}`, status)
+		case r.URL.Path == "/rest/api/2/issue/DEVOPS-1/transitions" && r.Method == http.MethodGet:
+			fmt.Fprintf(w, `{"transitions":%s}`, transitions[status])
+		case r.URL.Path == "/rest/api/2/issue/DEVOPS-1/transitions" && r.Method == http.MethodPost:
+			var payload struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			g.Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			if payload.Transition.ID == "21" && failResolve {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			g.Expect(strings.Contains(transitions[status], `"id":"`+payload.Transition.ID+`"`)).To(BeTrue())
+			applied = append(applied, payload.Transition.ID)
+			status = targets[payload.Transition.ID]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := jira.NewClient(ts.URL, "user", "pass")
+	g.Expect(err).ToNot(HaveOccurred())
+	plan := &Plan{Plugin: "harbor", Version: "v2.13.0", Jira: &JiraPlan{Issue: "DEVOPS-1", Transitions: []string{"Start Progress", "Resolve"}}}
+	steps := Steps(plan, Dependencies{Jira: client})
+	record := &Record{}
+	g.Expect(record.Prepare(plan, steps)).To(Succeed())
+
+	g.Expect((&Runner{Steps: steps, Record: record}).Run(ctx)).To(MatchError(ContainSubstring("step transition-jira failed")))
+	g.Expect(status).To(Equal("In Progress"))
+	g.Expect(record.Step(StepTransitionJira).Outputs).To(Equal(map[string]string{"applied": "1"}))
+
+	// Start Progress is not offered from In Progress anymore, the resumed run skips it
+	failResolve = false
+	g.Expect((&Runner{Steps: steps, Record: record}).Run(ctx)).To(Succeed())
+	g.Expect(status).To(Equal("Done"))
+	g.Expect(applied).To(Equal([]string{"11", "21"}))
+	g.Expect(record.Step(StepTransitionJira).Outputs).To(Equal(map[string]string{"applied": "2", "status": "Resolve"}))
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Step is a resumable step of a release
+type Step struct {
+	// Name identifies the step in the record
+	Name string
+	// Description is a short human readable description
+	Description string
+	// Run carries out the step and returns outputs recorded for the user, also when it fails
+	// previous are the outputs of the last failed run, so that a resumed step can skip the work already done
+	// it must be safe to run again after a failure
+	Run func(ctx context.Context, previous map[string]string) (map[string]string, error)
+}
+
+// Runner runs the steps of a release in order and records their progress
+type Runner struct {
+	Steps  []Step
+	Record *Record
+	// Save persists the record after each step, so a failed release can be resumed
+	Save func(*Record) error
+	// Now returns the current time, defaults to time.Now
+	Now func() time.Time
+}
+
+// Run runs the steps which did not succeed yet, stopping at the first failure
+func (r *Runner) Run(ctx context.Context) error {
+	now := r.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	for _, step := range r.Steps {
+		record := r.Record.Step(step.Name)
+		if record.Status == StepStatusSucceeded {
+			continue
+		}
+
+		record.StartedAt = now()
+		outputs, err := step.Run(ctx, record.Outputs)
+		record.FinishedAt = now()
+		record.Outputs = outputs
+		record.Error = ""
+		record.Status = StepStatusSucceeded
+		if err != nil {
+			record.Status = StepStatusFailed
+			record.Error = err.Error()
+		}
+
+		if saveErr := r.save(); saveErr != nil {
+			return saveErr
+		}
+		if err != nil {
+			return fmt.Errorf("step %s failed: %w", step.Name, err)
+		}
+	}
+	return nil
+}
+
+// save persists the record if a save function is set
+func (r *Runner) save() error {
+	if r.Save == nil {
+		return nil
+	}
+	return r.Save(r.Record)
+}
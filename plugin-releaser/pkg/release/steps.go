@@ -0,0 +1,202 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/artifacts"
+	"github.com/AlaudaDevops/toolbox/plugin-releaser/pkg/github"
+)
+
+// Names of the release steps
+const (
+	StepBumpVersions    = "bump-versions"
+	StepUpdateArtifacts = "update-artifacts"
+	StepPushBranch      = "push-branch"
+	StepOpenPullRequest = "open-pull-request"
+	StepTransitionJira  = "transition-jira"
+)
+
+// GitFunc runs a git command in a directory and returns its trimmed output
+type GitFunc func(ctx context.Context, dir string, args ...string) (string, error)
+
+// Transitioner moves a Jira issue through a transition
+type Transitioner interface {
+	TransitionIssue(ctx context.Context, key, name string) error
+}
+
+// Dependencies are the clients used by the release steps
+type Dependencies struct {
+	// Git runs git commands, defaults to ExecGit
+	Git GitFunc
+	// GitHub opens the pull request, required if the plan has a pull request
+	GitHub *github.Client
+	// Jira moves the issue, required if the plan has jira transitions
+	Jira Transitioner
+}
+
+// ExecGit runs the git binary in a directory
+func ExecGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Steps returns the steps carrying out the plan, in order
+// steps without configuration in the plan are omitted
+func Steps(plan *Plan, deps Dependencies) []Step {
+	git := deps.Git
+	if git == nil {
+		git = ExecGit
+	}
+
+	var steps []Step
+	if len(plan.Channels) > 0 {
+		steps = append(steps, Step{
+			Name:        StepBumpVersions,
+			Description: fmt.Sprintf("set %s to %s in %s", strings.Join(plan.Channels, ", "), plan.Version, artifacts.VersionsFile),
+			Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+				changed, err := artifacts.SetVersions(plan.ArtifactsDir, plan.Plugin, plan.Version, plan.Channels...)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]string{"changed": strconv.FormatBool(changed)}, nil
+			},
+		})
+	}
+
+	if len(plan.Artifacts) > 0 {
+		steps = append(steps, Step{
+			Name:        StepUpdateArtifacts,
+			Description: fmt.Sprintf("update %d artifacts in %s", len(plan.Artifacts), artifacts.ArtifactsFile),
+			Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+				changed, err := artifacts.UpdateArtifacts(plan.ArtifactsDir, plan.Plugin, plan.Version, plan.Artifacts...)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]string{"changed": strconv.FormatBool(changed)}, nil
+			},
+		})
+	}
+
+	if pr := plan.PullRequest; pr != nil {
+		steps = append(steps, Step{
+			Name:        StepPushBranch,
+			Description: fmt.Sprintf("commit the changes of %s and push %s to %s", plan.Plugin, pr.Branch, pr.Remote),
+			Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+				return pushBranch(ctx, git, plan)
+			},
+		}, Step{
+			Name:        StepOpenPullRequest,
+			Description: fmt.Sprintf("open a pull request from %s to %s on %s", pr.Branch, pr.Base, pr.Repository),
+			Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+				if deps.GitHub == nil {
+					return nil, fmt.Errorf("github client is not configured")
+				}
+				created, err := deps.GitHub.CreatePullRequest(ctx, pr.Repository, github.NewPullRequest{
+					Title: pr.Title,
+					Head:  pr.Branch,
+					Base:  pr.Base,
+					Body:  pr.Body,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return map[string]string{"number": strconv.Itoa(created.Number), "url": created.HTMLURL}, nil
+			},
+		})
+	}
+
+	if jira := plan.Jira; jira != nil {
+		steps = append(steps, Step{
+			Name:        StepTransitionJira,
+			Description: fmt.Sprintf("move %s through %s", jira.Issue, strings.Join(jira.Transitions, " -> ")),
+			Run: func(ctx context.Context, previous map[string]string) (map[string]string, error) {
+				if deps.Jira == nil {
+					return nil, fmt.Errorf("jira client is not configured")
+				}
+				// the transitions applied by a failed run are skipped, they are usually not offered
+				// from the status they lead to
+				applied, _ := strconv.Atoi(previous["applied"])
+				for i := min(applied, len(jira.Transitions)); i < len(jira.Transitions); i++ {
+					if err := deps.Jira.TransitionIssue(ctx, jira.Issue, jira.Transitions[i]); err != nil {
+						return map[string]string{"applied": strconv.Itoa(i)}, err
+					}
+				}
+				return map[string]string{
+					"applied": strconv.Itoa(len(jira.Transitions)),
+					"status":  jira.Transitions[len(jira.Transitions)-1],
+				}, nil
+			},
+		})
+	}
+
+	return steps
+}
+
+// pushBranch commits the changes of the plugin folder on the release branch and pushes it
+// the branch starts from the fetched base so that local commits are not released
+// the commit is skipped when there are no changes, e.g. when resuming after a failed push
+func pushBranch(ctx context.Context, git GitFunc, plan *Plan) (map[string]string, error) {
+	pr := plan.PullRequest
+	dir := plan.ArtifactsDir
+
+	if _, err := git(ctx, dir, "fetch", pr.Remote, pr.Base); err != nil {
+		return nil, err
+	}
+	base := pr.Remote + "/" + pr.Base
+	if _, err := git(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+pr.Branch); err != nil {
+		if _, err := git(ctx, dir, "checkout", "-B", pr.Branch, base); err != nil {
+			return nil, err
+		}
+	} else {
+		// the branch of a failed run keeps its commit, moved onto the latest base
+		if _, err := git(ctx, dir, "rebase", "--autostash", base, pr.Branch); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := git(ctx, dir, "add", "--", plan.Plugin); err != nil {
+		return nil, err
+	}
+	status, err := git(ctx, dir, "status", "--porcelain", "--", plan.Plugin)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		if _, err := git(ctx, dir, "commit", "-m", pr.Title, "--", plan.Plugin); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := git(ctx, dir, "push", "-u", pr.Remote, pr.Branch); err != nil {
+		return nil, err
+	}
+
+	commit, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"branch": pr.Branch, "commit": commit}, nil
+}
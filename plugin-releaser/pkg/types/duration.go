@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration that can be written in YAML as a Go duration (e.g. "72h")
+// or as a number of days or weeks (e.g. "30d", "2w")
+type Duration time.Duration
+
+// ParseDuration parses a Go duration or a number of days ("d") or weeks ("w")
+func ParseDuration(value string) (Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	if unit, ok := units[value[len(value)-1:]]; ok {
+		count, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		return Duration(time.Duration(count) * unit), nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	return Duration(duration), nil
+}
+
+// String returns the duration in days when it is a whole number of days
+func (d Duration) String() string {
+	day := 24 * time.Hour
+	if d > 0 && time.Duration(d)%day == 0 {
+		return fmt.Sprintf("%dd", time.Duration(d)/day)
+	}
+	return time.Duration(d).String()
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	duration, err := ParseDuration(node.Value)
+	if err != nil {
+		return err
+	}
+	*d = duration
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (d Duration) MarshalYAML() (interface{}, error) {
+	if d == 0 {
+		return "", nil
+	}
+	return d.String(), nil
+}
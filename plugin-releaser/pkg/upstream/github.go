@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubURL is the GitHub API URL
+const DefaultGitHubURL = "https://api.github.com"
+
+// GitHubSource lists the releases of a GitHub repository
+type GitHubSource struct {
+	Client *http.Client
+	// BaseURL is the API URL, defaults to DefaultGitHubURL
+	BaseURL string
+	// Repository is the repository as owner/repo
+	Repository string
+	// Token is an optional API token
+	Token string
+}
+
+var _ Source = &GitHubSource{}
+
+// githubRelease is a release returned by the GitHub API
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+}
+
+// Releases returns the latest 100 published releases of the repository
+func (s *GitHubSource) Releases(ctx context.Context) ([]Release, error) {
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGitHubURL
+	}
+	if strings.Count(s.Repository, "/") != 1 {
+		return nil, fmt.Errorf("invalid github repository %q, expected owner/repo", s.Repository)
+	}
+
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if s.Token != "" {
+		headers["Authorization"] = "Bearer " + s.Token
+	}
+
+	var githubReleases []githubRelease
+	endpoint := fmt.Sprintf("%s/repos/%s/releases?per_page=100", strings.TrimSuffix(baseURL, "/"), (&url.URL{Path: s.Repository}).EscapedPath())
+	if _, err := getJSON(ctx, s.Client, endpoint, headers, &githubReleases); err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, 0, len(githubReleases))
+	for _, release := range githubReleases {
+		if release.Draft {
+			continue
+		}
+		releases = append(releases, Release{
+			Tag:         release.TagName,
+			URL:         release.HTMLURL,
+			PublishedAt: release.PublishedAt,
+			Prerelease:  release.Prerelease,
+		})
+	}
+	return releases, nil
+}
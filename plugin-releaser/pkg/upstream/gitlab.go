@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitLabURL is the GitLab URL
+const DefaultGitLabURL = "https://gitlab.com"
+
+// GitLabSource lists the releases of a GitLab project
+type GitLabSource struct {
+	Client *http.Client
+	// BaseURL is the GitLab URL, defaults to DefaultGitLabURL
+	BaseURL string
+	// Project is the project path, e.g. gitlab-org/gitlab
+	Project string
+	// Token is an optional API token
+	Token string
+}
+
+var _ Source = &GitLabSource{}
+
+// gitlabRelease is a release returned by the GitLab API
+type gitlabRelease struct {
+	TagName         string    `json:"tag_name"`
+	ReleasedAt      time.Time `json:"released_at"`
+	UpcomingRelease bool      `json:"upcoming_release"`
+	Links           struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// Releases returns the latest 100 releases of the project, upcoming releases are ignored
+func (s *GitLabSource) Releases(ctx context.Context) ([]Release, error) {
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+	if s.Project == "" {
+		return nil, fmt.Errorf("gitlab project is required")
+	}
+
+	headers := map[string]string{}
+	if s.Token != "" {
+		headers["PRIVATE-TOKEN"] = s.Token
+	}
+
+	var gitlabReleases []gitlabRelease
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/releases?per_page=100", strings.TrimSuffix(baseURL, "/"), url.PathEscape(s.Project))
+	if _, err := getJSON(ctx, s.Client, endpoint, headers, &gitlabReleases); err != nil {
+		return nil, err
+	}
+
+	releases := make([]Release, 0, len(gitlabReleases))
+	for _, release := range gitlabReleases {
+		if release.UpcomingRelease {
+			continue
+		}
+		releases = append(releases, Release{
+			Tag:         release.TagName,
+			URL:         release.Links.Self,
+			PublishedAt: release.ReleasedAt,
+		})
+	}
+	return releases, nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HelmSource lists the versions of a chart in a Helm repository index
+type HelmSource struct {
+	Client *http.Client
+	// RepositoryURL is the Helm repository URL, index.yaml is read from it
+	RepositoryURL string
+	// Chart is the chart name
+	Chart string
+	// AppVersion uses the appVersion of the charts instead of the chart version
+	AppVersion bool
+}
+
+var _ Source = &HelmSource{}
+
+// helmIndex is the subset of a Helm repository index.yaml used to list versions
+type helmIndex struct {
+	Entries map[string][]struct {
+		Version    string    `yaml:"version"`
+		AppVersion string    `yaml:"appVersion"`
+		Created    time.Time `yaml:"created"`
+	} `yaml:"entries"`
+}
+
+// Releases returns the versions of the chart
+func (s *HelmSource) Releases(ctx context.Context) ([]Release, error) {
+	endpoint := strings.TrimSuffix(s.RepositoryURL, "/") + "/index.yaml"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: status %d", endpoint, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", endpoint, err)
+	}
+	var index helmIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", endpoint, err)
+	}
+
+	entries, ok := index.Entries[s.Chart]
+	if !ok {
+		return nil, fmt.Errorf("chart %s not found in %s", s.Chart, endpoint)
+	}
+
+	releases := make([]Release, 0, len(entries))
+	for _, entry := range entries {
+		tag := entry.Version
+		if s.AppVersion {
+			tag = entry.AppVersion
+		}
+		if tag == "" {
+			continue
+		}
+		releases = append(releases, Release{Tag: tag, PublishedAt: entry.Created})
+	}
+	return releases, nil
+}
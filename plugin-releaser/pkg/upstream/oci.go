@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// dockerHubRegistry is the registry API host of Docker Hub
+const dockerHubRegistry = "registry-1.docker.io"
+
+// linkNextPattern extracts the next page of a Link header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// challengePattern extracts the parameters of a WWW-Authenticate Bearer challenge
+var challengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// OCISource lists the tags of an OCI repository using the distribution API
+type OCISource struct {
+	Client *http.Client
+	// RegistryURL overrides the registry URL, defaults to https://<registry host>
+	RegistryURL string
+	// Repository is the repository including the registry host, e.g. ghcr.io/org/image
+	Repository string
+	// Username and Token are the optional credentials used to request a registry token
+	Username string
+	Token    string
+}
+
+var _ Source = &OCISource{}
+
+// Releases returns all the tags of the repository
+func (s *OCISource) Releases(ctx context.Context) ([]Release, error) {
+	registry, name, err := s.registryAndName()
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []Release{}
+	endpoint := fmt.Sprintf("%s/v2/%s/tags/list?n=1000", registry, name)
+	bearer := ""
+	for endpoint != "" {
+		var tags struct {
+			Tags []string `json:"tags"`
+		}
+		header, token, err := s.get(ctx, endpoint, bearer, &tags)
+		if err != nil {
+			return nil, err
+		}
+		bearer = token
+		for _, tag := range tags.Tags {
+			releases = append(releases, Release{Tag: tag})
+		}
+
+		endpoint = ""
+		if match := linkNextPattern.FindStringSubmatch(header.Get("Link")); match != nil {
+			next, err := url.Parse(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid next link %q: %w", match[1], err)
+			}
+			base, _ := url.Parse(registry)
+			endpoint = base.ResolveReference(next).String()
+		}
+	}
+	return releases, nil
+}
+
+// registryAndName splits the repository into the registry URL and the repository name
+func (s *OCISource) registryAndName() (string, string, error) {
+	host, name, found := strings.Cut(s.Repository, "/")
+	if !found || name == "" {
+		return "", "", fmt.Errorf("invalid oci repository %q, expected <registry>/<name>", s.Repository)
+	}
+	if host == "docker.io" {
+		host = dockerHubRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+
+	registry := s.RegistryURL
+	if registry == "" {
+		registry = "https://" + host
+	}
+	return strings.TrimSuffix(registry, "/"), name, nil
+}
+
+// get sends a GET request and decodes the JSON response into out
+// if the registry answers with a Bearer challenge, a token is requested and the request is retried
+// Returns the response headers and the bearer token to reuse for the next requests
+func (s *OCISource) get(ctx context.Context, endpoint, bearer string, out any) (http.Header, string, error) {
+	resp, err := s.do(ctx, endpoint, bearer)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized && bearer == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return nil, "", fmt.Errorf("failed to get %s: unauthorized", endpoint)
+		}
+		if bearer, err = s.token(ctx, challenge); err != nil {
+			return nil, "", err
+		}
+		return s.get(ctx, endpoint, bearer, out)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, "", fmt.Errorf("failed to get %s: status %d: %s", endpoint, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s: %w", endpoint, err)
+	}
+	return resp.Header, bearer, nil
+}
+
+func (s *OCISource) do(ctx context.Context, endpoint, bearer string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", endpoint, err)
+	}
+	return resp, nil
+}
+
+// token requests a registry token for a Bearer challenge
+func (s *OCISource) token(ctx context.Context, challenge string) (string, error) {
+	params := map[string]string{}
+	for _, match := range challengePattern.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("invalid registry challenge %q", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if s.Token != "" {
+		req.SetBasicAuth(s.Username, s.Token)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get registry token: status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upstream detects the releases of the upstream project of a plugin
+// and compares them with the version currently shipped
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Type is the type of an upstream source
+type Type string
+
+const (
+	// TypeGitHub lists the releases of a GitHub repository
+	TypeGitHub Type = "github"
+	// TypeGitLab lists the releases of a GitLab project
+	TypeGitLab Type = "gitlab"
+	// TypeHelm lists the versions of a chart in a Helm repository index
+	TypeHelm Type = "helm"
+	// TypeOCI lists the tags of an OCI repository
+	TypeOCI Type = "oci"
+)
+
+// versionPlaceholder is replaced with the release tag in the changelog URL
+const versionPlaceholder = "<version>"
+
+// Config configures the upstream source of a plugin and how its versions are compared
+type Config struct {
+	// Type of the source: github, gitlab, helm or oci
+	Type Type `yaml:"type"`
+	// Repository is the GitHub owner/repo, the GitLab project path or the OCI repository (e.g. ghcr.io/org/image)
+	Repository string `yaml:"repository"`
+	// URL is the API URL for GitHub (default https://api.github.com) and GitLab (default https://gitlab.com),
+	// the Helm repository URL or the OCI registry URL (default https://<registry host>)
+	URL string `yaml:"url,omitempty"`
+	// Chart is the chart name in the Helm repository
+	Chart string `yaml:"chart,omitempty"`
+	// AppVersion compares the appVersion of Helm charts instead of the chart version
+	AppVersion bool `yaml:"appVersion,omitempty"`
+	// Username for OCI registries, the token is sent as password
+	Username string `yaml:"username,omitempty"`
+	// TokenEnv is the environment variable containing the API token
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// TagPattern selects the tags to consider, the first group, if any, is the version (e.g. ^gitlab-(.+)$)
+	TagPattern string `yaml:"tagPattern,omitempty"`
+	// Prerelease includes prereleases
+	Prerelease bool `yaml:"prerelease,omitempty"`
+	// ChangelogURL is the changelog link of a release, <version> is replaced with the release tag
+	// defaults to the release page for GitHub and GitLab
+	ChangelogURL string `yaml:"changelogURL,omitempty"`
+	// Channel is the versions.yaml channel to compare with, defaults to the highest version of all channels
+	Channel string `yaml:"channel,omitempty"`
+}
+
+// Release is an upstream release
+type Release struct {
+	// Tag is the tag of the release
+	Tag string `json:"tag" yaml:"tag"`
+	// Version is the version extracted from the tag
+	Version string `json:"version" yaml:"version"`
+	// URL is the changelog or release notes link
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// PublishedAt is the publication time, zero if unknown
+	PublishedAt time.Time `json:"publishedAt,omitempty" yaml:"publishedAt,omitempty"`
+	// Prerelease is true if the source flags the release as a prerelease
+	Prerelease bool `json:"prerelease,omitempty" yaml:"prerelease,omitempty"`
+
+	semver *semver.Version
+}
+
+// Source lists the releases of an upstream project
+type Source interface {
+	Releases(ctx context.Context) ([]Release, error)
+}
+
+// NewSource creates the source configured by cfg
+// client: the HTTP client used for the requests, http.DefaultClient if nil
+func NewSource(cfg Config, client *http.Client) (Source, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	token := ""
+	if cfg.TokenEnv != "" {
+		token = os.Getenv(cfg.TokenEnv)
+	}
+
+	switch cfg.Type {
+	case TypeGitHub:
+		return &GitHubSource{Client: client, BaseURL: cfg.URL, Repository: cfg.Repository, Token: token}, nil
+	case TypeGitLab:
+		return &GitLabSource{Client: client, BaseURL: cfg.URL, Project: cfg.Repository, Token: token}, nil
+	case TypeHelm:
+		if cfg.URL == "" || cfg.Chart == "" {
+			return nil, fmt.Errorf("helm upstream requires url and chart")
+		}
+		return &HelmSource{Client: client, RepositoryURL: cfg.URL, Chart: cfg.Chart, AppVersion: cfg.AppVersion}, nil
+	case TypeOCI:
+		return &OCISource{Client: client, RegistryURL: cfg.URL, Repository: cfg.Repository, Username: cfg.Username, Token: token}, nil
+	default:
+		return nil, fmt.Errorf("unknown upstream type %q", cfg.Type)
+	}
+}
+
+// Gap is the difference between the shipped version and the upstream releases
+type Gap struct {
+	// Current is the shipped version
+	Current string `json:"current" yaml:"current"`
+	// Latest is the latest upstream release
+	Latest Release `json:"latest" yaml:"latest"`
+	// Releases are the upstream releases newer than the shipped version, oldest first
+	Releases []Release `json:"releases" yaml:"releases"`
+}
+
+// Ahead returns true if upstream has releases newer than the shipped version
+func (g *Gap) Ahead() bool {
+	return len(g.Releases) > 0
+}
+
+// String returns a short description of the gap, e.g. "v1.2.0 -> v1.4.1 (3 releases)"
+func (g *Gap) String() string {
+	return fmt.Sprintf("%s -> %s (%d releases)", g.Current, g.Latest.Tag, len(g.Releases))
+}
+
+// Changelogs returns the changelog links of the releases in the gap
+func (g *Gap) Changelogs() []string {
+	links := []string{}
+	for _, release := range g.Releases {
+		if release.URL != "" {
+			links = append(links, release.URL)
+		}
+	}
+	return links
+}
+
+// Detect lists the upstream releases and compares them with the current version
+func Detect(ctx context.Context, source Source, cfg Config, current string) (*Gap, error) {
+	releases, err := source.Releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(releases, cfg, current)
+}
+
+// Compare filters the releases according to cfg and compares them with the current version
+// only the major, minor and patch of the current version are compared,
+// so v2.13.0-beta.56.g8b08d33 is considered up to date with an upstream v2.13.0
+func Compare(releases []Release, cfg Config, current string) (*Gap, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid current version %q: %w", current, err)
+	}
+	currentCore := semver.New(currentVersion.Major(), currentVersion.Minor(), currentVersion.Patch(), "", "")
+
+	filtered, err := filterReleases(releases, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no upstream release found")
+	}
+
+	gap := &Gap{Current: current, Latest: filtered[len(filtered)-1], Releases: []Release{}}
+	for _, release := range filtered {
+		if release.semver.GreaterThan(currentCore) {
+			gap.Releases = append(gap.Releases, release)
+		}
+	}
+	return gap, nil
+}
+
+// filterReleases parses the versions of the releases, drops the ones excluded by cfg
+// and returns them sorted by version, oldest first
+func filterReleases(releases []Release, cfg Config) ([]Release, error) {
+	var pattern *regexp.Regexp
+	if cfg.TagPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(cfg.TagPattern); err != nil {
+			return nil, fmt.Errorf("invalid tag pattern %q: %w", cfg.TagPattern, err)
+		}
+	}
+
+	seen := map[string]bool{}
+	filtered := []Release{}
+	for _, release := range releases {
+		version := release.Tag
+		if pattern != nil {
+			match := pattern.FindStringSubmatch(release.Tag)
+			if match == nil {
+				continue
+			}
+			if len(match) > 1 {
+				version = match[1]
+			}
+		}
+
+		parsed, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if (release.Prerelease || parsed.Prerelease() != "") && !cfg.Prerelease {
+			continue
+		}
+		if seen[parsed.String()] {
+			continue
+		}
+		seen[parsed.String()] = true
+
+		release.Version = version
+		release.semver = parsed
+		if cfg.ChangelogURL != "" {
+			release.URL = strings.ReplaceAll(cfg.ChangelogURL, versionPlaceholder, release.Tag)
+		}
+		filtered = append(filtered, release)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].semver.LessThan(filtered[j].semver) })
+	return filtered, nil
+}
+
+// CurrentVersion returns the shipped version of a channel in versions.yaml
+// if channel is empty, the highest version of all channels is returned
+func CurrentVersion(versions map[string]string, channel string) (string, error) {
+	if channel != "" {
+		version, ok := versions[channel]
+		if !ok || version == "" {
+			return "", fmt.Errorf("channel %q not found in versions", channel)
+		}
+		return version, nil
+	}
+
+	var highest *semver.Version
+	current := ""
+	for _, version := range versions {
+		parsed, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if highest == nil || parsed.GreaterThan(highest) {
+			highest, current = parsed, version
+		}
+	}
+	if current == "" {
+		return "", fmt.Errorf("no valid version found in versions")
+	}
+	return current, nil
+}
+
+// getJSON sends a GET request and decodes the JSON response into out
+// Returns the response headers
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, out any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.Header, fmt.Errorf("failed to get %s: status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.Header, fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2025 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompare(t *testing.T) {
+	releases := []Release{
+		{Tag: "v1.4.0", URL: "https://example.com/v1.4.0"},
+		{Tag: "v1.2.0"},
+		{Tag: "v1.5.0-rc.1"},
+		{Tag: "v1.3.1", Prerelease: true},
+		{Tag: "v1.3.0"},
+		{Tag: "latest"},
+	}
+
+	tests := []struct {
+		name           string
+		cfg            Config
+		current        string
+		expectedLatest string
+		expectedGap    []string
+		wantErr        bool
+	}{
+		{
+			name:           "upstream ahead",
+			current:        "v1.2.0",
+			expectedLatest: "v1.4.0",
+			expectedGap:    []string{"v1.3.0", "v1.4.0"},
+		},
+		{
+			name:           "only the core of the current version is compared",
+			current:        "v1.4.0-beta.56.g8b08d33",
+			expectedLatest: "v1.4.0",
+			expectedGap:    []string{},
+		},
+		{
+			name:           "prereleases included",
+			cfg:            Config{Prerelease: true},
+			current:        "v1.4.0",
+			expectedLatest: "v1.5.0-rc.1",
+			expectedGap:    []string{"v1.5.0-rc.1"},
+		},
+		{
+			name:    "invalid current version",
+			current: "main",
+			wantErr: true,
+		},
+		{
+			name:    "no matching release",
+			cfg:     Config{TagPattern: `^gitlab-(.+)$`},
+			current: "v1.0.0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			gap, err := Compare(releases, tt.cfg, tt.current)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(gap.Latest.Tag).To(Equal(tt.expectedLatest))
+
+			tags := []string{}
+			for _, release := range gap.Releases {
+				tags = append(tags, release.Tag)
+			}
+			g.Expect(tags).To(Equal(tt.expectedGap))
+			g.Expect(gap.Ahead()).To(Equal(len(tt.expectedGap) > 0))
+		})
+	}
+}
+
+func TestCompare_TagPatternAndChangelog(t *testing.T) {
+	g := NewWithT(t)
+
+	releases := []Release{{Tag: "gitlab-17.1.0"}, {Tag: "runner-18.0.0"}, {Tag: "gitlab-17.0.2"}}
+	cfg := Config{TagPattern: `^gitlab-(.+)$`, ChangelogURL: "https://example.com/releases/<version>"}
+	gap, err := Compare(releases, cfg, "v17.0.2")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gap.Latest.Version).To(Equal("17.1.0"))
+	g.Expect(gap.String()).To(Equal("v17.0.2 -> gitlab-17.1.0 (1 releases)"))
+	g.Expect(gap.Changelogs()).To(Equal([]string{"https://example.com/releases/gitlab-17.1.0"}))
+}
+
+func TestCurrentVersion(t *testing.T) {
+	g := NewWithT(t)
+	versions := map[string]string{"alpha": "v1.3.0-beta.1.gabc", "stable": "v1.2.0", "broken": "main"}
+
+	current, err := CurrentVersion(versions, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(current).To(Equal("v1.3.0-beta.1.gabc"))
+
+	current, err = CurrentVersion(versions, "stable")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(current).To(Equal("v1.2.0"))
+
+	_, err = CurrentVersion(versions, "missing")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGitHubSource_Releases(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(Equal("/repos/goharbor/harbor/releases"))
+		g.Expect(r.Header.Get("Authorization")).To(Equal("Bearer secret"))
+		fmt.Fprintln(w, `[
+			{"tag_name":"v2.13.1","html_url":"https://github.com/goharbor/harbor/releases/tag/v2.13.1","published_at":"2025-06-01T00:00:00Z"},
+			{"tag_name":"v2.14.0-rc1","prerelease":true},
+			{"tag_name":"v2.14.0","draft":true}
+		]`)
+	}))
+	defer ts.Close()
+
+	source := &GitHubSource{Client: ts.Client(), BaseURL: ts.URL, Repository: "goharbor/harbor", Token: "secret"}
+	releases, err := source.Releases(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(releases).To(HaveLen(2))
+	g.Expect(releases[0].Tag).To(Equal("v2.13.1"))
+	g.Expect(releases[0].URL).To(Equal("https://github.com/goharbor/harbor/releases/tag/v2.13.1"))
+	g.Expect(releases[1].Prerelease).To(BeTrue())
+}
+
+func TestGitLabSource_Releases(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.EscapedPath()).To(Equal("/api/v4/projects/gitlab-org%2Fgitlab/releases"))
+		g.Expect(r.Header.Get("PRIVATE-TOKEN")).To(Equal("secret"))
+		fmt.Fprintln(w, `[
+			{"tag_name":"v17.2.0-ee","upcoming_release":true},
+			{"tag_name":"v17.1.0-ee","released_at":"2025-06-01T00:00:00Z","_links":{"self":"https://gitlab.com/gitlab-org/gitlab/-/releases/v17.1.0-ee"}}
+		]`)
+	}))
+	defer ts.Close()
+
+	source := &GitLabSource{Client: ts.Client(), BaseURL: ts.URL, Project: "gitlab-org/gitlab", Token: "secret"}
+	releases, err := source.Releases(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(releases).To(HaveLen(1))
+	g.Expect(releases[0].Tag).To(Equal("v17.1.0-ee"))
+	g.Expect(releases[0].URL).To(Equal("https://gitlab.com/gitlab-org/gitlab/-/releases/v17.1.0-ee"))
+}
+
+func TestHelmSource_Releases(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(Equal("/charts/index.yaml"))
+		fmt.Fprint(w, `apiVersion: v1
+entries:
+  harbor:
+  - version: 1.17.1
+    appVersion: 2.13.1
+  - version: 1.17.0
+    appVersion: 2.13.0
+  other:
+  - version: 9.9.9
+`)
+	}))
+	defer ts.Close()
+
+	source := &HelmSource{Client: ts.Client(), RepositoryURL: ts.URL + "/charts/", Chart: "harbor"}
+	releases, err := source.Releases(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(releases).To(HaveLen(2))
+	g.Expect(releases[0].Tag).To(Equal("1.17.1"))
+
+	source.AppVersion = true
+	releases, err = source.Releases(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(releases[0].Tag).To(Equal("2.13.1"))
+
+	source.Chart = "missing"
+	_, err = source.Releases(context.Background())
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestOCISource_Releases(t *testing.T) {
+	g := NewWithT(t)
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			g.Expect(r.URL.Query().Get("scope")).To(Equal("repository:org/image:pull"))
+			fmt.Fprintln(w, `{"token":"registry-token"}`)
+		case "/v2/org/image/tags/list":
+			if r.Header.Get("Authorization") != "Bearer registry-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:org/image:pull"`, ts.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/org/image/tags/list?n=1000&last=v1.1.0>; rel="next"`)
+				fmt.Fprintln(w, `{"name":"org/image","tags":["v1.0.0","v1.1.0"]}`)
+				return
+			}
+			fmt.Fprintln(w, `{"name":"org/image","tags":["v1.2.0"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	source := &OCISource{Client: ts.Client(), RegistryURL: ts.URL, Repository: "ghcr.io/org/image"}
+	releases, err := source.Releases(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(releases).To(Equal([]Release{{Tag: "v1.0.0"}, {Tag: "v1.1.0"}, {Tag: "v1.2.0"}}))
+}
+
+func TestOCISource_DockerHub(t *testing.T) {
+	g := NewWithT(t)
+
+	registry, name, err := (&OCISource{Repository: "docker.io/nginx"}).registryAndName()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(registry).To(Equal("https://registry-1.docker.io"))
+	g.Expect(name).To(Equal("library/nginx"))
+
+	_, _, err = (&OCISource{Repository: "nginx"}).registryAndName()
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestNewSource(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, sourceType := range []Type{TypeGitHub, TypeGitLab, TypeOCI} {
+		source, err := NewSource(Config{Type: sourceType, Repository: "ghcr.io/org/image"}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(source).ToNot(BeNil())
+	}
+
+	_, err := NewSource(Config{Type: TypeHelm}, nil)
+	g.Expect(err).To(HaveOccurred())
+	_, err = NewSource(Config{Type: "svn"}, nil)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultConcurrency is the number of repositories or projects fetched at the same time
+	defaultConcurrency = 4
+	// maxRetries is the number of times a rate limited request is retried
+	maxRetries = 3
+	// maxRateLimitWait is the longest wait for a rate limit reset before giving up
+	maxRateLimitWait = 15 * time.Minute
+	// defaultRateLimitWait is the wait when a rate limited response does not tell when to retry
+	defaultRateLimitWait = time.Minute
+	// maxErrorBody is the size of a response body kept in error messages
+	maxErrorBody = 512
+)
+
+// APIError is returned when an API answers with an error status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// apiClient sends authenticated JSON requests to the GitHub or GitLab API
+// and retries the requests that are rate limited
+type apiClient struct {
+	httpClient *http.Client
+	// authHeader and authValue authenticate the requests, nothing is sent when authValue is empty
+	authHeader string
+	authValue  string
+	// rateLimited reports whether a successful response is a rate limit error, it can be nil
+	rateLimited func(body []byte) bool
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// newAPIClient creates a client authenticating with the given header
+func newAPIClient(authHeader, authValue string) *apiClient {
+	return &apiClient{
+		httpClient: &http.Client{Timeout: time.Minute},
+		authHeader: authHeader,
+		authValue:  authValue,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+}
+
+// sleepContext waits for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out
+// It returns the response headers, which hold the pagination links
+func (c *apiClient) do(ctx context.Context, method, rawURL string, body, out any) (http.Header, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.authValue != "" {
+			req.Header.Set(c.authHeader, c.authValue)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if wait, limited := c.rateLimitWait(resp, data); limited {
+			if attempt >= maxRetries {
+				return nil, &APIError{StatusCode: resp.StatusCode, Message: "rate limit exceeded"}
+			}
+			if wait > maxRateLimitWait {
+				return nil, fmt.Errorf("rate limit exceeded, resets in %s", wait.Round(time.Second))
+			}
+			if err := c.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			message := strings.TrimSpace(string(data))
+			if len(message) > maxErrorBody {
+				message = message[:maxErrorBody] + "..."
+			}
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: message}
+		}
+		if out != nil {
+			if err := json.Unmarshal(data, out); err != nil {
+				return nil, fmt.Errorf("failed to parse response: %w", err)
+			}
+		}
+		return resp.Header, nil
+	}
+}
+
+// rateLimitWait reports whether a response is rate limited and how long to wait before retrying
+// GitHub answers 403 or 429 with x-ratelimit-* headers, GitLab answers 429 with RateLimit-* headers
+func (c *apiClient) rateLimitWait(resp *http.Response, body []byte) (time.Duration, bool) {
+	limited := resp.StatusCode == http.StatusTooManyRequests
+	if resp.StatusCode == http.StatusForbidden {
+		limited = resp.Header.Get("Retry-After") != "" ||
+			resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			resp.Header.Get("RateLimit-Remaining") == "0"
+	}
+	if resp.StatusCode == http.StatusOK && c.rateLimited != nil {
+		limited = c.rateLimited(body)
+	}
+	if !limited {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	for _, header := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if reset, err := strconv.ParseInt(resp.Header.Get(header), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(c.now()), 0), true
+		}
+	}
+	return defaultRateLimitWait, true
+}
+
+// forEach calls fn for the indexes 0 to n-1 with at most concurrency calls running at the same time
+func forEach(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case semaphore <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAPIClientRateLimit(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// limit writes the rate limited response
+		limit         func(w http.ResponseWriter)
+		rateLimited   func(body []byte) bool
+		expectedWait  time.Duration
+		expectedError bool
+	}{
+		{
+			name: "GitHub secondary rate limit with Retry-After",
+			limit: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expectedWait: 30 * time.Second,
+		},
+		{
+			name: "GitHub primary rate limit until reset",
+			limit: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(2*time.Minute).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expectedWait: 2 * time.Minute,
+		},
+		{
+			name: "GitLab rate limit until reset",
+			limit: func(w http.ResponseWriter) {
+				w.Header().Set("RateLimit-Reset", strconv.FormatInt(now.Add(time.Minute).Unix(), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			expectedWait: time.Minute,
+		},
+		{
+			name: "GraphQL rate limit error",
+			limit: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(10*time.Second).Unix(), 10))
+				fmt.Fprint(w, `{"errors": [{"type": "RATE_LIMITED"}]}`)
+			},
+			rateLimited:  func(body []byte) bool { return string(body) == `{"errors": [{"type": "RATE_LIMITED"}]}` },
+			expectedWait: 10 * time.Second,
+		},
+		{
+			name: "reset too far away",
+			limit: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					tt.limit(w)
+					return
+				}
+				fmt.Fprint(w, `{"ok": true}`)
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			client := newAPIClient("Authorization", "")
+			client.rateLimited = tt.rateLimited
+			client.now = func() time.Time { return now }
+			client.sleep = func(ctx context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			var out struct {
+				OK bool `json:"ok"`
+			}
+			_, err := client.do(context.Background(), http.MethodGet, server.URL, nil, &out)
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("do() error = nil, want a rate limit error")
+				}
+				return
+			}
+			if err != nil || !out.OK {
+				t.Fatalf("do() = %+v, %v, want a retried request", out, err)
+			}
+			if !reflect.DeepEqual(waits, []time.Duration{tt.expectedWait}) {
+				t.Errorf("waits = %v, want [%v]", waits, tt.expectedWait)
+			}
+		})
+	}
+}
+
+func TestAPIClientGivesUpAfterRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newAPIClient("Authorization", "")
+	_, err := client.do(context.Background(), http.MethodGet, server.URL, nil, nil)
+	if err == nil {
+		t.Fatal("do() error = nil, want a rate limit error")
+	}
+	if got := requests.Load(); got != maxRetries+1 {
+		t.Errorf("requests = %d, want %d", got, maxRetries+1)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	var running, peak atomic.Int32
+	done := make([]bool, 20)
+	forEach(context.Background(), len(done), 3, func(ctx context.Context, i int) {
+		current := running.Add(1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		done[i] = true
+		running.Add(-1)
+	})
+
+	for i, ok := range done {
+		if !ok {
+			t.Errorf("index %d was not processed", i)
+		}
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", got)
+	}
+}
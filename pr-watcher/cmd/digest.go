@@ -0,0 +1,504 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// digestTemplates are the default templates of the digest messages
+//
+//go:embed templates/github-digest.tmpl templates/gitlab-digest.tmpl
+var digestTemplates embed.FS
+
+// digestMaxEntries is the number of pull requests shown by the digest templates,
+// the others are only recorded as notified when a later digest shows them
+const digestMaxEntries = 10
+
+// digestPullRequest is a pull request with the escalation tier it reached
+type digestPullRequest struct {
+	PullRequest
+	// Tier is the days of the tier reached
+	Tier int `json:"tier"`
+	// Notify are the usernames nagged
+	Notify []string `json:"notify"`
+	// Mentions are the users nagged as mentioned by the channel
+	Mentions []string `json:"mentions"`
+	// key is the key of the pull request in the state
+	key string
+}
+
+// digestMergeRequest is a merge request with the escalation tier it reached
+type digestMergeRequest struct {
+	GitLabMergeRequest
+	Tier     int      `json:"tier"`
+	Notify   []string `json:"notify"`
+	Mentions []string `json:"mentions"`
+	key      string
+}
+
+// githubDigest is the data of a GitHub digest template, a PRWatcherResult with the team
+type githubDigest struct {
+	Team         string              `json:"team"`
+	Lead         string              `json:"lead"`
+	Organization string              `json:"organization"`
+	ScanDate     time.Time           `json:"scan_date"`
+	MinDaysOpen  int                 `json:"min_days_open"`
+	TotalOldPRs  int                 `json:"total_old_prs"`
+	PullRequests []digestPullRequest `json:"pull_requests"`
+}
+
+// gitlabDigest is the data of a GitLab digest template, a GitLabWatcherResult with the team
+type gitlabDigest struct {
+	Team          string               `json:"team"`
+	Lead          string               `json:"lead"`
+	Group         string               `json:"group"`
+	ScanDate      time.Time            `json:"scan_date"`
+	MinDaysOpen   int                  `json:"min_days_open"`
+	TotalOldMRs   int                  `json:"total_old_mrs"`
+	MergeRequests []digestMergeRequest `json:"merge_requests"`
+}
+
+// teamDigest holds what a team is nagged about on a channel in a run
+type teamDigest struct {
+	Team    TeamConfig
+	Channel ChannelConfig
+	GitHub  githubDigest
+	GitLab  gitlabDigest
+}
+
+// digestCmd represents the digest command
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Send escalating digests of old pull requests to each team",
+	Long: `This command scans the GitHub organizations and GitLab groups of a config file,
+assigns the open pull requests and merge requests to the teams owning their repositories,
+and sends each team a digest to WeCom, Slack or email.
+
+Pull requests escalate through the tiers of the config, for example the author after
+7 days, the reviewers after 14 and the team lead after 30. A pull request is only
+sent again when it reaches a higher tier, the notifications are remembered in a state file.
+A digest shows the 10 most escalated pull requests, the others are sent by the next runs.
+
+Example:
+  pr-watcher digest --config digest.yaml
+  pr-watcher digest --config digest.yaml --dry-run`,
+	RunE: runDigest,
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().StringP("config", "c", "", "Digest config file (required)")
+	digestCmd.Flags().String("state", "", "State file (defaults to state_file of the config or "+defaultDigestStateFile+")")
+	digestCmd.Flags().Bool("dry-run", false, "Print the digests without sending them or updating the state")
+
+	digestCmd.MarkFlagRequired("config")
+}
+
+// runDigest executes the main logic for the digest command
+func runDigest(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	stateFile, _ := cmd.Flags().GetString("state")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	config, err := loadDigestConfig(configFile)
+	if err != nil {
+		return err
+	}
+	stateFile = firstNonEmpty(stateFile, config.StateFile, defaultDigestStateFile)
+	state, err := loadDigestState(stateFile)
+	if err != nil {
+		return err
+	}
+	githubTemplate, err := loadDigestTemplate("github-digest.tmpl", config.Templates.GitHub)
+	if err != nil {
+		return err
+	}
+	gitlabTemplate, err := loadDigestTemplate("gitlab-digest.tmpl", config.Templates.GitLab)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	var failures []string
+
+	prs, errs := fetchDigestPullRequests(ctx, config)
+	failures = append(failures, errs...)
+	mrs, errs := fetchDigestMergeRequests(ctx, config)
+	failures = append(failures, errs...)
+
+	digests := buildDigests(config, prs, mrs, state, now)
+	fmt.Printf("%d team channels have pull requests to nag about\n", len(digests))
+
+	for _, digest := range digests {
+		messages, err := digest.render(githubTemplate, gitlabTemplate)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			fmt.Printf("Would send to %s of team %s:\n", digest.Channel.Type, digest.Team.Name)
+			for _, message := range messages {
+				fmt.Printf("Subject: %s\n%s\n", message.subject, message.text)
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			channel := digest.Channel
+			if channel.Type == channelEmail {
+				// the users nagged receive the email as well
+				channel.To = appendNew(slices.Clone(channel.To), config.emails(message.users)...)
+			}
+			notifier, err := newNotifier(channel, config.Email)
+			if err != nil {
+				return err
+			}
+			// a message that failed is sent again by the next run, the other channels are not affected
+			if err := notifier.Notify(ctx, message.subject, message.text); err != nil {
+				fmt.Printf("Warning: team %s: %v\n", digest.Team.Name, err)
+				failures = append(failures, fmt.Sprintf("team %s: %v", digest.Team.Name, err))
+				continue
+			}
+			// only the pull requests shown are recorded
+			for key, tier := range message.notified {
+				state.notified(key, tier, now)
+			}
+			fmt.Printf("Sent %s to %s\n", message.subject, digest.Channel.Type)
+		}
+	}
+
+	if !dryRun {
+		state.prune(now)
+		if err := state.save(stateFile); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("digest completed with errors:\n%s", strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// fetchDigestPullRequests returns the open pull requests of the organizations of the config
+// An organization that cannot be read is reported in the errors and skipped
+func fetchDigestPullRequests(ctx context.Context, config *DigestConfig) ([]PullRequest, []string) {
+	if len(config.GitHub.Orgs) == 0 {
+		return nil, nil
+	}
+	token := firstNonEmpty(config.GitHub.Token, os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN"))
+	if token == "" {
+		return nil, []string{"a GitHub token is required, set github.token or the GITHUB_TOKEN environment variable"}
+	}
+	client := NewGitHubClient(config.GitHub.APIURL, token)
+
+	var prs []PullRequest
+	var errs []string
+	for _, org := range config.GitHub.Orgs {
+		fmt.Printf("Scanning organization '%s'...\n", org)
+		repos, err := client.ListOrgPullRequests(ctx, org, "open", config.Concurrency)
+		if err != nil {
+			fmt.Printf("Warning: failed to get repositories of %s: %v\n", org, err)
+			errs = append(errs, fmt.Sprintf("organization %s: %v", org, err))
+			continue
+		}
+		for _, repo := range repos {
+			if repo.Err != nil {
+				fmt.Printf("Warning: failed to get all PRs for %s: %v\n", repo.Name, repo.Err)
+			}
+			prs = append(prs, repo.PullRequests...)
+		}
+	}
+	return prs, errs
+}
+
+// fetchDigestMergeRequests returns the open merge requests of the groups of the config
+// A group that cannot be read is reported in the errors and skipped
+func fetchDigestMergeRequests(ctx context.Context, config *DigestConfig) ([]GitLabMergeRequest, []string) {
+	if len(config.GitLab.Groups) == 0 {
+		return nil, nil
+	}
+	token := firstNonEmpty(config.GitLab.Token, os.Getenv("GITLAB_TOKEN"))
+	if token == "" {
+		return nil, []string{"a GitLab token is required, set gitlab.token or the GITLAB_TOKEN environment variable"}
+	}
+	client := NewGitLabClient(firstNonEmpty(config.GitLab.Host, os.Getenv("GITLAB_HOST")), token)
+
+	var mrs []GitLabMergeRequest
+	var errs []string
+	for _, group := range config.GitLab.Groups {
+		fmt.Printf("Scanning GitLab group '%s'...\n", group)
+		_, groupMRs, err := listGroupMergeRequests(ctx, client, group, "opened", config.Concurrency)
+		if err != nil {
+			fmt.Printf("Warning: failed to get projects of %s: %v\n", group, err)
+			errs = append(errs, fmt.Sprintf("group %s: %v", group, err))
+			continue
+		}
+		mrs = append(mrs, groupMRs...)
+	}
+	return mrs, errs
+}
+
+// buildDigests assigns the pull requests and merge requests to the teams owning them
+// and keeps, for each channel of the team, those that reached an escalation tier not notified
+// to the channel yet, oldest first
+func buildDigests(config *DigestConfig, prs []PullRequest, mrs []GitLabMergeRequest, state *digestState, now time.Time) []*teamDigest {
+	var digests []*teamDigest
+	for _, team := range config.Teams {
+		tiers := config.escalation(team)
+		for _, channel := range team.Channels {
+			digest := &teamDigest{
+				Team:    team,
+				Channel: channel,
+				GitHub: githubDigest{
+					Team: team.Name, Lead: team.Lead, Organization: strings.Join(config.GitHub.Orgs, ", "),
+					ScanDate: now, MinDaysOpen: tiers[0].Days, PullRequests: []digestPullRequest{},
+				},
+				GitLab: gitlabDigest{
+					Team: team.Name, Lead: team.Lead, Group: strings.Join(config.GitLab.Groups, ", "),
+					ScanDate: now, MinDaysOpen: tiers[0].Days, MergeRequests: []digestMergeRequest{},
+				},
+			}
+			// the state is kept per channel so that a channel that failed does not resend the others
+			keyPrefix := team.Name + " " + channel.Name + " "
+
+			for _, pr := range prs {
+				if !team.owns(pr.Repository) || !shouldIncludePR(pr, tiers[0].Days, config.IncludeDrafts) {
+					continue
+				}
+				key := keyPrefix + pr.URL
+				state.seen(key, now)
+				level := escalationLevel(tiers, pr.DaysOpen)
+				if !state.due(key, tiers[level].Days) {
+					continue
+				}
+				notify := recipients(tiers[:level+1], pr.Author, slices.Concat(pr.Reviewers, pr.Assignees), team.Lead)
+				digest.GitHub.PullRequests = append(digest.GitHub.PullRequests, digestPullRequest{
+					PullRequest: pr,
+					Tier:        tiers[level].Days,
+					Notify:      notify,
+					Mentions:    config.mentions(channel.Type, notify),
+					key:         key,
+				})
+			}
+			for _, mr := range mrs {
+				if !team.owns(mr.Project) || !shouldIncludeMR(mr, tiers[0].Days, config.IncludeDrafts) {
+					continue
+				}
+				key := keyPrefix + mr.WebURL
+				state.seen(key, now)
+				level := escalationLevel(tiers, mr.DaysOpen)
+				if !state.due(key, tiers[level].Days) {
+					continue
+				}
+				notify := recipients(tiers[:level+1], mr.Author, slices.Concat(mr.Reviewers, mr.Assignees), team.Lead)
+				digest.GitLab.MergeRequests = append(digest.GitLab.MergeRequests, digestMergeRequest{
+					GitLabMergeRequest: mr,
+					Tier:               tiers[level].Days,
+					Notify:             notify,
+					Mentions:           config.mentions(channel.Type, notify),
+					key:                key,
+				})
+			}
+
+			if len(digest.GitHub.PullRequests) == 0 && len(digest.GitLab.MergeRequests) == 0 {
+				continue
+			}
+			// the templates show the first pull requests, so the most escalated come first
+			sort.SliceStable(digest.GitHub.PullRequests, func(i, j int) bool {
+				return digest.GitHub.PullRequests[i].DaysOpen > digest.GitHub.PullRequests[j].DaysOpen
+			})
+			sort.SliceStable(digest.GitLab.MergeRequests, func(i, j int) bool {
+				return digest.GitLab.MergeRequests[i].DaysOpen > digest.GitLab.MergeRequests[j].DaysOpen
+			})
+			digest.GitHub.TotalOldPRs = len(digest.GitHub.PullRequests)
+			digest.GitLab.TotalOldMRs = len(digest.GitLab.MergeRequests)
+			digests = append(digests, digest)
+		}
+	}
+	return digests
+}
+
+// escalationLevel returns the index of the highest tier reached, the tiers are sorted
+// and the pull request is at least as old as the first tier
+func escalationLevel(tiers []EscalationTier, daysOpen int) int {
+	level := 0
+	for i, tier := range tiers {
+		if daysOpen >= tier.Days {
+			level = i
+		}
+	}
+	return level
+}
+
+// recipients returns the usernames nagged by the tiers reached, each tier adds to the previous ones
+func recipients(tiers []EscalationTier, author string, reviewers []string, lead string) []string {
+	var users []string
+	add := func(user string) {
+		if user != "" && !slices.Contains(users, user) {
+			users = append(users, user)
+		}
+	}
+	for _, tier := range tiers {
+		switch tier.Notify {
+		case notifyAuthor:
+			add(author)
+		case notifyReviewers:
+			for _, reviewer := range reviewers {
+				add(reviewer)
+			}
+		case notifyLead:
+			add(lead)
+		}
+	}
+	return users
+}
+
+// appendNew appends the values that are not in the slice yet
+func appendNew(values []string, added ...string) []string {
+	for _, value := range added {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// digestMessage is a rendered digest
+type digestMessage struct {
+	subject string
+	text    string
+	// notified are the tier days of the state keys of the pull requests shown
+	notified map[string]int
+	// users are the usernames nagged by the pull requests shown
+	users []string
+}
+
+// render renders the GitHub and GitLab digests of the team that are not empty,
+// in the markdown of the channel
+func (d *teamDigest) render(githubTemplate, gitlabTemplate *template.Template) ([]digestMessage, error) {
+	var messages []digestMessage
+	if d.GitHub.TotalOldPRs > 0 {
+		text, err := renderDigest(githubTemplate, d.GitHub)
+		if err != nil {
+			return nil, err
+		}
+		notified := map[string]int{}
+		var users []string
+		for _, pr := range d.GitHub.PullRequests[:min(len(d.GitHub.PullRequests), digestMaxEntries)] {
+			notified[pr.key] = pr.Tier
+			users = appendNew(users, pr.Notify...)
+		}
+		messages = append(messages, digestMessage{subject: "GitHub PR digest for " + d.Team.Name, text: text, notified: notified, users: users})
+	}
+	if d.GitLab.TotalOldMRs > 0 {
+		text, err := renderDigest(gitlabTemplate, d.GitLab)
+		if err != nil {
+			return nil, err
+		}
+		notified := map[string]int{}
+		var users []string
+		for _, mr := range d.GitLab.MergeRequests[:min(len(d.GitLab.MergeRequests), digestMaxEntries)] {
+			notified[mr.key] = mr.Tier
+			users = appendNew(users, mr.Notify...)
+		}
+		messages = append(messages, digestMessage{subject: "GitLab MR digest for " + d.Team.Name, text: text, notified: notified, users: users})
+	}
+	// the templates are written in the markdown of WeCom
+	if d.Channel.Type == channelSlack {
+		for i := range messages {
+			messages[i].text = slackMarkdown(messages[i].text)
+		}
+	}
+	return messages, nil
+}
+
+// renderDigest executes a template on the JSON form of the data, like wecom-sender.go
+// so that the templates written for the watch-prs and watch-mrs output work
+func renderDigest(tmpl *template.Template, data any) (string, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal digest: %w", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(jsonData, &values); err != nil {
+		return "", fmt.Errorf("failed to parse digest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// loadDigestTemplate parses the template file, or the embedded template when file is empty
+func loadDigestTemplate(name, file string) (*template.Template, error) {
+	var content []byte
+	var err error
+	if file != "" {
+		content, err = os.ReadFile(file)
+	} else {
+		content, err = digestTemplates.ReadFile("templates/" + name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// templateFuncs are the functions of the templates, the same as in wecom-sender.go
+var templateFuncs = template.FuncMap{
+	"sub": func(a, b interface{}) float64 {
+		return toFloat64(a) - toFloat64(b)
+	},
+	"gt": func(a, b interface{}) bool {
+		return toFloat64(a) > toFloat64(b)
+	},
+	"lt": func(a, b interface{}) bool {
+		return toFloat64(a) < toFloat64(b)
+	},
+}
+
+// toFloat64 converts the numbers of the templates to float64, JSON numbers are float64 and indexes int
+func toFloat64(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case int:
+		return float64(val)
+	default:
+		return 0
+	}
+}
+
+var (
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	markdownBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownHeading = regexp.MustCompile(`(?m)^#{1,6} +(.+)$`)
+)
+
+// slackMarkdown converts the markdown of the templates to Slack mrkdwn
+func slackMarkdown(text string) string {
+	text = markdownLink.ReplaceAllString(text, "<$2|$1>")
+	text = markdownBold.ReplaceAllString(text, "*$1*")
+	return markdownHeading.ReplaceAllString(text, "*$1*")
+}
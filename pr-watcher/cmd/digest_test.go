@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscalationLevel(t *testing.T) {
+	tiers := []EscalationTier{{Days: 7, Notify: notifyAuthor}, {Days: 14, Notify: notifyReviewers}, {Days: 30, Notify: notifyLead}}
+
+	tests := []struct {
+		daysOpen int
+		expected int
+	}{
+		{daysOpen: 7, expected: 0},
+		{daysOpen: 13, expected: 0},
+		{daysOpen: 14, expected: 1},
+		{daysOpen: 90, expected: 2},
+	}
+
+	for _, tt := range tests {
+		if result := escalationLevel(tiers, tt.daysOpen); result != tt.expected {
+			t.Errorf("escalationLevel(%d) = %d, want %d", tt.daysOpen, result, tt.expected)
+		}
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	tiers := []EscalationTier{{Days: 7, Notify: notifyAuthor}, {Days: 14, Notify: notifyReviewers}, {Days: 30, Notify: notifyLead}}
+
+	tests := []struct {
+		name      string
+		tiers     []EscalationTier
+		author    string
+		reviewers []string
+		lead      string
+		expected  []string
+	}{
+		{
+			name:      "author tier",
+			tiers:     tiers[:1],
+			author:    "alice",
+			reviewers: []string{"bob"},
+			lead:      "lead",
+			expected:  []string{"alice"},
+		},
+		{
+			name:      "all tiers without duplicates",
+			tiers:     tiers,
+			author:    "alice",
+			reviewers: []string{"bob", "lead", "bob"},
+			lead:      "lead",
+			expected:  []string{"alice", "bob", "lead"},
+		},
+		{
+			name:     "deleted author and no lead",
+			tiers:    tiers,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := recipients(tt.tiers, tt.author, tt.reviewers, tt.lead)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("recipients() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildDigests(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	wecom := ChannelConfig{Name: "wecom-1", Type: channelWeCom, Webhook: "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=api"}
+	email := ChannelConfig{Name: "email-2", Type: channelEmail, To: []string{"api@example.com"}}
+	web := ChannelConfig{Name: "slack-1", Type: channelSlack, Webhook: "https://hooks.slack.com/services/web"}
+	config := &DigestConfig{
+		Escalation: []EscalationTier{{Days: 7, Notify: notifyAuthor}, {Days: 14, Notify: notifyReviewers}, {Days: 30, Notify: notifyLead}},
+		Teams: []TeamConfig{
+			{Name: "api", Lead: "lena", Repos: []string{"acme/api", "devops/api"}, Channels: []ChannelConfig{wecom, email}},
+			{Name: "web", Lead: "walt", Repos: []string{"acme/web"}, Channels: []ChannelConfig{web}},
+			{Name: "docs", Repos: []string{"acme/docs"}},
+		},
+		Users: map[string]UserConfig{"bob": {WeCom: "bob.wang"}},
+	}
+	config.GitHub.Orgs = []string{"acme"}
+	config.GitLab.Groups = []string{"devops"}
+
+	prs := []PullRequest{
+		{Repository: "acme/api", Number: 1, URL: "https://github.com/acme/api/pull/1", Author: "alice", DaysOpen: 8},
+		{Repository: "acme/api", Number: 2, URL: "https://github.com/acme/api/pull/2", Author: "bob", Reviewers: []string{"carol"}, DaysOpen: 31},
+		{Repository: "acme/api", Number: 3, URL: "https://github.com/acme/api/pull/3", Author: "dave", DaysOpen: 3},
+		{Repository: "acme/api", Number: 4, URL: "https://github.com/acme/api/pull/4", Author: "erin", DaysOpen: 20, Draft: true},
+		{Repository: "acme/web", Number: 5, URL: "https://github.com/acme/web/pull/5", Author: "frank", DaysOpen: 15},
+		{Repository: "acme/other", Number: 6, URL: "https://github.com/acme/other/pull/6", Author: "gina", DaysOpen: 40},
+	}
+	mrs := []GitLabMergeRequest{
+		{Project: "devops/api", IID: 7, WebURL: "https://gitlab.com/devops/api/-/merge_requests/7", Author: "hank", Assignees: []string{"ivy"}, DaysOpen: 14},
+	}
+
+	// the web pull request was already nagged at its tier, the first api pull request only on WeCom
+	wecomKey := "api wecom-1 "
+	emailKey := "api email-2 "
+	webKey := "web slack-1 https://github.com/acme/web/pull/5"
+	state := &digestState{Notifications: map[string]*notification{
+		webKey: {Tier: 14, NotifiedAt: now.Add(-24 * time.Hour), LastSeen: now.Add(-24 * time.Hour)},
+		wecomKey + "https://github.com/acme/api/pull/1": {Tier: 7, NotifiedAt: now.Add(-24 * time.Hour), LastSeen: now.Add(-24 * time.Hour)},
+	}}
+
+	digests := buildDigests(config, prs, mrs, state, now)
+	if len(digests) != 2 {
+		t.Fatalf("buildDigests() returned %d digests, want 2", len(digests))
+	}
+
+	tests := []struct {
+		channel     ChannelConfig
+		expectedPRs []digestPullRequest
+	}{
+		{
+			channel: wecom,
+			expectedPRs: []digestPullRequest{
+				{PullRequest: prs[1], Tier: 30, Notify: []string{"bob", "carol", "lena"}, Mentions: []string{"<@bob.wang>", "@carol", "@lena"}, key: wecomKey + "https://github.com/acme/api/pull/2"},
+			},
+		},
+		{
+			channel: email,
+			expectedPRs: []digestPullRequest{
+				{PullRequest: prs[1], Tier: 30, Notify: []string{"bob", "carol", "lena"}, Mentions: []string{"@bob", "@carol", "@lena"}, key: emailKey + "https://github.com/acme/api/pull/2"},
+				{PullRequest: prs[0], Tier: 7, Notify: []string{"alice"}, Mentions: []string{"@alice"}, key: emailKey + "https://github.com/acme/api/pull/1"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		digest := digests[i]
+		prefix := "api " + tt.channel.Name + " "
+		if !reflect.DeepEqual(digest.Channel, tt.channel) {
+			t.Errorf("Channel = %+v, want %+v", digest.Channel, tt.channel)
+		}
+		if !reflect.DeepEqual(digest.GitHub.PullRequests, tt.expectedPRs) {
+			t.Errorf("%s PullRequests = %+v, want %+v", tt.channel.Type, digest.GitHub.PullRequests, tt.expectedPRs)
+		}
+		expectedMRs := []digestMergeRequest{
+			{GitLabMergeRequest: mrs[0], Tier: 14, Notify: []string{"hank", "ivy"}, Mentions: []string{"@hank", "@ivy"}, key: prefix + "https://gitlab.com/devops/api/-/merge_requests/7"},
+		}
+		if !reflect.DeepEqual(digest.GitLab.MergeRequests, expectedMRs) {
+			t.Errorf("%s MergeRequests = %+v, want %+v", tt.channel.Type, digest.GitLab.MergeRequests, expectedMRs)
+		}
+		if digest.GitHub.TotalOldPRs != len(tt.expectedPRs) || digest.GitLab.TotalOldMRs != 1 || digest.GitHub.MinDaysOpen != 7 {
+			t.Errorf("%s totals = %d PRs, %d MRs, min days %d, want %d, 1 and 7", tt.channel.Type, digest.GitHub.TotalOldPRs, digest.GitLab.TotalOldMRs, digest.GitHub.MinDaysOpen, len(tt.expectedPRs))
+		}
+	}
+	if seen := state.Notifications[webKey].LastSeen; !seen.Equal(now) {
+		t.Errorf("LastSeen = %v, want %v", seen, now)
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	digest := &teamDigest{
+		Team: TeamConfig{Name: "api"},
+		GitHub: githubDigest{
+			Team: "api", Organization: "acme", MinDaysOpen: 7, TotalOldPRs: 1,
+			PullRequests: []digestPullRequest{{
+				PullRequest: PullRequest{Repository: "acme/api", Number: 2, Title: "Fix bug", URL: "https://github.com/acme/api/pull/2", Author: "bob", DaysOpen: 31},
+				Tier:        30,
+				Notify:      []string{"bob", "lena"},
+				Mentions:    []string{"<@U012BOB>", "@lena"},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		file        string
+		channelType string
+		expected    []string
+	}{
+		{
+			name: "embedded template",
+			expected: []string{
+				"# 📋 GitHub PR Digest: api",
+				"### [#2](https://github.com/acme/api/pull/2) Fix bug",
+				"**30+ days, ping:** <@U012BOB>, @lena",
+			},
+		},
+		{
+			name: "report template",
+			file: "../github-wecom.tmpl",
+			expected: []string{
+				"**Org:** acme | **Min Days:** 7 | **Total Old PRs:** 1",
+				"`acme/api` | @bob | 31 days",
+			},
+		},
+		{
+			name:        "slack channel",
+			channelType: channelSlack,
+			expected: []string{
+				"*📋 GitHub PR Digest: api*",
+				"*<https://github.com/acme/api/pull/2|#2> Fix bug*",
+				"*30+ days, ping:* <@U012BOB>, @lena",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			githubTemplate, err := loadDigestTemplate("github-digest.tmpl", tt.file)
+			if err != nil {
+				t.Fatalf("loadDigestTemplate() error = %v", err)
+			}
+			gitlabTemplate, err := loadDigestTemplate("gitlab-digest.tmpl", "")
+			if err != nil {
+				t.Fatalf("loadDigestTemplate() error = %v", err)
+			}
+
+			digest.Channel.Type = tt.channelType
+			messages, err := digest.render(githubTemplate, gitlabTemplate)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if len(messages) != 1 || messages[0].subject != "GitHub PR digest for api" {
+				t.Fatalf("render() = %+v, want a single GitHub message", messages)
+			}
+			for _, line := range tt.expected {
+				if !strings.Contains(messages[0].text, line) {
+					t.Errorf("render() = %q, want it to contain %q", messages[0].text, line)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderDigestNotified(t *testing.T) {
+	digest := &teamDigest{Team: TeamConfig{Name: "api"}, GitHub: githubDigest{Team: "api", TotalOldPRs: 12}}
+	for i := 1; i <= 12; i++ {
+		digest.GitHub.PullRequests = append(digest.GitHub.PullRequests, digestPullRequest{
+			PullRequest: PullRequest{Number: i, DaysOpen: 40 - i},
+			Tier:        30,
+			Notify:      []string{fmt.Sprintf("user%d", i)},
+			key:         fmt.Sprintf("api %d", i),
+		})
+	}
+	githubTemplate, err := loadDigestTemplate("github-digest.tmpl", "")
+	if err != nil {
+		t.Fatalf("loadDigestTemplate() error = %v", err)
+	}
+
+	messages, err := digest.render(githubTemplate, nil)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0].text, "...and 2 more PRs") {
+		t.Fatalf("render() = %+v, want a single message with 2 more PRs", messages)
+	}
+	// the pull requests not shown are sent by the next run
+	if len(messages[0].notified) != digestMaxEntries || messages[0].notified["api 10"] != 30 || messages[0].notified["api 11"] != 0 {
+		t.Errorf("notified = %v, want the first %d pull requests", messages[0].notified, digestMaxEntries)
+	}
+	if len(messages[0].users) != digestMaxEntries || slices.Contains(messages[0].users, "user11") {
+		t.Errorf("users = %v, want the users of the first %d pull requests", messages[0].users, digestMaxEntries)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	config := &DigestConfig{Users: map[string]UserConfig{
+		"bob":   {WeCom: "bob.wang", Slack: "U012BOB", Email: "bob@example.com"},
+		"carol": {Slack: "U012CAROL"},
+	}}
+	usernames := []string{"bob", "carol", "lena"}
+
+	tests := []struct {
+		channelType string
+		expected    []string
+	}{
+		{channelType: channelWeCom, expected: []string{"<@bob.wang>", "@carol", "@lena"}},
+		{channelType: channelSlack, expected: []string{"<@U012BOB>", "<@U012CAROL>", "@lena"}},
+		{channelType: channelEmail, expected: []string{"@bob", "@carol", "@lena"}},
+	}
+
+	for _, tt := range tests {
+		if result := config.mentions(tt.channelType, usernames); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("mentions(%s) = %v, want %v", tt.channelType, result, tt.expected)
+		}
+	}
+	if result := config.emails(usernames); !reflect.DeepEqual(result, []string{"bob@example.com"}) {
+		t.Errorf("emails() = %v, want [bob@example.com]", result)
+	}
+}
+
+func TestSlackMarkdown(t *testing.T) {
+	text := "### [#2](https://github.com/acme/api/pull/2) Fix bug\n**Org:** acme"
+	expected := "*<https://github.com/acme/api/pull/2|#2> Fix bug*\n*Org:* acme"
+	if result := slackMarkdown(text); result != expected {
+		t.Errorf("slackMarkdown() = %q, want %q", result, expected)
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Escalation targets, who is nagged when a pull request reaches a tier
+const (
+	notifyAuthor    = "author"
+	notifyReviewers = "reviewers"
+	notifyLead      = "lead"
+)
+
+// Channel types a digest can be sent to
+const (
+	channelWeCom = "wecom"
+	channelSlack = "slack"
+	channelEmail = "email"
+)
+
+// DigestConfig is the configuration file of the digest command
+type DigestConfig struct {
+	GitHub struct {
+		APIURL string   `yaml:"api_url"`
+		Token  string   `yaml:"token"`
+		Orgs   []string `yaml:"orgs"`
+	} `yaml:"github"`
+	GitLab struct {
+		Host   string   `yaml:"host"`
+		Token  string   `yaml:"token"`
+		Groups []string `yaml:"groups"`
+	} `yaml:"gitlab"`
+	Concurrency   int  `yaml:"concurrency"`
+	IncludeDrafts bool `yaml:"include_drafts"`
+	// StateFile remembers the notifications already sent
+	StateFile string `yaml:"state_file"`
+	// Escalation is the default escalation of the teams
+	Escalation []EscalationTier `yaml:"escalation"`
+	Email      EmailConfig      `yaml:"email"`
+	// Templates override the embedded digest templates, github-wecom.tmpl and gitlab-wecom.tmpl also work
+	Templates struct {
+		GitHub string `yaml:"github"`
+		GitLab string `yaml:"gitlab"`
+	} `yaml:"templates"`
+	Teams []TeamConfig `yaml:"teams"`
+	// Users map the GitHub and GitLab usernames to the accounts mentioned by the channels
+	Users map[string]UserConfig `yaml:"users"`
+}
+
+// UserConfig is how a user is mentioned on each channel type
+type UserConfig struct {
+	// WeCom is the userid of the WeCom account
+	WeCom string `yaml:"wecom"`
+	// Slack is the member ID of the Slack account, like U012AB3CDE
+	Slack string `yaml:"slack"`
+	// Email is added to the recipients of the email channels
+	Email string `yaml:"email"`
+}
+
+// EscalationTier notifies more people once a pull request is open for Days
+type EscalationTier struct {
+	Days   int    `yaml:"days"`
+	Notify string `yaml:"notify"`
+}
+
+// EmailConfig is the SMTP server used by the email channels
+type EmailConfig struct {
+	SMTPHost string `yaml:"smtp_host"`
+	SMTPPort int    `yaml:"smtp_port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// TeamConfig is a team owning repositories and projects
+type TeamConfig struct {
+	Name string `yaml:"name"`
+	// Lead is the username nagged by the lead tier
+	Lead string `yaml:"lead"`
+	// Repos are GitHub org/repo or GitLab group/project paths, * matches a path segment
+	Repos []string `yaml:"repos"`
+	// Escalation overrides the default escalation
+	Escalation []EscalationTier `yaml:"escalation"`
+	Channels   []ChannelConfig  `yaml:"channels"`
+}
+
+// ChannelConfig is where the digest of a team is sent
+type ChannelConfig struct {
+	// Name identifies the channel in the state file, it defaults to the type and position of the channel
+	// in the team like wecom-1, so that rotating a webhook key or editing the recipients does not notify again
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Webhook is the URL of a WeCom or Slack webhook
+	Webhook string `yaml:"webhook"`
+	// To are the recipients of an email
+	To []string `yaml:"to"`
+}
+
+// loadDigestConfig reads a configuration file, expanding ${VAR} environment variables
+// so that tokens and webhook keys can stay out of the file
+func loadDigestConfig(file string) (*DigestConfig, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	config := &DigestConfig{Concurrency: defaultConcurrency}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", file, err)
+	}
+	return config, nil
+}
+
+// validate checks the configuration, names the channels without a name and sorts the escalation tiers by days
+func (c *DigestConfig) validate() error {
+	if len(c.GitHub.Orgs) == 0 && len(c.GitLab.Groups) == 0 {
+		return fmt.Errorf("no github orgs or gitlab groups to scan")
+	}
+	if len(c.Teams) == 0 {
+		return fmt.Errorf("no teams")
+	}
+	if err := validateEscalation(c.Escalation); err != nil {
+		return err
+	}
+
+	for i := range c.Teams {
+		team := &c.Teams[i]
+		if team.Name == "" {
+			return fmt.Errorf("team %d has no name", i+1)
+		}
+		if len(team.Repos) == 0 {
+			return fmt.Errorf("team %s owns no repos", team.Name)
+		}
+		for _, pattern := range team.Repos {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("team %s: invalid repo pattern %q", team.Name, pattern)
+			}
+		}
+		if err := validateEscalation(team.Escalation); err != nil {
+			return fmt.Errorf("team %s: %w", team.Name, err)
+		}
+		if len(team.Escalation) == 0 && len(c.Escalation) == 0 {
+			return fmt.Errorf("team %s has no escalation tiers", team.Name)
+		}
+		if len(team.Channels) == 0 {
+			return fmt.Errorf("team %s has no channels", team.Name)
+		}
+		names := map[string]bool{}
+		for j := range team.Channels {
+			channel := &team.Channels[j]
+			if channel.Name == "" {
+				channel.Name = fmt.Sprintf("%s-%d", channel.Type, j+1)
+			}
+			if names[channel.Name] {
+				return fmt.Errorf("team %s: two channels named %s", team.Name, channel.Name)
+			}
+			names[channel.Name] = true
+			switch channel.Type {
+			case channelWeCom, channelSlack:
+				if channel.Webhook == "" {
+					return fmt.Errorf("team %s: %s channel has no webhook", team.Name, channel.Type)
+				}
+			case channelEmail:
+				if len(channel.To) == 0 {
+					return fmt.Errorf("team %s: email channel has no recipients", team.Name)
+				}
+				if c.Email.SMTPHost == "" || c.Email.From == "" {
+					return fmt.Errorf("team %s: email channel needs email.smtp_host and email.from", team.Name)
+				}
+			default:
+				return fmt.Errorf("team %s: unknown channel type %q, expected wecom, slack or email", team.Name, channel.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEscalation checks the tiers and sorts them by days
+func validateEscalation(tiers []EscalationTier) error {
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Days < tiers[j].Days })
+	for i, tier := range tiers {
+		if tier.Days < 0 {
+			return fmt.Errorf("escalation tier has negative days %d", tier.Days)
+		}
+		if i > 0 && tiers[i-1].Days == tier.Days {
+			return fmt.Errorf("two escalation tiers at %d days", tier.Days)
+		}
+		switch tier.Notify {
+		case notifyAuthor, notifyReviewers, notifyLead:
+		default:
+			return fmt.Errorf("unknown escalation target %q, expected author, reviewers or lead", tier.Notify)
+		}
+	}
+	return nil
+}
+
+// escalation returns the tiers of the team, the default ones when it has none
+func (c *DigestConfig) escalation(team TeamConfig) []EscalationTier {
+	if len(team.Escalation) > 0 {
+		return team.Escalation
+	}
+	return c.Escalation
+}
+
+// mentions returns how the usernames are mentioned on a channel type,
+// users without an account on the channel are written as @username and notify nobody
+func (c *DigestConfig) mentions(channelType string, usernames []string) []string {
+	mentions := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		user := c.Users[username]
+		switch {
+		// WeCom markdown messages ignore mentioned_list and mention with <@userid> instead
+		case channelType == channelWeCom && user.WeCom != "":
+			mentions = append(mentions, "<@"+user.WeCom+">")
+		case channelType == channelSlack && user.Slack != "":
+			mentions = append(mentions, "<@"+user.Slack+">")
+		default:
+			mentions = append(mentions, "@"+username)
+		}
+	}
+	return mentions
+}
+
+// emails returns the email addresses of the usernames that have one
+func (c *DigestConfig) emails(usernames []string) []string {
+	var emails []string
+	for _, username := range usernames {
+		if email := c.Users[username].Email; email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// owns reports whether the team owns a repository or project
+func (t TeamConfig) owns(repo string) bool {
+	for _, pattern := range t.Repos {
+		if matched, _ := path.Match(pattern, repo); matched {
+			return true
+		}
+	}
+	return false
+}
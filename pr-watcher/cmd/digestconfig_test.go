@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadDigestConfig(t *testing.T) {
+	t.Setenv("TEST_WECOM_KEY", "abc")
+	file := filepath.Join(t.TempDir(), "digest.yaml")
+	content := `
+github:
+  orgs: [acme]
+escalation:
+  - days: 30
+    notify: lead
+  - days: 7
+    notify: author
+teams:
+  - name: platform
+    repos: ["acme/api-*"]
+    channels:
+      - type: wecom
+        webhook: https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=${TEST_WECOM_KEY}
+      - name: alerts
+        type: slack
+        webhook: https://hooks.slack.com/services/platform
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := loadDigestConfig(file)
+	if err != nil {
+		t.Fatalf("loadDigestConfig() error = %v", err)
+	}
+	if config.Concurrency != defaultConcurrency {
+		t.Errorf("Concurrency = %d, want %d", config.Concurrency, defaultConcurrency)
+	}
+	expectedTiers := []EscalationTier{{Days: 7, Notify: notifyAuthor}, {Days: 30, Notify: notifyLead}}
+	if !reflect.DeepEqual(config.Escalation, expectedTiers) {
+		t.Errorf("Escalation = %v, want %v", config.Escalation, expectedTiers)
+	}
+	if webhook := config.Teams[0].Channels[0].Webhook; !strings.HasSuffix(webhook, "key=abc") {
+		t.Errorf("Webhook = %q, want the expanded key", webhook)
+	}
+	names := []string{config.Teams[0].Channels[0].Name, config.Teams[0].Channels[1].Name}
+	if expectedNames := []string{"wecom-1", "alerts"}; !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("channel names = %v, want %v", names, expectedNames)
+	}
+}
+
+func TestDigestConfigValidate(t *testing.T) {
+	tiers := []EscalationTier{{Days: 7, Notify: notifyAuthor}}
+
+	tests := []struct {
+		name     string
+		modify   func(c *DigestConfig)
+		expected string
+	}{
+		{
+			name:     "valid",
+			modify:   func(c *DigestConfig) {},
+			expected: "",
+		},
+		{
+			name:     "nothing to scan",
+			modify:   func(c *DigestConfig) { c.GitHub.Orgs = nil },
+			expected: "no github orgs or gitlab groups",
+		},
+		{
+			name:     "unknown escalation target",
+			modify:   func(c *DigestConfig) { c.Escalation = []EscalationTier{{Days: 7, Notify: "manager"}} },
+			expected: `unknown escalation target "manager"`,
+		},
+		{
+			name: "duplicate tiers",
+			modify: func(c *DigestConfig) {
+				c.Escalation = []EscalationTier{{Days: 7, Notify: notifyAuthor}, {Days: 7, Notify: notifyLead}}
+			},
+			expected: "two escalation tiers at 7 days",
+		},
+		{
+			name: "team without tiers",
+			modify: func(c *DigestConfig) {
+				c.Escalation = nil
+			},
+			expected: "team platform has no escalation tiers",
+		},
+		{
+			name:     "invalid repo pattern",
+			modify:   func(c *DigestConfig) { c.Teams[0].Repos = []string{"acme/["} },
+			expected: `invalid repo pattern "acme/["`,
+		},
+		{
+			name: "email without SMTP server",
+			modify: func(c *DigestConfig) {
+				c.Teams[0].Channels = []ChannelConfig{{Type: channelEmail, To: []string{"team@example.com"}}}
+			},
+			expected: "email channel needs email.smtp_host",
+		},
+		{
+			name: "duplicate channel names",
+			modify: func(c *DigestConfig) {
+				c.Teams[0].Channels = append(c.Teams[0].Channels, ChannelConfig{Name: "wecom-1", Type: channelSlack, Webhook: "https://example.com"})
+			},
+			expected: "team platform: two channels named wecom-1",
+		},
+		{
+			name:     "unknown channel",
+			modify:   func(c *DigestConfig) { c.Teams[0].Channels = []ChannelConfig{{Type: "teams"}} },
+			expected: `unknown channel type "teams"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &DigestConfig{
+				Escalation: append([]EscalationTier{}, tiers...),
+				Teams:      []TeamConfig{{Name: "platform", Repos: []string{"acme/*"}, Channels: []ChannelConfig{{Type: channelWeCom, Webhook: "https://example.com"}}}},
+			}
+			config.GitHub.Orgs = []string{"acme"}
+			tt.modify(config)
+
+			err := config.validate()
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("validate() error = %v, want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTeamOwns(t *testing.T) {
+	team := TeamConfig{Repos: []string{"acme/api", "acme/web-*", "devops/platform/*"}}
+
+	tests := []struct {
+		repo     string
+		expected bool
+	}{
+		{repo: "acme/api", expected: true},
+		{repo: "acme/api-gateway", expected: false},
+		{repo: "acme/web-console", expected: true},
+		{repo: "devops/platform/runner", expected: true},
+		{repo: "devops/platform/tools/cli", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			if result := team.owns(tt.repo); result != tt.expected {
+				t.Errorf("owns(%q) = %v, want %v", tt.repo, result, tt.expected)
+			}
+		})
+	}
+}
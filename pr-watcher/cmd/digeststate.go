@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// defaultDigestStateFile is the state file used when neither the flag nor the config set one
+	defaultDigestStateFile = ".pr-watcher-digest.json"
+	// stateRetention is how long a notification is remembered after its pull request was last seen,
+	// long enough that a repository which fails to load for a few runs is not nagged again
+	stateRetention = 30 * 24 * time.Hour
+)
+
+// notification is the last nag sent about a pull request or merge request to a team
+type notification struct {
+	// Tier is the days of the escalation tier notified
+	Tier       int       `json:"tier"`
+	NotifiedAt time.Time `json:"notified_at"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// digestState remembers the notifications sent by the previous digests
+type digestState struct {
+	// Notifications are keyed by team, channel name and URL
+	Notifications map[string]*notification `json:"notifications"`
+}
+
+// loadDigestState reads a state file, a missing file is an empty state
+func loadDigestState(file string) (*digestState, error) {
+	state := &digestState{Notifications: map[string]*notification{}}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", file, err)
+	}
+	if state.Notifications == nil {
+		state.Notifications = map[string]*notification{}
+	}
+	return state, nil
+}
+
+// save writes the state through a temporary file so that an interrupted run keeps the previous state
+func (s *digestState) save(file string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// due reports whether the tier was not notified yet, a lower tier does not count
+func (s *digestState) due(key string, tier int) bool {
+	n, ok := s.Notifications[key]
+	return !ok || n.Tier < tier
+}
+
+// seen records that a pull request is still open
+func (s *digestState) seen(key string, now time.Time) {
+	if n, ok := s.Notifications[key]; ok {
+		n.LastSeen = now
+	}
+}
+
+// notified records that the tier was sent
+func (s *digestState) notified(key string, tier int, now time.Time) {
+	s.Notifications[key] = &notification{Tier: tier, NotifiedAt: now, LastSeen: now}
+}
+
+// prune forgets the notifications of the pull requests not seen for the retention
+func (s *digestState) prune(now time.Time) {
+	for key, n := range s.Notifications {
+		if now.Sub(n.LastSeen) > stateRetention {
+			delete(s.Notifications, key)
+		}
+	}
+}
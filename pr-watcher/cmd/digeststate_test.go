@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDigestState(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	file := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := loadDigestState(file)
+	if err != nil {
+		t.Fatalf("loadDigestState() of a missing file error = %v", err)
+	}
+	if !state.due("platform https://github.com/acme/api/pull/1", 7) {
+		t.Errorf("due() = false for a new pull request, want true")
+	}
+
+	state.notified("platform https://github.com/acme/api/pull/1", 14, now.Add(-40*24*time.Hour))
+	state.notified("platform https://github.com/acme/api/pull/2", 7, now.Add(-40*24*time.Hour))
+	state.seen("platform https://github.com/acme/api/pull/1", now)
+	state.prune(now)
+
+	tests := []struct {
+		name     string
+		key      string
+		tier     int
+		expected bool
+	}{
+		{name: "same tier", key: "platform https://github.com/acme/api/pull/1", tier: 14, expected: false},
+		{name: "lower tier", key: "platform https://github.com/acme/api/pull/1", tier: 7, expected: false},
+		{name: "higher tier", key: "platform https://github.com/acme/api/pull/1", tier: 30, expected: true},
+		{name: "pruned", key: "platform https://github.com/acme/api/pull/2", tier: 7, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := state.due(tt.key, tt.tier); result != tt.expected {
+				t.Errorf("due(%q, %d) = %v, want %v", tt.key, tt.tier, result, tt.expected)
+			}
+		})
+	}
+
+	if err := state.save(file); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+	loaded, err := loadDigestState(file)
+	if err != nil {
+		t.Fatalf("loadDigestState() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("loadDigestState() = %+v, want %+v", loaded, state)
+	}
+}
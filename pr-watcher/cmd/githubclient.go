@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultGitHubAPIURL is the GraphQL endpoint of github.com
+const defaultGitHubAPIURL = "https://api.github.com/graphql"
+
+// pullRequestFields are the fields of a pull request read by the queries
+const pullRequestFields = `
+fragment PullRequestFields on PullRequest {
+  number
+  title
+  url
+  state
+  isDraft
+  createdAt
+  updatedAt
+  baseRefName
+  headRefName
+  author { login }
+  labels(first: 20) { nodes { name } }
+  assignees(first: 20) { nodes { login } }
+  reviewRequests(first: 20) {
+    nodes {
+      requestedReviewer {
+        ... on User { login }
+        ... on Mannequin { login }
+        ... on Team { slug }
+      }
+    }
+  }
+}`
+
+// orgRepositoriesQuery lists the repositories of an organization with their first pull requests,
+// so that most repositories need a single request
+const orgRepositoriesQuery = `
+query($org: String!, $states: [PullRequestState!], $cursor: String) {
+  organization(login: $org) {
+    repositories(first: 50, after: $cursor, orderBy: {field: NAME, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        pullRequests(first: 50, states: $states, orderBy: {field: CREATED_AT, direction: DESC}) {
+          pageInfo { hasNextPage endCursor }
+          nodes { ...PullRequestFields }
+        }
+      }
+    }
+  }
+}` + pullRequestFields
+
+// repositoryPullRequestsQuery lists the next pull requests of a repository
+const repositoryPullRequestsQuery = `
+query($owner: String!, $name: String!, $states: [PullRequestState!], $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: 100, after: $cursor, states: $states, orderBy: {field: CREATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes { ...PullRequestFields }
+    }
+  }
+}` + pullRequestFields
+
+// pageInfo is the pagination of a GraphQL connection
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// githubPullRequest is a pull request as returned by the GraphQL API
+type githubPullRequest struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	State       string    `json:"state"`
+	IsDraft     bool      `json:"isDraft"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	BaseRefName string    `json:"baseRefName"`
+	HeadRefName string    `json:"headRefName"`
+	Author      *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `json:"assignees"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer *struct {
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+}
+
+// pullRequestConnection is a page of pull requests
+type pullRequestConnection struct {
+	PageInfo pageInfo            `json:"pageInfo"`
+	Nodes    []githubPullRequest `json:"nodes"`
+}
+
+// GitHubRepository is a repository of an organization with its pull requests
+type GitHubRepository struct {
+	// Name is the full name of the repository, org/name
+	Name         string
+	PullRequests []PullRequest
+	// Err is set when the pull requests could not all be fetched
+	Err error
+}
+
+// GitHubClient reads pull requests with the GitHub GraphQL API
+type GitHubClient struct {
+	api      *apiClient
+	endpoint string
+	now      func() time.Time
+}
+
+// NewGitHubClient creates a client of the GraphQL endpoint authenticated with a token
+// endpoint defaults to github.com, GitHub Enterprise uses https://HOST/api/graphql
+func NewGitHubClient(endpoint, token string) *GitHubClient {
+	if endpoint == "" {
+		endpoint = defaultGitHubAPIURL
+	}
+	authValue := ""
+	if token != "" {
+		authValue = "Bearer " + token
+	}
+	api := newAPIClient("Authorization", authValue)
+	// the GraphQL API reports an exhausted rate limit in the errors of a successful response
+	api.rateLimited = func(body []byte) bool {
+		var response struct {
+			Errors []struct {
+				Type string `json:"type"`
+			} `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &response)
+		for _, e := range response.Errors {
+			if e.Type == "RATE_LIMITED" {
+				return true
+			}
+		}
+		return false
+	}
+	return &GitHubClient{api: api, endpoint: endpoint, now: time.Now}
+}
+
+// graphql runs a query and decodes its data into out
+func (c *GitHubClient) graphql(ctx context.Context, query string, variables map[string]any, out any) error {
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]any{"query": query, "variables": variables}
+	if _, err := c.api.do(ctx, http.MethodPost, c.endpoint, body, &response); err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		messages := make([]string, 0, len(response.Errors))
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL error: %s", strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(response.Data, out)
+}
+
+// githubStates converts a state flag to the GraphQL pull request states
+// closed includes the merged pull requests, like gh pr list
+func githubStates(state string) ([]string, error) {
+	switch strings.ToLower(state) {
+	case "open":
+		return []string{"OPEN"}, nil
+	case "closed":
+		return []string{"CLOSED", "MERGED"}, nil
+	case "merged":
+		return []string{"MERGED"}, nil
+	case "all":
+		return []string{"OPEN", "CLOSED", "MERGED"}, nil
+	default:
+		return nil, fmt.Errorf("unknown PR state %q, expected open, closed, merged or all", state)
+	}
+}
+
+// ListOrgPullRequests returns the repositories of an organization with their pull requests in the state
+// The pull requests beyond the first page of a repository are fetched by concurrency requests at a time
+func (c *GitHubClient) ListOrgPullRequests(ctx context.Context, org, state string, concurrency int) ([]GitHubRepository, error) {
+	states, err := githubStates(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []GitHubRepository
+	// next holds the cursor of the next pull requests of each repository, empty when complete
+	var next []string
+	cursor := ""
+	for {
+		var data struct {
+			Organization *struct {
+				Repositories struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						Name         string                `json:"name"`
+						PullRequests pullRequestConnection `json:"pullRequests"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"organization"`
+		}
+		variables := map[string]any{"org": org, "states": states, "cursor": nullable(cursor)}
+		if err := c.graphql(ctx, orgRepositoriesQuery, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", org, err)
+		}
+		if data.Organization == nil {
+			return nil, fmt.Errorf("organization %s not found", org)
+		}
+
+		for _, node := range data.Organization.Repositories.Nodes {
+			repo := GitHubRepository{Name: fmt.Sprintf("%s/%s", org, node.Name)}
+			repo.PullRequests = c.convert(repo.Name, node.PullRequests.Nodes)
+			repos = append(repos, repo)
+			pullRequestsCursor := ""
+			if node.PullRequests.PageInfo.HasNextPage {
+				pullRequestsCursor = node.PullRequests.PageInfo.EndCursor
+			}
+			next = append(next, pullRequestsCursor)
+		}
+
+		page := data.Organization.Repositories.PageInfo
+		if !page.HasNextPage {
+			break
+		}
+		cursor = page.EndCursor
+	}
+
+	forEach(ctx, len(repos), concurrency, func(ctx context.Context, i int) {
+		if next[i] == "" {
+			return
+		}
+		prs, err := c.listRepositoryPullRequests(ctx, repos[i].Name, states, next[i])
+		repos[i].PullRequests = append(repos[i].PullRequests, prs...)
+		repos[i].Err = err
+	})
+	return repos, ctx.Err()
+}
+
+// listRepositoryPullRequests returns the pull requests of a repository from the cursor on
+func (c *GitHubClient) listRepositoryPullRequests(ctx context.Context, repo string, states []string, cursor string) ([]PullRequest, error) {
+	owner, name, _ := strings.Cut(repo, "/")
+	var prs []PullRequest
+	for {
+		var data struct {
+			Repository *struct {
+				PullRequests pullRequestConnection `json:"pullRequests"`
+			} `json:"repository"`
+		}
+		variables := map[string]any{"owner": owner, "name": name, "states": states, "cursor": nullable(cursor)}
+		if err := c.graphql(ctx, repositoryPullRequestsQuery, variables, &data); err != nil {
+			return prs, err
+		}
+		if data.Repository == nil {
+			return prs, fmt.Errorf("repository %s not found", repo)
+		}
+
+		prs = append(prs, c.convert(repo, data.Repository.PullRequests.Nodes)...)
+		page := data.Repository.PullRequests.PageInfo
+		if !page.HasNextPage {
+			return prs, nil
+		}
+		cursor = page.EndCursor
+	}
+}
+
+// nullable returns nil for an empty cursor, the first page
+func nullable(cursor string) any {
+	if cursor == "" {
+		return nil
+	}
+	return cursor
+}
+
+// convert converts GraphQL pull requests of a repository to the report format
+func (c *GitHubClient) convert(repo string, nodes []githubPullRequest) []PullRequest {
+	prs := make([]PullRequest, 0, len(nodes))
+	for _, node := range nodes {
+		pr := PullRequest{
+			Repository: repo,
+			Number:     node.Number,
+			Title:      node.Title,
+			CreatedAt:  node.CreatedAt,
+			UpdatedAt:  node.UpdatedAt,
+			URL:        node.URL,
+			State:      node.State,
+			Draft:      node.IsDraft,
+			DaysOpen:   int(c.now().Sub(node.CreatedAt).Hours() / 24),
+			BaseBranch: node.BaseRefName,
+			HeadBranch: node.HeadRefName,
+		}
+		// the author is null when the account was deleted
+		if node.Author != nil {
+			pr.Author = node.Author.Login
+		}
+		for _, label := range node.Labels.Nodes {
+			pr.Labels = append(pr.Labels, label.Name)
+		}
+		for _, assignee := range node.Assignees.Nodes {
+			pr.Assignees = append(pr.Assignees, assignee.Login)
+		}
+		for _, request := range node.ReviewRequests.Nodes {
+			reviewer := request.RequestedReviewer
+			if reviewer == nil {
+				continue
+			}
+			// teams have a slug instead of a login
+			login := reviewer.Login
+			if login == "" {
+				login = reviewer.Slug
+			}
+			pr.Reviewers = append(pr.Reviewers, login)
+		}
+		prs = append(prs, pr)
+	}
+	return prs
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// graphqlRequest is the body of a GraphQL request
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// githubFixtures are the GraphQL responses keyed by query and cursor
+var githubFixtures = map[string]string{
+	"organization:": `{"data": {"organization": {"repositories": {
+		"pageInfo": {"hasNextPage": true, "endCursor": "repos-1"},
+		"nodes": [{"name": "alpha", "pullRequests": {
+			"pageInfo": {"hasNextPage": true, "endCursor": "prs-1"},
+			"nodes": [{
+				"number": 12, "title": "Add feature", "url": "https://github.com/acme/alpha/pull/12", "state": "OPEN",
+				"isDraft": false, "createdAt": "2025-05-22T00:00:00Z", "updatedAt": "2025-05-30T00:00:00Z",
+				"baseRefName": "main", "headRefName": "feature", "author": {"login": "alice"},
+				"labels": {"nodes": [{"name": "enhancement"}]},
+				"assignees": {"nodes": [{"login": "bob"}]},
+				"reviewRequests": {"nodes": [{"requestedReviewer": {"login": "carol"}}, {"requestedReviewer": {"slug": "maintainers"}}]}
+			}]
+		}}]
+	}}}}`,
+	"organization:repos-1": `{"data": {"organization": {"repositories": {
+		"pageInfo": {"hasNextPage": false, "endCursor": "repos-2"},
+		"nodes": [{"name": "beta", "pullRequests": {
+			"pageInfo": {"hasNextPage": false, "endCursor": ""},
+			"nodes": [{
+				"number": 3, "title": "WIP", "url": "https://github.com/acme/beta/pull/3", "state": "OPEN",
+				"isDraft": true, "createdAt": "2025-05-31T00:00:00Z", "updatedAt": "2025-05-31T00:00:00Z",
+				"baseRefName": "main", "headRefName": "wip", "author": null,
+				"labels": {"nodes": []}, "assignees": {"nodes": []}, "reviewRequests": {"nodes": []}
+			}]
+		}}]
+	}}}}`,
+	"repository:prs-1": `{"data": {"repository": {"pullRequests": {
+		"pageInfo": {"hasNextPage": false, "endCursor": "prs-2"},
+		"nodes": [{
+			"number": 7, "title": "Fix bug", "url": "https://github.com/acme/alpha/pull/7", "state": "OPEN",
+			"isDraft": false, "createdAt": "2025-05-01T00:00:00Z", "updatedAt": "2025-05-02T00:00:00Z",
+			"baseRefName": "main", "headRefName": "fix", "author": {"login": "dave"},
+			"labels": {"nodes": []}, "assignees": {"nodes": []}, "reviewRequests": {"nodes": []}
+		}]
+	}}}}`,
+}
+
+// newGitHubServer serves the fixtures, checking the token and the variables of the requests
+func newGitHubServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer secret")
+		}
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if states, _ := json.Marshal(req.Variables["states"]); string(states) != `["OPEN"]` {
+			t.Errorf("states variable = %s, want [\"OPEN\"]", states)
+		}
+
+		kind := "organization"
+		if strings.Contains(req.Query, "repository(owner") {
+			kind = "repository"
+			if req.Variables["owner"] != "acme" || req.Variables["name"] != "alpha" {
+				t.Errorf("repository variables = %v, want acme/alpha", req.Variables)
+			}
+		}
+		cursor, _ := req.Variables["cursor"].(string)
+		fixture, ok := githubFixtures[kind+":"+cursor]
+		if !ok {
+			t.Errorf("unexpected %s query with cursor %q", kind, cursor)
+		}
+		w.Write([]byte(fixture))
+	}))
+}
+
+func TestGitHubClientListOrgPullRequests(t *testing.T) {
+	server := newGitHubServer(t)
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, "secret")
+	client.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	repos, err := client.ListOrgPullRequests(context.Background(), "acme", "open", 2)
+	if err != nil {
+		t.Fatalf("ListOrgPullRequests() error = %v", err)
+	}
+
+	expected := []GitHubRepository{
+		{
+			Name: "acme/alpha",
+			PullRequests: []PullRequest{
+				{
+					Repository: "acme/alpha", Number: 12, Title: "Add feature", Author: "alice",
+					CreatedAt: time.Date(2025, 5, 22, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC),
+					URL: "https://github.com/acme/alpha/pull/12", State: "OPEN", DaysOpen: 10,
+					Labels: []string{"enhancement"}, Assignees: []string{"bob"}, Reviewers: []string{"carol", "maintainers"},
+					BaseBranch: "main", HeadBranch: "feature",
+				},
+				{
+					Repository: "acme/alpha", Number: 7, Title: "Fix bug", Author: "dave",
+					CreatedAt: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC),
+					URL: "https://github.com/acme/alpha/pull/7", State: "OPEN", DaysOpen: 31,
+					BaseBranch: "main", HeadBranch: "fix",
+				},
+			},
+		},
+		{
+			Name: "acme/beta",
+			PullRequests: []PullRequest{
+				{
+					Repository: "acme/beta", Number: 3, Title: "WIP",
+					CreatedAt: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+					URL: "https://github.com/acme/beta/pull/3", State: "OPEN", Draft: true, DaysOpen: 1,
+					BaseBranch: "main", HeadBranch: "wip",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Errorf("ListOrgPullRequests() = %+v, want %+v", repos, expected)
+	}
+}
+
+func TestGitHubClientErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    string
+		response string
+		expected string
+	}{
+		{
+			name:     "unknown state",
+			state:    "draft",
+			expected: `unknown PR state "draft"`,
+		},
+		{
+			name:     "GraphQL error",
+			state:    "open",
+			response: `{"data": {"organization": null}, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to an Organization"}]}`,
+			expected: "GraphQL error: Could not resolve to an Organization",
+		},
+		{
+			name:     "missing organization",
+			state:    "open",
+			response: `{"data": {"organization": null}}`,
+			expected: "organization acme not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			_, err := NewGitHubClient(server.URL, "secret").ListOrgPullRequests(context.Background(), "acme", tt.state, 1)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ListOrgPullRequests() error = %v, want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGitHubStates(t *testing.T) {
+	tests := []struct {
+		state    string
+		expected []string
+	}{
+		{state: "open", expected: []string{"OPEN"}},
+		{state: "closed", expected: []string{"CLOSED", "MERGED"}},
+		{state: "MERGED", expected: []string{"MERGED"}},
+		{state: "all", expected: []string{"OPEN", "CLOSED", "MERGED"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			states, err := githubStates(tt.state)
+			if err != nil || !reflect.DeepEqual(states, tt.expected) {
+				t.Errorf("githubStates(%q) = %v, %v, want %v", tt.state, states, err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultGitLabHost is the GitLab instance used when no host is given
+const defaultGitLabHost = "gitlab.com"
+
+// gitlabMergeRequest is a merge request as returned by the REST API
+type gitlabMergeRequest struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	WebURL    string    `json:"web_url"`
+	State     string    `json:"state"`
+	Draft     bool      `json:"draft"`
+	Labels    []string  `json:"labels"`
+	Assignees []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+	Reviewers []struct {
+		Username string `json:"username"`
+	} `json:"reviewers"`
+	TargetBranch string `json:"target_branch"`
+	SourceBranch string `json:"source_branch"`
+	// Pipeline is only returned by older GitLab versions, HeadPipeline by the single merge request API
+	Pipeline *struct {
+		Status string `json:"status"`
+	} `json:"pipeline"`
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+// GitLabClient reads projects and merge requests with the GitLab REST API
+type GitLabClient struct {
+	api     *apiClient
+	baseURL string
+	now     func() time.Time
+}
+
+// NewGitLabClient creates a client of a GitLab instance authenticated with a personal access token
+// host is a host name, gitlab.com by default, or the URL of the instance
+func NewGitLabClient(host, token string) *GitLabClient {
+	if host == "" {
+		host = defaultGitLabHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return &GitLabClient{
+		api:     newAPIClient("PRIVATE-TOKEN", token),
+		baseURL: strings.TrimSuffix(host, "/") + "/api/v4",
+		now:     time.Now,
+	}
+}
+
+// ListGroupProjects returns the full paths of the projects of a group
+func (c *GitLabClient) ListGroupProjects(ctx context.Context, group string) ([]string, error) {
+	query := url.Values{"per_page": {"100"}, "order_by": {"path"}, "sort": {"asc"}}
+	rawURL := fmt.Sprintf("%s/groups/%s/projects?%s", c.baseURL, url.PathEscape(group), query.Encode())
+	projects, err := getAllPages[struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	}](ctx, c.api, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects of %s: %w", group, err)
+	}
+
+	paths := make([]string, 0, len(projects))
+	for _, project := range projects {
+		paths = append(paths, project.PathWithNamespace)
+	}
+	return paths, nil
+}
+
+// ListProjectMergeRequests returns the merge requests of a project in the state
+func (c *GitLabClient) ListProjectMergeRequests(ctx context.Context, project, state string) ([]GitLabMergeRequest, error) {
+	query := url.Values{"state": {state}, "per_page": {"100"}}
+	rawURL := fmt.Sprintf("%s/projects/%s/merge_requests?%s", c.baseURL, url.PathEscape(project), query.Encode())
+	glMRs, err := getAllPages[gitlabMergeRequest](ctx, c.api, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge requests of %s: %w", project, err)
+	}
+
+	mrs := make([]GitLabMergeRequest, 0, len(glMRs))
+	for _, glMR := range glMRs {
+		mr := GitLabMergeRequest{
+			Project:      project,
+			IID:          glMR.IID,
+			Title:        glMR.Title,
+			Author:       glMR.Author.Username,
+			CreatedAt:    glMR.CreatedAt,
+			UpdatedAt:    glMR.UpdatedAt,
+			WebURL:       glMR.WebURL,
+			State:        glMR.State,
+			Draft:        glMR.Draft,
+			DaysOpen:     int(c.now().Sub(glMR.CreatedAt).Hours() / 24),
+			Labels:       glMR.Labels,
+			TargetBranch: glMR.TargetBranch,
+			SourceBranch: glMR.SourceBranch,
+			Pipeline:     "none",
+		}
+		for _, assignee := range glMR.Assignees {
+			mr.Assignees = append(mr.Assignees, assignee.Username)
+		}
+		for _, reviewer := range glMR.Reviewers {
+			mr.Reviewers = append(mr.Reviewers, reviewer.Username)
+		}
+		if glMR.HeadPipeline != nil && glMR.HeadPipeline.Status != "" {
+			mr.Pipeline = glMR.HeadPipeline.Status
+		} else if glMR.Pipeline != nil && glMR.Pipeline.Status != "" {
+			mr.Pipeline = glMR.Pipeline.Status
+		}
+		mrs = append(mrs, mr)
+	}
+	return mrs, nil
+}
+
+// getAllPages reads every page of a list, following the pagination headers of GitLab
+func getAllPages[T any](ctx context.Context, api *apiClient, rawURL string) ([]T, error) {
+	var items []T
+	for rawURL != "" {
+		var page []T
+		header, err := api.do(ctx, http.MethodGet, rawURL, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		rawURL, err = nextPageURL(rawURL, header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// nextPageURL returns the URL of the next page, empty on the last page
+// It prefers the Link header, which keyset pagination also sets, over X-Next-Page
+func nextPageURL(rawURL string, header http.Header) (string, error) {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		if ok && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>"), nil
+		}
+	}
+
+	nextPage := header.Get("X-Next-Page")
+	if nextPage == "" {
+		return "", nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("page", nextPage)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
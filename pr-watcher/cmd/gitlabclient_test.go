@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newGitLabServer serves two pages of projects linked by a Link header
+// and two pages of merge requests linked by X-Next-Page
+func newGitLabServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "secret" {
+			t.Errorf("PRIVATE-TOKEN header = %q, want %q", got, "secret")
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/api/v4/groups/acme%2Fplatform/projects":
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(w, `[{"path_with_namespace": "acme/platform/web"}]`)
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v4/groups/acme%%2Fplatform/projects?page=2&per_page=100>; rel="next", <%s/last>; rel="last"`, server.URL, server.URL))
+			fmt.Fprint(w, `[{"path_with_namespace": "acme/platform/api"}]`)
+		case "/api/v4/projects/acme%2Fplatform%2Fapi/merge_requests":
+			if state := r.URL.Query().Get("state"); state != "opened" {
+				t.Errorf("state = %q, want opened", state)
+			}
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(w, `[{"iid": 2, "title": "Draft: refactor", "author": {"username": "bob"}, "created_at": "2025-05-31T00:00:00Z",
+					"updated_at": "2025-05-31T00:00:00Z", "web_url": "https://gitlab.com/acme/platform/api/-/merge_requests/2",
+					"state": "opened", "draft": true, "labels": [], "assignees": [], "reviewers": [],
+					"target_branch": "main", "source_branch": "refactor", "pipeline": {"status": "failed"}}]`)
+				return
+			}
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{"iid": 1, "title": "Add endpoint", "author": {"username": "alice"}, "created_at": "2025-05-22T00:00:00Z",
+				"updated_at": "2025-05-30T00:00:00Z", "web_url": "https://gitlab.com/acme/platform/api/-/merge_requests/1",
+				"state": "opened", "draft": false, "labels": ["backend"], "assignees": [{"username": "carol"}],
+				"reviewers": [{"username": "dave"}], "target_branch": "main", "source_branch": "endpoint"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server
+}
+
+func TestGitLabClientListGroupProjects(t *testing.T) {
+	server := newGitLabServer(t)
+	defer server.Close()
+
+	projects, err := NewGitLabClient(server.URL, "secret").ListGroupProjects(context.Background(), "acme/platform")
+	if err != nil {
+		t.Fatalf("ListGroupProjects() error = %v", err)
+	}
+	expected := []string{"acme/platform/api", "acme/platform/web"}
+	if !reflect.DeepEqual(projects, expected) {
+		t.Errorf("ListGroupProjects() = %v, want %v", projects, expected)
+	}
+}
+
+func TestGitLabClientListProjectMergeRequests(t *testing.T) {
+	server := newGitLabServer(t)
+	defer server.Close()
+
+	client := NewGitLabClient(server.URL, "secret")
+	client.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	mrs, err := client.ListProjectMergeRequests(context.Background(), "acme/platform/api", "opened")
+	if err != nil {
+		t.Fatalf("ListProjectMergeRequests() error = %v", err)
+	}
+	expected := []GitLabMergeRequest{
+		{
+			Project: "acme/platform/api", IID: 1, Title: "Add endpoint", Author: "alice",
+			CreatedAt: time.Date(2025, 5, 22, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC),
+			WebURL: "https://gitlab.com/acme/platform/api/-/merge_requests/1", State: "opened", DaysOpen: 10,
+			Labels: []string{"backend"}, Assignees: []string{"carol"}, Reviewers: []string{"dave"},
+			TargetBranch: "main", SourceBranch: "endpoint", Pipeline: "none",
+		},
+		{
+			Project: "acme/platform/api", IID: 2, Title: "Draft: refactor", Author: "bob",
+			CreatedAt: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+			WebURL: "https://gitlab.com/acme/platform/api/-/merge_requests/2", State: "opened", Draft: true, DaysOpen: 1,
+			Labels: []string{}, TargetBranch: "main", SourceBranch: "refactor", Pipeline: "failed",
+		},
+	}
+	if !reflect.DeepEqual(mrs, expected) {
+		t.Errorf("ListProjectMergeRequests() = %+v, want %+v", mrs, expected)
+	}
+}
+
+func TestGitLabClientNotFound(t *testing.T) {
+	server := newGitLabServer(t)
+	defer server.Close()
+
+	_, err := NewGitLabClient(server.URL, "secret").ListProjectMergeRequests(context.Background(), "acme/missing", "opened")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("ListProjectMergeRequests() error = %v, want a 404 API error", err)
+	}
+}
+
+func TestNewGitLabClient(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{host: "", expected: "https://gitlab.com/api/v4"},
+		{host: "gitlab.example.com", expected: "https://gitlab.example.com/api/v4"},
+		{host: "http://localhost:8080/", expected: "http://localhost:8080/api/v4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := NewGitLabClient(tt.host, "").baseURL; got != tt.expected {
+				t.Errorf("NewGitLabClient(%q).baseURL = %q, want %q", tt.host, got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -48,6 +48,8 @@ var gitlabWatcherCmd = &cobra.Command{
 merge requests that have been open for longer than the specified number of days.
 It returns a comprehensive JSON report with all relevant MR information.
 
+It reads the GitLab REST API with a token from --token or GITLAB_TOKEN.
+
 Example:
   pr-watcher watch-mrs --group mygroup --days 7 --output results.json`,
 	RunE: runGitLabWatcher,
@@ -62,7 +64,9 @@ func init() {
 	gitlabWatcherCmd.Flags().StringP("output", "f", "", "Output file path (optional, prints to stdout if not specified)")
 	gitlabWatcherCmd.Flags().BoolP("include-drafts", "", false, "Include draft merge requests in the results")
 	gitlabWatcherCmd.Flags().StringP("state", "s", "opened", "MR state to filter by (opened, closed, merged, all)")
-	gitlabWatcherCmd.Flags().StringP("host", "", "", "GitLab host (optional, defaults to gitlab.com)")
+	gitlabWatcherCmd.Flags().StringP("host", "", "", "GitLab host (optional, defaults to GITLAB_HOST or gitlab.com)")
+	gitlabWatcherCmd.Flags().String("token", "", "GitLab token (defaults to the GITLAB_TOKEN environment variable)")
+	gitlabWatcherCmd.Flags().Int("concurrency", defaultConcurrency, "Number of projects fetched at the same time")
 
 	// Mark required flags
 	gitlabWatcherCmd.MarkFlagRequired("group")
@@ -70,11 +74,6 @@ func init() {
 
 // runGitLabWatcher executes the main logic for the GitLab MR watcher command
 func runGitLabWatcher(cmd *cobra.Command, args []string) error {
-	// Check if GitLab CLI is available
-	if err := checkGitLabCLI(); err != nil {
-		return err
-	}
-
 	// Get flag values
 	group, _ := cmd.Flags().GetString("group")
 	days, _ := cmd.Flags().GetInt("days")
@@ -82,11 +81,15 @@ func runGitLabWatcher(cmd *cobra.Command, args []string) error {
 	includeDrafts, _ := cmd.Flags().GetBool("include-drafts")
 	state, _ := cmd.Flags().GetString("state")
 	host, _ := cmd.Flags().GetString("host")
+	token, _ := cmd.Flags().GetString("token")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
 
-	// Set GitLab host if specified
-	if host != "" {
-		os.Setenv("GITLAB_HOST", host)
+	token = firstNonEmpty(token, os.Getenv("GITLAB_TOKEN"))
+	if token == "" {
+		return fmt.Errorf("a GitLab token is required, set --token or the GITLAB_TOKEN environment variable")
 	}
+	client := NewGitLabClient(firstNonEmpty(host, os.Getenv("GITLAB_HOST")), token)
+	ctx := context.Background()
 
 	fmt.Printf("Scanning GitLab group '%s' for MRs older than %d days...\n", group, days)
 
@@ -98,30 +101,17 @@ func runGitLabWatcher(cmd *cobra.Command, args []string) error {
 		MergeRequests: []GitLabMergeRequest{},
 	}
 
-	// Get all projects for the group
-	projects, err := getGroupProjects(group)
+	// Get all projects for the group with their merge requests
+	projects, mrs, err := listGroupMergeRequests(ctx, client, group, state, concurrency)
 	if err != nil {
 		return fmt.Errorf("failed to get projects: %w", err)
 	}
+	result.TotalProjects = projects
 
-	result.TotalProjects = len(projects)
-	fmt.Printf("Found %d projects\n", len(projects))
-
-	// Process each project
-	for i, project := range projects {
-		fmt.Printf("Processing project %d/%d: %s\n", i+1, len(projects), project)
-
-		mrs, err := getProjectMRs(project, state)
-		if err != nil {
-			fmt.Printf("Warning: failed to get MRs for %s: %v\n", project, err)
-			continue
-		}
-
-		// Filter MRs based on criteria
-		for _, mr := range mrs {
-			if shouldIncludeMR(mr, days, includeDrafts) {
-				result.MergeRequests = append(result.MergeRequests, mr)
-			}
+	// Filter MRs based on criteria
+	for _, mr := range mrs {
+		if shouldIncludeMR(mr, days, includeDrafts) {
+			result.MergeRequests = append(result.MergeRequests, mr)
 		}
 	}
 
@@ -148,145 +138,32 @@ func runGitLabWatcher(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// checkGitLabCLI verifies that GitLab CLI is available and authenticated
-func checkGitLabCLI() error {
-	// Check if glab is installed
-	_, err := exec.LookPath("glab")
-	if err != nil {
-		return fmt.Errorf("GitLab CLI (glab) is not installed. Please install it from: https://gitlab.com/gitlab-org/cli")
-	}
-
-	// Check if glab is authenticated
-	cmd := exec.Command("glab", "auth", "status")
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("GitLab CLI is not authenticated. Please run: glab auth login")
-	}
-
-	return nil
-}
-
-// getGroupProjects fetches all projects for the given group
-func getGroupProjects(group string) ([]string, error) {
-	cmd := exec.Command("glab", "api", fmt.Sprintf("groups/%s/projects", group), "--paginate")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute glab command: %w", err)
-	}
-
-	var projects []struct {
-		PathWithNamespace string `json:"path_with_namespace"`
-	}
-
-	err = json.Unmarshal(output, &projects)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse projects JSON: %w", err)
-	}
-
-	var projectNames []string
-	for _, project := range projects {
-		projectNames = append(projectNames, project.PathWithNamespace)
-	}
-
-	return projectNames, nil
-}
-
-// getProjectMRs fetches all merge requests for the given project
-func getProjectMRs(project, state string) ([]GitLabMergeRequest, error) {
-	stateParam := "opened"
-	if state != "opened" {
-		stateParam = state
-	}
-
-	// URL encode the project path
-	projectPath := project
-
-	cmd := exec.Command("glab", "api", fmt.Sprintf("projects/%s/merge_requests", projectPath),
-		"--paginate",
-		"--field", fmt.Sprintf("state=%s", stateParam),
-		"--field", "per_page=100")
-
-	output, err := cmd.Output()
+// listGroupMergeRequests returns the number of projects of a group and their merge requests in project order
+// The projects are processed concurrently, a project whose merge requests cannot be read is skipped with a warning
+func listGroupMergeRequests(ctx context.Context, client *GitLabClient, group, state string, concurrency int) (int, []GitLabMergeRequest, error) {
+	projects, err := client.ListGroupProjects(ctx, group)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute glab mr list: %w", err)
-	}
-
-	var glMRs []struct {
-		IID          int       `json:"iid"`
-		Title        string    `json:"title"`
-		Author       struct {
-			Username string `json:"username"`
-		} `json:"author"`
-		CreatedAt    time.Time `json:"created_at"`
-		UpdatedAt    time.Time `json:"updated_at"`
-		WebURL       string    `json:"web_url"`
-		State        string    `json:"state"`
-		Draft        bool      `json:"draft"`
-		Labels       []string  `json:"labels"`
-		Assignees    []struct {
-			Username string `json:"username"`
-		} `json:"assignees"`
-		Reviewers    []struct {
-			Username string `json:"username"`
-		} `json:"reviewers"`
-		TargetBranch string `json:"target_branch"`
-		SourceBranch string `json:"source_branch"`
-		Pipeline     struct {
-			Status string `json:"status"`
-		} `json:"pipeline"`
+		return 0, nil, err
 	}
+	fmt.Printf("Found %d projects\n", len(projects))
 
-	err = json.Unmarshal(output, &glMRs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse MR JSON: %w", err)
-	}
-
-	var mrs []GitLabMergeRequest
-	for _, glMR := range glMRs {
-		// Calculate days open
-		daysOpen := int(time.Since(glMR.CreatedAt).Hours() / 24)
-
-		// Extract assignees
-		var assignees []string
-		for _, assignee := range glMR.Assignees {
-			assignees = append(assignees, assignee.Username)
-		}
-
-		// Extract reviewers
-		var reviewers []string
-		for _, reviewer := range glMR.Reviewers {
-			reviewers = append(reviewers, reviewer.Username)
-		}
-
-		// Get pipeline status
-		pipelineStatus := "none"
-		if glMR.Pipeline.Status != "" {
-			pipelineStatus = glMR.Pipeline.Status
-		}
+	projectMRs := make([][]GitLabMergeRequest, len(projects))
+	forEach(ctx, len(projects), concurrency, func(ctx context.Context, i int) {
+		fmt.Printf("Processing project %d/%d: %s\n", i+1, len(projects), projects[i])
 
-		mr := GitLabMergeRequest{
-			Project:      project,
-			IID:          glMR.IID,
-			Title:        glMR.Title,
-			Author:       glMR.Author.Username,
-			CreatedAt:    glMR.CreatedAt,
-			UpdatedAt:    glMR.UpdatedAt,
-			WebURL:       glMR.WebURL,
-			State:        glMR.State,
-			Draft:        glMR.Draft,
-			DaysOpen:     daysOpen,
-			Labels:       glMR.Labels,
-			Assignees:    assignees,
-			Reviewers:    reviewers,
-			TargetBranch: glMR.TargetBranch,
-			SourceBranch: glMR.SourceBranch,
-			Pipeline:     pipelineStatus,
+		mrs, err := client.ListProjectMergeRequests(ctx, projects[i], state)
+		if err != nil {
+			fmt.Printf("Warning: failed to get MRs for %s: %v\n", projects[i], err)
+			return
 		}
+		projectMRs[i] = mrs
+	})
 
-		mrs = append(mrs, mr)
+	var mrs []GitLabMergeRequest
+	for _, projectMR := range projectMRs {
+		mrs = append(mrs, projectMR...)
 	}
-
-	return mrs, nil
+	return len(projects), mrs, ctx.Err()
 }
 
 // shouldIncludeMR determines if an MR should be included in the results
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// SMTP ports, the submission port upgrades the connection with STARTTLS when the server offers it
+// and the submissions port is encrypted from the start (implicit TLS)
+const (
+	defaultSMTPPort = 587
+	smtpsPort       = 465
+)
+
+// Notifier sends a rendered digest to a channel
+type Notifier interface {
+	Notify(ctx context.Context, subject, message string) error
+}
+
+// newNotifier creates the notifier of a channel
+func newNotifier(channel ChannelConfig, email EmailConfig) (Notifier, error) {
+	switch channel.Type {
+	case channelWeCom:
+		return &wecomNotifier{api: newAPIClient("", ""), webhook: channel.Webhook}, nil
+	case channelSlack:
+		return &slackNotifier{api: newAPIClient("", ""), webhook: channel.Webhook}, nil
+	case channelEmail:
+		return &emailNotifier{config: email, to: channel.To, dial: (&net.Dialer{}).DialContext}, nil
+	default:
+		return nil, fmt.Errorf("unknown channel type %q", channel.Type)
+	}
+}
+
+// wecomNotifier posts markdown messages to a WeCom group bot webhook
+type wecomNotifier struct {
+	api     *apiClient
+	webhook string
+}
+
+// Notify sends the message, WeCom shows no subject
+func (n *wecomNotifier) Notify(ctx context.Context, subject, message string) error {
+	payload := map[string]any{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": message},
+	}
+	// WeCom answers 200 and reports errors in the body
+	var response struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if _, err := n.api.do(ctx, http.MethodPost, n.webhook, payload, &response); err != nil {
+		return fmt.Errorf("failed to send WeCom message: %w", err)
+	}
+	if response.ErrCode != 0 {
+		return fmt.Errorf("failed to send WeCom message: [%d] %s", response.ErrCode, response.ErrMsg)
+	}
+	return nil
+}
+
+// slackNotifier posts messages to a Slack incoming webhook
+type slackNotifier struct {
+	api     *apiClient
+	webhook string
+}
+
+// Notify sends the message, Slack shows no subject
+func (n *slackNotifier) Notify(ctx context.Context, subject, message string) error {
+	// incoming webhooks answer a plain text ok, so the response is not decoded
+	if _, err := n.api.do(ctx, http.MethodPost, n.webhook, map[string]string{"text": message}, nil); err != nil {
+		return fmt.Errorf("failed to send Slack message: %w", err)
+	}
+	return nil
+}
+
+// emailNotifier sends messages by email through an SMTP server
+type emailNotifier struct {
+	config EmailConfig
+	to     []string
+	// dial connects to the SMTP server, replaced in tests
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+	// tlsConfig verifies the server, the system roots when nil
+	tlsConfig *tls.Config
+}
+
+// Notify sends the message as a plain text email, the markdown of the templates reads fine as text
+func (n *emailNotifier) Notify(ctx context.Context, subject, message string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(message, "\n", "\r\n"))
+
+	if err := n.send(ctx, []byte(msg.String())); err != nil {
+		// a cancelled exchange fails on the closed connection
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send delivers a message like smtp.SendMail, which cannot be cancelled and only knows STARTTLS
+func (n *emailNotifier) send(ctx context.Context, msg []byte) error {
+	port := n.config.SMTPPort
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	tlsConfig := &tls.Config{ServerName: n.config.SMTPHost}
+	if n.tlsConfig != nil {
+		tlsConfig = n.tlsConfig.Clone()
+		tlsConfig.ServerName = n.config.SMTPHost
+	}
+
+	conn, err := n.dial(ctx, "tcp", net.JoinHostPort(n.config.SMTPHost, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	if port == smtpsPort {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	// the SMTP client has no context, closing the connection aborts the exchange
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, n.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != smtpsPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if n.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("%s does not support authentication", n.config.SMTPHost)
+		}
+		// PlainAuth refuses to send the password over an unencrypted connection except to localhost
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.SMTPHost)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.config.From); err != nil {
+		return err
+	}
+	for _, to := range n.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeComNotifier(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		expectedError string
+	}{
+		{name: "sent", response: `{"errcode": 0, "errmsg": "ok"}`},
+		{name: "invalid key", response: `{"errcode": 93000, "errmsg": "invalid webhook url"}`, expectedError: "[93000] invalid webhook url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&payload)
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			notifier, _ := newNotifier(ChannelConfig{Type: channelWeCom, Webhook: server.URL}, EmailConfig{})
+			err := notifier.Notify(context.Background(), "subject", "# Digest")
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("Notify() error = %v, want %q", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			expected := map[string]any{"msgtype": "markdown", "markdown": map[string]any{"content": "# Digest"}}
+			if !reflect.DeepEqual(payload, expected) {
+				t.Errorf("payload = %v, want %v", payload, expected)
+			}
+		})
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["text"] == "" {
+			http.Error(w, "no_text", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	notifier, _ := newNotifier(ChannelConfig{Type: channelSlack, Webhook: server.URL}, EmailConfig{})
+	if err := notifier.Notify(context.Background(), "subject", "*Digest*"); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if payload["text"] != "*Digest*" {
+		t.Errorf("text = %q, want %q", payload["text"], "*Digest*")
+	}
+	if err := notifier.Notify(context.Background(), "subject", ""); err == nil || !strings.Contains(err.Error(), "no_text") {
+		t.Errorf("Notify() error = %v, want no_text", err)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	tlsConfig, rootCAs := newTestTLSConfig(t)
+
+	tests := []struct {
+		name             string
+		port             int
+		username         string
+		implicitTLS      bool
+		startTLS         bool
+		expectedAddr     string
+		expectedCommands []string
+	}{
+		{
+			name:             "submission port without TLS",
+			expectedAddr:     "smtp.example.com:587",
+			expectedCommands: []string{"EHLO localhost", "MAIL FROM:<pr-watcher@example.com>", "RCPT TO:<alice@example.com>", "RCPT TO:<bob@example.com>", "DATA", "QUIT"},
+		},
+		{
+			name:         "submission port with STARTTLS",
+			port:         587,
+			username:     "pr-watcher",
+			startTLS:     true,
+			expectedAddr: "smtp.example.com:587",
+			expectedCommands: []string{"EHLO localhost", "STARTTLS", "EHLO localhost", "AUTH PLAIN AHByLXdhdGNoZXIAc2VjcmV0",
+				"MAIL FROM:<pr-watcher@example.com>", "RCPT TO:<alice@example.com>", "RCPT TO:<bob@example.com>", "DATA", "QUIT"},
+		},
+		{
+			name:         "submissions port with implicit TLS",
+			port:         465,
+			username:     "pr-watcher",
+			implicitTLS:  true,
+			expectedAddr: "smtp.example.com:465",
+			expectedCommands: []string{"EHLO localhost", "AUTH PLAIN AHByLXdhdGNoZXIAc2VjcmV0",
+				"MAIL FROM:<pr-watcher@example.com>", "RCPT TO:<alice@example.com>", "RCPT TO:<bob@example.com>", "DATA", "QUIT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &smtpServer{tlsConfig: tlsConfig, implicitTLS: tt.implicitTLS, startTLS: tt.startTLS}
+			listener := server.start(t)
+			var addr string
+			notifier := &emailNotifier{
+				config: EmailConfig{SMTPHost: "smtp.example.com", SMTPPort: tt.port, Username: tt.username, Password: "secret", From: "pr-watcher@example.com"},
+				to:     []string{"alice@example.com", "bob@example.com"},
+				dial: func(ctx context.Context, network, a string) (net.Conn, error) {
+					addr = a
+					return (&net.Dialer{}).DialContext(ctx, network, listener.Addr().String())
+				},
+				tlsConfig: &tls.Config{RootCAs: rootCAs},
+			}
+
+			if err := notifier.Notify(context.Background(), "GitHub PR digest for platform", "line 1\nline 2"); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			<-server.done
+			if addr != tt.expectedAddr {
+				t.Errorf("addr = %q, want %q", addr, tt.expectedAddr)
+			}
+			if !reflect.DeepEqual(server.commands, tt.expectedCommands) {
+				t.Errorf("commands = %q, want %q", server.commands, tt.expectedCommands)
+			}
+			// the data is read back with \n line endings
+			expected := "From: pr-watcher@example.com\n" +
+				"To: alice@example.com, bob@example.com\n" +
+				"Subject: GitHub PR digest for platform\n" +
+				"MIME-Version: 1.0\n" +
+				"Content-Type: text/plain; charset=UTF-8\n" +
+				"\n" +
+				"line 1\nline 2\n"
+			if server.data != expected {
+				t.Errorf("message = %q, want %q", server.data, expected)
+			}
+		})
+	}
+}
+
+func TestEmailNotifierCancel(t *testing.T) {
+	// the listener accepts connections but the server never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	notifier := &emailNotifier{
+		config: EmailConfig{SMTPHost: host, SMTPPort: portNumber, From: "pr-watcher@example.com"},
+		to:     []string{"alice@example.com"},
+		dial:   (&net.Dialer{}).DialContext,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := notifier.Notify(ctx, "subject", "message"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Notify() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// smtpServer is an SMTP server accepting a single session and recording its commands and data
+type smtpServer struct {
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	startTLS    bool
+	commands    []string
+	data        string
+	done        chan struct{}
+}
+
+func (s *smtpServer) start(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	if s.implicitTLS {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+	s.done = make(chan struct{})
+	go s.serve(listener)
+	return listener
+}
+
+func (s *smtpServer) serve(listener net.Listener) {
+	defer close(s.done)
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { conn.Close() }()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 smtp.example.com ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands = append(s.commands, line)
+		verb, _, _ := strings.Cut(line, " ")
+		switch verb {
+		case "EHLO":
+			_, encrypted := conn.(*tls.Conn)
+			if s.startTLS && !encrypted {
+				text.PrintfLine("250-smtp.example.com\r\n250 STARTTLS")
+			} else {
+				text.PrintfLine("250-smtp.example.com\r\n250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			text.PrintfLine("220 Ready to start TLS")
+			conn = tls.Server(conn, s.tlsConfig)
+			text = textproto.NewConn(conn)
+		case "AUTH":
+			text.PrintfLine("235 Authenticated")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data = string(data)
+			text.PrintfLine("250 Queued")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+// newTestTLSConfig returns the TLS config of a server with a self-signed certificate for smtp.example.com
+// and the roots trusting it
+func newTestTLSConfig(t *testing.T) (*tls.Config, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "smtp.example.com"},
+		DNSNames:     []string{"smtp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(certificate)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, roots
+}
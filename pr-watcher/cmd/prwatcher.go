@@ -1,10 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -47,6 +47,8 @@ var prWatcherCmd = &cobra.Command{
 pull requests that have been open for longer than the specified number of days.
 It returns a comprehensive JSON report with all relevant PR information.
 
+It reads the GitHub GraphQL API with a token from --token, GITHUB_TOKEN or GH_TOKEN.
+
 Example:
   pr-watcher watch-prs --org myorg --days 7 --output results.json`,
 	RunE: runPRWatcher,
@@ -61,6 +63,9 @@ func init() {
 	prWatcherCmd.Flags().StringP("output", "f", "", "Output file path (optional, prints to stdout if not specified)")
 	prWatcherCmd.Flags().BoolP("include-drafts", "", false, "Include draft pull requests in the results")
 	prWatcherCmd.Flags().StringP("state", "s", "open", "PR state to filter by (open, closed, merged, all)")
+	prWatcherCmd.Flags().String("token", "", "GitHub token (defaults to the GITHUB_TOKEN or GH_TOKEN environment variable)")
+	prWatcherCmd.Flags().String("api-url", defaultGitHubAPIURL, "GitHub GraphQL endpoint, https://HOST/api/graphql for GitHub Enterprise")
+	prWatcherCmd.Flags().Int("concurrency", defaultConcurrency, "Number of repositories fetched at the same time")
 
 	// Mark required flags
 	prWatcherCmd.MarkFlagRequired("org")
@@ -74,6 +79,15 @@ func runPRWatcher(cmd *cobra.Command, args []string) error {
 	outputFile, _ := cmd.Flags().GetString("output")
 	includeDrafts, _ := cmd.Flags().GetBool("include-drafts")
 	state, _ := cmd.Flags().GetString("state")
+	token, _ := cmd.Flags().GetString("token")
+	apiURL, _ := cmd.Flags().GetString("api-url")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+	token = firstNonEmpty(token, os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN"))
+	if token == "" {
+		return fmt.Errorf("a GitHub token is required, set --token or the GITHUB_TOKEN environment variable")
+	}
+	client := NewGitHubClient(apiURL, token)
 
 	fmt.Printf("Scanning organization '%s' for PRs older than %d days...\n", org, days)
 
@@ -85,8 +99,8 @@ func runPRWatcher(cmd *cobra.Command, args []string) error {
 		PullRequests: []PullRequest{},
 	}
 
-	// Get all repositories for the organization
-	repos, err := getOrgRepositories(org)
+	// Get all repositories of the organization with their pull requests
+	repos, err := client.ListOrgPullRequests(context.Background(), org, state, concurrency)
 	if err != nil {
 		return fmt.Errorf("failed to get repositories: %w", err)
 	}
@@ -94,18 +108,12 @@ func runPRWatcher(cmd *cobra.Command, args []string) error {
 	result.TotalRepos = len(repos)
 	fmt.Printf("Found %d repositories\n", len(repos))
 
-	// Process each repository
-	for i, repo := range repos {
-		fmt.Printf("Processing repository %d/%d: %s\n", i+1, len(repos), repo)
-
-		prs, err := getRepositoryPRs(repo, state)
-		if err != nil {
-			fmt.Printf("Warning: failed to get PRs for %s: %v\n", repo, err)
-			continue
+	// Filter PRs based on criteria
+	for _, repo := range repos {
+		if repo.Err != nil {
+			fmt.Printf("Warning: failed to get all PRs for %s: %v\n", repo.Name, repo.Err)
 		}
-
-		// Filter PRs based on criteria
-		for _, pr := range prs {
+		for _, pr := range repo.PullRequests {
 			if shouldIncludePR(pr, days, includeDrafts) {
 				result.PullRequests = append(result.PullRequests, pr)
 			}
@@ -135,118 +143,14 @@ func runPRWatcher(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// getOrgRepositories fetches all repositories for the given organization
-func getOrgRepositories(org string) ([]string, error) {
-	cmd := exec.Command("gh", "repo", "list", org, "--limit", "1000", "--json", "name")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute gh command: %w", err)
-	}
-
-	var repos []struct {
-		Name string `json:"name"`
-	}
-
-	err = json.Unmarshal(output, &repos)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse repository JSON: %w", err)
-	}
-
-	var repoNames []string
-	for _, repo := range repos {
-		repoNames = append(repoNames, fmt.Sprintf("%s/%s", org, repo.Name))
-	}
-
-	return repoNames, nil
-}
-
-// getRepositoryPRs fetches all pull requests for the given repository
-func getRepositoryPRs(repo, state string) ([]PullRequest, error) {
-	cmd := exec.Command("gh", "pr", "list",
-		"--repo", repo,
-		"--state", state,
-		"--limit", "100",
-		"--json", "number,title,author,createdAt,updatedAt,url,state,isDraft,labels,assignees,reviewRequests,baseRefName,headRefName")
-
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute gh pr list: %w", err)
-	}
-
-	var ghPRs []struct {
-		Number         int       `json:"number"`
-		Title          string    `json:"title"`
-		Author         struct {
-			Login string `json:"login"`
-		} `json:"author"`
-		CreatedAt      time.Time `json:"createdAt"`
-		UpdatedAt      time.Time `json:"updatedAt"`
-		URL            string    `json:"url"`
-		State          string    `json:"state"`
-		IsDraft        bool      `json:"isDraft"`
-		Labels         []struct {
-			Name string `json:"name"`
-		} `json:"labels"`
-		Assignees      []struct {
-			Login string `json:"login"`
-		} `json:"assignees"`
-		ReviewRequests []struct {
-			Login string `json:"login"`
-		} `json:"reviewRequests"`
-		BaseRefName    string `json:"baseRefName"`
-		HeadRefName    string `json:"headRefName"`
-	}
-
-	err = json.Unmarshal(output, &ghPRs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse PR JSON: %w", err)
-	}
-
-	var prs []PullRequest
-	for _, ghPR := range ghPRs {
-		// Calculate days open
-		daysOpen := int(time.Since(ghPR.CreatedAt).Hours() / 24)
-
-		// Extract labels
-		var labels []string
-		for _, label := range ghPR.Labels {
-			labels = append(labels, label.Name)
-		}
-
-		// Extract assignees
-		var assignees []string
-		for _, assignee := range ghPR.Assignees {
-			assignees = append(assignees, assignee.Login)
-		}
-
-		// Extract reviewers
-		var reviewers []string
-		for _, reviewer := range ghPR.ReviewRequests {
-			reviewers = append(reviewers, reviewer.Login)
+// firstNonEmpty returns the first non empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
 		}
-
-		pr := PullRequest{
-			Repository: repo,
-			Number:     ghPR.Number,
-			Title:      ghPR.Title,
-			Author:     ghPR.Author.Login,
-			CreatedAt:  ghPR.CreatedAt,
-			UpdatedAt:  ghPR.UpdatedAt,
-			URL:        ghPR.URL,
-			State:      ghPR.State,
-			Draft:      ghPR.IsDraft,
-			DaysOpen:   daysOpen,
-			Labels:     labels,
-			Assignees:  assignees,
-			Reviewers:  reviewers,
-			BaseBranch: ghPR.BaseRefName,
-			HeadBranch: ghPR.HeadRefName,
-		}
-
-		prs = append(prs, pr)
 	}
-
-	return prs, nil
+	return ""
 }
 
 // shouldIncludePR determines if a PR should be included in the results
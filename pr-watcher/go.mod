@@ -2,7 +2,10 @@ module github.com/alaudadevops/toolbox/pr-watcher
 
 go 1.25.4
 
-require github.com/spf13/cobra v1.9.1
+require (
+	github.com/spf13/cobra v1.9.1
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
@@ -1,53 +1,48 @@
 #!/bin/bash
 
-# Script to check GitHub CLI prerequisites
+# Script to check the API tokens used by PR Watcher
 set -e
 
 echo "🔍 Checking prerequisites for PR Watcher CLI..."
 
-# Check if GitHub CLI is installed
-if ! command -v gh &> /dev/null; then
-    echo "❌ GitHub CLI (gh) is not installed"
-    echo "   Please install it from: https://cli.github.com/"
+GITHUB_API_URL=${GITHUB_API_URL:-https://api.github.com}
+GITLAB_HOST=${GITLAB_HOST:-gitlab.com}
+
+# Check if a GitHub token is set
+GITHUB_TOKEN=${GITHUB_TOKEN:-$GH_TOKEN}
+if [ -z "$GITHUB_TOKEN" ]; then
+    echo "❌ GITHUB_TOKEN is not set"
+    echo "   Please export a token with the repo and read:org scopes"
     exit 1
 fi
 
-echo "✅ GitHub CLI is installed: $(gh --version | head -n1)"
+echo "✅ GitHub token is set"
 
-# Check if GitHub CLI is authenticated
-if ! gh auth status &> /dev/null; then
-    echo "❌ GitHub CLI is not authenticated"
-    echo "   Please run: gh auth login"
+# Check if the GitHub token is valid
+if ! USER_INFO=$(curl -fsS -H "Authorization: Bearer $GITHUB_TOKEN" "$GITHUB_API_URL/user" | sed -n 's/.*"login": *"\([^"]*\)".*/\1/p'); then
+    echo "❌ GitHub token is invalid"
     exit 1
 fi
-
-echo "✅ GitHub CLI is authenticated"
-
-# Get current user info
-USER_INFO=$(gh api user --jq '.login')
 echo "✅ Authenticated as: $USER_INFO"
 
 echo ""
-# Check if GitLab CLI is installed (optional)
-if command -v glab &> /dev/null; then
-    echo "✅ GitLab CLI is installed: $(glab --version | head -n1)"
-
-    # Check if GitLab CLI is authenticated
-    if glab auth status &> /dev/null; then
-        echo "✅ GitLab CLI is authenticated"
-        GITLAB_USER=$(glab api user --jq '.username' 2>/dev/null || echo "Unknown")
+# Check if a GitLab token is set (optional)
+if [ -n "$GITLAB_TOKEN" ]; then
+    echo "✅ GitLab token is set"
+
+    # Check if the GitLab token is valid
+    if GITLAB_USER=$(curl -fsS -H "PRIVATE-TOKEN: $GITLAB_TOKEN" "https://$GITLAB_HOST/api/v4/user" | sed -n 's/.*"username": *"\([^"]*\)".*/\1/p'); then
         echo "✅ GitLab authenticated as: $GITLAB_USER"
     else
-        echo "⚠️  GitLab CLI is not authenticated (run: glab auth login)"
+        echo "⚠️  GitLab token is invalid for $GITLAB_HOST"
     fi
 else
-    echo "⚠️  GitLab CLI (glab) is not installed (optional for GitLab features)"
-    echo "   Install from: https://gitlab.com/gitlab-org/cli"
+    echo "⚠️  GITLAB_TOKEN is not set (optional for GitLab features)"
 fi
 
 echo ""
 echo "🎉 GitHub prerequisites are met!"
-if command -v glab &> /dev/null && glab auth status &> /dev/null; then
+if [ -n "$GITLAB_TOKEN" ]; then
     echo "🎉 GitLab prerequisites are also met!"
 fi
 
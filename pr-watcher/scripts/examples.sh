@@ -53,7 +53,7 @@ echo ""
 
 echo "🔧 Prerequisites:"
 echo "================="
-echo "- For GitHub: Install and authenticate 'gh' (GitHub CLI)"
-echo "- For GitLab: Install and authenticate 'glab' (GitLab CLI)"
+echo "- For GitHub: Export a token in GITHUB_TOKEN"
+echo "- For GitLab: Export a token in GITLAB_TOKEN"
 echo ""
 echo "Run './scripts/check-prerequisites.sh' to verify your setup."
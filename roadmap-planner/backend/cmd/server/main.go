@@ -26,11 +26,15 @@ import (
 	"time"
 
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/audit"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/cache"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/calculators"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -67,18 +71,63 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Create router
-	router := api.NewRouter(cfg)
-
 	// Create context for background workers
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Create the read cache for Jira queries and refresh it in background
+	readCache := cache.New(&cfg.Cache)
+	go func() {
+		if err := readCache.Start(ctx); err != nil && err != context.Canceled {
+			logger.Error("Cache refresher stopped with error", zap.Error(err))
+		}
+	}()
+
+	// Open the storage persisting the sessions, audit log, collected data and metric snapshots
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Error("Failed to open storage, continuing without persistence", zap.Error(err))
+	}
+	if store != nil {
+		logger.Info("Storage opened", zap.String("driver", cfg.Storage.Driver), zap.String("path", cfg.Storage.Path))
+		go func() {
+			<-ctx.Done()
+			if err := store.Close(); err != nil {
+				logger.Warn("Failed to close storage", zap.Error(err))
+			}
+		}()
+	}
+
+	// Create the session manager and remove expired sessions in background
+	// sessions are kept in the storage when there is one so that they survive restarts
+	var sessionStore session.Store = session.NewMemoryStore()
+	if store != nil {
+		sessionStore = session.NewStorageStore(store)
+	} else {
+		logger.Warn("No storage configured, sessions are kept in memory and will not survive restarts")
+	}
+	sessions, err := session.NewManager(&cfg.Auth, sessionStore)
+	if err != nil {
+		logger.Fatal("Failed to create session manager", zap.Error(err))
+	}
+	if cfg.Auth.OIDC.Enabled {
+		// single sign-on sessions read and write with the jira service account
+		sessions.UseServiceAccount(&cfg.Jira)
+	}
+	go func() {
+		if err := sessions.Start(ctx); err != nil && err != context.Canceled {
+			logger.Error("Session cleanup stopped with error", zap.Error(err))
+		}
+	}()
+
+	// Create router
+	router := api.NewRouter(cfg, sessions, readCache, audit.NewLog(store))
+
 	// Initialize metrics system if enabled
 
 	if cfg.Metrics.Enabled {
 		logger.Info("Initializing metrics system")
-		err = initMetrics(ctx, router, cfg)
+		err = initMetrics(ctx, router, cfg, sessions, store)
 		if err != nil {
 			logger.Error("Failed to initialize metrics system", zap.Error(err))
 		}
@@ -119,7 +168,7 @@ func main() {
 }
 
 // initMetrics initializes the metrics system if enabled in config
-func initMetrics(ctx context.Context, router *gin.Engine, cfg *config.Config) error {
+func initMetrics(ctx context.Context, router *gin.Engine, cfg *config.Config, sessions *session.Manager, store storage.Store) error {
 	if cfg.Jira.BaseURL == "" || cfg.Jira.Username == "" || cfg.Jira.Password == "" {
 		logger.Warn("Metrics enabled but Jira credentials not configured in config file")
 		return nil
@@ -136,8 +185,9 @@ func initMetrics(ctx context.Context, router *gin.Engine, cfg *config.Config) er
 		return err
 	}
 	// Create collector and service
-	collector := metrics.NewCollector(jiraClient, &cfg.Metrics)
-	metricsService := metrics.NewService(&cfg.Metrics, collector)
+	collector := metrics.NewCollector(jiraClient, &cfg.Metrics, store)
+	metricsService := metrics.NewService(&cfg.Metrics, collector, store)
+	metricsService.TrendWindow = parseDuration(cfg.Storage.TrendWindow, metricsService.TrendWindow)
 
 	// Register calculators
 	registerCalculators(metricsService, &cfg.Metrics)
@@ -149,8 +199,19 @@ func initMetrics(ctx context.Context, router *gin.Engine, cfg *config.Config) er
 		}
 	}()
 
+	// Record metric snapshots in background
+	if store != nil {
+		snapshotInterval := parseDuration(cfg.Storage.SnapshotInterval, time.Hour)
+		retention := time.Duration(cfg.Storage.RetentionDays) * 24 * time.Hour
+		go func() {
+			if err := metricsService.StartSnapshots(ctx, snapshotInterval, retention); err != nil && err != context.Canceled {
+				logger.Error("Metric snapshot recorder stopped with error", zap.Error(err))
+			}
+		}()
+	}
+
 	// Add metrics API routes
-	api.AddMetricsRoutes(router, cfg, metricsService)
+	api.AddMetricsRoutes(router, cfg, sessions, metricsService)
 	logger.Info("Metrics API routes added")
 
 	// Initialize Prometheus exporter if enabled
@@ -171,6 +232,18 @@ func initMetrics(ctx context.Context, router *gin.Engine, cfg *config.Config) er
 	return nil
 }
 
+// parseDuration parses a configured duration, falling back to the default when invalid
+func parseDuration(value string, defaultValue time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		if value != "" {
+			logger.Warn("Invalid duration, using default", zap.String("configured", value), zap.Duration("default", defaultValue))
+		}
+		return defaultValue
+	}
+	return d
+}
+
 // registerCalculators registers all metric calculators with the service
 func registerCalculators(svc *metrics.Service, cfg *config.Metrics) {
 	// Get options for each calculator from config
@@ -208,5 +281,30 @@ func registerCalculators(svc *metrics.Service, cfg *config.Metrics) {
 		logger.Warn("Failed to register time_to_patch calculator", zap.Error(err))
 	}
 
+	// Register change failure rate calculator
+	if err := svc.RegisterCalculator(calculators.NewChangeFailureRateCalculator(getOptions("change_failure_rate"))); err != nil {
+		logger.Warn("Failed to register change_failure_rate calculator", zap.Error(err))
+	}
+
+	// Register mean time to restore calculator
+	if err := svc.RegisterCalculator(calculators.NewMeanTimeToRestoreCalculator(getOptions("mean_time_to_restore"))); err != nil {
+		logger.Warn("Failed to register mean_time_to_restore calculator", zap.Error(err))
+	}
+
+	// Register WIP age calculator
+	if err := svc.RegisterCalculator(calculators.NewWIPAgeCalculator(getOptions("wip_age"))); err != nil {
+		logger.Warn("Failed to register wip_age calculator", zap.Error(err))
+	}
+
+	// Register throughput calculator
+	if err := svc.RegisterCalculator(calculators.NewThroughputCalculator(getOptions("throughput"))); err != nil {
+		logger.Warn("Failed to register throughput calculator", zap.Error(err))
+	}
+
+	// Register flow efficiency calculator
+	if err := svc.RegisterCalculator(calculators.NewFlowEfficiencyCalculator(getOptions("flow_efficiency"))); err != nil {
+		logger.Warn("Failed to register flow_efficiency calculator", zap.Error(err))
+	}
+
 	logger.Info("Metric calculators registered", zap.Int("count", svc.Registry().Count()))
 }
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/audit"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAuditLimit is the number of audit entries returned when no limit is given
+	defaultAuditLimit = 100
+	// maxAuditLimit is the largest number of audit entries returned at once
+	maxAuditLimit = 1000
+)
+
+// GetAuditLog returns the changes of the roadmap, most recent first,
+// filtered by entity, entity type, user and date
+func (h *RoadmapHandler) GetAuditLog(c *gin.Context) {
+	from, err := parseAuditTime(c.Query("from"), false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	to, err := parseAuditTime(c.Query("to"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAuditLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit value",
+		})
+		return
+	}
+
+	project, _ := middleware.GetProject(c)
+	entries, err := h.audit.List(c.Request.Context(), storage.AuditQuery{
+		Project:    project,
+		EntityType: c.Query("entity_type"),
+		Entity:     c.Query("entity"),
+		Actor:      c.Query("user"),
+		From:       from,
+		To:         to,
+		Limit:      min(limit, maxAuditLimit),
+	})
+	if err != nil {
+		h.logger.Error("Failed to list audit entries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list audit entries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
+
+// GetAuditSnapshot returns the milestones and epics as they were at a past time,
+// replaying the audit log back from the current roadmap
+func (h *RoadmapHandler) GetAuditSnapshot(c *gin.Context) {
+	jiraClient, ok := middleware.GetJiraClient(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Jira client not available",
+		})
+		return
+	}
+
+	if c.Query("at") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "The at parameter is required",
+		})
+		return
+	}
+	at, err := parseAuditTime(c.Query("at"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	milestones, err := jiraClient.GetMilestonesWithFilter(ctx, nil, nil)
+	if err != nil {
+		h.logger.Error("Failed to fetch milestones", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch milestones",
+		})
+		return
+	}
+	epics, err := jiraClient.GetEpicsWithFilter(ctx, nil, nil, nil, nil)
+	if err != nil {
+		h.logger.Error("Failed to fetch epics", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch epics",
+		})
+		return
+	}
+
+	project, _ := middleware.GetProject(c)
+	entries, err := h.audit.List(ctx, storage.AuditQuery{Project: project, From: at})
+	if err != nil {
+		h.logger.Error("Failed to list audit entries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list audit entries",
+		})
+		return
+	}
+
+	board, err := audit.Replay(milestones, epics, entries, at)
+	if err != nil {
+		h.logger.Error("Failed to replay audit log", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to replay audit log",
+		})
+		return
+	}
+	filterBoardByQuarter(board, c.QueryArray("quarter"))
+
+	c.JSON(http.StatusOK, board)
+}
+
+// filterBoardByQuarter keeps the milestones of the quarters and the epics linked to them
+// The filter is applied after the replay because the quarter of a milestone can change
+func filterBoardByQuarter(board *audit.Board, quarters []string) {
+	if len(quarters) == 0 {
+		return
+	}
+	selected := map[string]bool{}
+	for _, quarter := range quarters {
+		selected[quarter] = true
+	}
+
+	kept := map[string]bool{}
+	milestones := []models.Milestone{}
+	for _, milestone := range board.Milestones {
+		if selected[milestone.Quarter] {
+			kept[milestone.ID] = true
+			milestones = append(milestones, milestone)
+		}
+	}
+	epics := []models.Epic{}
+	for _, epic := range board.Epics {
+		for _, milestoneID := range epic.MilestoneIDs {
+			if kept[milestoneID] {
+				epics = append(epics, epic)
+				break
+			}
+		}
+	}
+	board.Milestones, board.Epics = milestones, epics
+}
+
+// parseAuditTime parses an RFC 3339 time or a date
+// A date is the end of the day when endOfDay is true, so that the whole day is included
+func parseAuditTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339 or YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Millisecond)
+	}
+	return t, nil
+}
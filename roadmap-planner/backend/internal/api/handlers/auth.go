@@ -17,28 +17,39 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/cache"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	logger *zap.Logger
+	logger   *zap.Logger
+	config   *config.Config
+	sessions *session.Manager
+	cache    *cache.Cache
 }
 
 // NewAuthHandler creates a new AuthHandler
-func NewAuthHandler() *AuthHandler {
+// the cached queries of a user are removed on logout, readCache can be nil
+func NewAuthHandler(cfg *config.Config, sessions *session.Manager, readCache *cache.Cache) *AuthHandler {
 	return &AuthHandler{
-		logger: logger.WithComponent("auth-handler"),
+		logger:   logger.WithComponent("auth-handler"),
+		config:   cfg,
+		sessions: sessions,
+		cache:    readCache,
 	}
 }
 
-// Login handles user authentication with Jira
+// Login validates the Jira credentials and starts a session stored in an HTTP-only cookie
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.AuthRequest
 	h.logger.Info("Received login request")
@@ -55,8 +66,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		zap.String("base_url", req.BaseURL),
 		zap.String("username", req.Username))
 
+	project := req.Project
+	if project == "" {
+		project = h.config.Jira.Project
+	}
+
 	// Create Jira client to test credentials
-	jiraClient, err := jira.NewClient(req.BaseURL, req.Username, req.Password, "DEVOPS")
+	jiraClient, err := jira.NewClient(req.BaseURL, req.Username, req.Password, project)
 	if err != nil {
 		h.logger.Error("Failed to create Jira client", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -74,17 +90,38 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// In a real implementation, you would create a session or JWT token here
-	// For simplicity, we'll just return success
+	token, userSession, err := h.sessions.Create(c.Request.Context(), req.Username, req.Password, req.BaseURL, project)
+	if err != nil {
+		h.logger.Error("Failed to create session", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create session",
+		})
+		return
+	}
+	setSessionCookie(c, &h.config.Auth, token, int(h.sessions.TTL().Seconds()))
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Authentication successful",
-		"user":    req.Username,
+		"message":    "Authentication successful",
+		"user":       userSession.Username,
+		"base_url":   userSession.BaseURL,
+		"project":    userSession.Project,
+		"expires_at": userSession.ExpiresAt,
 	})
 }
 
-// Logout handles user logout
+// Logout revokes the session, removes the cached queries of the user and clears the session cookie
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// In a real implementation, you would invalidate the session or token here
+	if token, err := c.Cookie(h.config.Auth.CookieName); err == nil && token != "" {
+		// the cached queries are reloaded with the Jira client of the session
+		if userSession, err := h.sessions.Authenticate(c.Request.Context(), token); err == nil {
+			h.cache.InvalidateUser(userSession.Username)
+		}
+		if err := h.sessions.Revoke(c.Request.Context(), token); err != nil && !errors.Is(err, session.ErrInvalidToken) {
+			h.logger.Error("Failed to revoke session", zap.Error(err))
+		}
+	}
+	setSessionCookie(c, &h.config.Auth, "", -1)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logout successful",
 	})
@@ -92,9 +129,16 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 // Status returns the current authentication status
 func (h *AuthHandler) Status(c *gin.Context) {
-	// Check if user is authenticated by trying to get user from context
-	username := c.GetHeader("X-Jira-Username")
-	if username == "" {
+	token, err := c.Cookie(h.config.Auth.CookieName)
+	if err != nil || token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"authenticated": false,
+		})
+		return
+	}
+
+	userSession, err := h.sessions.Authenticate(c.Request.Context(), token)
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"authenticated": false,
 		})
@@ -103,6 +147,23 @@ func (h *AuthHandler) Status(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"authenticated": true,
-		"user":          username,
+		"user":          userSession.Username,
+		"base_url":      userSession.BaseURL,
+		"project":       userSession.Project,
+		"expires_at":    userSession.ExpiresAt,
+		"sso":           userSession.Delegated,
 	})
 }
+
+// Config returns the login methods offered to the users
+func (h *AuthHandler) Config(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"oidc": h.config.Auth.OIDC.Enabled,
+	})
+}
+
+// setSessionCookie sets the session cookie, a negative maxAge deletes it
+func setSessionCookie(c *gin.Context, cfg *config.Auth, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(cfg.CookieName, token, maxAge, "/", "", cfg.CookieSecure, true)
+}
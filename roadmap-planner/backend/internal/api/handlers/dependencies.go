@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/cache"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/dependency"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetDependencies returns the dependency graph of the epics of milestones or quarters
+// Epics outside the selection that the selected epics depend on are added as external nodes
+func (h *RoadmapHandler) GetDependencies(c *gin.Context) {
+	jiraClient, ok := middleware.GetJiraClient(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Jira client not available",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	milestoneIDs := c.QueryArray("milestone_id")
+	quarters := c.QueryArray("quarter")
+
+	// restrict the milestones to the quarters
+	if len(quarters) > 0 {
+		milestones, err := cache.Get(ctx, h.cache, h.cacheKey(c, cache.KindMilestones, cache.Filter(nil, quarters)), func(ctx context.Context) ([]models.Milestone, error) {
+			return jiraClient.GetMilestonesWithFilter(ctx, nil, quarters)
+		})
+		if err != nil {
+			h.logger.Error("Failed to fetch milestones", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch milestones",
+			})
+			return
+		}
+		inQuarters := []string{}
+		for _, milestone := range milestones {
+			if len(milestoneIDs) == 0 || slices.Contains(milestoneIDs, milestone.ID) {
+				inQuarters = append(inQuarters, milestone.ID)
+			}
+		}
+		if len(inQuarters) == 0 {
+			c.JSON(http.StatusOK, dependency.Build(nil, nil, nil))
+			return
+		}
+		milestoneIDs = inQuarters
+	}
+
+	epics, err := cache.Get(ctx, h.cache, h.cacheKey(c, cache.KindEpics, cache.Filter(milestoneIDs, nil, nil, nil)), func(ctx context.Context) ([]models.Epic, error) {
+		return jiraClient.GetEpicsWithFilter(ctx, milestoneIDs, nil, nil, nil)
+	})
+	if err != nil {
+		h.logger.Error("Failed to fetch epics", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch epics",
+		})
+		return
+	}
+
+	external, err := jiraClient.GetEpicsByKeys(ctx, externalDependencies(epics))
+	if err != nil {
+		h.logger.Error("Failed to fetch dependencies", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch dependencies",
+		})
+		return
+	}
+
+	projectKey, _ := middleware.GetProject(c)
+	project, err := cache.Get(ctx, h.cache, h.cacheKey(c, cache.KindProject, projectKey), func(ctx context.Context) (*models.Project, error) {
+		return jiraClient.GetProjectDetails(ctx, projectKey)
+	})
+	if err != nil {
+		h.logger.Error("Failed to fetch project details", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch project details",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dependency.Build(epics, external, dependency.ReleaseDates(project.Versions)))
+}
+
+// externalDependencies returns the keys of the epics blocking the epics without being part of them
+func externalDependencies(epics []models.Epic) []string {
+	selected := map[string]bool{}
+	for _, epic := range epics {
+		selected[epic.Key] = true
+	}
+	keys := []string{}
+	for _, epic := range epics {
+		for _, key := range epic.BlockedBy {
+			if !selected[key] && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/forecast"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxForecastRuns bounds the number of simulations requested for each milestone
+const maxForecastRuns = 100000
+
+// GetForecast forecasts the completion of the open milestones of quarters, or of the given milestones,
+// from the throughput of the collected epics and issues
+// GET /api/forecast
+func (h *MetricsHandler) GetForecast(c *gin.Context) {
+	jiraClient, ok := middleware.GetJiraClient(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Jira client not available",
+		})
+		return
+	}
+	// the collector only has the throughput of the project of the service account
+	if project, _ := middleware.GetProject(c); !strings.EqualFold(project, h.config.Jira.Project) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Forecasts are only available for project " + h.config.Jira.Project,
+		})
+		return
+	}
+
+	milestoneIDs := c.QueryArray("milestone_id")
+	quarters := c.QueryArray("quarter")
+	if len(milestoneIDs) == 0 && len(quarters) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "quarter or milestone_id is required",
+		})
+		return
+	}
+	historyWeeks, err := strconv.Atoi(c.DefaultQuery("history_weeks", strconv.Itoa(forecast.DefaultHistoryWeeks)))
+	if err != nil || historyWeeks <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "history_weeks must be a positive number",
+		})
+		return
+	}
+	runs, err := strconv.Atoi(c.DefaultQuery("runs", strconv.Itoa(forecast.DefaultRuns)))
+	if err != nil || runs <= 0 || runs > maxForecastRuns {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "runs must be between 1 and " + strconv.Itoa(maxForecastRuns),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	milestones, err := jiraClient.GetMilestonesWithFilter(ctx, nil, quarters)
+	if err != nil {
+		h.logger.Error("Failed to fetch milestones", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch milestones",
+		})
+		return
+	}
+	if len(milestoneIDs) > 0 {
+		milestones = slices.DeleteFunc(milestones, func(milestone baseModels.Milestone) bool {
+			return !slices.Contains(milestoneIDs, milestone.ID)
+		})
+	}
+
+	now := time.Now()
+	collector := h.service.Collector()
+	data, err := collector.GetData()
+	if err != nil {
+		h.logger.Error("Failed to get collected data", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get collected data",
+		})
+		return
+	}
+	history := forecast.NewHistory(data.Epics, data.Issues, now, historyWeeks)
+
+	// epics are fetched at once and split by milestone
+	epicsByMilestone := map[string][]baseModels.Epic{}
+	if len(milestones) > 0 {
+		ids := make([]string, 0, len(milestones))
+		for _, milestone := range milestones {
+			ids = append(ids, milestone.ID)
+		}
+		epics, err := jiraClient.GetEpicsWithFilter(ctx, ids, nil, nil, nil)
+		if err != nil {
+			h.logger.Error("Failed to fetch epics", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch epics",
+			})
+			return
+		}
+		for _, epic := range epics {
+			for _, milestoneID := range epic.MilestoneIDs {
+				epicsByMilestone[milestoneID] = append(epicsByMilestone[milestoneID], epic)
+			}
+		}
+	}
+
+	// issues are limited to the collected types, the history only has their throughput
+	issueTypes := collector.IssueTypes()
+	forecasts := make([]forecast.Milestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		epics := epicsByMilestone[milestone.ID]
+		var issues []baseModels.Issue
+		if len(epics) > 0 {
+			epicIDs := make([]string, 0, len(epics))
+			for _, epic := range epics {
+				epicIDs = append(epicIDs, epic.ID)
+			}
+			issues, err = jiraClient.GetIssuesWithFilter(ctx, epicIDs, nil, nil, issueTypes)
+			if err != nil {
+				h.logger.Error("Failed to fetch issues", zap.String("milestone", milestone.Key), zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to fetch issues",
+				})
+				return
+			}
+		}
+		forecasts = append(forecasts, forecast.Forecast(milestone, epics, issues, history, forecast.Options{Now: now, Runs: runs}))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history_weeks": historyWeeks,
+		"runs":          runs,
+		"forecasts":     forecasts,
+	})
+}
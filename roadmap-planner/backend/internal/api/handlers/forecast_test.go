@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetForecast_Project(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jiraClient, err := jira.NewClient("https://jira.example.com", "user", "token", "DEVOPS")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	handler := NewMetricsHandler(&config.Config{Jira: config.Jira{Project: "DEVOPS"}}, nil)
+
+	// the requests stop at the missing quarter once the project is accepted, before Jira or the collector are used
+	tests := map[string]struct {
+		project   string
+		wantError string
+	}{
+		"project of the collector":         {project: "DEVOPS", wantError: "quarter or milestone_id is required"},
+		"project of the collector in case": {project: "devops", wantError: "quarter or milestone_id is required"},
+		"another project":                  {project: "OTHER", wantError: "Forecasts are only available for project DEVOPS"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/forecast", nil)
+			c.Set(middleware.JiraClientKey, jiraClient)
+			c.Set(middleware.ProjectKey, tc.project)
+
+			handler.GetForecast(c)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if body["error"] != tc.wantError {
+				t.Errorf("error = %q, want %q", body["error"], tc.wantError)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -91,6 +92,50 @@ func (h *MetricsHandler) GetMetric(c *gin.Context) {
 	}
 }
 
+// GetMetricHistory returns the recorded values of a metric over time
+// GET /api/metrics/:name/history
+func (h *MetricsHandler) GetMetricHistory(c *gin.Context) {
+	name := c.Param("name")
+	component := c.Query("component")
+	timeRange := h.parseTimeRange(c)
+
+	snapshots, err := h.service.MetricHistory(c.Request.Context(), name, component, timeRange)
+	if errors.Is(err, metrics.ErrStorageDisabled) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to load metric history",
+			zap.String("metric", name),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load metric history: " + err.Error(),
+		})
+		return
+	}
+
+	points := make([]gin.H, 0, len(snapshots))
+	unit := ""
+	for _, snapshot := range snapshots {
+		points = append(points, gin.H{
+			"timestamp": snapshot.TakenAt,
+			"value":     snapshot.Value,
+		})
+		unit = snapshot.Unit
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":      name,
+		"component": component,
+		"unit":      unit,
+		"from":      timeRange.Start,
+		"to":        timeRange.End,
+		"points":    points,
+	})
+}
+
 // GetSummary returns all metrics aggregated
 // GET /api/metrics/summary
 func (h *MetricsHandler) GetSummary(c *gin.Context) {
@@ -138,6 +183,7 @@ func (h *MetricsHandler) GetCollectorStatus(c *gin.Context) {
 		"releases_count": collector.ReleaseCount(),
 		"epics_count":    collector.EpicCount(),
 		"issues_count":   collector.IssuesCount(),
+		"collection":     collector.Status(),
 	})
 }
 
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// pendingLoginTTL is how long a user has to complete the login at the provider
+	pendingLoginTTL = 10 * time.Minute
+	// stateCookieName binds a login to the browser that started it, it holds the hash of the state
+	stateCookieName = "roadmap_oidc_state"
+)
+
+// OIDCHandler signs users in with an OpenID Connect provider such as Keycloak
+// The identity is mapped to a Jira user, the session then uses the Jira service account
+type OIDCHandler struct {
+	logger   *zap.Logger
+	config   *config.Config
+	sessions *session.Manager
+	now      func() time.Time
+
+	mu sync.Mutex
+	// provider is discovered on first use and kept once discovery succeeded
+	provider *oidc.Provider
+	// pending are the logins started and not completed yet, by state
+	pending map[string]pendingLogin
+}
+
+// pendingLogin is a login redirected to the provider
+type pendingLogin struct {
+	nonce     string
+	verifier  string
+	expiresAt time.Time
+}
+
+// NewOIDCHandler creates a new OIDCHandler
+func NewOIDCHandler(cfg *config.Config, sessions *session.Manager) *OIDCHandler {
+	return &OIDCHandler{
+		logger:   logger.WithComponent("oidc-handler"),
+		config:   cfg,
+		sessions: sessions,
+		now:      time.Now,
+		pending:  map[string]pendingLogin{},
+	}
+}
+
+// Login redirects the browser to the provider
+func (h *OIDCHandler) Login(c *gin.Context) {
+	provider, err := h.getProvider(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to discover OIDC provider", zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error": "Single sign-on provider is unavailable",
+		})
+		return
+	}
+
+	state, stateErr := randomString()
+	nonce, nonceErr := randomString()
+	if err := errors.Join(stateErr, nonceErr); err != nil {
+		h.logger.Error("Failed to generate login state", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to start single sign-on",
+		})
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	h.mu.Lock()
+	now := h.now()
+	for key, login := range h.pending {
+		if !now.Before(login.expiresAt) {
+			delete(h.pending, key)
+		}
+	}
+	h.pending[state] = pendingLogin{nonce: nonce, verifier: verifier, expiresAt: now.Add(pendingLoginTTL)}
+	h.mu.Unlock()
+	h.setStateCookie(c, hashState(state), int(pendingLoginTTL.Seconds()))
+
+	authURL := h.oauth2Config(provider).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback completes the login: it verifies the ID token, maps it to a Jira user and starts a session
+// Failures redirect to the post login URL with an auth_error query parameter
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	stateCookie, _ := c.Cookie(stateCookieName)
+	h.setStateCookie(c, "", -1)
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		h.logger.Warn("OIDC provider returned an error",
+			zap.String("error", providerErr),
+			zap.String("description", c.Query("error_description")))
+		h.fail(c, "Single sign-on was denied")
+		return
+	}
+
+	h.mu.Lock()
+	login, ok := h.pending[c.Query("state")]
+	delete(h.pending, c.Query("state"))
+	h.mu.Unlock()
+	if !ok || !h.now().Before(login.expiresAt) {
+		h.fail(c, "Single sign-on expired, please try again")
+		return
+	}
+	// a callback URL of another browser would sign this browser in as someone else
+	if subtle.ConstantTimeCompare([]byte(stateCookie), []byte(hashState(c.Query("state")))) != 1 {
+		h.logger.Warn("OIDC callback does not match the login of the browser")
+		h.fail(c, "Single sign-on was started in another browser, please try again")
+		return
+	}
+
+	ctx := c.Request.Context()
+	provider, err := h.getProvider(ctx)
+	if err != nil {
+		h.logger.Error("Failed to discover OIDC provider", zap.Error(err))
+		h.fail(c, "Single sign-on provider is unavailable")
+		return
+	}
+
+	identity, err := h.verify(ctx, provider, c.Query("code"), login)
+	if err != nil {
+		h.logger.Error("Failed to verify OIDC login", zap.Error(err))
+		h.fail(c, "Single sign-on failed")
+		return
+	}
+
+	serviceClient, err := jira.NewClient(h.config.Jira.BaseURL, h.config.Jira.Username, h.config.Jira.Password, h.config.Jira.Project)
+	if err != nil {
+		h.logger.Error("Failed to create Jira service account client", zap.Error(err))
+		h.fail(c, "Failed to initialize Jira client")
+		return
+	}
+	user, err := serviceClient.FindUser(ctx, identity)
+	if err != nil {
+		h.logger.Warn("Failed to map OIDC identity to a Jira user", zap.String("identity", identity), zap.Error(err))
+		if errors.Is(err, jira.ErrUserNotFound) {
+			h.fail(c, fmt.Sprintf("No Jira user matches %s", identity))
+		} else {
+			h.fail(c, "Failed to look up the Jira user")
+		}
+		return
+	}
+
+	token, _, err := h.sessions.CreateDelegated(ctx, user.Name, "")
+	if err != nil {
+		h.logger.Error("Failed to create session", zap.Error(err))
+		h.fail(c, "Failed to create session")
+		return
+	}
+	setSessionCookie(c, &h.config.Auth, token, int(h.sessions.TTL().Seconds()))
+
+	h.logger.Info("Single sign-on succeeded", zap.String("identity", identity), zap.String("jira_user", user.Name))
+	c.Redirect(http.StatusFound, h.config.Auth.OIDC.PostLoginURL)
+}
+
+// verify exchanges the authorization code and returns the configured claim of the verified ID token
+func (h *OIDCHandler) verify(ctx context.Context, provider *oidc.Provider, code string, login pendingLogin) (string, error) {
+	token, err := h.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(login.verifier))
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return "", errors.New("token response has no id_token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: h.config.Auth.OIDC.ClientID, Now: h.now}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify id token: %w", err)
+	}
+	if idToken.Nonce != login.nonce {
+		return "", errors.New("id token nonce mismatch")
+	}
+
+	claims := map[string]interface{}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return "", fmt.Errorf("failed to decode id token claims: %w", err)
+	}
+	identity, _ := claims[h.config.Auth.OIDC.UserClaim].(string)
+	if identity == "" {
+		return "", fmt.Errorf("id token has no %q claim", h.config.Auth.OIDC.UserClaim)
+	}
+	return identity, nil
+}
+
+// getProvider returns the provider, discovering it on first use
+func (h *OIDCHandler) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.provider != nil {
+		return h.provider, nil
+	}
+	provider, err := oidc.NewProvider(ctx, h.config.Auth.OIDC.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	h.provider = provider
+	return provider, nil
+}
+
+func (h *OIDCHandler) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     h.config.Auth.OIDC.ClientID,
+		ClientSecret: h.config.Auth.OIDC.ClientSecret,
+		RedirectURL:  h.config.Auth.OIDC.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       h.config.Auth.OIDC.Scopes,
+	}
+}
+
+// fail redirects to the post login URL with the error in the auth_error query parameter
+func (h *OIDCHandler) fail(c *gin.Context, message string) {
+	target, err := url.Parse(h.config.Auth.OIDC.PostLoginURL)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": message,
+		})
+		return
+	}
+	query := target.Query()
+	query.Set("auth_error", message)
+	target.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
+
+// setStateCookie sets the state cookie, a negative maxAge deletes it
+func (h *OIDCHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(stateCookieName, value, maxAge, "/", "", h.config.Auth.CookieSecure, true)
+}
+
+// hashState returns the hash of a state stored in the state cookie
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString returns 32 random bytes encoded for URLs
+func randomString() (string, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// mockProvider is a minimal OpenID Connect provider issuing signed ID tokens
+type mockProvider struct {
+	*httptest.Server
+	t   *testing.T
+	key *rsa.PrivateKey
+	// claims are added to the ID token, the nonce and challenge are those of the authorization request
+	claims    map[string]interface{}
+	nonce     string
+	challenge string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	p := &mockProvider{t: t, key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/auth",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &p.key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   300,
+			"id_token":     p.idToken(),
+		})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// idToken signs an ID token for the test client
+func (p *mockProvider) idToken() string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key}, (&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"))
+	if err != nil {
+		p.t.Fatalf("NewSigner() error = %v", err)
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":   p.URL,
+		"aud":   "roadmap-planner",
+		"sub":   "user-id",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": p.nonce,
+	}
+	for key, value := range p.claims {
+		claims[key] = value
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		p.t.Fatalf("Serialize() error = %v", err)
+	}
+	return token
+}
+
+// newMockJira serves the user search of the Jira service account
+func newMockJira(t *testing.T, users ...map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/user/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if user, _, ok := r.BasicAuth(); !ok || user != "robot" {
+			t.Errorf("user search authenticated as %q, want the service account", user)
+		}
+		found := []map[string]interface{}{}
+		for _, user := range users {
+			if user["name"] == r.URL.Query().Get("username") || user["emailAddress"] == r.URL.Query().Get("username") {
+				found = append(found, user)
+			}
+		}
+		writeJSON(w, found)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func TestOIDCHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jiraServer := newMockJira(t,
+		map[string]interface{}{"name": "alice", "key": "alice", "emailAddress": "alice@example.com", "active": true},
+		map[string]interface{}{"name": "carol", "key": "carol", "emailAddress": "carol@example.com", "active": false},
+	)
+
+	tests := map[string]struct {
+		claims map[string]interface{}
+		// modify changes the callback query
+		modify func(query url.Values)
+		// modifyCookie changes the state cookie sent to the callback, nil drops it
+		modifyCookie  func(cookie *http.Cookie) *http.Cookie
+		wantLocation  string
+		wantAuthError bool
+		wantUser      string
+	}{
+		"maps the preferred username to a jira user": {
+			claims:       map[string]interface{}{"preferred_username": "alice"},
+			wantLocation: "/roadmap",
+			wantUser:     "alice",
+		},
+		"maps the email to a jira user": {
+			claims:       map[string]interface{}{"preferred_username": "alice@example.com"},
+			wantLocation: "/roadmap",
+			wantUser:     "alice",
+		},
+		"rejects unknown users": {
+			claims:        map[string]interface{}{"preferred_username": "bob"},
+			wantAuthError: true,
+		},
+		"rejects inactive users": {
+			claims:        map[string]interface{}{"preferred_username": "carol"},
+			wantAuthError: true,
+		},
+		"rejects tokens without the claim": {
+			claims:        map[string]interface{}{"email": "alice@example.com"},
+			wantAuthError: true,
+		},
+		"rejects a replayed nonce": {
+			claims:        map[string]interface{}{"preferred_username": "alice", "nonce": "another"},
+			wantAuthError: true,
+		},
+		"rejects an unknown state": {
+			claims:        map[string]interface{}{"preferred_username": "alice"},
+			modify:        func(query url.Values) { query.Set("state", "forged") },
+			wantAuthError: true,
+		},
+		"rejects a callback without the state cookie": {
+			claims:        map[string]interface{}{"preferred_username": "alice"},
+			modifyCookie:  func(cookie *http.Cookie) *http.Cookie { return nil },
+			wantAuthError: true,
+		},
+		"rejects a state cookie of another login": {
+			claims: map[string]interface{}{"preferred_username": "alice"},
+			modifyCookie: func(cookie *http.Cookie) *http.Cookie {
+				cookie.Value = hashState("another")
+				return cookie
+			},
+			wantAuthError: true,
+		},
+		"rejects an invalid code": {
+			claims:        map[string]interface{}{"preferred_username": "alice"},
+			modify:        func(query url.Values) { query.Set("code", "bad-code") },
+			wantAuthError: true,
+		},
+		"reports provider errors": {
+			claims:        map[string]interface{}{"preferred_username": "alice"},
+			modify:        func(query url.Values) { query.Set("error", "access_denied") },
+			wantAuthError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			provider := newMockProvider(t)
+			provider.claims = tt.claims
+
+			cfg := &config.Config{
+				Jira: config.Jira{BaseURL: jiraServer.URL, Username: "robot", Password: "robot-token", Project: "DEVOPS"},
+				Auth: config.Auth{
+					CookieName: "roadmap_session",
+					OIDC: config.OIDC{
+						Enabled:      true,
+						IssuerURL:    provider.URL,
+						ClientID:     "roadmap-planner",
+						ClientSecret: "secret",
+						RedirectURL:  "http://planner.example.com/api/auth/oidc/callback",
+						Scopes:       []string{"openid", "profile"},
+						UserClaim:    "preferred_username",
+						PostLoginURL: "/roadmap",
+					},
+				},
+			}
+			sessions, err := session.NewManager(&cfg.Auth, session.NewMemoryStore())
+			if err != nil {
+				t.Fatalf("NewManager() error = %v", err)
+			}
+			sessions.UseServiceAccount(&cfg.Jira)
+
+			handler := NewOIDCHandler(cfg, sessions)
+			router := gin.New()
+			router.GET("/login", handler.Login)
+			router.GET("/callback", handler.Callback)
+
+			// the login redirects to the provider with a state, a nonce and a PKCE challenge
+			login := httptest.NewRecorder()
+			router.ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/login", nil))
+			if login.Code != http.StatusFound {
+				t.Fatalf("login status = %d, want %d", login.Code, http.StatusFound)
+			}
+			authURL, err := url.Parse(login.Header().Get("Location"))
+			if err != nil {
+				t.Fatalf("login location error = %v", err)
+			}
+			authQuery := authURL.Query()
+			if authURL.Path != "/auth" || authQuery.Get("client_id") != "roadmap-planner" || authQuery.Get("code_challenge_method") != "S256" {
+				t.Fatalf("login location = %s", authURL)
+			}
+			if _, ok := tt.claims["nonce"]; !ok {
+				provider.nonce = authQuery.Get("nonce")
+			}
+			provider.challenge = authQuery.Get("code_challenge")
+
+			// the provider redirects back with the code
+			callbackQuery := url.Values{"state": {authQuery.Get("state")}, "code": {"good-code"}}
+			if tt.modify != nil {
+				tt.modify(callbackQuery)
+			}
+			// the browser sends back the state cookie set by the login
+			var stateCookie *http.Cookie
+			for _, c := range login.Result().Cookies() {
+				if c.Name == stateCookieName {
+					stateCookie = c
+				}
+			}
+			if stateCookie == nil || !stateCookie.HttpOnly || stateCookie.SameSite != http.SameSiteLaxMode {
+				t.Fatalf("login cookie = %+v, want an HTTP-only SameSite=Lax state cookie", stateCookie)
+			}
+			if tt.modifyCookie != nil {
+				stateCookie = tt.modifyCookie(stateCookie)
+			}
+			callbackRequest := httptest.NewRequest(http.MethodGet, "/callback?"+callbackQuery.Encode(), nil)
+			if stateCookie != nil {
+				callbackRequest.AddCookie(stateCookie)
+			}
+			callback := httptest.NewRecorder()
+			router.ServeHTTP(callback, callbackRequest)
+			if callback.Code != http.StatusFound {
+				t.Fatalf("callback status = %d, want %d", callback.Code, http.StatusFound)
+			}
+			location, err := url.Parse(callback.Header().Get("Location"))
+			if err != nil {
+				t.Fatalf("callback location error = %v", err)
+			}
+			if location.Path != "/roadmap" {
+				t.Errorf("callback location = %s, want path /roadmap", location)
+			}
+
+			var cookie *http.Cookie
+			for _, c := range callback.Result().Cookies() {
+				if c.Name == "roadmap_session" {
+					cookie = c
+				}
+			}
+			if tt.wantAuthError {
+				if location.Query().Get("auth_error") == "" {
+					t.Errorf("callback location = %s, want an auth_error", location)
+				}
+				if cookie != nil {
+					t.Errorf("callback set a session cookie on failure")
+				}
+				return
+			}
+
+			if cookie == nil || !cookie.HttpOnly {
+				t.Fatalf("callback cookie = %+v, want an HTTP-only session cookie", cookie)
+			}
+			userSession, err := sessions.Authenticate(context.Background(), cookie.Value)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if !userSession.Delegated || userSession.Username != tt.wantUser || userSession.Project != "DEVOPS" {
+				t.Errorf("session = %+v, want a delegated session of %s", userSession, tt.wantUser)
+			}
+			client, err := sessions.Client(userSession, "")
+			if err != nil {
+				t.Fatalf("Client() error = %v", err)
+			}
+			if client.Actor() != tt.wantUser {
+				t.Errorf("Client().Actor() = %q, want %q", client.Actor(), tt.wantUser)
+			}
+		})
+	}
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/audit"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/cache"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
 	"github.com/gin-gonic/gin"
@@ -32,16 +36,41 @@ import (
 type RoadmapHandler struct {
 	logger *zap.Logger
 	config *config.Config
+	cache  *cache.Cache
+	audit  *audit.Log
 }
 
 // NewRoadmapHandler creates a new RoadmapHandler
-func NewRoadmapHandler(cfg *config.Config) *RoadmapHandler {
+// readCache caches the Jira read queries and auditLog records the writes, both can be nil
+func NewRoadmapHandler(cfg *config.Config, readCache *cache.Cache, auditLog *audit.Log) *RoadmapHandler {
 	return &RoadmapHandler{
 		logger: logger.WithComponent("roadmap-handler"),
 		config: cfg,
+		cache:  readCache,
+		audit:  auditLog,
 	}
 }
 
+// cacheKey returns the cache key of a query of the current user and project
+func (h *RoadmapHandler) cacheKey(c *gin.Context, kind, filter string) cache.Key {
+	project, _ := middleware.GetProject(c)
+	user, _ := middleware.GetUser(c)
+	return cache.Key{Project: project, Kind: kind, User: user, Filter: filter}
+}
+
+// writer returns a writer recording the changes of the current user in the audit log
+func (h *RoadmapHandler) writer(c *gin.Context, jiraClient *jira.Client) *audit.Writer {
+	project, _ := middleware.GetProject(c)
+	user, _ := middleware.GetUser(c)
+	return h.audit.Writer(jiraClient, user, project)
+}
+
+// invalidate removes the cached queries of the given kinds of the current project after a write
+func (h *RoadmapHandler) invalidate(c *gin.Context, kinds ...string) {
+	project, _ := middleware.GetProject(c)
+	h.cache.Invalidate(project, kinds...)
+}
+
 // GetBasicData returns basic roadmap data (pillars, quarters, components, versions)
 func (h *RoadmapHandler) GetBasicData(c *gin.Context) {
 	jiraClient, ok := middleware.GetJiraClient(c)
@@ -53,7 +82,7 @@ func (h *RoadmapHandler) GetBasicData(c *gin.Context) {
 	}
 
 	// Fetch basic pillars (without milestones and epics)
-	basicPillars, err := jiraClient.GetBasicPillars(c)
+	basicPillars, err := cache.Get(c.Request.Context(), h.cache, h.cacheKey(c, cache.KindPillars, ""), jiraClient.GetBasicPillars)
 	if err != nil {
 		h.logger.Error("Failed to fetch basic pillars", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -71,7 +100,9 @@ func (h *RoadmapHandler) GetBasicData(c *gin.Context) {
 	}
 
 	// fetche project details
-	project, err := jiraClient.GetProjectDetails(c, projectKey)
+	project, err := cache.Get(c.Request.Context(), h.cache, h.cacheKey(c, cache.KindProject, projectKey), func(ctx context.Context) (*models.Project, error) {
+		return jiraClient.GetProjectDetails(ctx, projectKey)
+	})
 	if err != nil {
 		h.logger.Error("Failed to fetch project details", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -103,7 +134,10 @@ func (h *RoadmapHandler) GetMilestones(c *gin.Context) {
 	pillarIDs := c.QueryArray("pillar_id")
 	quarters := c.QueryArray("quarter")
 
-	milestones, err := jiraClient.GetMilestonesWithFilter(c.Request.Context(), pillarIDs, quarters)
+	key := h.cacheKey(c, cache.KindMilestones, cache.Filter(pillarIDs, quarters))
+	milestones, err := cache.Get(c.Request.Context(), h.cache, key, func(ctx context.Context) ([]models.Milestone, error) {
+		return jiraClient.GetMilestonesWithFilter(ctx, pillarIDs, quarters)
+	})
 	if err != nil {
 		h.logger.Error("Failed to fetch milestones", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -133,7 +167,10 @@ func (h *RoadmapHandler) GetEpics(c *gin.Context) {
 	components := c.QueryArray("component")
 	versions := c.QueryArray("version")
 
-	epics, err := jiraClient.GetEpicsWithFilter(c.Request.Context(), milestoneIDs, pillarIDs, components, versions)
+	key := h.cacheKey(c, cache.KindEpics, cache.Filter(milestoneIDs, pillarIDs, components, versions))
+	epics, err := cache.Get(c.Request.Context(), h.cache, key, func(ctx context.Context) ([]models.Epic, error) {
+		return jiraClient.GetEpicsWithFilter(ctx, milestoneIDs, pillarIDs, components, versions)
+	})
 	if err != nil {
 		h.logger.Error("Failed to fetch epics", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -167,7 +204,7 @@ func (h *RoadmapHandler) CreateMilestone(c *gin.Context) {
 
 	h.logger.Sugar().Debugw("create milestone request payload", "payload", req)
 
-	milestone, err := jiraClient.CreateMilestone(c.Request.Context(), req)
+	milestone, err := h.writer(c, jiraClient).CreateMilestone(c.Request.Context(), req)
 	if err != nil {
 		h.logger.Error("Failed to create milestone", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -175,6 +212,7 @@ func (h *RoadmapHandler) CreateMilestone(c *gin.Context) {
 		})
 		return
 	}
+	h.invalidate(c, cache.KindMilestones)
 
 	c.JSON(http.StatusCreated, milestone)
 }
@@ -205,7 +243,7 @@ func (h *RoadmapHandler) UpdateMilestone(c *gin.Context) {
 		return
 	}
 
-	err := jiraClient.UpdateMilestone(c.Request.Context(), milestoneID, req)
+	err := h.writer(c, jiraClient).UpdateMilestone(c.Request.Context(), milestoneID, req)
 	if err != nil {
 		h.logger.Error("Failed to update milestone", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -213,6 +251,7 @@ func (h *RoadmapHandler) UpdateMilestone(c *gin.Context) {
 		})
 		return
 	}
+	h.invalidate(c, cache.KindMilestones)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Milestone updated successfully",
@@ -237,7 +276,7 @@ func (h *RoadmapHandler) CreateEpic(c *gin.Context) {
 		return
 	}
 
-	epic, err := jiraClient.CreateEpic(c.Request.Context(), req)
+	epic, err := h.writer(c, jiraClient).CreateEpic(c.Request.Context(), req)
 	if err != nil {
 		h.logger.Error("Failed to create epic", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -245,6 +284,8 @@ func (h *RoadmapHandler) CreateEpic(c *gin.Context) {
 		})
 		return
 	}
+	// epics are linked to milestones
+	h.invalidate(c, cache.KindEpics, cache.KindMilestones)
 
 	c.JSON(http.StatusCreated, epic)
 }
@@ -275,7 +316,7 @@ func (h *RoadmapHandler) UpdateEpicMilestone(c *gin.Context) {
 		return
 	}
 
-	err := jiraClient.UpdateEpicMilestone(c.Request.Context(), epicID, req.MilestoneID)
+	err := h.writer(c, jiraClient).UpdateEpicMilestone(c.Request.Context(), epicID, req.MilestoneID)
 	if err != nil {
 		h.logger.Error("Failed to update epic milestone", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -283,6 +324,7 @@ func (h *RoadmapHandler) UpdateEpicMilestone(c *gin.Context) {
 		})
 		return
 	}
+	h.invalidate(c, cache.KindEpics, cache.KindMilestones)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Epic milestone updated successfully",
@@ -315,7 +357,7 @@ func (h *RoadmapHandler) UpdateEpic(c *gin.Context) {
 		return
 	}
 
-	err := jiraClient.UpdateEpic(c.Request.Context(), epicID, req)
+	err := h.writer(c, jiraClient).UpdateEpic(c.Request.Context(), epicID, req)
 	if err != nil {
 		h.logger.Error("Failed to update epic", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -323,6 +365,7 @@ func (h *RoadmapHandler) UpdateEpic(c *gin.Context) {
 		})
 		return
 	}
+	h.invalidate(c, cache.KindEpics)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Epic updated successfully",
@@ -347,7 +390,9 @@ func (h *RoadmapHandler) GetComponentVersions(c *gin.Context) {
 		return
 	}
 
-	versions, err := jiraClient.GetComponentVersions(c.Request.Context(), component)
+	versions, err := cache.Get(c.Request.Context(), h.cache, h.cacheKey(c, cache.KindVersions, component), func(ctx context.Context) ([]string, error) {
+		return jiraClient.GetComponentVersions(ctx, component)
+	})
 	if err != nil {
 		h.logger.Error("Failed to fetch component versions", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
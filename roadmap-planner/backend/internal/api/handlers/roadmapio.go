@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/cache"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/roadmapio"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxImportSize is the largest sheet accepted by the import
+const maxImportSize = 10 << 20
+
+// ExportRoadmap downloads the roadmap filtered by quarter, pillar and component as CSV, XLSX, Markdown or JSON
+func (h *RoadmapHandler) ExportRoadmap(c *gin.Context) {
+	jiraClient, ok := middleware.GetJiraClient(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Jira client not available",
+		})
+		return
+	}
+
+	format, err := roadmapio.ParseFormat(c.DefaultQuery("format", "csv"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	pillars, err := jiraClient.GetPillars(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to fetch roadmap", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch roadmap",
+		})
+		return
+	}
+
+	filter := roadmapio.Filter{
+		Quarters:   c.QueryArray("quarter"),
+		PillarIDs:  c.QueryArray("pillar_id"),
+		Components: c.QueryArray("component"),
+	}
+	data := filter.Apply(models.RoadmapData{Pillars: pillars, Quarters: h.config.Jira.Quarters})
+
+	var buf bytes.Buffer
+	if err := roadmapio.Export(&buf, format, data); err != nil {
+		h.logger.Error("Failed to export roadmap", zap.String("format", string(format)), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export roadmap",
+		})
+		return
+	}
+
+	project, _ := middleware.GetProject(c)
+	filename := fmt.Sprintf("roadmap-%s-%s.%s", project, time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// ImportRoadmap compares an uploaded CSV, XLSX or JSON sheet with Jira and applies the differences
+// It only previews the changes unless dry_run=false, and applies nothing when a row is invalid
+func (h *RoadmapHandler) ImportRoadmap(c *gin.Context) {
+	jiraClient, ok := middleware.GetJiraClient(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Jira client not available",
+		})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid dry_run value",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "A file is required",
+		})
+		return
+	}
+
+	var format roadmapio.Format
+	if name := c.Query("format"); name != "" {
+		format, err = roadmapio.ParseFormat(name)
+	} else {
+		format, err = roadmapio.FormatOfFile(fileHeader.Filename)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read the file",
+		})
+		return
+	}
+	defer file.Close()
+
+	rows, err := roadmapio.Read(file, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	pillars, err := jiraClient.GetPillars(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to fetch roadmap", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch roadmap",
+		})
+		return
+	}
+
+	user, _ := middleware.GetUser(c)
+	plan := roadmapio.NewPlan(rows, models.RoadmapData{Pillars: pillars}, user)
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"dry_run": true,
+			"plan":    plan,
+		})
+		return
+	}
+	if len(plan.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "The sheet has invalid rows, nothing was imported",
+			"dry_run": false,
+			"plan":    plan,
+		})
+		return
+	}
+
+	results := roadmapio.Apply(c.Request.Context(), h.writer(c, jiraClient), plan)
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if len(results) > failed {
+		h.invalidate(c, cache.KindEpics, cache.KindMilestones)
+	}
+	h.logger.Info("Imported roadmap",
+		zap.String("file", fileHeader.Filename),
+		zap.Int("changes", len(results)),
+		zap.Int("failed", failed))
+
+	c.JSON(http.StatusOK, gin.H{
+		"dry_run": false,
+		"plan":    plan,
+		"results": results,
+		"failed":  failed,
+	})
+}
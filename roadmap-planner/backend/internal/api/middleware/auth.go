@@ -17,10 +17,14 @@ limitations under the License.
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -32,17 +36,16 @@ const (
 	UserKey = "user"
 	// ProjectKey is the key use to store project information in the context
 	ProjectKey = "project"
+	// SessionKey is the key used to store the session in the context
+	SessionKey = "session"
 )
 
-// AuthMiddleware creates a middleware that validates Jira authentication
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware creates a middleware that validates the session cookie
+// and provides the pooled Jira client of the session
+func AuthMiddleware(cfg *config.Auth, sessions *session.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// For now, we'll use session-based authentication
-		// In a production environment, you might want to use JWT tokens or similar
-
-		// Check if user is authenticated (has valid session)
-		session := getSession(c)
-		if session == nil {
+		token, err := c.Cookie(cfg.CookieName)
+		if err != nil || token == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Authentication required",
 			})
@@ -50,31 +53,41 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		logger.Info("Creating Jira client in middleware",
-			zap.String("base_url", session.BaseURL),
-			zap.String("username", session.Username))
-
-		// Create Jira client with stored credentials
-		jiraClient, err := jira.NewClient(
-			session.BaseURL,
-			session.Username,
-			session.Password,
-			session.Project,
-		)
+		userSession, err := sessions.Authenticate(c.Request.Context(), token)
 		if err != nil {
-			logger.Error("Failed to create Jira client", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to initialize Jira client",
+			if !errors.Is(err, session.ErrInvalidToken) {
+				logger.Error("Failed to load session", zap.Error(err))
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Session expired or invalid",
 			})
 			c.Abort()
 			return
 		}
 
-		// Test the connection
-		if err := jiraClient.TestConnection(c.Request.Context()); err != nil {
-			logger.Error("Jira connection test failed", zap.Error(err))
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid Jira credentials",
+		// the project can be switched per request, the credentials come from the session only
+		project := c.GetHeader("X-Jira-Project")
+		if project == "" {
+			project = userSession.Project
+		}
+		// delegated sessions use the service account, which may reach more projects than the user:
+		// they are pinned to the project of the session
+		if userSession.Delegated {
+			if !strings.EqualFold(project, userSession.Project) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": "Single sign-on sessions cannot switch the Jira project",
+				})
+				c.Abort()
+				return
+			}
+			project = userSession.Project
+		}
+
+		jiraClient, err := sessions.Client(userSession, project)
+		if err != nil {
+			logger.Error("Failed to create Jira client", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to initialize Jira client",
 			})
 			c.Abort()
 			return
@@ -82,46 +95,23 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// Store the Jira client in the context
 		c.Set(JiraClientKey, jiraClient)
-		c.Set(UserKey, session.Username)
-		c.Set(ProjectKey, session.Project)
+		c.Set(UserKey, userSession.Username)
+		c.Set(ProjectKey, project)
+		c.Set(SessionKey, userSession)
 
 		c.Next()
 	}
 }
 
-// SessionData represents user session data
-type SessionData struct {
-	Username string `json:"username"`
-	BaseURL  string `json:"base_url"`
-	Password string `json:"password"`
-	Project  string `json:"project"`
-}
-
-// getSession retrieves session data from the request
-// In a real implementation, this would use proper session management
-func getSession(c *gin.Context) *SessionData {
-	// For simplicity, we'll use headers for authentication
-	// In production, use proper session management or JWT tokens
-
-	username := c.GetHeader("X-Jira-Username")
-	password := c.GetHeader("X-Jira-Password")
-	baseURL := c.GetHeader("X-Jira-BaseURL")
-	project := c.GetHeader("X-Jira-Project")
-
-	if username == "" || password == "" || baseURL == "" {
-		return nil
-	}
-
-	if project == "" {
-		project = "DEVOPS" // Default project
+// GetSession retrieves the session of the current user from the context
+func GetSession(c *gin.Context) (*session.Session, bool) {
+	value, exists := c.Get(SessionKey)
+	if !exists {
+		return nil, false
 	}
 
-	return &SessionData{
-		Username: username,
-		Password: password,
-		BaseURL:  baseURL,
-		Project:  project,
-	}
+	userSession, ok := value.(*session.Session)
+	return userSession, ok
 }
 
 // GetJiraClient retrieves the Jira client from the context
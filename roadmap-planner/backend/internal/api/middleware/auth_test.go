@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthMiddleware_Project(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	cfg := &config.Auth{SessionSecret: "secret", CookieName: "roadmap_session"}
+	sessions, err := session.NewManager(cfg, session.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	sessions.UseServiceAccount(&config.Jira{BaseURL: "https://jira.example.com", Username: "service", Password: "secret", Project: "DEVOPS"})
+
+	userToken, _, err := sessions.Create(ctx, "alice", "api-token", "https://jira.example.com", "DEVOPS")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	ssoToken, _, err := sessions.CreateDelegated(ctx, "bob", "")
+	if err != nil {
+		t.Fatalf("CreateDelegated() error = %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/project", AuthMiddleware(cfg, sessions), func(c *gin.Context) {
+		project, _ := GetProject(c)
+		c.String(http.StatusOK, project)
+	})
+
+	table := map[string]struct {
+		token       string
+		header      string
+		wantStatus  int
+		wantProject string
+	}{
+		"session project":                   {token: userToken, wantStatus: http.StatusOK, wantProject: "DEVOPS"},
+		"user switches project":             {token: userToken, header: "OTHER", wantStatus: http.StatusOK, wantProject: "OTHER"},
+		"sso session project":               {token: ssoToken, wantStatus: http.StatusOK, wantProject: "DEVOPS"},
+		"sso header of the session project": {token: ssoToken, header: "devops", wantStatus: http.StatusOK, wantProject: "DEVOPS"},
+		"sso session cannot switch project": {token: ssoToken, header: "OTHER", wantStatus: http.StatusForbidden},
+		"no session":                        {wantStatus: http.StatusUnauthorized},
+	}
+
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/project", nil)
+			if tc.token != "" {
+				req.AddCookie(&http.Cookie{Name: cfg.CookieName, Value: tc.token})
+			}
+			if tc.header != "" {
+				req.Header.Set("X-Jira-Project", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d, body: %s", rec.Code, tc.wantStatus, rec.Body.String())
+			}
+			if tc.wantStatus == http.StatusOK && rec.Body.String() != tc.wantProject {
+				t.Errorf("project = %q, want %q", rec.Body.String(), tc.wantProject)
+			}
+		})
+	}
+}
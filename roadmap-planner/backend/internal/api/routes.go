@@ -24,13 +24,17 @@ import (
 
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/handlers"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/api/middleware"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/audit"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/cache"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/session"
 	"github.com/gin-gonic/gin"
 )
 
 // NewRouter creates a new Gin router with all routes configured
-func NewRouter(cfg *config.Config) *gin.Engine {
+// readCache caches the Jira read queries and auditLog records the roadmap changes, both can be nil
+func NewRouter(cfg *config.Config, sessions *session.Manager, readCache *cache.Cache, auditLog *audit.Log) *gin.Engine {
 	router := gin.New()
 
 	// Add middleware
@@ -39,8 +43,8 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 	router.Use(middleware.CORSMiddleware(cfg))
 
 	// Create handlers
-	authHandler := handlers.NewAuthHandler()
-	roadmapHandler := handlers.NewRoadmapHandler(cfg)
+	authHandler := handlers.NewAuthHandler(cfg, sessions, readCache)
+	roadmapHandler := handlers.NewRoadmapHandler(cfg, readCache, auditLog)
 	projectsHandler := handlers.NewProjectsHandler(cfg)
 
 	// Health check endpoint (no auth required)
@@ -55,11 +59,17 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/logout", authHandler.Logout)
 			auth.GET("/status", authHandler.Status)
+			auth.GET("/config", authHandler.Config)
+			if cfg.Auth.OIDC.Enabled {
+				oidcHandler := handlers.NewOIDCHandler(cfg, sessions)
+				auth.GET("/oidc/login", oidcHandler.Login)
+				auth.GET("/oidc/callback", oidcHandler.Callback)
+			}
 		}
 
 		// Protected routes (require authentication)
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(&cfg.Auth, sessions))
 		{
 			// Projects routes (keep for future use)
 			protected.GET("/projects", projectsHandler.ListProjects)
@@ -70,6 +80,7 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 			// Filtering APIs
 			protected.GET("/milestones", roadmapHandler.GetMilestones)
 			protected.GET("/epics", roadmapHandler.GetEpics)
+			protected.GET("/dependencies", roadmapHandler.GetDependencies)
 
 			// Milestone routes
 			protected.POST("/milestones", roadmapHandler.CreateMilestone)
@@ -80,6 +91,16 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 			protected.PUT("/epics/:id", roadmapHandler.UpdateEpic)
 			protected.PUT("/epics/:id/milestone", roadmapHandler.UpdateEpicMilestone)
 
+			// Export and bulk import of the roadmap
+			protected.GET("/export", roadmapHandler.ExportRoadmap)
+			protected.POST("/import", roadmapHandler.ImportRoadmap)
+
+			// Audit log of the roadmap changes, only available with a storage
+			if auditLog != nil {
+				protected.GET("/audit", roadmapHandler.GetAuditLog)
+				protected.GET("/audit/snapshot", roadmapHandler.GetAuditSnapshot)
+			}
+
 			// Component routes
 			protected.GET("/components/:name/versions", roadmapHandler.GetComponentVersions)
 
@@ -132,7 +153,7 @@ func NewRouter(cfg *config.Config) *gin.Engine {
 }
 
 // AddMetricsRoutes adds metrics-related routes to an existing router
-func AddMetricsRoutes(router *gin.Engine, cfg *config.Config, metricsService *metrics.Service) {
+func AddMetricsRoutes(router *gin.Engine, cfg *config.Config, sessions *session.Manager, metricsService *metrics.Service) {
 	if metricsService == nil {
 		return
 	}
@@ -142,11 +163,15 @@ func AddMetricsRoutes(router *gin.Engine, cfg *config.Config, metricsService *me
 	// Protected metrics routes (require authentication)
 	api := router.Group("/api")
 	metricsGroup := api.Group("/metrics")
-	metricsGroup.Use(middleware.AuthMiddleware())
+	metricsGroup.Use(middleware.AuthMiddleware(&cfg.Auth, sessions))
 	{
 		metricsGroup.GET("", metricsHandler.ListMetrics)
 		metricsGroup.GET("/summary", metricsHandler.GetSummary)
 		metricsGroup.GET("/status", metricsHandler.GetCollectorStatus)
 		metricsGroup.GET("/:name", metricsHandler.GetMetric)
+		metricsGroup.GET("/:name/history", metricsHandler.GetMetricHistory)
 	}
+
+	// Milestone forecasts use the throughput of the collected data
+	api.GET("/forecast", middleware.AuthMiddleware(&cfg.Auth, sessions), metricsHandler.GetForecast)
 }
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the changes made to the roadmap through the planner
+// and replays them to show the board as it was at a past time
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+	"go.uber.org/zap"
+)
+
+// Entity types of the audit entries
+const (
+	EntityMilestone = "milestone"
+	EntityEpic      = "epic"
+)
+
+// Actions of the audit entries
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	// ActionMove moves an epic to another milestone
+	ActionMove = "move"
+)
+
+// Log records the changes of the roadmap in a store
+// A nil Log records nothing
+type Log struct {
+	store  storage.Store
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewLog creates an audit log saving its entries in store
+// Returns nil when the store is nil
+func NewLog(store storage.Store) *Log {
+	if store == nil {
+		return nil
+	}
+	return &Log{
+		store:  store,
+		logger: logger.WithComponent("audit"),
+		now:    time.Now,
+	}
+}
+
+// Record saves a change of an entity with its values before and after the change
+// The change already happened, so failures are only logged
+func (l *Log) Record(ctx context.Context, entry storage.AuditEntry, before, after any) {
+	if l == nil {
+		return
+	}
+	entry.Before = encode(before)
+	entry.After = encode(after)
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = l.now()
+	}
+	if err := l.store.SaveAuditEntry(ctx, &entry); err != nil {
+		l.logger.Error("Failed to record audit entry",
+			zap.String("action", entry.Action),
+			zap.String("entity_type", entry.EntityType),
+			zap.String("entity_id", entry.EntityID),
+			zap.Error(err))
+	}
+}
+
+// encode returns the JSON value, empty for nil values
+func encode(value any) json.RawMessage {
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// List returns the entries matching the query, most recent first
+func (l *Log) List(ctx context.Context, query storage.AuditQuery) ([]storage.AuditEntry, error) {
+	if l == nil {
+		return []storage.AuditEntry{}, nil
+	}
+	return l.store.ListAuditEntries(ctx, query)
+}
+
+// Client is the part of the Jira client writing the roadmap
+type Client interface {
+	CreateMilestone(ctx context.Context, req models.CreateMilestoneRequest) (*models.Milestone, error)
+	UpdateMilestone(ctx context.Context, milestoneID string, req models.UpdateMilestoneRequest) error
+	CreateEpic(ctx context.Context, req models.CreateEpicRequest) (*models.Epic, error)
+	UpdateEpic(ctx context.Context, epicID string, req models.UpdateEpicRequest) error
+	UpdateEpicMilestone(ctx context.Context, epicID, milestoneID string) error
+	GetMilestone(ctx context.Context, milestoneID string) (*models.Milestone, error)
+	GetEpic(ctx context.Context, epicID string) (*models.Epic, error)
+}
+
+// Writer writes the roadmap with a client and records the successful writes in the audit log
+// It implements roadmapio.Writer so that the imports are audited too
+type Writer struct {
+	client  Client
+	log     *Log
+	actor   string
+	project string
+}
+
+// Writer returns a writer attributing its changes to actor in project
+func (l *Log) Writer(client Client, actor, project string) *Writer {
+	return &Writer{client: client, log: l, actor: actor, project: project}
+}
+
+// CreateMilestone creates a milestone and records it
+func (w *Writer) CreateMilestone(ctx context.Context, req models.CreateMilestoneRequest) (*models.Milestone, error) {
+	milestone, err := w.client.CreateMilestone(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	w.record(ctx, ActionCreate, EntityMilestone, milestone.ID, milestone.Key, nil, milestone)
+	return milestone, nil
+}
+
+// UpdateMilestone updates a milestone and records its values before and after the update
+func (w *Writer) UpdateMilestone(ctx context.Context, milestoneID string, req models.UpdateMilestoneRequest) error {
+	before := w.milestone(ctx, milestoneID)
+	if err := w.client.UpdateMilestone(ctx, milestoneID, req); err != nil {
+		return err
+	}
+	after := w.milestone(ctx, milestoneID)
+	w.record(ctx, ActionUpdate, EntityMilestone, milestoneID, milestoneKey(before, after), before, after)
+	return nil
+}
+
+// CreateEpic creates an epic and records it
+func (w *Writer) CreateEpic(ctx context.Context, req models.CreateEpicRequest) (*models.Epic, error) {
+	epic, err := w.client.CreateEpic(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	w.record(ctx, ActionCreate, EntityEpic, epic.ID, epic.Key, nil, epic)
+	return epic, nil
+}
+
+// UpdateEpic updates an epic and records its values before and after the update
+func (w *Writer) UpdateEpic(ctx context.Context, epicID string, req models.UpdateEpicRequest) error {
+	before := w.epic(ctx, epicID)
+	if err := w.client.UpdateEpic(ctx, epicID, req); err != nil {
+		return err
+	}
+	after := w.epic(ctx, epicID)
+	w.record(ctx, ActionUpdate, EntityEpic, epicID, epicKey(before, after), before, after)
+	return nil
+}
+
+// UpdateEpicMilestone moves an epic to another milestone and records its values before and after the move
+func (w *Writer) UpdateEpicMilestone(ctx context.Context, epicID, milestoneID string) error {
+	before := w.epic(ctx, epicID)
+	if err := w.client.UpdateEpicMilestone(ctx, epicID, milestoneID); err != nil {
+		return err
+	}
+	after := w.epic(ctx, epicID)
+	w.record(ctx, ActionMove, EntityEpic, epicID, epicKey(before, after), before, after)
+	return nil
+}
+
+// record saves an entry of the writer's actor and project
+func (w *Writer) record(ctx context.Context, action, entityType, id, key string, before, after any) {
+	w.log.Record(ctx, storage.AuditEntry{
+		Project:    w.project,
+		Actor:      w.actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   id,
+		EntityKey:  key,
+	}, before, after)
+}
+
+// milestone fetches the current value of a milestone, nil when it is not audited or cannot be fetched
+func (w *Writer) milestone(ctx context.Context, milestoneID string) *models.Milestone {
+	if w.log == nil {
+		return nil
+	}
+	milestone, err := w.client.GetMilestone(ctx, milestoneID)
+	if err != nil {
+		w.log.logger.Warn("Failed to fetch milestone for the audit log", zap.String("milestone_id", milestoneID), zap.Error(err))
+		return nil
+	}
+	return milestone
+}
+
+// epic fetches the current value of an epic, nil when it is not audited or cannot be fetched
+func (w *Writer) epic(ctx context.Context, epicID string) *models.Epic {
+	if w.log == nil {
+		return nil
+	}
+	epic, err := w.client.GetEpic(ctx, epicID)
+	if err != nil {
+		w.log.logger.Warn("Failed to fetch epic for the audit log", zap.String("epic_id", epicID), zap.Error(err))
+		return nil
+	}
+	return epic
+}
+
+// milestoneKey returns the key of the first known milestone value
+func milestoneKey(values ...*models.Milestone) string {
+	for _, value := range values {
+		if value != nil {
+			return value.Key
+		}
+	}
+	return ""
+}
+
+// epicKey returns the key of the first known epic value
+func epicKey(values ...*models.Epic) string {
+	for _, value := range values {
+		if value != nil {
+			return value.Key
+		}
+	}
+	return ""
+}
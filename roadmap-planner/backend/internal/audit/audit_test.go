@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeClient keeps the roadmap in memory
+type fakeClient struct {
+	milestones map[string]models.Milestone
+	epics      map[string]models.Epic
+	nextID     int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		milestones: map[string]models.Milestone{"1": {ID: "1", Key: "DEVOPS-1", Name: "Milestone", Quarter: "2025Q1"}},
+		epics:      map[string]models.Epic{"2": {ID: "2", Key: "DEVOPS-2", Name: "Epic", MilestoneIDs: []string{"1"}}},
+		nextID:     10,
+	}
+}
+
+func (f *fakeClient) id() (string, string) {
+	f.nextID++
+	id := strconv.Itoa(f.nextID)
+	return id, "DEVOPS-" + id
+}
+
+func (f *fakeClient) CreateMilestone(ctx context.Context, req models.CreateMilestoneRequest) (*models.Milestone, error) {
+	id, key := f.id()
+	f.milestones[id] = models.Milestone{ID: id, Key: key, Name: req.Name, Quarter: req.Quarter, PillarID: req.PillarID}
+	milestone := f.milestones[id]
+	return &milestone, nil
+}
+
+func (f *fakeClient) UpdateMilestone(ctx context.Context, milestoneID string, req models.UpdateMilestoneRequest) error {
+	milestone, ok := f.milestones[milestoneID]
+	if !ok {
+		return errors.New("not found")
+	}
+	milestone.Name, milestone.Quarter = req.Name, req.Quarter
+	f.milestones[milestoneID] = milestone
+	return nil
+}
+
+func (f *fakeClient) CreateEpic(ctx context.Context, req models.CreateEpicRequest) (*models.Epic, error) {
+	id, key := f.id()
+	f.epics[id] = models.Epic{ID: id, Key: key, Name: req.Name, MilestoneIDs: []string{req.MilestoneID}}
+	epic := f.epics[id]
+	return &epic, nil
+}
+
+func (f *fakeClient) UpdateEpic(ctx context.Context, epicID string, req models.UpdateEpicRequest) error {
+	epic, ok := f.epics[epicID]
+	if !ok {
+		return errors.New("not found")
+	}
+	epic.Name, epic.Priority = req.Name, req.Priority
+	f.epics[epicID] = epic
+	return nil
+}
+
+func (f *fakeClient) UpdateEpicMilestone(ctx context.Context, epicID, milestoneID string) error {
+	epic, ok := f.epics[epicID]
+	if !ok {
+		return errors.New("not found")
+	}
+	epic.MilestoneIDs = []string{milestoneID}
+	f.epics[epicID] = epic
+	return nil
+}
+
+func (f *fakeClient) GetMilestone(ctx context.Context, milestoneID string) (*models.Milestone, error) {
+	milestone, ok := f.milestones[milestoneID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &milestone, nil
+}
+
+func (f *fakeClient) GetEpic(ctx context.Context, epicID string) (*models.Epic, error) {
+	epic, ok := f.epics[epicID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &epic, nil
+}
+
+// newTestLog returns a log in memory whose clock moves one hour at every entry
+func newTestLog(start time.Time) *Log {
+	log := NewLog(storage.NewMemoryStore())
+	now := start
+	log.now = func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}
+	return log
+}
+
+// jsonOf encodes a value like the log does
+func jsonOf(t *testing.T, value any) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestWriter(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	log := newTestLog(start)
+	client := newFakeClient()
+	writer := log.Writer(client, "alice", "DEVOPS")
+
+	milestone, err := writer.CreateMilestone(ctx, models.CreateMilestoneRequest{Name: "New", Quarter: "2025Q2", PillarID: "P"})
+	if err != nil {
+		t.Fatalf("CreateMilestone() error = %v", err)
+	}
+	if err := writer.UpdateMilestone(ctx, "1", models.UpdateMilestoneRequest{Name: "Renamed", Quarter: "2025Q3"}); err != nil {
+		t.Fatalf("UpdateMilestone() error = %v", err)
+	}
+	if err := writer.UpdateEpicMilestone(ctx, "2", milestone.ID); err != nil {
+		t.Fatalf("UpdateEpicMilestone() error = %v", err)
+	}
+	// failed writes are not recorded
+	if err := writer.UpdateEpic(ctx, "404", models.UpdateEpicRequest{Name: "Missing"}); err == nil {
+		t.Fatal("UpdateEpic() of a missing epic error = nil")
+	}
+
+	entries, err := log.List(ctx, storage.AuditQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []storage.AuditEntry{
+		{
+			ID: 3, Project: "DEVOPS", Actor: "alice", Action: ActionMove, EntityType: EntityEpic, EntityID: "2", EntityKey: "DEVOPS-2",
+			Before:    jsonOf(t, models.Epic{ID: "2", Key: "DEVOPS-2", Name: "Epic", MilestoneIDs: []string{"1"}}),
+			After:     jsonOf(t, models.Epic{ID: "2", Key: "DEVOPS-2", Name: "Epic", MilestoneIDs: []string{"11"}}),
+			Timestamp: start.Add(3 * time.Hour),
+		},
+		{
+			ID: 2, Project: "DEVOPS", Actor: "alice", Action: ActionUpdate, EntityType: EntityMilestone, EntityID: "1", EntityKey: "DEVOPS-1",
+			Before:    jsonOf(t, models.Milestone{ID: "1", Key: "DEVOPS-1", Name: "Milestone", Quarter: "2025Q1"}),
+			After:     jsonOf(t, models.Milestone{ID: "1", Key: "DEVOPS-1", Name: "Renamed", Quarter: "2025Q3"}),
+			Timestamp: start.Add(2 * time.Hour),
+		},
+		{
+			ID: 1, Project: "DEVOPS", Actor: "alice", Action: ActionCreate, EntityType: EntityMilestone, EntityID: "11", EntityKey: "DEVOPS-11",
+			After:     jsonOf(t, models.Milestone{ID: "11", Key: "DEVOPS-11", Name: "New", Quarter: "2025Q2", PillarID: "P"}),
+			Timestamp: start.Add(time.Hour),
+		},
+	}
+	if diff := cmp.Diff(want, entries); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriter_NilLog(t *testing.T) {
+	var log *Log
+	client := newFakeClient()
+	if err := log.Writer(client, "alice", "DEVOPS").UpdateMilestone(context.Background(), "1", models.UpdateMilestoneRequest{Name: "Renamed", Quarter: "2025Q3"}); err != nil {
+		t.Fatalf("UpdateMilestone() error = %v", err)
+	}
+	if client.milestones["1"].Name != "Renamed" {
+		t.Errorf("UpdateMilestone() did not write through a nil log")
+	}
+	entries, err := log.List(context.Background(), storage.AuditQuery{})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("List() of a nil log = %v, %v, want no entries", entries, err)
+	}
+}
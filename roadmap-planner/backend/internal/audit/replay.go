@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+)
+
+// Board is the roadmap as it was at a point in time
+type Board struct {
+	At         time.Time          `json:"at"`
+	Milestones []models.Milestone `json:"milestones"`
+	Epics      []models.Epic      `json:"epics"`
+	// Undone is the number of changes rolled back from the current roadmap
+	Undone int `json:"undone"`
+	// Incomplete lists the entries that could not be rolled back because their previous value is unknown
+	Incomplete []int64 `json:"incomplete,omitempty"`
+}
+
+// Replay rolls the current roadmap back to the given time by undoing the changes recorded after it
+// entries must be sorted most recent first, the entries recorded at or before at are ignored
+// Changes made in Jira outside of the planner are not in the log and stay as they currently are
+func Replay(milestones []models.Milestone, epics []models.Epic, entries []storage.AuditEntry, at time.Time) (*Board, error) {
+	board := &Board{
+		At:         at,
+		Milestones: append([]models.Milestone{}, milestones...),
+		Epics:      append([]models.Epic{}, epics...),
+	}
+
+	for _, entry := range entries {
+		if !entry.Timestamp.After(at) {
+			continue
+		}
+		var undone bool
+		var err error
+		switch entry.EntityType {
+		case EntityMilestone:
+			board.Milestones, undone, err = undo(board.Milestones, entry, func(m models.Milestone) string { return m.ID })
+		case EntityEpic:
+			board.Epics, undone, err = undo(board.Epics, entry, func(e models.Epic) string { return e.ID })
+		}
+		if err != nil {
+			return nil, err
+		}
+		if undone {
+			board.Undone++
+		} else {
+			board.Incomplete = append(board.Incomplete, entry.ID)
+		}
+	}
+
+	models.SortMilestones(board.Milestones)
+	models.SortEpics(board.Epics)
+	return board, nil
+}
+
+// undo rolls back the change of an entry: a created entity is removed, an updated one gets its previous value back
+// Returns false when the entry cannot be rolled back
+func undo[T any](items []T, entry storage.AuditEntry, id func(T) string) ([]T, bool, error) {
+	index := -1
+	for i, item := range items {
+		if id(item) == entry.EntityID {
+			index = i
+			break
+		}
+	}
+
+	if entry.Action == ActionCreate {
+		if index >= 0 {
+			items = append(items[:index], items[index+1:]...)
+		}
+		return items, true, nil
+	}
+
+	if len(entry.Before) == 0 {
+		return items, false, nil
+	}
+	var before T
+	if err := json.Unmarshal(entry.Before, &before); err != nil {
+		return nil, false, fmt.Errorf("failed to decode audit entry %d: %w", entry.ID, err)
+	}
+	if index >= 0 {
+		items[index] = before
+	} else {
+		items = append(items, before)
+	}
+	return items, true, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	log := newTestLog(start)
+	client := newFakeClient()
+	writer := log.Writer(client, "alice", "DEVOPS")
+	initialMilestones := []models.Milestone{client.milestones["1"]}
+	initialEpics := []models.Epic{client.epics["2"]}
+
+	// 01:00 creates milestone 11, 02:00 moves epic 2 to it, 03:00 creates epic 12 in it,
+	// 04:00 renames milestone 1 and 05:00 renames epic 12
+	milestone, _ := writer.CreateMilestone(ctx, models.CreateMilestoneRequest{Name: "New", Quarter: "2025Q2"})
+	_ = writer.UpdateEpicMilestone(ctx, "2", milestone.ID)
+	epic, _ := writer.CreateEpic(ctx, models.CreateEpicRequest{Name: "Added", MilestoneID: milestone.ID})
+	_ = writer.UpdateMilestone(ctx, "1", models.UpdateMilestoneRequest{Name: "Renamed", Quarter: "2025Q1"})
+	_ = writer.UpdateEpic(ctx, epic.ID, models.UpdateEpicRequest{Name: "Added and renamed"})
+
+	entries, err := log.List(ctx, storage.AuditQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	current := []models.Milestone{client.milestones["1"], client.milestones["11"]}
+	currentEpics := []models.Epic{client.epics["2"], client.epics["12"]}
+
+	tests := map[string]struct {
+		at   time.Time
+		want *Board
+	}{
+		"before every change": {
+			at:   start,
+			want: &Board{Milestones: initialMilestones, Epics: initialEpics, Undone: 5},
+		},
+		"after the epic creation": {
+			at: start.Add(3 * time.Hour),
+			want: &Board{
+				Milestones: []models.Milestone{
+					{ID: "1", Key: "DEVOPS-1", Name: "Milestone", Quarter: "2025Q1"},
+					client.milestones["11"],
+				},
+				Epics: []models.Epic{
+					client.epics["2"],
+					{ID: "12", Key: "DEVOPS-12", Name: "Added", MilestoneIDs: []string{"11"}},
+				},
+				Undone: 2,
+			},
+		},
+		"now": {
+			at:   start.Add(5 * time.Hour),
+			want: &Board{Milestones: current, Epics: currentEpics},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Replay(current, currentEpics, entries, tc.at)
+			if err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+			tc.want.At = tc.at
+			models.SortMilestones(tc.want.Milestones)
+			models.SortEpics(tc.want.Epics)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Replay() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplay_Incomplete(t *testing.T) {
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	epics := []models.Epic{{ID: "2", Name: "Epic"}}
+	entries := []storage.AuditEntry{
+		// the value before the update could not be fetched
+		{ID: 7, Action: ActionUpdate, EntityType: EntityEpic, EntityID: "2", Timestamp: at.Add(time.Hour)},
+	}
+
+	got, err := Replay(nil, epics, entries, at)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	want := &Board{At: at, Milestones: []models.Milestone{}, Epics: epics, Incomplete: []int64{7}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Replay() mismatch (-want +got):\n%s", diff)
+	}
+
+	entries[0].Before = []byte("not json")
+	if _, err := Replay(nil, epics, entries, at); err == nil {
+		t.Error("Replay() of an invalid entry error = nil")
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache provides a read cache for Jira queries with background refresh
+package cache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Kinds of cached queries
+const (
+	KindPillars    = "pillars"
+	KindProject    = "project"
+	KindMilestones = "milestones"
+	KindEpics      = "epics"
+	KindVersions   = "versions"
+)
+
+const (
+	defaultTTL             = 5 * time.Minute
+	defaultRefreshInterval = time.Minute
+	// refreshTimeout bounds a background refresh of an entry
+	refreshTimeout = 30 * time.Second
+)
+
+// Key identifies a cached query
+// Entries are cached per user as Jira permissions can differ between users
+type Key struct {
+	Project string
+	Kind    string
+	User    string
+	Filter  string
+}
+
+// String returns a readable representation of the key
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s/%s?%s", k.Project, k.Kind, k.User, k.Filter)
+}
+
+// Filter builds the filter part of a key from groups of values
+// The values of each group are sorted so that the order of query parameters does not matter
+func Filter(groups ...[]string) string {
+	parts := make([]string, 0, len(groups))
+	for _, group := range groups {
+		sorted := slices.Clone(group)
+		slices.Sort(sorted)
+		parts = append(parts, strings.Join(sorted, ","))
+	}
+	return strings.Join(parts, "|")
+}
+
+// entry is a cached query result
+type entry struct {
+	value      any
+	load       func(context.Context) (any, error)
+	loadedAt   time.Time
+	usedAt     time.Time
+	refreshing bool
+}
+
+// Cache caches the results of read queries
+// Entries are served for up to ttl after they were loaded, entries used within ttl are reloaded
+// in the background every refresh interval and the others are evicted
+// A nil Cache does not cache anything
+type Cache struct {
+	ttl             time.Duration
+	refreshInterval time.Duration
+	logger          *zap.Logger
+	now             func() time.Time
+
+	mu      sync.Mutex
+	entries map[Key]*entry
+	// generation changes on every invalidation so that loads started before it are not stored
+	generation uint64
+}
+
+// New creates a cache from the configuration, it returns nil if the ttl is 0
+func New(cfg *config.Cache) *Cache {
+	ttl := parseDuration(cfg.TTL, defaultTTL)
+	if ttl == 0 {
+		return nil
+	}
+	refreshInterval := parseDuration(cfg.RefreshInterval, defaultRefreshInterval)
+	if refreshInterval <= 0 || refreshInterval > ttl {
+		refreshInterval = ttl
+	}
+
+	return &Cache{
+		ttl:             ttl,
+		refreshInterval: refreshInterval,
+		logger:          logger.WithComponent("cache"),
+		now:             time.Now,
+		entries:         map[Key]*entry{},
+	}
+}
+
+// parseDuration parses value, returning the default value if it is empty or invalid
+func parseDuration(value string, defaultValue time.Duration) time.Duration {
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return defaultValue
+	}
+	return d
+}
+
+// Get returns the cached value of the key, calling load on a miss
+// Errors are not cached
+func Get[T any](ctx context.Context, c *Cache, key Key, load func(context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return load(ctx)
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	if e, ok := c.entries[key]; ok && now.Sub(e.loadedAt) < c.ttl {
+		e.usedAt = now
+		value := e.value
+		c.mu.Unlock()
+		return value.(T), nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		now = c.now()
+		c.entries[key] = &entry{
+			value:    value,
+			load:     func(ctx context.Context) (any, error) { return load(ctx) },
+			loadedAt: now,
+			usedAt:   now,
+		}
+	}
+	return value, nil
+}
+
+// Invalidate removes the entries of the given kinds of a project for all users
+// It removes all the entries of the project when no kind is given
+func (c *Cache) Invalidate(project string, kinds ...string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	removed := 0
+	for key := range c.entries {
+		if key.Project == project && (len(kinds) == 0 || slices.Contains(kinds, key.Kind)) {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	c.logger.Debug("Invalidated cache entries",
+		zap.String("project", project),
+		zap.Strings("kinds", kinds),
+		zap.Int("removed", removed))
+}
+
+// InvalidateUser removes the entries of a user for all projects
+// The loads of the entries use the Jira client of the user session, they must not be refreshed after logout
+func (c *Cache) InvalidateUser(user string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	removed := 0
+	for key := range c.entries {
+		if key.User == user {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	c.logger.Debug("Invalidated user cache entries",
+		zap.String("user", user),
+		zap.Int("removed", removed))
+}
+
+// Len returns the number of cached entries
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Start refreshes the cached entries in the background until the context is cancelled
+func (c *Cache) Start(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	c.logger.Info("Cache refresher started",
+		zap.Duration("ttl", c.ttl),
+		zap.Duration("refresh_interval", c.refreshInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh evicts the entries not used within the ttl and reloads the entries older than the refresh interval
+func (c *Cache) Refresh(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	stale := map[Key]*entry{}
+	for key, e := range c.entries {
+		switch {
+		case now.Sub(e.usedAt) >= c.ttl:
+			delete(c.entries, key)
+		case now.Sub(e.loadedAt) >= c.refreshInterval && !e.refreshing:
+			e.refreshing = true
+			stale[key] = e
+		}
+	}
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for key, e := range stale {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.refresh(ctx, key, e)
+		}()
+	}
+	wg.Wait()
+}
+
+// refresh reloads an entry, keeping the previous value if the load fails
+func (c *Cache) refresh(ctx context.Context, key Key, e *entry) {
+	loadCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+	value, err := e.load(loadCtx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refreshing = false
+	if err != nil {
+		c.logger.Warn("Failed to refresh cache entry", zap.Stringer("key", key), zap.Error(err))
+		return
+	}
+	// the entry may have been evicted or invalidated during the load
+	if c.entries[key] != e {
+		return
+	}
+	e.value = value
+	e.loadedAt = c.now()
+}
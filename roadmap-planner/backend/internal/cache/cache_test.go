@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+// counter is a loader counting its calls
+type counter struct {
+	calls int
+	err   error
+}
+
+func (l *counter) load(ctx context.Context) (int, error) {
+	l.calls++
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.calls, nil
+}
+
+// newTestCache returns a cache with a controllable clock
+func newTestCache(t *testing.T) (*Cache, *time.Time) {
+	t.Helper()
+	c := New(&config.Cache{TTL: "5m", RefreshInterval: "1m"})
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	c, now := newTestCache(t)
+	key := Key{Project: "DEVOPS", Kind: KindEpics, User: "alice"}
+	loader := &counter{}
+
+	for i := 0; i < 2; i++ {
+		if value, err := Get(ctx, c, key, loader.load); err != nil || value != 1 {
+			t.Fatalf("Get() = %d, %v, want 1, nil", value, err)
+		}
+	}
+
+	// other users do not share entries
+	if value, _ := Get(ctx, c, Key{Project: "DEVOPS", Kind: KindEpics, User: "bob"}, loader.load); value != 2 {
+		t.Errorf("Get() for another user = %d, want 2", value)
+	}
+
+	// expired entries are loaded again
+	*now = now.Add(5 * time.Minute)
+	if value, _ := Get(ctx, c, key, loader.load); value != 3 {
+		t.Errorf("Get() after ttl = %d, want 3", value)
+	}
+
+	// errors are not cached
+	failing := &counter{err: errors.New("jira is down")}
+	errKey := Key{Project: "DEVOPS", Kind: KindMilestones, User: "alice"}
+	for i := 0; i < 2; i++ {
+		if _, err := Get(ctx, c, errKey, failing.load); err == nil {
+			t.Errorf("Get() expected error")
+		}
+	}
+	if failing.calls != 2 {
+		t.Errorf("failing loader calls = %d, want 2", failing.calls)
+	}
+}
+
+func TestGet_NilCache(t *testing.T) {
+	loader := &counter{}
+	var c *Cache
+	for i := 1; i <= 2; i++ {
+		if value, _ := Get(context.Background(), c, Key{}, loader.load); value != i {
+			t.Errorf("Get() on a nil cache = %d, want %d", value, i)
+		}
+	}
+	c.Invalidate("DEVOPS")
+	c.InvalidateUser("alice")
+	c.Refresh(context.Background())
+	if c.Len() != 0 {
+		t.Errorf("Len() of a nil cache = %d", c.Len())
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCache(t)
+	keys := []Key{
+		{Project: "DEVOPS", Kind: KindEpics, User: "alice"},
+		{Project: "DEVOPS", Kind: KindEpics, User: "bob", Filter: "1|||"},
+		{Project: "DEVOPS", Kind: KindMilestones, User: "alice"},
+		{Project: "DEVOPS", Kind: KindVersions, User: "alice", Filter: "argo-cd"},
+		{Project: "OTHER", Kind: KindEpics, User: "alice"},
+	}
+	for _, key := range keys {
+		loader := &counter{}
+		_, _ = Get(ctx, c, key, loader.load)
+	}
+
+	c.Invalidate("DEVOPS", KindEpics, KindMilestones)
+
+	remaining := []Key{}
+	for key := range c.entries {
+		remaining = append(remaining, key)
+	}
+	want := []Key{keys[3], keys[4]}
+	if diff := cmp.Diff(want, remaining, cmpKeys); diff != "" {
+		t.Errorf("entries after Invalidate() mismatch (-want +got):\n%s", diff)
+	}
+
+	c.Invalidate("DEVOPS")
+	if c.Len() != 1 {
+		t.Errorf("Len() after invalidating a project = %d, want 1", c.Len())
+	}
+}
+
+func TestInvalidateUser(t *testing.T) {
+	ctx := context.Background()
+	c, now := newTestCache(t)
+	keys := []Key{
+		{Project: "DEVOPS", Kind: KindEpics, User: "alice"},
+		{Project: "OTHER", Kind: KindMilestones, User: "alice"},
+		{Project: "DEVOPS", Kind: KindEpics, User: "bob"},
+	}
+	loaders := []*counter{{}, {}, {}}
+	for i, key := range keys {
+		_, _ = Get(ctx, c, key, loaders[i].load)
+	}
+
+	c.InvalidateUser("alice")
+
+	remaining := []Key{}
+	for key := range c.entries {
+		remaining = append(remaining, key)
+	}
+	if diff := cmp.Diff([]Key{keys[2]}, remaining, cmpKeys); diff != "" {
+		t.Errorf("entries after InvalidateUser() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the loads of the removed entries are not called by the refresh anymore
+	*now = now.Add(time.Minute)
+	c.Refresh(ctx)
+	if loaders[0].calls != 1 || loaders[1].calls != 1 || loaders[2].calls != 2 {
+		t.Errorf("loader calls after Refresh() = %d, %d, %d, want 1, 1, 2", loaders[0].calls, loaders[1].calls, loaders[2].calls)
+	}
+}
+
+func TestInvalidate_DuringLoad(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestCache(t)
+	key := Key{Project: "DEVOPS", Kind: KindEpics, User: "alice"}
+
+	// a write invalidating the entry while it loads must not let the stale result be cached
+	value, err := Get(ctx, c, key, func(ctx context.Context) (string, error) {
+		c.Invalidate("DEVOPS", KindEpics)
+		return "stale", nil
+	})
+	if err != nil || value != "stale" {
+		t.Fatalf("Get() = %q, %v", value, err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want the stale result not to be cached", c.Len())
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	c, now := newTestCache(t)
+	hot := Key{Project: "DEVOPS", Kind: KindEpics, User: "alice"}
+	idle := Key{Project: "DEVOPS", Kind: KindMilestones, User: "alice"}
+	failing := Key{Project: "DEVOPS", Kind: KindPillars, User: "alice"}
+	hotLoader, idleLoader, failingLoader := &counter{}, &counter{}, &counter{}
+	_, _ = Get(ctx, c, hot, hotLoader.load)
+	_, _ = Get(ctx, c, idle, idleLoader.load)
+	_, _ = Get(ctx, c, failing, failingLoader.load)
+
+	// fresh entries are not reloaded
+	c.Refresh(ctx)
+	if hotLoader.calls != 1 {
+		t.Errorf("loader calls after refreshing fresh entries = %d, want 1", hotLoader.calls)
+	}
+
+	// entries older than the refresh interval are reloaded, failures keep the previous value
+	*now = now.Add(2 * time.Minute)
+	failingLoader.err = errors.New("jira is down")
+	c.Refresh(ctx)
+	if value, _ := Get(ctx, c, hot, hotLoader.load); value != 2 {
+		t.Errorf("Get() after refresh = %d, want 2", value)
+	}
+	if value, _ := Get(ctx, c, failing, failingLoader.load); value != 1 {
+		t.Errorf("Get() after a failed refresh = %d, want 1", value)
+	}
+
+	// entries not used within the ttl are evicted
+	*now = now.Add(4 * time.Minute)
+	c.Refresh(ctx)
+	if _, ok := c.entries[idle]; ok {
+		t.Errorf("idle entry was not evicted")
+	}
+	if _, ok := c.entries[hot]; !ok {
+		t.Errorf("hot entry was evicted")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		cfg             config.Cache
+		wantNil         bool
+		wantTTL         time.Duration
+		wantRefreshEach time.Duration
+	}{
+		"configured":             {cfg: config.Cache{TTL: "10m", RefreshInterval: "2m"}, wantTTL: 10 * time.Minute, wantRefreshEach: 2 * time.Minute},
+		"defaults":               {wantTTL: defaultTTL, wantRefreshEach: defaultRefreshInterval},
+		"invalid values":         {cfg: config.Cache{TTL: "soon", RefreshInterval: "-1m"}, wantTTL: defaultTTL, wantRefreshEach: defaultRefreshInterval},
+		"refresh capped to ttl":  {cfg: config.Cache{TTL: "30s", RefreshInterval: "1m"}, wantTTL: 30 * time.Second, wantRefreshEach: 30 * time.Second},
+		"disabled with zero ttl": {cfg: config.Cache{TTL: "0"}, wantNil: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := New(&tt.cfg)
+			if tt.wantNil {
+				if c != nil {
+					t.Errorf("New() = %+v, want nil", c)
+				}
+				return
+			}
+			if c.ttl != tt.wantTTL || c.refreshInterval != tt.wantRefreshEach {
+				t.Errorf("New() ttl = %v, refresh interval = %v, want %v, %v", c.ttl, c.refreshInterval, tt.wantTTL, tt.wantRefreshEach)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := map[string]struct {
+		groups [][]string
+		want   string
+	}{
+		"no groups":       {want: ""},
+		"empty groups":    {groups: [][]string{nil, nil}, want: "|"},
+		"sorted values":   {groups: [][]string{{"2", "1"}, {"argo-cd"}}, want: "1,2|argo-cd"},
+		"groups are kept": {groups: [][]string{nil, {"1"}}, want: "|1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Filter(tt.groups...); got != tt.want {
+				t.Errorf("Filter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// cmpKeys compares keys regardless of their order
+var cmpKeys = cmp.Transformer("sort", func(keys []Key) map[Key]bool {
+	set := map[Key]bool{}
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+})
@@ -23,14 +23,19 @@ import (
 	"github.com/spf13/viper"
 )
 
+// placeholderSessionSecret is the session secret of older example configs, it is refused
+const placeholderSessionSecret = "change-me"
+
 // Config represents the application configuration
 type Config struct {
 	Debug   bool    `mapstructure:"debug"`
 	Logger  Logger  `mapstructure:"logger"`
 	Jira    Jira    `mapstructure:"jira"`
 	Server  Server  `mapstructure:"server"`
+	Auth    Auth    `mapstructure:"auth"`
 	Cache   Cache   `mapstructure:"cache"`
 	Metrics Metrics `mapstructure:"metrics"`
+	Storage Storage `mapstructure:"storage"`
 }
 
 // Logger represents logger configuration settings
@@ -56,6 +61,39 @@ type Server struct {
 	StaticFilesPath string `mapstructure:"static_files_path"`
 }
 
+// Auth represents session authentication settings
+type Auth struct {
+	// SessionSecret signs the session cookies and encrypts the stored Jira tokens
+	// A random secret is generated when it is empty, sessions are then lost on restart
+	SessionSecret string `mapstructure:"session_secret"`
+	// SessionTTL is the lifetime of a session
+	SessionTTL string `mapstructure:"session_ttl"`
+	// CookieName is the name of the session cookie
+	CookieName string `mapstructure:"cookie_name"`
+	// CookieSecure restricts the session cookie to HTTPS
+	CookieSecure bool `mapstructure:"cookie_secure"`
+	// OIDC configures single sign-on with an OpenID Connect provider such as Keycloak
+	OIDC OIDC `mapstructure:"oidc"`
+}
+
+// OIDC configures single sign-on with an OpenID Connect provider
+// SSO users are mapped to Jira users, reads run with the jira service account
+// and writes are attributed to the signed in user with a comment on the issue
+type OIDC struct {
+	Enabled bool `mapstructure:"enabled"`
+	// IssuerURL is the issuer of the provider, e.g. https://keycloak.example.com/realms/devops
+	IssuerURL    string `mapstructure:"issuer_url"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	// RedirectURL is the public URL of /api/auth/oidc/callback
+	RedirectURL string   `mapstructure:"redirect_url"`
+	Scopes      []string `mapstructure:"scopes"`
+	// UserClaim is the ID token claim matched against the Jira username or email
+	UserClaim string `mapstructure:"user_claim"`
+	// PostLoginURL is where the browser is redirected after signing in
+	PostLoginURL string `mapstructure:"post_login_url"`
+}
+
 // CORS represents CORS configuration settings
 type CORS struct {
 	AllowedOrigins []string `mapstructure:"allowed_origins"`
@@ -67,14 +105,30 @@ type Cache struct {
 	RefreshInterval string `mapstructure:"refresh_interval"`
 }
 
+// Storage represents persistent storage configuration
+type Storage struct {
+	// Driver is the storage backend: sqlite, memory or none, none disables the audit log
+	Driver string `mapstructure:"driver"`
+	// Path is the SQLite database file
+	Path string `mapstructure:"path"`
+	// SnapshotInterval is how often metric snapshots are recorded
+	SnapshotInterval string `mapstructure:"snapshot_interval"`
+	// TrendWindow is how far back the summary trend compares against
+	TrendWindow string `mapstructure:"trend_window"`
+	// RetentionDays is how long metric snapshots are kept
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
 // Metrics represents metrics system configuration
 type Metrics struct {
-	Enabled            bool             `mapstructure:"enabled"`
-	CollectionInterval string           `mapstructure:"collection_interval"`
-	HistoricalDays     int              `mapstructure:"historical_days"`
-	Prometheus         PrometheusConfig `mapstructure:"prometheus"`
-	Filters            []OptionsConfig  `mapstructure:"filters"`
-	Calculators        []OptionsConfig  `mapstructure:"calculators"`
+	Enabled              bool             `mapstructure:"enabled"`
+	CollectionInterval   string           `mapstructure:"collection_interval"`
+	HistoricalDays       int              `mapstructure:"historical_days"`
+	FullSyncInterval     string           `mapstructure:"full_sync_interval"`
+	ChangelogConcurrency int              `mapstructure:"changelog_concurrency"`
+	Prometheus           PrometheusConfig `mapstructure:"prometheus"`
+	Filters              []OptionsConfig  `mapstructure:"filters"`
+	Calculators          []OptionsConfig  `mapstructure:"calculators"`
 }
 
 // PrometheusConfig represents Prometheus exporter configuration
@@ -202,6 +256,13 @@ func Load() (*Config, error) {
 	viper.SetDefault("server.cors.allowed_origins", []string{"http://localhost:3000"})
 	viper.SetDefault("jira.project", "DEVOPS")
 	viper.SetDefault("jira.quarters", []string{"2025Q1", "2025Q2", "2025Q3", "2025Q4", "2026Q1", "2026Q2", "2026Q4"})
+	viper.SetDefault("auth.session_ttl", "12h")
+	viper.SetDefault("auth.cookie_name", "roadmap_session")
+	viper.SetDefault("auth.cookie_secure", false)
+	viper.SetDefault("auth.oidc.enabled", false)
+	viper.SetDefault("auth.oidc.scopes", []string{"openid", "profile", "email"})
+	viper.SetDefault("auth.oidc.user_claim", "preferred_username")
+	viper.SetDefault("auth.oidc.post_login_url", "/")
 	viper.SetDefault("cache.ttl", "5m")
 	viper.SetDefault("cache.refresh_interval", "1m")
 
@@ -209,11 +270,20 @@ func Load() (*Config, error) {
 	viper.SetDefault("metrics.enabled", false)
 	viper.SetDefault("metrics.collection_interval", "5m")
 	viper.SetDefault("metrics.historical_days", 365)
+	viper.SetDefault("metrics.full_sync_interval", "24h")
+	viper.SetDefault("metrics.changelog_concurrency", 4)
 	viper.SetDefault("metrics.prometheus.enabled", true)
 	viper.SetDefault("metrics.prometheus.path", "/metrics")
 	viper.SetDefault("metrics.prometheus.namespace", "roadmap")
 	viper.SetDefault("metrics.filters", []OptionsConfig{})
 
+	// Storage defaults
+	viper.SetDefault("storage.driver", "sqlite")
+	viper.SetDefault("storage.path", "roadmap-planner.db")
+	viper.SetDefault("storage.snapshot_interval", "1h")
+	viper.SetDefault("storage.trend_window", "720h")
+	viper.SetDefault("storage.retention_days", 730)
+
 	// Environment variable mapping
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
@@ -222,12 +292,23 @@ func Load() (*Config, error) {
 	_ = viper.BindEnv("jira.base_url", "JIRA_BASE_URL")
 	_ = viper.BindEnv("jira.username", "JIRA_USERNAME")
 	_ = viper.BindEnv("jira.password", "JIRA_PASSWORD")
+	_ = viper.BindEnv("auth.session_secret", "AUTH_SESSION_SECRET")
+	_ = viper.BindEnv("auth.cookie_secure", "AUTH_COOKIE_SECURE")
+	_ = viper.BindEnv("auth.oidc.enabled", "AUTH_OIDC_ENABLED")
+	_ = viper.BindEnv("auth.oidc.issuer_url", "AUTH_OIDC_ISSUER_URL")
+	_ = viper.BindEnv("auth.oidc.client_id", "AUTH_OIDC_CLIENT_ID")
+	_ = viper.BindEnv("auth.oidc.client_secret", "AUTH_OIDC_CLIENT_SECRET")
+	_ = viper.BindEnv("auth.oidc.redirect_url", "AUTH_OIDC_REDIRECT_URL")
 	_ = viper.BindEnv("server.static_files_path", "STATIC_FILES_PATH")
 	_ = viper.BindEnv("server.port", "SERVER_PORT")
 	_ = viper.BindEnv("debug", "DEBUG")
 	_ = viper.BindEnv("metrics.enabled", "METRICS_ENABLED")
 	_ = viper.BindEnv("metrics.collection_interval", "METRICS_COLLECTION_INTERVAL")
 	_ = viper.BindEnv("metrics.historical_days", "METRICS_HISTORICAL_DAYS")
+	_ = viper.BindEnv("metrics.full_sync_interval", "METRICS_FULL_SYNC_INTERVAL")
+	_ = viper.BindEnv("metrics.changelog_concurrency", "METRICS_CHANGELOG_CONCURRENCY")
+	_ = viper.BindEnv("storage.driver", "STORAGE_DRIVER")
+	_ = viper.BindEnv("storage.path", "STORAGE_PATH")
 
 	// Read config file if it exists
 	if err := viper.ReadInConfig(); err != nil {
@@ -240,6 +321,18 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 
 	return &config, nil
 }
+
+// Validate checks the configuration
+func (c *Config) Validate() error {
+	// a copied example config must not encrypt the stored Jira tokens with a publicly known key
+	if c.Auth.SessionSecret == placeholderSessionSecret {
+		return fmt.Errorf("auth.session_secret is the %q placeholder, set AUTH_SESSION_SECRET to a random value", placeholderSessionSecret)
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dependency builds the dependency graph of epics from their "blocks" links
+package dependency
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+)
+
+// Violation reasons
+const (
+	// ReasonScheduledBeforeDependency flags an epic released before an epic it depends on
+	ReasonScheduledBeforeDependency = "scheduled_before_dependency"
+	// ReasonDependencyUnscheduled flags a scheduled epic depending on an unscheduled and unresolved epic
+	ReasonDependencyUnscheduled = "dependency_unscheduled"
+)
+
+// Node is an epic of the graph
+type Node struct {
+	ID           string   `json:"id"`
+	Key          string   `json:"key"`
+	Name         string   `json:"name"`
+	Status       string   `json:"status"`
+	MilestoneIDs []string `json:"milestone_ids"`
+	Versions     []string `json:"versions"`
+	// ReleaseDate is the earliest release date of the fix versions, empty when the epic is not scheduled
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+	// Finish is the latest release date of the epic and of the epics it depends on transitively
+	Finish *time.Time `json:"finish,omitempty"`
+	// External epics are dependencies outside of the selected milestones
+	External bool `json:"external"`
+	Critical bool `json:"critical"`
+	InCycle  bool `json:"in_cycle"`
+	// Depth is the number of epics of the longest dependency chain ending with the epic
+	Depth int `json:"depth"`
+
+	resolved bool
+}
+
+// Edge is a dependency: To depends on From, From blocks To
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Violation is a dependency the schedule does not respect
+type Violation struct {
+	Epic                  string     `json:"epic"`
+	DependsOn             string     `json:"depends_on"`
+	Reason                string     `json:"reason"`
+	ReleaseDate           *time.Time `json:"release_date,omitempty"`
+	DependencyReleaseDate *time.Time `json:"dependency_release_date,omitempty"`
+}
+
+// Graph is the dependency graph of a set of epics
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+	// Cycles are the groups of epics depending on each other, each sorted by key
+	Cycles [][]string `json:"cycles"`
+	// CriticalPath is the chain of dependencies, first to last, ending with the epic finishing last
+	// Each step goes back to the dependency finishing last, edges inside cycles are ignored
+	CriticalPath []string    `json:"critical_path"`
+	Violations   []Violation `json:"violations"`
+}
+
+// ReleaseDates maps the names of the versions to their release dates
+func ReleaseDates(versions []models.Version) map[string]time.Time {
+	dates := map[string]time.Time{}
+	for _, version := range versions {
+		if version.ReleaseDate == "" {
+			continue
+		}
+		if date, err := time.Parse("2006-01-02", version.ReleaseDate); err == nil {
+			dates[version.Name] = date
+		}
+	}
+	return dates
+}
+
+// Build builds the graph of the selected epics and of their external dependencies
+// Links to epics missing from both lists are ignored
+func Build(epics, external []models.Epic, releaseDates map[string]time.Time) *Graph {
+	graph := &Graph{Nodes: []*Node{}, Edges: []Edge{}, Cycles: [][]string{}, CriticalPath: []string{}, Violations: []Violation{}}
+	nodes := map[string]*Node{}
+	add := func(epic models.Epic, isExternal bool) {
+		if _, ok := nodes[epic.Key]; ok {
+			return
+		}
+		node := &Node{
+			ID:           epic.ID,
+			Key:          epic.Key,
+			Name:         epic.Name,
+			Status:       epic.Status,
+			MilestoneIDs: epic.MilestoneIDs,
+			Versions:     epic.Versions,
+			ReleaseDate:  earliestRelease(epic.Versions, releaseDates),
+			External:     isExternal,
+			resolved:     !epic.ResolutionDate.IsZero(),
+		}
+		nodes[epic.Key] = node
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, epic := range epics {
+		add(epic, false)
+	}
+	for _, epic := range external {
+		add(epic, true)
+	}
+
+	// links are read from both ends, an epic may not be linked back
+	edges := map[Edge]bool{}
+	for _, epic := range append(append([]models.Epic{}, epics...), external...) {
+		for _, from := range epic.BlockedBy {
+			edges[Edge{From: from, To: epic.Key}] = true
+		}
+		for _, to := range epic.Blocks {
+			edges[Edge{From: epic.Key, To: to}] = true
+		}
+	}
+	for edge := range edges {
+		if nodes[edge.From] != nil && nodes[edge.To] != nil {
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	dependencies := map[string][]string{}
+	dependents := map[string][]string{}
+	for _, edge := range graph.Edges {
+		dependencies[edge.To] = append(dependencies[edge.To], edge.From)
+		dependents[edge.From] = append(dependents[edge.From], edge.To)
+	}
+
+	component := graph.findCycles(nodes, dependents)
+	// acyclic keeps the edges between different strongly connected components
+	acyclic := func(from, to string) bool {
+		return component[from] != component[to]
+	}
+
+	graph.schedule(nodes, dependencies, dependents, acyclic)
+	graph.findCriticalPath(nodes, dependencies, acyclic)
+	graph.findViolations(nodes)
+	return graph
+}
+
+// earliestRelease returns the earliest release date of the versions, nil if none is scheduled
+func earliestRelease(versions []string, releaseDates map[string]time.Time) *time.Time {
+	var earliest *time.Time
+	for _, version := range versions {
+		if date, ok := releaseDates[version]; ok && (earliest == nil || date.Before(*earliest)) {
+			earliest = &date
+		}
+	}
+	return earliest
+}
+
+// findCycles finds the strongly connected components with Tarjan's algorithm, records the cycles
+// and returns the component of each epic
+func (g *Graph) findCycles(nodes map[string]*Node, dependents map[string][]string) map[string]int {
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	component := map[string]int{}
+	stack := []string{}
+	next, components := 0, 0
+
+	var connect func(key string)
+	connect = func(key string) {
+		index[key], lowLink[key] = next, next
+		next++
+		stack = append(stack, key)
+		onStack[key] = true
+
+		for _, dependent := range dependents[key] {
+			if _, visited := index[dependent]; !visited {
+				connect(dependent)
+				lowLink[key] = min(lowLink[key], lowLink[dependent])
+			} else if onStack[dependent] {
+				lowLink[key] = min(lowLink[key], index[dependent])
+			}
+		}
+
+		if lowLink[key] != index[key] {
+			return
+		}
+		members := []string{}
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component[member] = components
+			members = append(members, member)
+			if member == key {
+				break
+			}
+		}
+		components++
+		if len(members) > 1 || slices.Contains(dependents[key], key) {
+			sort.Strings(members)
+			g.Cycles = append(g.Cycles, members)
+			for _, member := range members {
+				nodes[member].InCycle = true
+			}
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if _, visited := index[node.Key]; !visited {
+			connect(node.Key)
+		}
+	}
+	sort.Slice(g.Cycles, func(i, j int) bool { return g.Cycles[i][0] < g.Cycles[j][0] })
+	return component
+}
+
+// schedule computes the finish date and the dependency depth of the epics in topological order
+func (g *Graph) schedule(nodes map[string]*Node, dependencies, dependents map[string][]string, acyclic func(from, to string) bool) {
+	pending := map[string]int{}
+	for _, node := range g.Nodes {
+		for _, dependency := range dependencies[node.Key] {
+			if acyclic(dependency, node.Key) {
+				pending[node.Key]++
+			}
+		}
+	}
+	queue := []string{}
+	for _, node := range g.Nodes {
+		if pending[node.Key] == 0 {
+			queue = append(queue, node.Key)
+		}
+	}
+
+	for len(queue) > 0 {
+		node := nodes[queue[0]]
+		queue = queue[1:]
+
+		node.Finish = node.ReleaseDate
+		node.Depth = 1
+		for _, key := range dependencies[node.Key] {
+			if !acyclic(key, node.Key) {
+				continue
+			}
+			dependency := nodes[key]
+			if later(dependency.Finish, node.Finish) {
+				node.Finish = dependency.Finish
+			}
+			node.Depth = max(node.Depth, dependency.Depth+1)
+		}
+
+		for _, dependent := range dependents[node.Key] {
+			if !acyclic(node.Key, dependent) {
+				continue
+			}
+			if pending[dependent]--; pending[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+}
+
+// findCriticalPath starts from the epic finishing last and goes back through the dependencies finishing last
+func (g *Graph) findCriticalPath(nodes map[string]*Node, dependencies map[string][]string, acyclic func(from, to string) bool) {
+	var last *Node
+	for _, node := range g.Nodes {
+		if ahead(node, last) {
+			last = node
+		}
+	}
+
+	path := []string{}
+	for node := last; node != nil; {
+		node.Critical = true
+		path = append(path, node.Key)
+		var previous *Node
+		for _, key := range dependencies[node.Key] {
+			if acyclic(key, node.Key) && ahead(nodes[key], previous) {
+				previous = nodes[key]
+			}
+		}
+		node = previous
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	g.CriticalPath = path
+}
+
+// ahead returns true if node finishes after other, then if it has the longest dependency chain
+func ahead(node, other *Node) bool {
+	switch {
+	case other == nil:
+		return true
+	case later(node.Finish, other.Finish):
+		return true
+	case later(other.Finish, node.Finish):
+		return false
+	case node.Depth != other.Depth:
+		return node.Depth > other.Depth
+	}
+	return node.Key < other.Key
+}
+
+// later returns true if a is after b, unscheduled dates are before any date
+func later(a, b *time.Time) bool {
+	return a != nil && (b == nil || a.After(*b))
+}
+
+// findViolations flags the scheduled epics released before the epics they depend on
+// Resolved dependencies are not flagged
+func (g *Graph) findViolations(nodes map[string]*Node) {
+	for _, edge := range g.Edges {
+		epic, dependency := nodes[edge.To], nodes[edge.From]
+		if epic.ReleaseDate == nil || dependency.resolved {
+			continue
+		}
+		violation := Violation{
+			Epic:                  epic.Key,
+			DependsOn:             dependency.Key,
+			ReleaseDate:           epic.ReleaseDate,
+			DependencyReleaseDate: dependency.ReleaseDate,
+		}
+		switch {
+		case dependency.ReleaseDate == nil:
+			violation.Reason = ReasonDependencyUnscheduled
+		case epic.ReleaseDate.Before(*dependency.ReleaseDate):
+			violation.Reason = ReasonScheduledBeforeDependency
+		default:
+			continue
+		}
+		g.Violations = append(g.Violations, violation)
+	}
+}
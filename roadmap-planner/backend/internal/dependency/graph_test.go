@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func date(value string) *time.Time {
+	t, _ := time.Parse("2006-01-02", value)
+	return &t
+}
+
+func TestReleaseDates(t *testing.T) {
+	got := ReleaseDates([]models.Version{
+		{Name: "v1", ReleaseDate: "2025-03-01"},
+		{Name: "v2"},
+		{Name: "v3", ReleaseDate: "not a date"},
+	})
+	if diff := cmp.Diff(map[string]time.Time{"v1": *date("2025-03-01")}, got); diff != "" {
+		t.Errorf("ReleaseDates() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	releaseDates := map[string]time.Time{"v1": *date("2025-03-01"), "v2": *date("2025-06-01")}
+
+	tests := map[string]struct {
+		epics    []models.Epic
+		external []models.Epic
+		// want are the graph without the nodes
+		want         *Graph
+		wantInCycle  []string
+		wantCritical []string
+	}{
+		"schedule with an external dependency and a cycle": {
+			epics: []models.Epic{
+				{Key: "A", Versions: []string{"v1"}, BlockedBy: []string{"Z"}},
+				{Key: "B", Versions: []string{"v2"}, BlockedBy: []string{"A"}, Blocks: []string{"C"}},
+				// C is only linked from B, it is released before B
+				{Key: "C", Versions: []string{"v1"}},
+				{Key: "D", BlockedBy: []string{"A", "MISSING"}},
+				{Key: "X", BlockedBy: []string{"Y"}},
+				{Key: "Y", Blocks: []string{"C"}, BlockedBy: []string{"X"}},
+			},
+			external: []models.Epic{
+				{Key: "Z", Blocks: []string{"A"}},
+				{Key: "A"},
+			},
+			want: &Graph{
+				Edges: []Edge{
+					{From: "A", To: "B"}, {From: "A", To: "D"}, {From: "B", To: "C"},
+					{From: "X", To: "Y"}, {From: "Y", To: "C"}, {From: "Y", To: "X"}, {From: "Z", To: "A"},
+				},
+				Cycles:       [][]string{{"X", "Y"}},
+				CriticalPath: []string{"Z", "A", "B", "C"},
+				Violations: []Violation{
+					{Epic: "C", DependsOn: "B", Reason: ReasonScheduledBeforeDependency, ReleaseDate: date("2025-03-01"), DependencyReleaseDate: date("2025-06-01")},
+					{Epic: "C", DependsOn: "Y", Reason: ReasonDependencyUnscheduled, ReleaseDate: date("2025-03-01")},
+					{Epic: "A", DependsOn: "Z", Reason: ReasonDependencyUnscheduled, ReleaseDate: date("2025-03-01")},
+				},
+			},
+			wantInCycle:  []string{"X", "Y"},
+			wantCritical: []string{"A", "B", "C", "Z"},
+		},
+		"resolved dependencies are not flagged": {
+			epics: []models.Epic{
+				{Key: "A", ResolutionDate: *date("2025-01-15")},
+				{Key: "B", Versions: []string{"v1"}, BlockedBy: []string{"A"}},
+			},
+			want: &Graph{
+				Edges:        []Edge{{From: "A", To: "B"}},
+				Cycles:       [][]string{},
+				CriticalPath: []string{"A", "B"},
+				Violations:   []Violation{},
+			},
+			wantCritical: []string{"A", "B"},
+		},
+		"self dependency is a cycle": {
+			epics: []models.Epic{
+				{Key: "A", Versions: []string{"v2"}, BlockedBy: []string{"A"}},
+			},
+			want: &Graph{
+				Edges:        []Edge{{From: "A", To: "A"}},
+				Cycles:       [][]string{{"A"}},
+				CriticalPath: []string{"A"},
+				Violations:   []Violation{},
+			},
+			wantInCycle:  []string{"A"},
+			wantCritical: []string{"A"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Build(tt.epics, tt.external, releaseDates)
+
+			inCycle, critical := []string{}, []string{}
+			for _, node := range got.Nodes {
+				if node.InCycle {
+					inCycle = append(inCycle, node.Key)
+				}
+				if node.Critical {
+					critical = append(critical, node.Key)
+				}
+			}
+			if tt.wantInCycle == nil {
+				tt.wantInCycle = []string{}
+			}
+			if diff := cmp.Diff(tt.wantInCycle, inCycle); diff != "" {
+				t.Errorf("Build() in cycle mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCritical, critical); diff != "" {
+				t.Errorf("Build() critical mismatch (-want +got):\n%s", diff)
+			}
+
+			got.Nodes = nil
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Build() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuild_Nodes(t *testing.T) {
+	got := Build(
+		[]models.Epic{{ID: "1", Key: "A", Name: "Cache", Versions: []string{"v2", "v1"}, BlockedBy: []string{"Z"}}},
+		[]models.Epic{{ID: "2", Key: "Z", Versions: []string{"v2"}}},
+		map[string]time.Time{"v1": *date("2025-03-01"), "v2": *date("2025-06-01")},
+	)
+
+	want := []*Node{
+		{ID: "1", Key: "A", Name: "Cache", Versions: []string{"v2", "v1"}, ReleaseDate: date("2025-03-01"), Finish: date("2025-06-01"), Critical: true, Depth: 2},
+		{ID: "2", Key: "Z", Versions: []string{"v2"}, ReleaseDate: date("2025-06-01"), Finish: date("2025-06-01"), External: true, Critical: true, Depth: 1},
+	}
+	if diff := cmp.Diff(want, got.Nodes, cmp.AllowUnexported(Node{})); diff != "" {
+		t.Errorf("Build() nodes mismatch (-want +got):\n%s", diff)
+	}
+}
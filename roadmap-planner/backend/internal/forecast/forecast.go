@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forecast forecasts the completion of milestones with Monte Carlo simulations
+// of the historical throughput of each component
+package forecast
+
+import (
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	metricsModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+)
+
+const (
+	// DefaultRuns is the default number of simulations of each milestone
+	DefaultRuns = 10000
+	// DefaultHistoryWeeks is the default number of past weeks sampled for the throughput
+	DefaultHistoryWeeks = 12
+	// maxWeeks stops runs that cannot complete, they count as completing after maxWeeks
+	maxWeeks = 520
+
+	week = 7 * 24 * time.Hour
+	// unknown is the component of the items without component
+	unknown = "unknown"
+)
+
+// Work is a number of epics and issues
+type Work struct {
+	Epics  int `json:"epics"`
+	Issues int `json:"issues"`
+}
+
+// History is the work completed by each component in each past week, oldest week first
+type History map[string][]Work
+
+// NewHistory counts the epics and issues of each component resolved in each of the weeks before end
+// Items with several components count for each of them
+func NewHistory(epics, issues []metricsModels.EnrichedIssue, end time.Time, weeks int) History {
+	history := History{}
+	count := func(item metricsModels.EnrichedIssue, add func(*Work)) {
+		if item.ResolvedDate.IsZero() || !item.ResolvedDate.Before(end) {
+			return
+		}
+		index := weeks - 1 - int(end.Sub(item.ResolvedDate)/week)
+		if index < 0 {
+			return
+		}
+		for _, component := range componentsOf(item.Components) {
+			if history[component] == nil {
+				history[component] = make([]Work, weeks)
+			}
+			add(&history[component][index])
+		}
+	}
+	for _, epic := range epics {
+		count(epic, func(w *Work) { w.Epics++ })
+	}
+	for _, issue := range issues {
+		count(issue, func(w *Work) { w.Issues++ })
+	}
+	return history
+}
+
+// Remaining counts the unresolved epics and issues of each component
+func Remaining(epics []models.Epic, issues []models.Issue) map[string]Work {
+	remaining := map[string]Work{}
+	for _, epic := range epics {
+		if !epic.ResolutionDate.IsZero() {
+			continue
+		}
+		for _, component := range componentsOf(epic.Components) {
+			work := remaining[component]
+			work.Epics++
+			remaining[component] = work
+		}
+	}
+	for _, issue := range issues {
+		if !issue.ResolutionDate.IsZero() {
+			continue
+		}
+		for _, component := range componentsOf(issue.Components) {
+			work := remaining[component]
+			work.Issues++
+			remaining[component] = work
+		}
+	}
+	return remaining
+}
+
+// Simulate returns the number of weeks each run needs to complete the remaining work, sorted
+// Each component completes its work with the throughput of random past weeks, the run completes
+// with the last component
+// missing are the components with remaining work but no completed work of that kind in their history,
+// no run is simulated then
+func Simulate(rng *rand.Rand, history History, remaining map[string]Work, runs int) (weeks []int, missing []string) {
+	components := make([]string, 0, len(remaining))
+	for component, work := range remaining {
+		if work.Epics == 0 && work.Issues == 0 {
+			continue
+		}
+		var completed Work
+		for _, w := range history[component] {
+			completed.Epics += w.Epics
+			completed.Issues += w.Issues
+		}
+		if (work.Epics > 0 && completed.Epics == 0) || (work.Issues > 0 && completed.Issues == 0) {
+			missing = append(missing, component)
+			continue
+		}
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		return nil, missing
+	}
+
+	weeks = make([]int, runs)
+	for run := range weeks {
+		for _, component := range components {
+			left := remaining[component]
+			samples := history[component]
+			n := 0
+			for (left.Epics > 0 || left.Issues > 0) && n < maxWeeks {
+				sample := samples[rng.IntN(len(samples))]
+				left.Epics -= sample.Epics
+				left.Issues -= sample.Issues
+				n++
+			}
+			weeks[run] = max(weeks[run], n)
+		}
+	}
+	sort.Ints(weeks)
+	return weeks, nil
+}
+
+// Options configures the forecasts
+type Options struct {
+	// Now is the start of the simulations
+	Now  time.Time
+	Runs int
+	Rand *rand.Rand
+}
+
+// Milestone is the forecast of a milestone
+type Milestone struct {
+	ID      string `json:"id"`
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Quarter string `json:"quarter"`
+	// Deadline is the last day of the quarter of the milestone
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	Remaining Work       `json:"remaining"`
+	// P50, P85 and P95 are the dates by which the milestone completes in that share of the runs
+	P50 *time.Time `json:"p50,omitempty"`
+	P85 *time.Time `json:"p85,omitempty"`
+	P95 *time.Time `json:"p95,omitempty"`
+	// AtRisk is set when the milestone completes after its deadline at P85, or cannot be forecast
+	AtRisk bool `json:"at_risk"`
+	// MissingHistory are the components with remaining work but no throughput to forecast it
+	MissingHistory []string `json:"missing_history,omitempty"`
+}
+
+// Forecast forecasts the completion of a milestone from its epics and their issues
+func Forecast(milestone models.Milestone, epics []models.Epic, issues []models.Issue, history History, opts Options) Milestone {
+	forecast := Milestone{
+		ID:      milestone.ID,
+		Key:     milestone.Key,
+		Name:    milestone.Name,
+		Quarter: milestone.Quarter,
+	}
+	if deadline, ok := models.QuarterEnd(milestone.Quarter); ok {
+		forecast.Deadline = &deadline
+	}
+
+	if opts.Runs <= 0 {
+		opts.Runs = DefaultRuns
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+	}
+
+	remaining := Remaining(epics, issues)
+	for _, work := range remaining {
+		forecast.Remaining.Epics += work.Epics
+		forecast.Remaining.Issues += work.Issues
+	}
+
+	weeks, missing := Simulate(opts.Rand, history, remaining, opts.Runs)
+	if len(missing) > 0 {
+		forecast.MissingHistory = missing
+		forecast.AtRisk = true
+		return forecast
+	}
+	forecast.P50 = completionDate(opts.Now, weeks, 50)
+	forecast.P85 = completionDate(opts.Now, weeks, 85)
+	forecast.P95 = completionDate(opts.Now, weeks, 95)
+	forecast.AtRisk = forecast.Deadline != nil && forecast.P85.After(*forecast.Deadline)
+	return forecast
+}
+
+// completionDate returns the day by which the pth percentile of the runs completes
+func completionDate(now time.Time, weeks []int, p int) *time.Time {
+	n := 0
+	if len(weeks) > 0 {
+		index := (len(weeks)*p+99)/100 - 1
+		n = weeks[max(index, 0)]
+	}
+	date := now.UTC().Truncate(24 * time.Hour).Add(time.Duration(n) * week)
+	return &date
+}
+
+// componentsOf returns the components of an item, unknown when it has none
+func componentsOf(components []string) []string {
+	if len(components) == 0 {
+		return []string{unknown}
+	}
+	return components
+}
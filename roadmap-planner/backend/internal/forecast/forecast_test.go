@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forecast
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	metricsModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// monday is the start of the simulations, the history ends on it
+var monday = time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+func date(year int, month time.Month, day int) *time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func TestNewHistory(t *testing.T) {
+	resolved := func(component string, daysAgo int) metricsModels.EnrichedIssue {
+		issue := metricsModels.EnrichedIssue{ResolvedDate: monday.AddDate(0, 0, -daysAgo)}
+		if component != "" {
+			issue.Components = []string{component}
+		}
+		return issue
+	}
+	epics := []metricsModels.EnrichedIssue{
+		resolved("tekton", 1),
+		resolved("tekton", 2),
+		resolved("tekton", 20),
+		// before the history
+		resolved("tekton", 22),
+		{Components: []string{"tekton", "argo-cd"}, ResolvedDate: monday.AddDate(0, 0, -8)},
+		// not resolved
+		{Components: []string{"tekton"}},
+	}
+	issues := []metricsModels.EnrichedIssue{
+		resolved("tekton", 9),
+		resolved("", 3),
+		// resolved after the end of the history
+		resolved("tekton", -1),
+	}
+
+	want := History{
+		"tekton":  {{Epics: 1}, {Epics: 1, Issues: 1}, {Epics: 2}},
+		"argo-cd": {{}, {Epics: 1}, {}},
+		"unknown": {{}, {}, {Issues: 1}},
+	}
+	if diff := cmp.Diff(want, NewHistory(epics, issues, monday, 3)); diff != "" {
+		t.Errorf("NewHistory() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	tests := map[string]struct {
+		history     History
+		remaining   map[string]Work
+		wantWeeks   []int
+		wantMissing []string
+	}{
+		"constant throughput": {
+			history:   History{"tekton": {{Epics: 2, Issues: 5}, {Epics: 2, Issues: 5}}},
+			remaining: map[string]Work{"tekton": {Epics: 5, Issues: 6}},
+			wantWeeks: []int{3, 3, 3},
+		},
+		"the slowest component completes the run": {
+			history: History{
+				"tekton":  {{Epics: 1}},
+				"argo-cd": {{Epics: 4}},
+			},
+			remaining: map[string]Work{"tekton": {Epics: 2}, "argo-cd": {Epics: 4}},
+			wantWeeks: []int{2, 2, 2},
+		},
+		"no remaining work": {
+			history:   History{},
+			remaining: map[string]Work{"tekton": {}},
+			wantWeeks: []int{0, 0, 0},
+		},
+		"components without throughput": {
+			history: History{
+				"tekton":  {{Epics: 1}},
+				"argo-cd": {{}, {}},
+			},
+			remaining:   map[string]Work{"tekton": {Epics: 1, Issues: 1}, "argo-cd": {Epics: 1}, "unknown": {Issues: 1}},
+			wantMissing: []string{"argo-cd", "tekton", "unknown"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			weeks, missing := Simulate(rand.New(rand.NewPCG(1, 2)), tt.history, tt.remaining, 3)
+			if diff := cmp.Diff(tt.wantWeeks, weeks); diff != "" {
+				t.Errorf("Simulate() weeks mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantMissing, missing); diff != "" {
+				t.Errorf("Simulate() missing mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSimulate_Distribution(t *testing.T) {
+	// one epic every other week: 4 epics take from 4 to many weeks, 8 weeks on average
+	history := History{"tekton": {{Epics: 1}, {}}}
+	weeks, _ := Simulate(rand.New(rand.NewPCG(1, 2)), history, map[string]Work{"tekton": {Epics: 4}}, 2000)
+
+	sum := 0
+	for _, w := range weeks {
+		sum += w
+	}
+	if weeks[0] != 4 || weeks[len(weeks)-1] <= 8 {
+		t.Errorf("Simulate() weeks from %d to %d, want from 4 to more than 8", weeks[0], weeks[len(weeks)-1])
+	}
+	if mean := float64(sum) / float64(len(weeks)); mean < 7.5 || mean > 8.5 {
+		t.Errorf("Simulate() mean weeks = %v, want about 8", mean)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	history := History{"tekton": {{Epics: 1, Issues: 2}}}
+	milestone := models.Milestone{ID: "10", Key: "M-1", Name: "Pipelines", Quarter: "2025Q2"}
+	epic := func(resolved bool) models.Epic {
+		epic := models.Epic{Components: []string{"tekton"}}
+		if resolved {
+			epic.ResolutionDate = monday
+		}
+		return epic
+	}
+
+	tests := map[string]struct {
+		epics  []models.Epic
+		issues []models.Issue
+		want   Milestone
+	}{
+		"completes before the deadline": {
+			epics:  []models.Epic{epic(false), epic(false), epic(true)},
+			issues: []models.Issue{{Components: []string{"tekton"}}},
+			want: Milestone{
+				ID: "10", Key: "M-1", Name: "Pipelines", Quarter: "2025Q2", Deadline: date(2025, 6, 30),
+				Remaining: Work{Epics: 2, Issues: 1},
+				P50:       date(2025, 6, 16), P85: date(2025, 6, 16), P95: date(2025, 6, 16),
+			},
+		},
+		"completes after the deadline": {
+			epics: []models.Epic{epic(false), epic(false), epic(false), epic(false), epic(false)},
+			want: Milestone{
+				ID: "10", Key: "M-1", Name: "Pipelines", Quarter: "2025Q2", Deadline: date(2025, 6, 30),
+				Remaining: Work{Epics: 5},
+				P50:       date(2025, 7, 7), P85: date(2025, 7, 7), P95: date(2025, 7, 7),
+				AtRisk: true,
+			},
+		},
+		"no throughput": {
+			epics: []models.Epic{{Components: []string{"argo-cd"}}},
+			want: Milestone{
+				ID: "10", Key: "M-1", Name: "Pipelines", Quarter: "2025Q2", Deadline: date(2025, 6, 30),
+				Remaining:      Work{Epics: 1},
+				AtRisk:         true,
+				MissingHistory: []string{"argo-cd"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Forecast(milestone, tt.epics, tt.issues, history, Options{Now: monday, Runs: 10, Rand: rand.New(rand.NewPCG(1, 2))})
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Forecast() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,8 +18,10 @@ package jira
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
 	"strings"
 	"time"
@@ -31,18 +33,31 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	// epicFields are the fields requested when searching epics
+	epicFields = []string{"summary", "assignee", "priority", "components", "status", "parent", "fixVersions", "created", "issuelinks", "resolutiondate", "customfield_12242", "customfield_10020", "customfield_10021", "customfield_12801", "customfield_sequence", "customfield_rank"}
+	// issueFields are the fields requested when searching issues
+	issueFields = []string{"summary", "assignee", "priority", "components", "issuetype", "status", "parent", "fixVersions", "created", "issuelinks", "resolutiondate", "customfield_12242", "customfield_10020", "customfield_10021", "customfield_12801", "customfield_sequence", "customfield_rank"}
+)
+
+// ErrUserNotFound is returned when no Jira user matches a lookup
+var ErrUserNotFound = errors.New("jira user not found")
+
 // Client wraps the Jira client with roadmap-specific functionality
 type Client struct {
 	inner   *jira.Client
 	project string
 	logger  *zap.Logger
+	// actor is the user on whose behalf a shared service account client writes
+	actor string
 }
 
 // NewClient creates a new Jira client with the given credentials
 func NewClient(baseURL, username, password, project string) (*Client, error) {
 	tp := jira.BasicAuthTransport{
-		Username: username,
-		Password: password,
+		Username:  username,
+		Password:  password,
+		Transport: newRetryTransport(nil),
 	}
 
 	if _, err := url.ParseRequestURI(baseURL); err != nil {
@@ -64,6 +79,37 @@ func NewClient(baseURL, username, password, project string) (*Client, error) {
 	}, nil
 }
 
+// WithActor returns a copy of the client attributing its writes to actor
+// It is used when the client authenticates as a service account on behalf of an SSO user,
+// every created or updated issue gets a comment naming the actor
+func (c *Client) WithActor(actor string) *Client {
+	clone := *c
+	clone.actor = actor
+	clone.logger = c.logger.With(zap.String("actor", actor))
+	return &clone
+}
+
+// Actor returns the user the writes of the client are attributed to, empty if none
+func (c *Client) Actor() string {
+	return c.actor
+}
+
+// recordActor comments on an issue to attribute a change to the actor of the client
+// The change already happened, so failures are only logged
+func (c *Client) recordActor(ctx context.Context, issueID, action string) {
+	if c.actor == "" || issueID == "" {
+		return
+	}
+	comment := &jira.Comment{Body: fmt.Sprintf("%s %s via roadmap-planner", c.actor, action)}
+	_, resp, err := c.inner.Issue.AddCommentWithContext(ctx, issueID, comment)
+	if err != nil {
+		c.logger.Warn("Failed to record actor on issue",
+			zap.String("issue_id", issueID),
+			zap.String("action", action),
+			zap.String("error", c.handleError(resp, err)))
+	}
+}
+
 // TestConnection tests the connection to Jira
 func (c *Client) TestConnection(ctx context.Context) error {
 	_, resp, err := c.inner.User.GetSelfWithContext(ctx)
@@ -311,8 +357,11 @@ func (c *Client) CreateMilestone(ctx context.Context, req models.CreateMilestone
 		return nil, fmt.Errorf("failed to create milestone: %s", c.handleError(resp, err))
 	}
 	c.logger.Sugar().Infow("Milestone created", "issue", createdIssue)
+	c.recordActor(ctx, createdIssue.ID, "created this milestone")
 
 	milestone := models.ConvertJiraIssueToMilestone(issue, req.PillarID)
+	milestone.ID = createdIssue.ID
+	milestone.Key = createdIssue.Key
 	milestone.Quarter = req.Quarter
 
 	return milestone, nil
@@ -357,6 +406,7 @@ func (c *Client) CreateEpic(ctx context.Context, req models.CreateEpicRequest) (
 		return nil, fmt.Errorf("failed to create epic: %s", c.handleError(resp, err))
 	}
 	c.logger.Sugar().Infow("Created epic issue", "epic", createdIssue, "req", req)
+	c.recordActor(ctx, createdIssue.ID, "created this epic")
 
 	// Link the epic to the milestone using "blocks" relationship
 	if err := c.LinkEpicToMilestone(ctx, createdIssue.ID, req.MilestoneID); err != nil {
@@ -401,7 +451,11 @@ func (c *Client) UpdateEpicMilestone(ctx context.Context, epicID, newMilestoneID
 	}
 
 	// Create new link to the new milestone
-	return c.LinkEpicToMilestone(ctx, epicID, newMilestoneID)
+	if err := c.LinkEpicToMilestone(ctx, epicID, newMilestoneID); err != nil {
+		return err
+	}
+	c.recordActor(ctx, epicID, "moved this epic to milestone "+newMilestoneID)
+	return nil
 }
 
 // LinkEpicToMilestone creates a "blocks" link between an epic and a milestone
@@ -458,6 +512,7 @@ func (c *Client) UpdateMilestone(ctx context.Context, milestoneID string, req mo
 		zap.String("milestone_id", milestoneID),
 		zap.String("name", req.Name),
 		zap.String("quarter", req.Quarter))
+	c.recordActor(ctx, milestoneID, "updated this milestone")
 
 	return nil
 }
@@ -525,6 +580,7 @@ func (c *Client) UpdateEpic(ctx context.Context, epicID string, req models.Updat
 		zap.String("component", req.Component),
 		zap.String("version", req.Version),
 		zap.String("priority", req.Priority))
+	c.recordActor(ctx, epicID, "updated this epic")
 
 	return nil
 }
@@ -607,6 +663,39 @@ func (c *Client) GetAssignableUsers(ctx context.Context, query string, projectKe
 	return assignableUsers, nil
 }
 
+// FindUser returns the active Jira user whose username or email address is exactly value
+// The user search of Jira matches prefixes, so partial matches are discarded
+func (c *Client) FindUser(ctx context.Context, value string) (*models.User, error) {
+	req, err := c.inner.NewRequestWithContext(ctx, "GET", "rest/api/2/user/search", nil)
+	if err != nil {
+		return nil, err
+	}
+	urlQuery := req.URL.Query()
+	urlQuery.Add("username", value)
+	req.URL.RawQuery = urlQuery.Encode()
+
+	users := make([]jira.User, 0, 10)
+	resp, err := c.inner.Do(req, &users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %s", c.handleError(resp, err))
+	}
+
+	for _, user := range users {
+		if !user.Active {
+			continue
+		}
+		if strings.EqualFold(user.Name, value) || strings.EqualFold(user.EmailAddress, value) {
+			return &models.User{
+				AccountID:    user.Key,
+				Name:         user.Name,
+				DisplayName:  user.DisplayName,
+				EmailAddress: user.EmailAddress,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUserNotFound, value)
+}
+
 // GetQuartersFromMilestones extracts quarters from existing milestone data
 func (c *Client) GetQuartersFromMilestones(ctx context.Context) ([]string, error) {
 	// Get all pillars to extract quarters from their milestones
@@ -805,7 +894,7 @@ func (c *Client) GetEpicsWithFilter(ctx context.Context, milestoneIDs []string,
 	jqlQuery := strings.Join(jqlParts, " AND ") + " ORDER BY created ASC"
 
 	searchOptions := &jira.SearchOptions{
-		Fields:     []string{"summary", "assignee", "priority", "components", "status", "parent", "fixVersions", "created", "issuelinks", "resolutiondate", "customfield_12242", "customfield_10020", "customfield_10021", "customfield_12801", "customfield_sequence", "customfield_rank"},
+		Fields:     epicFields,
 		MaxResults: 2000,
 	}
 
@@ -845,6 +934,59 @@ func (c *Client) GetEpicsWithFilter(ctx context.Context, milestoneIDs []string,
 	return epics, nil
 }
 
+// GetEpicsByKeys fetches epics by key, whatever their project or milestone
+func (c *Client) GetEpicsByKeys(ctx context.Context, keys []string) ([]models.Epic, error) {
+	if len(keys) == 0 {
+		return []models.Epic{}, nil
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		quoted = append(quoted, fmt.Sprintf("%q", key))
+	}
+	jqlQuery := fmt.Sprintf("issuetype = Epic AND key in (%s) ORDER BY created ASC", strings.Join(quoted, ","))
+
+	issues, resp, err := c.inner.Issue.SearchWithContext(ctx, jqlQuery, &jira.SearchOptions{
+		Fields:     epicFields,
+		MaxResults: len(keys),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch epics by key: %s", c.handleError(resp, err))
+	}
+
+	epics := make([]models.Epic, 0, len(issues))
+	for _, issue := range issues {
+		epics = append(epics, *models.ConvertJiraIssueToEpic(&issue, ""))
+	}
+	return epics, nil
+}
+
+// GetMilestone fetches a milestone by ID or key
+func (c *Client) GetMilestone(ctx context.Context, milestoneID string) (*models.Milestone, error) {
+	issue, resp, err := c.inner.Issue.GetWithContext(ctx, milestoneID, &jira.GetQueryOptions{
+		Fields: "summary,status,parent,customfield_12242,customfield_10020,customfield_10021,customfield_12801,customfield_sequence,customfield_rank,created",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch milestone %s: %s", milestoneID, c.handleError(resp, err))
+	}
+
+	pillarID := ""
+	if issue.Fields.Parent != nil {
+		pillarID = issue.Fields.Parent.ID
+	}
+	return models.ConvertJiraIssueToMilestone(issue, pillarID), nil
+}
+
+// GetEpic fetches an epic by ID or key
+func (c *Client) GetEpic(ctx context.Context, epicID string) (*models.Epic, error) {
+	issue, resp, err := c.inner.Issue.GetWithContext(ctx, epicID, &jira.GetQueryOptions{
+		Fields: strings.Join(epicFields, ","),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch epic %s: %s", epicID, c.handleError(resp, err))
+	}
+	return models.ConvertJiraIssueToEpic(issue, ""), nil
+}
+
 // GetIssuesWithFilter fetches issues with optional filtering
 func (c *Client) GetIssuesWithFilter(ctx context.Context, epicIDs []string, components []string, versions []string, issueTypes []string) ([]models.Issue, error) {
 	// Build JQL query with filters
@@ -878,7 +1020,7 @@ func (c *Client) GetIssuesWithFilter(ctx context.Context, epicIDs []string, comp
 	jqlQuery := strings.Join(jqlParts, " AND ") + " ORDER BY created ASC"
 
 	searchOptions := &jira.SearchOptions{
-		Fields:     []string{"summary", "assignee", "priority", "components", "issuetype", "status", "parent", "fixVersions", "created", "issuelinks", "resolutiondate", "customfield_12242", "customfield_10020", "customfield_10021", "customfield_12801", "customfield_sequence", "customfield_rank"},
+		Fields:     issueFields,
 		MaxResults: 2000,
 	}
 
@@ -904,6 +1046,79 @@ func (c *Client) GetIssuesWithFilter(ctx context.Context, epicIDs []string, comp
 	return issues, nil
 }
 
+// GetEpicsUpdatedSince fetches all epics updated since the given time
+// Cancelled epics are included so callers can drop them from previously collected data
+func (c *Client) GetEpicsUpdatedSince(ctx context.Context, since time.Time) ([]models.Epic, error) {
+	jqlQuery := fmt.Sprintf("project = %s AND issuetype = Epic AND %s ORDER BY updated ASC", c.project, updatedSinceClause(since))
+
+	epics := []models.Epic{}
+	err := c.inner.Issue.SearchPagesWithContext(ctx, jqlQuery, &jira.SearchOptions{Fields: epicFields, MaxResults: 100}, func(issue jira.Issue) error {
+		epics = append(epics, *models.ConvertJiraIssueToEpic(&issue, ""))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch updated epics: %w", err)
+	}
+
+	c.logger.Info("Found updated epics", zap.Int("count", len(epics)), zap.Time("since", since))
+	return epics, nil
+}
+
+// GetMilestonePillars maps the ID of every milestone of the project, including resolved ones, to the ID of its pillar
+// Milestones without parent are left out
+func (c *Client) GetMilestonePillars(ctx context.Context) (map[string]string, error) {
+	jqlQuery := fmt.Sprintf("project = %s AND issuetype = Milestone ORDER BY created ASC", c.project)
+
+	pillars := map[string]string{}
+	err := c.inner.Issue.SearchPagesWithContext(ctx, jqlQuery, &jira.SearchOptions{Fields: []string{"parent"}, MaxResults: 100}, func(issue jira.Issue) error {
+		if issue.Fields != nil && issue.Fields.Parent != nil {
+			pillars[issue.ID] = issue.Fields.Parent.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch milestone pillars: %w", err)
+	}
+
+	c.logger.Debug("Found milestone pillars", zap.Int("count", len(pillars)))
+	return pillars, nil
+}
+
+// GetIssuesUpdatedSince fetches all issues of the given types updated since the given time
+// Cancelled issues are included so callers can drop them from previously collected data
+func (c *Client) GetIssuesUpdatedSince(ctx context.Context, since time.Time, issueTypes []string) ([]models.Issue, error) {
+	var jqlParts []string
+	jqlParts = append(jqlParts, fmt.Sprintf("project = %s", c.project))
+	jqlParts = append(jqlParts, "created > startOfDay(-366)")
+	jqlParts = append(jqlParts, updatedSinceClause(since))
+	if len(issueTypes) > 0 {
+		jqlParts = append(jqlParts, fmt.Sprintf(`issuetype in (%s)`, strings.Join(issueTypes, ",")))
+	}
+	jqlQuery := strings.Join(jqlParts, " AND ") + " ORDER BY updated ASC"
+
+	issues := []models.Issue{}
+	err := c.inner.Issue.SearchPagesWithContext(ctx, jqlQuery, &jira.SearchOptions{Fields: issueFields, MaxResults: 100}, func(issue jira.Issue) error {
+		issues = append(issues, *models.ConvertJiraIssueToIssue(&issue))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch updated issues: %w", err)
+	}
+
+	c.logger.Info("Found updated issues", zap.Int("count", len(issues)), zap.Time("since", since), zap.Strings("issueTypes", issueTypes))
+	return issues, nil
+}
+
+// updatedSinceClause returns a JQL clause matching issues updated since the given time
+// A relative duration is used because absolute JQL dates are interpreted in the Jira user's timezone
+func updatedSinceClause(since time.Time) string {
+	minutes := int(math.Ceil(time.Since(since).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("updated >= -%dm", minutes)
+}
+
 // removeIssueLink removes an issue link
 func (c *Client) removeIssueLink(ctx context.Context, linkID string) error {
 	resp, err := c.inner.Issue.DeleteLinkWithContext(ctx, linkID)
@@ -17,7 +17,10 @@ limitations under the License.
 package jira
 
 import (
-	// "context"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
@@ -170,3 +173,58 @@ func TestClient_Methods(t *testing.T) {
 		}
 	*/
 }
+
+func TestClient_WithActor(t *testing.T) {
+	tests := map[string]struct {
+		actor        string
+		wantComments []string
+	}{
+		"service account acting for a user comments the change": {
+			actor:        "alice",
+			wantComments: []string{"alice updated this milestone via roadmap-planner"},
+		},
+		"user clients do not comment": {},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			comments := []string{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/10001":
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/10001/comment":
+					var comment struct {
+						Body string `json:"body"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+						t.Errorf("decode comment error = %v", err)
+					}
+					comments = append(comments, comment.Body)
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id":"1"}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL, "robot", "token", "TEST")
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			if tt.actor != "" {
+				client = client.WithActor(tt.actor)
+			}
+
+			err = client.UpdateMilestone(context.Background(), "10001", models.UpdateMilestoneRequest{Name: "Renamed", Quarter: "2025Q3"})
+			if err != nil {
+				t.Fatalf("UpdateMilestone() error = %v", err)
+			}
+			if len(comments) != len(tt.wantComments) || (len(comments) > 0 && comments[0] != tt.wantComments[0]) {
+				t.Errorf("comments = %v, want %v", comments, tt.wantComments)
+			}
+		})
+	}
+}
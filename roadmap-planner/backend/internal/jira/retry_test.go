@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := map[string]struct {
+		rateLimited int
+		retryAfter  string
+		maxRetries  int
+		wantStatus  int
+		wantCalls   int32
+	}{
+		"no rate limiting": {
+			wantStatus: http.StatusOK,
+			maxRetries: 3,
+			wantCalls:  1,
+		},
+		"retries until the request succeeds": {
+			rateLimited: 2,
+			maxRetries:  3,
+			wantStatus:  http.StatusOK,
+			wantCalls:   3,
+		},
+		"honours retry after": {
+			rateLimited: 1,
+			retryAfter:  "0",
+			maxRetries:  3,
+			wantStatus:  http.StatusOK,
+			wantCalls:   2,
+		},
+		"gives up after max retries": {
+			rateLimited: 5,
+			maxRetries:  2,
+			wantStatus:  http.StatusTooManyRequests,
+			wantCalls:   3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body := make([]byte, 4)
+				n, _ := r.Body.Read(body)
+				if string(body[:n]) != "body" {
+					t.Errorf("request body = %q, want %q", body[:n], "body")
+				}
+				if int(calls.Add(1)) <= tt.rateLimited {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			transport := newRetryTransport(nil)
+			transport.maxRetries = tt.maxRetries
+			transport.baseDelay = time.Millisecond
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("body"))
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("RoundTrip() calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := newRetryTransport(nil).RoundTrip(req); err == nil {
+		t.Errorf("RoundTrip() expected error when the context is cancelled")
+	}
+}
+
+func TestRetryTransport_Delay(t *testing.T) {
+	transport := &retryTransport{baseDelay: time.Second}
+	tests := map[string]struct {
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		"exponential backoff":      {attempt: 2, want: 4 * time.Second},
+		"backoff is capped":        {attempt: 10, want: maxRetryDelay},
+		"retry after seconds":      {retryAfter: "7", attempt: 3, want: 7 * time.Second},
+		"retry after is capped":    {retryAfter: "3600", want: maxRetryDelay},
+		"invalid retry after":      {retryAfter: "soon", attempt: 1, want: 2 * time.Second},
+		"retry after date in past": {retryAfter: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := transport.delay(resp, tt.attempt); got != tt.want {
+				t.Errorf("delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdatedSinceClause(t *testing.T) {
+	tests := map[string]struct {
+		since time.Time
+		want  string
+	}{
+		"rounds up to the next minute": {since: time.Now().Add(-90 * time.Second), want: "updated >= -2m"},
+		"future time":                  {since: time.Now().Add(time.Hour), want: "updated >= -1m"},
+		"days ago":                     {since: time.Now().Add(-48*time.Hour + time.Second), want: "updated >= -2880m"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := updatedSinceClause(tt.since); got != tt.want {
+				t.Errorf("updatedSinceClause() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
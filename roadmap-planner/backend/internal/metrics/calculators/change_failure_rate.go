@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+// ChangeFailureRateCalculator calculates the ratio of releases followed by a hotfix or by bugs
+type ChangeFailureRateCalculator struct {
+	BaseCalculator
+}
+
+// NewChangeFailureRateCalculator creates a new change failure rate calculator
+func NewChangeFailureRateCalculator(options map[string]interface{}) *ChangeFailureRateCalculator {
+	return &ChangeFailureRateCalculator{
+		BaseCalculator: NewBaseCalculator(
+			"change_failure_rate",
+			"Ratio of releases followed by a hotfix release or by bugs",
+			"ratio",
+			[]string{"component"},
+			options,
+		),
+	}
+}
+
+// Calculate computes the change failure rate metric
+// A release failed when a patch of the same minor version is released within the hotfix window,
+// or when a bug of its component is reported within the window before the next release
+func (c *ChangeFailureRateCalculator) Calculate(ctx context.Context, data *models.CalculationContext) ([]models.MetricResult, error) {
+	windowDays := c.GetIntOption("hotfix_window_days", 14)
+	failureTypes := c.GetStringSliceOption("failure_issue_types", []string{"Bug"})
+	window := time.Duration(windowDays) * 24 * time.Hour
+
+	// Group released versions by component, hotfixes may be released after the time range
+	componentReleases := make(map[string][]models.EnrichedRelease)
+	for _, release := range data.Releases {
+		if !release.Released || release.ReleaseDate.IsZero() {
+			continue
+		}
+		component := release.Component
+		if component == "" {
+			component = unknown
+		}
+		if len(data.Filters.Components) > 0 && !Contains(data.Filters.Components, component) {
+			continue
+		}
+		componentReleases[component] = append(componentReleases[component], release)
+	}
+
+	// Bugs are attributed to the last release of their component before they were reported
+	failures := make(map[string]map[string]bool)
+	for _, issue := range data.Issues {
+		if !Contains(failureTypes, issue.IssueType) || issue.CreatedDate.IsZero() {
+			continue
+		}
+		components := issue.Components
+		if len(components) == 0 {
+			components = []string{unknown}
+		}
+		for _, component := range components {
+			var cause *models.EnrichedRelease
+			for i, release := range componentReleases[component] {
+				if release.ReleaseDate.Before(issue.CreatedDate) && (cause == nil || release.ReleaseDate.After(cause.ReleaseDate)) {
+					cause = &componentReleases[component][i]
+				}
+			}
+			if cause != nil && issue.CreatedDate.Sub(cause.ReleaseDate) <= window {
+				if failures[component] == nil {
+					failures[component] = make(map[string]bool)
+				}
+				failures[component][cause.Name] = true
+			}
+		}
+	}
+
+	components := make([]string, 0, len(componentReleases))
+	for component := range componentReleases {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	results := make([]models.MetricResult, 0, len(components))
+	for _, component := range components {
+		total, failed, hotfixed, withBugs := 0, 0, 0, 0
+		for _, release := range componentReleases[component] {
+			if !inRange(release.ReleaseDate, data.TimeRange) {
+				continue
+			}
+			total++
+			hotfix := hasHotfix(release, componentReleases[component], window)
+			if hotfix {
+				hotfixed++
+			}
+			if failures[component][release.Name] {
+				withBugs++
+			}
+			if hotfix || failures[component][release.Name] {
+				failed++
+			}
+		}
+		if total == 0 {
+			continue
+		}
+
+		results = append(results, models.MetricResult{
+			Name:  c.Name(),
+			Value: float64(failed) / float64(total),
+			Unit:  c.Unit(),
+			Labels: map[string]string{
+				"component": component,
+			},
+			Timestamp: time.Now(),
+			Metadata: map[string]interface{}{
+				"total_releases":     total,
+				"failed_releases":    failed,
+				"hotfixed_releases":  hotfixed,
+				"releases_with_bugs": withBugs,
+				"hotfix_window_days": windowDays,
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// hasHotfix checks if a later patch of the same minor version is released within the window
+func hasHotfix(release models.EnrichedRelease, releases []models.EnrichedRelease, window time.Duration) bool {
+	for _, other := range releases {
+		if other.Type != "patch" || other.Major != release.Major || other.Minor != release.Minor || other.Patch <= release.Patch {
+			continue
+		}
+		if other.ReleaseDate.After(release.ReleaseDate) && other.ReleaseDate.Sub(release.ReleaseDate) <= window {
+			return true
+		}
+	}
+	return false
+}
+
+// PrometheusMetrics returns the Prometheus metric descriptors
+func (c *ChangeFailureRateCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
+	return []models.PrometheusMetricDesc{
+		{
+			Name:       "change_failure_rate",
+			Help:       "Ratio of releases followed by a hotfix release or by bugs",
+			Type:       "gauge",
+			LabelNames: []string{"component"},
+		},
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChangeFailureRateCalculator_Calculate(t *testing.T) {
+	release := func(name string, n int, minor, patch int) models.EnrichedRelease {
+		releaseType := "minor"
+		if patch > 0 {
+			releaseType = "patch"
+		}
+		return models.EnrichedRelease{Name: name, Released: true, ReleaseDate: day(n), Component: "tekton",
+			Type: releaseType, Major: 1, Minor: minor, Patch: patch}
+	}
+
+	tests := map[string]struct {
+		options map[string]interface{}
+		data    models.CalculationContext
+		want    []models.MetricResult
+	}{
+		"hotfix releases": {
+			data: models.CalculationContext{
+				Releases: []models.EnrichedRelease{
+					release("tekton-1.1.0", 2, 1, 0),
+					// hotfix of 1.1.0, the hotfix itself did not fail
+					release("tekton-1.1.1", 10, 1, 1),
+					// patch of 1.2.0 released after the window
+					release("tekton-1.2.0", 11, 2, 0),
+					release("tekton-1.2.1", 28, 2, 1),
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "change_failure_rate", Value: 0.25, Unit: "ratio", Labels: map[string]string{"component": "tekton"},
+				Metadata: map[string]interface{}{"total_releases": 4, "failed_releases": 1, "hotfixed_releases": 1,
+					"releases_with_bugs": 0, "hotfix_window_days": 14},
+			}},
+		},
+		"bugs are attributed to the last release": {
+			options: map[string]interface{}{"hotfix_window_days": 7, "failure_issue_types": []string{"Bug", "Regression"}},
+			data: models.CalculationContext{
+				Releases: []models.EnrichedRelease{
+					release("tekton-1.1.0", 2, 1, 0),
+					release("tekton-1.2.0", 11, 2, 0),
+					release("tekton-1.3.0", 20, 3, 0),
+				},
+				Issues: []models.EnrichedIssue{
+					{Key: "B-1", IssueType: "Regression", Components: []string{"tekton"}, CreatedDate: day(12)},
+					// reported after the window of tekton-1.3.0
+					{Key: "B-2", IssueType: "Bug", Components: []string{"tekton"}, CreatedDate: day(29)},
+					{Key: "B-3", IssueType: "Story", Components: []string{"tekton"}, CreatedDate: day(3)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "change_failure_rate", Value: 1.0 / 3, Unit: "ratio", Labels: map[string]string{"component": "tekton"},
+				Metadata: map[string]interface{}{"total_releases": 3, "failed_releases": 1, "hotfixed_releases": 0,
+					"releases_with_bugs": 1, "hotfix_window_days": 7},
+			}},
+		},
+		"releases out of range or unreleased": {
+			data: models.CalculationContext{
+				Releases: []models.EnrichedRelease{
+					{Name: "tekton-1.0.0", Released: true, ReleaseDate: day(1).AddDate(0, -1, 0), Component: "tekton"},
+					{Name: "tekton-1.1.0", Component: "tekton"},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewChangeFailureRateCalculator(tt.options).Calculate(context.Background(), &tt.data)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpResults); diff != "" {
+				t.Errorf("Calculate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
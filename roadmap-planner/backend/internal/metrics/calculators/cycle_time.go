@@ -180,7 +180,7 @@ func (c *CycleTimeCalculator) PrometheusMetrics() []models.PrometheusMetricDesc
 			Name:       "cycle_time_days",
 			Help:       "Cycle time from epic in-progress to done in days",
 			Type:       "gauge",
-			LabelNames: []string{"component", "pillar"},
+			LabelNames: []string{"component"},
 		},
 	}
 }
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+// FlowEfficiencyCalculator calculates the share of the cycle time spent in active statuses
+type FlowEfficiencyCalculator struct {
+	BaseCalculator
+}
+
+// NewFlowEfficiencyCalculator creates a new flow efficiency calculator
+func NewFlowEfficiencyCalculator(options map[string]interface{}) *FlowEfficiencyCalculator {
+	return &FlowEfficiencyCalculator{
+		BaseCalculator: NewBaseCalculator(
+			"flow_efficiency",
+			"Share of the cycle time of epics spent in active statuses",
+			"ratio",
+			[]string{"component", "pillar"},
+			options,
+		),
+	}
+}
+
+// Calculate computes the flow efficiency metric, the average over the epics resolved in the time range
+func (c *FlowEfficiencyCalculator) Calculate(ctx context.Context, data *models.CalculationContext) ([]models.MetricResult, error) {
+	activeStatuses := c.GetStringSliceOption("active_statuses", []string{"In Progress", "In Development"})
+	doneStatuses := c.GetStringSliceOption("done_statuses", []string{"Done", "Closed", "Released", "已完成"})
+
+	efficiencies := samples{}
+	for _, epic := range data.Epics {
+		if epic.ResolvedDate.IsZero() || !inRange(epic.ResolvedDate, data.TimeRange) {
+			continue
+		}
+		if efficiency, ok := flowEfficiency(epic, activeStatuses, doneStatuses); ok {
+			efficiencies.add(epic, data.Filters, efficiency)
+		}
+	}
+
+	return efficiencies.results(c.Name(), c.Unit(), mean, sampleStats), nil
+}
+
+// flowEfficiency returns the share of the time between the first active status and the last done status
+// spent in active statuses, false when the status changes do not cover both
+func flowEfficiency(epic models.EnrichedIssue, activeStatuses, doneStatuses []string) (float64, bool) {
+	changes := sortedStatusChanges(epic)
+
+	var start, end time.Time
+	for _, change := range changes {
+		if start.IsZero() && Contains(activeStatuses, change.ToStatus) {
+			start = change.ChangedAt
+		}
+		if Contains(doneStatuses, change.ToStatus) {
+			end = change.ChangedAt
+		}
+	}
+	if start.IsZero() || !end.After(start) {
+		return 0, false
+	}
+
+	var active time.Duration
+	for i, change := range changes {
+		if !Contains(activeStatuses, change.ToStatus) {
+			continue
+		}
+		from, to := change.ChangedAt, end
+		if i+1 < len(changes) && changes[i+1].ChangedAt.Before(end) {
+			to = changes[i+1].ChangedAt
+		}
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(from) {
+			active += to.Sub(from)
+		}
+	}
+
+	return active.Hours() / end.Sub(start).Hours(), true
+}
+
+// PrometheusMetrics returns the Prometheus metric descriptors
+func (c *FlowEfficiencyCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
+	return []models.PrometheusMetricDesc{
+		{
+			Name:       "flow_efficiency",
+			Help:       "Share of the cycle time of epics spent in active statuses",
+			Type:       "gauge",
+			LabelNames: []string{"component"},
+		},
+	}
+}
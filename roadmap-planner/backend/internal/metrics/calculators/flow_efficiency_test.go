@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFlowEfficiencyCalculator_Calculate(t *testing.T) {
+	tests := map[string]struct {
+		options map[string]interface{}
+		data    models.CalculationContext
+		want    []models.MetricResult
+	}{
+		"active time between start and done": {
+			data: models.CalculationContext{
+				Epics: []models.EnrichedIssue{
+					// active 2 days, blocked 4 days, active 2 days
+					{Key: "E-1", Components: []string{"tekton"}, PillarID: "10", ResolvedDate: day(11), StatusChanges: []baseModels.StatusChange{
+						moved(1, "Backlog", "To Do"),
+						moved(3, "To Do", "In Progress"),
+						moved(5, "In Progress", "Blocked"),
+						moved(9, "Blocked", "In Development"),
+						moved(11, "In Development", "Done"),
+					}},
+					// always active
+					{Key: "E-2", Components: []string{"tekton"}, PillarID: "20", ResolvedDate: day(6), StatusChanges: []baseModels.StatusChange{
+						moved(6, "In Progress", "Done"),
+						moved(2, "To Do", "In Progress"),
+					}},
+					// no status changes
+					{Key: "E-3", Components: []string{"tekton"}, ResolvedDate: day(6)},
+					// not resolved
+					{Key: "E-4", Components: []string{"tekton"}, StatusChanges: []baseModels.StatusChange{
+						moved(2, "To Do", "In Progress"),
+					}},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "flow_efficiency", Value: 0.75, Unit: "ratio", Labels: map[string]string{"component": "tekton"},
+				Breakdown: []models.MetricBreakdown{
+					{Dimension: "pillar", Key: "10", Value: 0.5},
+					{Dimension: "pillar", Key: "20", Value: 1},
+				},
+				Metadata: map[string]interface{}{"sample_size": 2, "min": 0.5, "max": 1.0, "average": 0.75},
+			}},
+		},
+		"configured statuses": {
+			options: map[string]interface{}{"active_statuses": []string{"Doing", "Review"}, "done_statuses": []string{"Shipped"}},
+			data: models.CalculationContext{
+				Epics: []models.EnrichedIssue{
+					{Key: "E-1", ResolvedDate: day(12), StatusChanges: []baseModels.StatusChange{
+						moved(2, "To Do", "Doing"),
+						moved(4, "Doing", "Review"),
+						moved(6, "Review", "Waiting"),
+						moved(12, "Waiting", "Shipped"),
+					}},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "flow_efficiency", Value: 0.4, Unit: "ratio", Labels: map[string]string{"component": "unknown"},
+				Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "unknown", Value: 0.4}},
+				Metadata:  map[string]interface{}{"sample_size": 1, "min": 0.4, "max": 0.4, "average": 0.4},
+			}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewFlowEfficiencyCalculator(tt.options).Calculate(context.Background(), &tt.data)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpResults); diff != "" {
+				t.Errorf("Calculate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
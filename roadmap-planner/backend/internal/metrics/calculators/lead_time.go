@@ -131,7 +131,7 @@ func (c *LeadTimeCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
 			Name:       "lead_time_days",
 			Help:       "Lead time from epic creation to release in days",
 			Type:       "gauge",
-			LabelNames: []string{"component", "pillar"},
+			LabelNames: []string{"component"},
 		},
 	}
 }
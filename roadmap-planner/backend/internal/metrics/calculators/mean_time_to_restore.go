@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+// MeanTimeToRestoreCalculator calculates the time from incident report to restoration
+type MeanTimeToRestoreCalculator struct {
+	BaseCalculator
+}
+
+// NewMeanTimeToRestoreCalculator creates a new mean time to restore calculator
+func NewMeanTimeToRestoreCalculator(options map[string]interface{}) *MeanTimeToRestoreCalculator {
+	return &MeanTimeToRestoreCalculator{
+		BaseCalculator: NewBaseCalculator(
+			"mean_time_to_restore",
+			"Mean time from incident report to restoration",
+			"hours",
+			[]string{"component", "pillar"},
+			options,
+		),
+	}
+}
+
+// Calculate computes the mean time to restore metric over the incidents restored in the time range
+func (c *MeanTimeToRestoreCalculator) Calculate(ctx context.Context, data *models.CalculationContext) ([]models.MetricResult, error) {
+	incidentTypes := c.GetStringSliceOption("incident_types", []string{"Incident"})
+	restoredStatuses := c.GetStringSliceOption("restored_statuses", []string{"Resolved", "Done", "Closed"})
+
+	restoreTimes := samples{}
+	for _, issue := range data.Issues {
+		if !Contains(incidentTypes, issue.IssueType) || issue.CreatedDate.IsZero() {
+			continue
+		}
+		restoredAt := issue.ResolvedDate
+		// The first transition to a restored status, the incident may be closed later
+		for _, change := range sortedStatusChanges(issue) {
+			if Contains(restoredStatuses, change.ToStatus) && !change.ChangedAt.Before(issue.CreatedDate) {
+				restoredAt = change.ChangedAt
+				break
+			}
+		}
+		if restoredAt.IsZero() || !inRange(restoredAt, data.TimeRange) {
+			continue
+		}
+		restoreTimes.add(issue, data.Filters, restoredAt.Sub(issue.CreatedDate).Hours())
+	}
+
+	return restoreTimes.results(c.Name(), c.Unit(), mean, sampleStats), nil
+}
+
+// PrometheusMetrics returns the Prometheus metric descriptors
+func (c *MeanTimeToRestoreCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
+	return []models.PrometheusMetricDesc{
+		{
+			Name:       "mean_time_to_restore_hours",
+			Help:       "Mean time from incident report to restoration in hours",
+			Type:       "gauge",
+			LabelNames: []string{"component"},
+		},
+	}
+}
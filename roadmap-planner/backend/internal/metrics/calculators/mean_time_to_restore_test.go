@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMeanTimeToRestoreCalculator_Calculate(t *testing.T) {
+	tests := map[string]struct {
+		options map[string]interface{}
+		data    models.CalculationContext
+		want    []models.MetricResult
+	}{
+		"restored by status or resolution": {
+			data: models.CalculationContext{
+				Issues: []models.EnrichedIssue{
+					// restored on the first transition to a restored status
+					{Key: "I-1", IssueType: "Incident", Components: []string{"tekton"}, PillarID: "10",
+						CreatedDate: day(2), ResolvedDate: day(9), StatusChanges: []baseModels.StatusChange{
+							moved(5, "Investigating", "Closed"),
+							moved(3, "Open", "Resolved"),
+						}},
+					// no status changes, restored when resolved
+					{Key: "I-2", IssueType: "Incident", Components: []string{"tekton"}, PillarID: "20",
+						CreatedDate: day(4), ResolvedDate: day(7)},
+					{Key: "B-1", IssueType: "Bug", Components: []string{"tekton"}, CreatedDate: day(4), ResolvedDate: day(5)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "mean_time_to_restore", Value: 48, Unit: "hours", Labels: map[string]string{"component": "tekton"},
+				Breakdown: []models.MetricBreakdown{
+					{Dimension: "pillar", Key: "10", Value: 24},
+					{Dimension: "pillar", Key: "20", Value: 72},
+				},
+				Metadata: map[string]interface{}{"sample_size": 2, "min": 24.0, "max": 72.0, "average": 48.0},
+			}},
+		},
+		"configured incident types": {
+			options: map[string]interface{}{"incident_types": []interface{}{"Outage"}, "restored_statuses": []string{"Mitigated"}},
+			data: models.CalculationContext{
+				Issues: []models.EnrichedIssue{
+					{Key: "O-1", IssueType: "Outage", CreatedDate: day(2), StatusChanges: []baseModels.StatusChange{
+						moved(3, "Open", "Mitigated"),
+					}},
+					{Key: "I-1", IssueType: "Incident", CreatedDate: day(2), ResolvedDate: day(3)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "mean_time_to_restore", Value: 24, Unit: "hours", Labels: map[string]string{"component": "unknown"},
+				Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "unknown", Value: 24}},
+				Metadata:  map[string]interface{}{"sample_size": 1, "min": 24.0, "max": 24.0, "average": 24.0},
+			}},
+		},
+		"unrestored or out of range": {
+			data: models.CalculationContext{
+				Issues: []models.EnrichedIssue{
+					{Key: "I-1", IssueType: "Incident", CreatedDate: day(2)},
+					{Key: "I-2", IssueType: "Incident", CreatedDate: day(1).AddDate(0, -1, 0), ResolvedDate: day(1).AddDate(0, 0, -2)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewMeanTimeToRestoreCalculator(tt.options).Calculate(context.Background(), &tt.data)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpResults); diff != "" {
+				t.Errorf("Calculate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -130,7 +130,7 @@ func (c *PatchRatioCalculator) PrometheusMetrics() []models.PrometheusMetricDesc
 			Name:       "patch_ratio",
 			Help:       "Ratio of patch releases to total releases",
 			Type:       "gauge",
-			LabelNames: []string{"component", "pillar"},
+			LabelNames: []string{"component"},
 		},
 	}
 }
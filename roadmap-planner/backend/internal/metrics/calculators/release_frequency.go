@@ -130,7 +130,7 @@ func (c *ReleaseFrequencyCalculator) PrometheusMetrics() []models.PrometheusMetr
 			Name:       "release_frequency",
 			Help:       "Number of releases per month",
 			Type:       "gauge",
-			LabelNames: []string{"component", "pillar", "release_type"},
+			LabelNames: []string{"component"},
 		},
 	}
 }
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+)
+
+// unknown is the label of the issues without component or pillar
+const unknown = "unknown"
+
+// samples groups the values of a metric by component, then by pillar
+type samples map[string]map[string][]float64
+
+// add records the value of an issue for each of its components matching the filters
+// Issues without component or pillar are recorded as "unknown"
+func (s samples) add(issue models.EnrichedIssue, filters models.MetricFilters, value float64) {
+	pillar := issue.PillarID
+	if pillar == "" {
+		pillar = unknown
+	}
+	if len(filters.Pillars) > 0 && !Contains(filters.Pillars, pillar) {
+		return
+	}
+
+	components := issue.Components
+	if len(components) == 0 {
+		components = []string{unknown}
+	}
+	for _, component := range components {
+		if len(filters.Components) > 0 && !Contains(filters.Components, component) {
+			continue
+		}
+		if s[component] == nil {
+			s[component] = map[string][]float64{}
+		}
+		s[component][pillar] = append(s[component][pillar], value)
+	}
+}
+
+// results returns a result per component, sorted by component, with the aggregate of its values
+// and a breakdown by pillar; metadata can be nil
+func (s samples) results(name, unit string, aggregate func([]float64) float64, metadata func([]float64) map[string]interface{}) []models.MetricResult {
+	components := make([]string, 0, len(s))
+	for component := range s {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	results := make([]models.MetricResult, 0, len(components))
+	for _, component := range components {
+		pillars := make([]string, 0, len(s[component]))
+		var values []float64
+		for pillar, pillarValues := range s[component] {
+			pillars = append(pillars, pillar)
+			values = append(values, pillarValues...)
+		}
+		sort.Strings(pillars)
+
+		breakdown := make([]models.MetricBreakdown, 0, len(pillars))
+		for _, pillar := range pillars {
+			breakdown = append(breakdown, models.MetricBreakdown{
+				Dimension: "pillar",
+				Key:       pillar,
+				Value:     aggregate(s[component][pillar]),
+			})
+		}
+
+		result := models.MetricResult{
+			Name:  name,
+			Value: aggregate(values),
+			Unit:  unit,
+			Labels: map[string]string{
+				"component": component,
+			},
+			Timestamp: time.Now(),
+			Breakdown: breakdown,
+		}
+		if metadata != nil {
+			result.Metadata = metadata(values)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// percentileOf returns the pth percentile of the values, as computed by the other calculators
+func percentileOf(values []float64, p int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	idx := int(float64(len(sorted)-1) * float64(p) / 100)
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// mean returns the average of the values
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// sampleStats returns the metadata describing the distribution of the values
+func sampleStats(values []float64) map[string]interface{} {
+	return map[string]interface{}{
+		"sample_size": len(values),
+		"min":         percentileOf(values, 0),
+		"max":         percentileOf(values, 100),
+		"average":     mean(values),
+	}
+}
+
+// inRange checks if a date is within the time range, bounds included
+func inRange(date time.Time, timeRange models.TimeRange) bool {
+	return !date.Before(timeRange.Start) && !date.After(timeRange.End)
+}
+
+// sortedStatusChanges returns the status changes of an issue in chronological order
+func sortedStatusChanges(issue models.EnrichedIssue) []baseModels.StatusChange {
+	changes := append([]baseModels.StatusChange(nil), issue.StatusChanges...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+	return changes
+}
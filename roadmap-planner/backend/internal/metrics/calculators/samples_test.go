@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// cmpResults ignores the calculation time and rounding errors of the results
+var cmpResults = cmp.Options{
+	cmpopts.IgnoreFields(models.MetricResult{}, "Timestamp"),
+	cmpopts.EquateApprox(0, 1e-9),
+}
+
+// day returns the date of a day of June 2025
+func day(n int) time.Time {
+	return time.Date(2025, 6, n, 0, 0, 0, 0, time.UTC)
+}
+
+// june is the time range of the calculation contexts
+var june = models.TimeRange{Start: day(1), End: day(30)}
+
+// moved returns the status change of an issue at a day of June 2025
+func moved(n int, from, to string) baseModels.StatusChange {
+	return baseModels.StatusChange{FromStatus: from, ToStatus: to, ChangedAt: day(n)}
+}
+
+func TestSamples_Results(t *testing.T) {
+	tests := map[string]struct {
+		issues  []models.EnrichedIssue
+		filters models.MetricFilters
+		want    []models.MetricResult
+	}{
+		"groups by component with a breakdown by pillar": {
+			issues: []models.EnrichedIssue{
+				{Key: "A-1", Components: []string{"tekton", "argo-cd"}, PillarID: "10"},
+				{Key: "A-2", Components: []string{"tekton"}, PillarID: "20"},
+				{Key: "A-3"},
+			},
+			want: []models.MetricResult{
+				{Name: "test", Value: 1, Unit: "items", Labels: map[string]string{"component": "argo-cd"},
+					Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "10", Value: 1}}},
+				{Name: "test", Value: 2, Unit: "items", Labels: map[string]string{"component": "tekton"},
+					Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "10", Value: 1}, {Dimension: "pillar", Key: "20", Value: 1}}},
+				{Name: "test", Value: 1, Unit: "items", Labels: map[string]string{"component": "unknown"},
+					Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "unknown", Value: 1}}},
+			},
+		},
+		"applies the component and pillar filters": {
+			issues: []models.EnrichedIssue{
+				{Key: "A-1", Components: []string{"tekton", "argo-cd"}, PillarID: "10"},
+				{Key: "A-2", Components: []string{"tekton"}, PillarID: "20"},
+			},
+			filters: models.MetricFilters{Components: []string{"tekton"}, Pillars: []string{"10"}},
+			want: []models.MetricResult{
+				{Name: "test", Value: 1, Unit: "items", Labels: map[string]string{"component": "tekton"},
+					Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "10", Value: 1}}},
+			},
+		},
+		"no issues": {
+			want: []models.MetricResult{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := samples{}
+			for _, issue := range tt.issues {
+				s.add(issue, tt.filters, 1)
+			}
+			got := s.results("test", "items", func(values []float64) float64 { return float64(len(values)) }, nil)
+			if diff := cmp.Diff(tt.want, got, cmpResults); diff != "" {
+				t.Errorf("results() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPercentileOf(t *testing.T) {
+	tests := map[string]struct {
+		values     []float64
+		percentile int
+		want       float64
+	}{
+		"median":       {values: []float64{5, 1, 3}, percentile: 50, want: 3},
+		"lower median": {values: []float64{4, 1, 3, 2}, percentile: 50, want: 2},
+		"maximum":      {values: []float64{4, 1, 3, 2}, percentile: 100, want: 4},
+		"empty":        {percentile: 50, want: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := percentileOf(tt.values, tt.percentile); got != tt.want {
+				t.Errorf("percentileOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
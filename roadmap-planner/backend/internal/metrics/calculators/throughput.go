@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+// ThroughputCalculator calculates the number of epics completed per week
+type ThroughputCalculator struct {
+	BaseCalculator
+}
+
+// NewThroughputCalculator creates a new throughput calculator
+func NewThroughputCalculator(options map[string]interface{}) *ThroughputCalculator {
+	return &ThroughputCalculator{
+		BaseCalculator: NewBaseCalculator(
+			"throughput",
+			"Number of epics completed per week",
+			"epics/week",
+			[]string{"component", "pillar"},
+			options,
+		),
+	}
+}
+
+// Calculate computes the throughput metric over the window ending with the time range
+func (c *ThroughputCalculator) Calculate(ctx context.Context, data *models.CalculationContext) ([]models.MetricResult, error) {
+	windowWeeks := c.GetIntOption("window_weeks", 4)
+	if windowWeeks <= 0 {
+		windowWeeks = 4
+	}
+	windowStart := data.TimeRange.End.AddDate(0, 0, -7*windowWeeks)
+
+	completed := samples{}
+	for _, epic := range data.Epics {
+		if epic.ResolvedDate.IsZero() {
+			continue
+		}
+		if !epic.ResolvedDate.After(windowStart) || epic.ResolvedDate.After(data.TimeRange.End) {
+			continue
+		}
+		completed.add(epic, data.Filters, 1)
+	}
+
+	return completed.results(c.Name(), c.Unit(), func(values []float64) float64 {
+		return float64(len(values)) / float64(windowWeeks)
+	}, func(values []float64) map[string]interface{} {
+		return map[string]interface{}{
+			"completed_epics": len(values),
+			"window_weeks":    windowWeeks,
+		}
+	}), nil
+}
+
+// PrometheusMetrics returns the Prometheus metric descriptors
+func (c *ThroughputCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
+	return []models.PrometheusMetricDesc{
+		{
+			Name:       "throughput_epics_per_week",
+			Help:       "Number of epics completed per week",
+			Type:       "gauge",
+			LabelNames: []string{"component"},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestThroughputCalculator_Calculate(t *testing.T) {
+	tests := map[string]struct {
+		options map[string]interface{}
+		data    models.CalculationContext
+		want    []models.MetricResult
+	}{
+		"epics resolved in the window": {
+			data: models.CalculationContext{
+				Epics: []models.EnrichedIssue{
+					{Key: "E-1", Components: []string{"tekton"}, PillarID: "10", ResolvedDate: day(5)},
+					{Key: "E-2", Components: []string{"tekton"}, PillarID: "10", ResolvedDate: day(29)},
+					{Key: "E-3", Components: []string{"tekton"}, PillarID: "20", ResolvedDate: day(30)},
+					// before the four weeks window
+					{Key: "E-4", Components: []string{"tekton"}, PillarID: "20", ResolvedDate: day(2)},
+					{Key: "E-5", Components: []string{"tekton"}},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "throughput", Value: 0.75, Unit: "epics/week", Labels: map[string]string{"component": "tekton"},
+				Breakdown: []models.MetricBreakdown{
+					{Dimension: "pillar", Key: "10", Value: 0.5},
+					{Dimension: "pillar", Key: "20", Value: 0.25},
+				},
+				Metadata: map[string]interface{}{"completed_epics": 3, "window_weeks": 4},
+			}},
+		},
+		"configured window": {
+			options: map[string]interface{}{"window_weeks": 1.0},
+			data: models.CalculationContext{
+				Epics: []models.EnrichedIssue{
+					{Key: "E-1", ResolvedDate: day(22)},
+					{Key: "E-2", ResolvedDate: day(25)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "throughput", Value: 1, Unit: "epics/week", Labels: map[string]string{"component": "unknown"},
+				Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "unknown", Value: 1}},
+				Metadata:  map[string]interface{}{"completed_epics": 1, "window_weeks": 1},
+			}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewThroughputCalculator(tt.options).Calculate(context.Background(), &tt.data)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpResults); diff != "" {
+				t.Errorf("Calculate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -152,7 +152,7 @@ func (c *TimeToPatchCalculator) PrometheusMetrics() []models.PrometheusMetricDes
 			Name:       "time_to_patch_days",
 			Help:       "Time from bug report to patch release in days",
 			Type:       "gauge",
-			LabelNames: []string{"component", "pillar"},
+			LabelNames: []string{"component"},
 		},
 	}
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+// WIPAgeCalculator calculates the age of the epics in progress
+type WIPAgeCalculator struct {
+	BaseCalculator
+}
+
+// NewWIPAgeCalculator creates a new WIP age calculator
+func NewWIPAgeCalculator(options map[string]interface{}) *WIPAgeCalculator {
+	return &WIPAgeCalculator{
+		BaseCalculator: NewBaseCalculator(
+			"wip_age",
+			"Time since the epics in progress were started",
+			"days",
+			[]string{"component", "pillar"},
+			options,
+		),
+	}
+}
+
+// Calculate computes the WIP age metric at the end of the time range
+func (c *WIPAgeCalculator) Calculate(ctx context.Context, data *models.CalculationContext) ([]models.MetricResult, error) {
+	percentile := c.GetIntOption("percentile", 50)
+	inProgressStatuses := c.GetStringSliceOption("in_progress_statuses", []string{"In Progress", "In Development"})
+
+	now := data.TimeRange.End
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	ages := samples{}
+	for _, epic := range data.Epics {
+		if !epic.ResolvedDate.IsZero() || !Contains(inProgressStatuses, epic.Status) {
+			continue
+		}
+		// Started when first entering an in progress status, as for the cycle time
+		startedAt := epic.CreatedDate
+		for _, change := range sortedStatusChanges(epic) {
+			if Contains(inProgressStatuses, change.ToStatus) {
+				startedAt = change.ChangedAt
+				break
+			}
+		}
+		if startedAt.IsZero() || startedAt.After(now) {
+			continue
+		}
+		ages.add(epic, data.Filters, now.Sub(startedAt).Hours()/24)
+	}
+
+	return ages.results(c.Name(), c.Unit(), func(values []float64) float64 {
+		return percentileOf(values, percentile)
+	}, func(values []float64) map[string]interface{} {
+		stats := sampleStats(values)
+		stats["percentile"] = percentile
+		return stats
+	}), nil
+}
+
+// PrometheusMetrics returns the Prometheus metric descriptors
+func (c *WIPAgeCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
+	return []models.PrometheusMetricDesc{
+		{
+			Name:       "wip_age_days",
+			Help:       "Age of the epics in progress in days",
+			Type:       "gauge",
+			LabelNames: []string{"component"},
+		},
+	}
+}
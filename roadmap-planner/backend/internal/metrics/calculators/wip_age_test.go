@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calculators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWIPAgeCalculator_Calculate(t *testing.T) {
+	tests := map[string]struct {
+		options map[string]interface{}
+		data    models.CalculationContext
+		want    []models.MetricResult
+	}{
+		"epics in progress at the end of the range": {
+			data: models.CalculationContext{
+				Epics: []models.EnrichedIssue{
+					{Key: "E-1", Status: "In Progress", Components: []string{"tekton"}, CreatedDate: day(1),
+						StatusChanges: []baseModels.StatusChange{moved(10, "To Do", "In Progress")}},
+					// no status changes, started when created
+					{Key: "E-2", Status: "In Development", Components: []string{"tekton"}, CreatedDate: day(20)},
+					{Key: "E-3", Status: "In Progress", Components: []string{"tekton"}, CreatedDate: day(26)},
+					{Key: "E-4", Status: "To Do", Components: []string{"tekton"}, CreatedDate: day(1)},
+					{Key: "E-5", Status: "In Progress", Components: []string{"tekton"}, CreatedDate: day(1), ResolvedDate: day(5)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "wip_age", Value: 10, Unit: "days", Labels: map[string]string{"component": "tekton"},
+				Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "unknown", Value: 10}},
+				Metadata:  map[string]interface{}{"sample_size": 3, "min": 4.0, "max": 20.0, "average": 34.0 / 3, "percentile": 50},
+			}},
+		},
+		"configured statuses and percentile": {
+			options: map[string]interface{}{"percentile": 100, "in_progress_statuses": []string{"Doing"}},
+			data: models.CalculationContext{
+				Epics: []models.EnrichedIssue{
+					{Key: "E-1", Status: "Doing", CreatedDate: day(20)},
+					{Key: "E-2", Status: "Doing", CreatedDate: day(10)},
+					{Key: "E-3", Status: "In Progress", CreatedDate: day(1)},
+				},
+				TimeRange: june,
+			},
+			want: []models.MetricResult{{
+				Name: "wip_age", Value: 20, Unit: "days", Labels: map[string]string{"component": "unknown"},
+				Breakdown: []models.MetricBreakdown{{Dimension: "pillar", Key: "unknown", Value: 20}},
+				Metadata:  map[string]interface{}{"sample_size": 2, "min": 10.0, "max": 20.0, "average": 15.0, "percentile": 100},
+			}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewWIPAgeCalculator(tt.options).Calculate(context.Background(), &tt.data)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpResults); diff != "" {
+				t.Errorf("Calculate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,13 +31,75 @@ import (
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
 	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
 	"go.uber.org/zap"
 )
 
+// Collection modes
+const (
+	// CollectionModeFull refetches all the data from Jira
+	CollectionModeFull = "full"
+	// CollectionModeIncremental only fetches the issues updated since the last collection
+	CollectionModeIncremental = "incremental"
+)
+
+// Collection phases
+const (
+	CollectionPhaseReleases   = "releases"
+	CollectionPhaseEpics      = "epics"
+	CollectionPhaseIssues     = "issues"
+	CollectionPhaseChangelogs = "changelogs"
+)
+
+const (
+	// incrementalOverlap is searched again before the last collection time so that
+	// updates made while the previous collection was running are not missed
+	incrementalOverlap = time.Minute
+	// defaultChangelogConcurrency is used when no concurrency is configured
+	defaultChangelogConcurrency = 4
+	// maxStatusErrors is the number of changelog errors kept in the collection status
+	maxStatusErrors = 10
+)
+
+// cancelledStatuses are the statuses of issues excluded from the collected data
+var cancelledStatuses = map[string]struct{}{
+	"Cancelled": {},
+	"已取消":       {},
+}
+
+// CollectionStatus describes the progress and errors of the collector
+type CollectionStatus struct {
+	// Running is true while a collection is in progress
+	Running bool `json:"running"`
+	// Mode is the mode of the current or last collection
+	Mode string `json:"mode,omitempty"`
+	// Phase is the phase of the current collection
+	Phase string `json:"phase,omitempty"`
+	// Processed and Total count the items of the current phase
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+	// StartedAt is the start time of the current or last collection
+	StartedAt time.Time `json:"started_at"`
+	// Duration is the duration of the last finished collection
+	Duration string `json:"duration,omitempty"`
+	// LastFullSync is the time of the last full collection
+	LastFullSync time.Time `json:"last_full_sync"`
+	// UpdatedEpics and UpdatedIssues count the items fetched by the last collection
+	UpdatedEpics  int `json:"updated_epics"`
+	UpdatedIssues int `json:"updated_issues"`
+	// Error is the error of the last collection, empty if it succeeded
+	Error string `json:"error,omitempty"`
+	// ChangelogErrors are the first changelog fetch errors of the last collection
+	ChangelogErrors []string `json:"changelog_errors,omitempty"`
+	// FailedChangelogs counts the changelogs that could not be fetched by the last collection
+	FailedChangelogs int `json:"failed_changelogs"`
+}
+
 // Collector handles periodic data collection from Jira
 type Collector struct {
 	jiraClient *jira.Client
 	config     *config.Metrics
+	store      storage.Store
 	logger     *zap.Logger
 
 	mu            sync.RWMutex
@@ -44,13 +107,21 @@ type Collector struct {
 	epics         []models.EnrichedIssue
 	issues        []models.EnrichedIssue
 	lastCollected time.Time
+	lastFullSync  time.Time
+
+	// collecting serializes collections, statusMu guards status
+	collecting sync.Mutex
+	statusMu   sync.RWMutex
+	status     CollectionStatus
 }
 
 // NewCollector creates a new Jira data collector
-func NewCollector(jiraClient *jira.Client, cfg *config.Metrics) *Collector {
+// store persists the collected data across restarts, it can be nil
+func NewCollector(jiraClient *jira.Client, cfg *config.Metrics, store storage.Store) *Collector {
 	return &Collector{
 		jiraClient: jiraClient,
 		config:     cfg,
+		store:      store,
 		logger:     logger.WithComponent("metrics-collector"),
 		releases:   []models.EnrichedRelease{},
 		epics:      []models.EnrichedIssue{},
@@ -60,12 +131,6 @@ func NewCollector(jiraClient *jira.Client, cfg *config.Metrics) *Collector {
 
 // Start begins periodic data collection
 func (c *Collector) Start(ctx context.Context) error {
-	// Initial collection
-	if err := c.Collect(ctx); err != nil {
-		c.logger.Error("Initial collection failed", zap.Error(err))
-		// Don't return error - allow service to start even if initial collection fails
-	}
-
 	// Parse interval
 	interval, err := time.ParseDuration(c.config.CollectionInterval)
 	if err != nil {
@@ -75,6 +140,20 @@ func (c *Collector) Start(ctx context.Context) error {
 			zap.Duration("default", interval))
 	}
 
+	// Restore the data of the previous run, skipping the initial collection if it is recent enough
+	if err := c.Restore(ctx); err != nil {
+		c.logger.Error("Failed to restore collected data", zap.Error(err))
+	}
+
+	// Initial collection
+	if lastCollected := c.LastCollected(); !lastCollected.IsZero() && time.Since(lastCollected) < interval {
+		c.logger.Info("Restored data is recent, skipping initial collection",
+			zap.Time("last_collected", lastCollected))
+	} else if err := c.Collect(ctx); err != nil {
+		c.logger.Error("Initial collection failed", zap.Error(err))
+		// Don't return error - allow service to start even if initial collection fails
+	}
+
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -96,11 +175,53 @@ func (c *Collector) Start(ctx context.Context) error {
 }
 
 // Collect fetches data from Jira and caches it
+// The first collection and one per full sync interval refetch everything,
+// the others only fetch the epics and issues updated since the last collection
 func (c *Collector) Collect(ctx context.Context) error {
-	c.logger.Info("Starting data collection")
+	c.collecting.Lock()
+	defer c.collecting.Unlock()
+
 	startTime := time.Now()
+	c.mu.RLock()
+	lastCollected, lastFullSync := c.lastCollected, c.lastFullSync
+	c.mu.RUnlock()
+
+	mode := CollectionModeIncremental
+	if lastCollected.IsZero() || time.Since(lastFullSync) >= c.fullSyncInterval() {
+		mode = CollectionModeFull
+	}
+	c.logger.Info("Starting data collection", zap.String("mode", mode))
+
+	c.statusMu.Lock()
+	c.status = CollectionStatus{
+		Running:      true,
+		Mode:         mode,
+		StartedAt:    startTime,
+		LastFullSync: lastFullSync,
+	}
+	c.statusMu.Unlock()
+
+	var since time.Time
+	if mode == CollectionModeIncremental {
+		since = lastCollected.Add(-incrementalOverlap)
+	}
+	err := c.collect(ctx, since, startTime)
+
+	c.statusMu.Lock()
+	c.status.Running = false
+	c.status.Phase = ""
+	c.status.Duration = time.Since(startTime).Round(time.Millisecond).String()
+	if err != nil {
+		c.status.Error = err.Error()
+	}
+	c.statusMu.Unlock()
+	return err
+}
 
+// collect fetches the data updated since the given time, everything if since is zero
+func (c *Collector) collect(ctx context.Context, since time.Time, startTime time.Time) error {
 	// Fetch releases (versions) from Jira
+	c.setPhase(CollectionPhaseReleases, 0)
 	releases, err := c.fetchReleases(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch releases: %w", err)
@@ -110,33 +231,156 @@ func (c *Collector) Collect(ctx context.Context) error {
 	c.mu.Unlock()
 
 	// Fetch epics
-	epics, err := c.fetchEpics(ctx)
+	c.setPhase(CollectionPhaseEpics, 0)
+	epics, err := c.fetchEpics(ctx, since)
 	if err != nil {
 		return fmt.Errorf("failed to fetch epics: %w", err)
 	}
 
+	// Fetch the pillars of all milestones, milestones can move to another pillar without their epics being updated
+	milestonePillars, err := c.jiraClient.GetMilestonePillars(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch milestone pillars: %w", err)
+	}
+
 	// Fetch issues (Bugs)
-	issues, err := c.fetchIssues(ctx)
+	c.setPhase(CollectionPhaseIssues, 0)
+	issues, err := c.fetchIssues(ctx, since)
 	if err != nil {
 		return fmt.Errorf("failed to fetch issues: %w", err)
 	}
 
+	c.statusMu.Lock()
+	c.status.UpdatedEpics = len(epics)
+	c.status.UpdatedIssues = len(issues)
+	c.statusMu.Unlock()
+
+	// Fetch the status changes of the fetched epics and incidents, they are used to compute cycle times and restore times
+	if err := c.fetchStatusChanges(ctx, epics, issues); err != nil {
+		return fmt.Errorf("failed to fetch changelogs: %w", err)
+	}
+
 	// Update cached data
 	c.mu.Lock()
+	if since.IsZero() {
+		c.lastFullSync = startTime
+		keepStatusChanges(c.epics, epics)
+		keepStatusChanges(c.issues, issues)
+	} else {
+		epics = mergeIssues(c.epics, epics)
+		issues = mergeIssues(c.issues, issues)
+	}
+	epics = removeCancelled(epics)
+	issues = removeCancelled(issues)
+	versionDates := releaseDates(releases)
+	c.applyReleaseDates(epics, versionDates)
+	c.applyReleaseDates(issues, versionDates)
+	applyPillars(epics, milestonePillars)
 	c.epics = epics
 	c.issues = issues
-	c.lastCollected = time.Now()
+	c.lastCollected = startTime
+	lastFullSync := c.lastFullSync
 	c.mu.Unlock()
 
+	c.statusMu.Lock()
+	c.status.LastFullSync = lastFullSync
+	c.statusMu.Unlock()
+
 	c.logger.Info("Collection complete",
 		zap.Int("releases", len(releases)),
 		zap.Int("epics", len(epics)),
 		zap.Int("issues", len(issues)),
+		zap.Bool("full", since.IsZero()),
 		zap.Duration("duration", time.Since(startTime)))
 
+	c.persist(ctx)
 	return nil
 }
 
+// fullSyncInterval returns the configured interval between full collections
+func (c *Collector) fullSyncInterval() time.Duration {
+	interval, err := time.ParseDuration(c.config.FullSyncInterval)
+	if err != nil || interval <= 0 {
+		return 24 * time.Hour
+	}
+	return interval
+}
+
+// setPhase records the phase of the running collection and its number of items
+func (c *Collector) setPhase(phase string, total int) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+	c.status.Phase = phase
+	c.status.Processed = 0
+	c.status.Total = total
+}
+
+// Status returns the progress and errors of the collector
+func (c *Collector) Status() CollectionStatus {
+	c.statusMu.RLock()
+	defer c.statusMu.RUnlock()
+	status := c.status
+	status.ChangelogErrors = append([]string(nil), c.status.ChangelogErrors...)
+	return status
+}
+
+// Restore loads the data persisted by a previous run
+func (c *Collector) Restore(ctx context.Context) error {
+	if c.store == nil {
+		return nil
+	}
+
+	entities, err := c.store.LoadEntities(ctx)
+	if err != nil {
+		return err
+	}
+	if entities.CollectedAt.IsZero() {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.releases = entities.Releases
+	c.epics = entities.Epics
+	c.issues = entities.Issues
+	c.lastCollected = entities.CollectedAt
+	c.lastFullSync = entities.FullSyncedAt
+	c.mu.Unlock()
+
+	c.statusMu.Lock()
+	c.status.LastFullSync = entities.FullSyncedAt
+	c.statusMu.Unlock()
+
+	c.logger.Info("Restored collected data",
+		zap.Int("releases", len(entities.Releases)),
+		zap.Int("epics", len(entities.Epics)),
+		zap.Int("issues", len(entities.Issues)),
+		zap.Time("collected_at", entities.CollectedAt))
+	return nil
+}
+
+// persist saves the collected data to the store
+// failures are logged only, the data stays available in memory
+func (c *Collector) persist(ctx context.Context) {
+	if c.store == nil {
+		return
+	}
+
+	c.mu.RLock()
+	entities := &storage.Entities{
+		Releases:     c.releases,
+		Epics:        c.epics,
+		Issues:       c.issues,
+		CollectedAt:  c.lastCollected,
+		FullSyncedAt: c.lastFullSync,
+	}
+	err := c.store.SaveEntities(ctx, entities)
+	c.mu.RUnlock()
+
+	if err != nil {
+		c.logger.Error("Failed to persist collected data", zap.Error(err))
+	}
+}
+
 // fetchReleases gets release/version data from Jira
 func (c *Collector) fetchReleases(ctx context.Context) ([]models.EnrichedRelease, error) {
 	// Get project details which includes versions
@@ -236,26 +480,22 @@ func classifyReleaseType(major, minor, patch int, name string) string {
 }
 
 // fetchEpics gets epic data from Jira
-func (c *Collector) fetchEpics(ctx context.Context) ([]models.EnrichedIssue, error) {
-	// Use the existing GetEpicsWithFilter method
-	rawEpics, err := c.jiraClient.GetEpicsWithFilter(ctx, nil, nil, nil, nil)
+// When since is set only the epics updated since then are fetched, including cancelled ones
+func (c *Collector) fetchEpics(ctx context.Context, since time.Time) ([]models.EnrichedIssue, error) {
+	var rawEpics []baseModels.Epic
+	var err error
+	if since.IsZero() {
+		rawEpics, err = c.jiraClient.GetEpicsWithFilter(ctx, nil, nil, nil, nil)
+	} else {
+		rawEpics, err = c.jiraClient.GetEpicsUpdatedSince(ctx, since)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Build a map of version names to release dates
-	c.mu.RLock()
-	versionDates := make(map[string]time.Time)
-	for _, r := range c.releases {
-		if !r.ReleaseDate.IsZero() {
-			versionDates[r.Name] = r.ReleaseDate
-		}
-	}
-	c.mu.RUnlock()
-
 	epics := make([]models.EnrichedIssue, 0, len(rawEpics))
 	for _, epic := range rawEpics {
-		enriched := models.EnrichedIssue{
+		epics = append(epics, enrichIssue(models.EnrichedIssue{
 			ID:           epic.ID,
 			Key:          epic.Key,
 			Name:         epic.Name,
@@ -266,28 +506,8 @@ func (c *Collector) fetchEpics(ctx context.Context) ([]models.EnrichedIssue, err
 			IssueType:    "Epic",
 			ResolvedDate: epic.ResolutionDate,
 			CreatedDate:  epic.CreationDate,
-		}
-
-		// fallback to extracting component data from versions
-		extractComponentFromVersions := (len(enriched.Components) == 0 && len(epic.Versions) > 0)
-
-		// Find the earliest release date from versions
-		for _, versionName := range epic.Versions {
-			if releaseDate, ok := versionDates[versionName]; ok {
-				if enriched.ReleaseDate.IsZero() || releaseDate.Before(enriched.ReleaseDate) {
-					enriched.ReleaseDate = releaseDate
-				}
-			}
-			if extractComponentFromVersions {
-				component, major, minor, _ := parseVersionName(versionName)
-				if component != "" && major+minor > 0 {
-					// Only add component if it's a valid version
-					enriched.Components = append(enriched.Components, component)
-				}
-			}
-		}
-
-		epics = append(epics, enriched)
+			MilestoneIDs: epic.MilestoneIDs,
+		}))
 	}
 
 	c.logger.Debug("Fetched epics", zap.Int("count", len(epics)))
@@ -295,33 +515,24 @@ func (c *Collector) fetchEpics(ctx context.Context) ([]models.EnrichedIssue, err
 }
 
 // fetchIssues gets issues data from Jira
-func (c *Collector) fetchIssues(ctx context.Context) ([]models.EnrichedIssue, error) {
-	// Use the existing GetEpicsWithFilter method
-	var issueTypes []string
-	if filter := c.config.GetFilter("issues"); filter != nil && filter.Enabled && len(filter.Options) > 0 {
-		issueTypes = filter.GetStringSliceOption("issuetypes", []string{"Bug"})
+// When since is set only the issues updated since then are fetched, including cancelled ones
+func (c *Collector) fetchIssues(ctx context.Context, since time.Time) ([]models.EnrichedIssue, error) {
+	issueTypes := c.IssueTypes()
+
+	var rawIssues []baseModels.Issue
+	var err error
+	if since.IsZero() {
+		rawIssues, err = c.jiraClient.GetIssuesWithFilter(ctx, nil, nil, nil, issueTypes)
+	} else {
+		rawIssues, err = c.jiraClient.GetIssuesUpdatedSince(ctx, since, issueTypes)
 	}
-
-	rawIssues, err := c.jiraClient.GetIssuesWithFilter(ctx, nil, nil, nil, issueTypes)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build a map of version names to release dates
-	c.mu.RLock()
-	versionDates := make(map[string]time.Time)
-	for _, r := range c.releases {
-		if !r.ReleaseDate.IsZero() {
-			versionDates[r.Name] = r.ReleaseDate
-		}
-	}
-	c.mu.RUnlock()
-
-	countWithout := 0
-	countWith := 0
 	issues := make([]models.EnrichedIssue, 0, len(rawIssues))
 	for _, issue := range rawIssues {
-		enriched := models.EnrichedIssue{
+		issues = append(issues, enrichIssue(models.EnrichedIssue{
 			ID:           issue.ID,
 			Key:          issue.Key,
 			Name:         issue.Name,
@@ -332,34 +543,203 @@ func (c *Collector) fetchIssues(ctx context.Context) ([]models.EnrichedIssue, er
 			IssueType:    issue.Type,
 			ResolvedDate: issue.ResolutionDate,
 			CreatedDate:  issue.CreationDate,
+		}))
+	}
+
+	c.logger.Debug("Fetched issues", zap.Int("count", len(issues)))
+	return issues, nil
+}
+
+// IssueTypes returns the types of the collected issues, nil when all types are collected
+func (c *Collector) IssueTypes() []string {
+	if filter := c.config.GetFilter("issues"); filter != nil && filter.Enabled && len(filter.Options) > 0 {
+		return filter.GetStringSliceOption("issuetypes", []string{"Bug"})
+	}
+	return nil
+}
+
+// IncidentTypes returns the types of the issues whose status changes are fetched for the mean time to restore,
+// nil when the calculator is disabled
+func (c *Collector) IncidentTypes() []string {
+	calculator := c.config.GetCalculator("mean_time_to_restore")
+	if calculator == nil {
+		return []string{"Incident"}
+	}
+	if !calculator.Enabled {
+		return nil
+	}
+	return calculator.GetStringSliceOption("incident_types", []string{"Incident"})
+}
+
+// enrichIssue falls back to extracting the components from the versions of an issue
+func enrichIssue(enriched models.EnrichedIssue) models.EnrichedIssue {
+	if len(enriched.Components) > 0 {
+		return enriched
+	}
+	for _, versionName := range enriched.Versions {
+		component, major, minor, _ := parseVersionName(versionName)
+		if component != "" && major+minor > 0 {
+			// Only add component if it's a valid version
+			enriched.Components = append(enriched.Components, component)
 		}
+	}
+	return enriched
+}
 
-		// fallback to extracting component data from versions
-		extractComponentFromVersions := (len(enriched.Components) == 0 && len(issue.Versions) > 0)
+// releaseDates maps version names to their release dates
+func releaseDates(releases []models.EnrichedRelease) map[string]time.Time {
+	versionDates := make(map[string]time.Time)
+	for _, r := range releases {
+		if !r.ReleaseDate.IsZero() {
+			versionDates[r.Name] = r.ReleaseDate
+		}
+	}
+	return versionDates
+}
 
-		// Find the earliest release date from versions
-		for _, versionName := range issue.Versions {
-			if releaseDate, ok := versionDates[versionName]; ok {
-				if enriched.ReleaseDate.IsZero() || releaseDate.Before(enriched.ReleaseDate) {
-					enriched.ReleaseDate = releaseDate
-				}
-				countWith++
-			} else {
+// applyReleaseDates sets the release date of the issues to the earliest release date of their versions
+// It runs on every collection as versions can be released without their issues being updated
+func (c *Collector) applyReleaseDates(issues []models.EnrichedIssue, versionDates map[string]time.Time) {
+	countWithout := 0
+	countWith := 0
+	for i := range issues {
+		issues[i].ReleaseDate = time.Time{}
+		for _, versionName := range issues[i].Versions {
+			releaseDate, ok := versionDates[versionName]
+			if !ok {
 				countWithout++
+				continue
 			}
-			if extractComponentFromVersions {
-				component, major, minor, _ := parseVersionName(versionName)
-				if component != "" && major+minor > 0 {
-					// Only add component if it's a valid version
-					enriched.Components = append(enriched.Components, component)
+			countWith++
+			if issues[i].ReleaseDate.IsZero() || releaseDate.Before(issues[i].ReleaseDate) {
+				issues[i].ReleaseDate = releaseDate
+			}
+		}
+	}
+	c.logger.Debug("Applied release dates", zap.Int("count", len(issues)), zap.Int("without", countWithout), zap.Int("with", countWith))
+}
+
+// applyPillars sets the pillar of the epics to the pillar of their first milestone that has one
+// It runs on every collection as milestones can move between pillars without their epics being updated
+func applyPillars(epics []models.EnrichedIssue, milestonePillars map[string]string) {
+	for i := range epics {
+		epics[i].PillarID = ""
+		for _, milestoneID := range epics[i].MilestoneIDs {
+			if pillarID, ok := milestonePillars[milestoneID]; ok {
+				epics[i].PillarID = pillarID
+				break
+			}
+		}
+	}
+}
+
+// fetchStatusChanges fetches in place the status changes of the epics and of the incidents among the issues,
+// skipping cancelled ones and using at most the configured number of concurrent requests
+// Failures are recorded in the collection status and leave the issue without status changes
+func (c *Collector) fetchStatusChanges(ctx context.Context, epics, issues []models.EnrichedIssue) error {
+	concurrency := c.config.ChangelogConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultChangelogConcurrency
+	}
+
+	incidentTypes := c.IncidentTypes()
+	targets := make([]*models.EnrichedIssue, 0, len(epics))
+	for i := range epics {
+		if !isCancelled(epics[i]) {
+			targets = append(targets, &epics[i])
+		}
+	}
+	for i := range issues {
+		if !isCancelled(issues[i]) && slices.Contains(incidentTypes, issues[i].IssueType) {
+			targets = append(targets, &issues[i])
+		}
+	}
+	c.setPhase(CollectionPhaseChangelogs, len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, target := range targets {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(issue *models.EnrichedIssue) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			changes, err := c.jiraClient.GetStatusChanges(ctx, issue.ID)
+
+			c.statusMu.Lock()
+			defer c.statusMu.Unlock()
+			c.status.Processed++
+			if err != nil {
+				c.status.FailedChangelogs++
+				if len(c.status.ChangelogErrors) < maxStatusErrors {
+					c.status.ChangelogErrors = append(c.status.ChangelogErrors, fmt.Sprintf("%s: %v", issue.Key, err))
 				}
+				return
 			}
+			issue.StatusChanges = changes
+		}(target)
+	}
+	wg.Wait()
+
+	if failed := c.Status().FailedChangelogs; failed > 0 {
+		c.logger.Warn("Failed to fetch some changelogs", zap.Int("failed", failed), zap.Int("total", len(targets)))
+	}
+	return ctx.Err()
+}
+
+// mergeIssues replaces the current issues with their updated version and appends the new ones
+// The status changes of an issue are kept when they could not be fetched again
+func mergeIssues(current, updated []models.EnrichedIssue) []models.EnrichedIssue {
+	updatedKeys := make(map[string]struct{}, len(updated))
+	for _, issue := range updated {
+		updatedKeys[issue.Key] = struct{}{}
+	}
+
+	merged := make([]models.EnrichedIssue, 0, len(current)+len(updated))
+	for _, issue := range current {
+		if _, ok := updatedKeys[issue.Key]; !ok {
+			merged = append(merged, issue)
 		}
-		issues = append(issues, enriched)
 	}
+	keepStatusChanges(current, updated)
+	return append(merged, updated...)
+}
 
-	c.logger.Debug("Fetched issues", zap.Int("count", len(issues)), zap.Int("without", countWithout), zap.Int("with", countWith))
-	return issues, nil
+// keepStatusChanges sets in place the previous status changes of the updated issues whose changelog could not be fetched
+func keepStatusChanges(previous, updated []models.EnrichedIssue) {
+	changes := make(map[string][]baseModels.StatusChange, len(previous))
+	for _, issue := range previous {
+		changes[issue.Key] = issue.StatusChanges
+	}
+	for i := range updated {
+		if updated[i].StatusChanges == nil {
+			updated[i].StatusChanges = changes[updated[i].Key]
+		}
+	}
+}
+
+// removeCancelled returns the issues that are not cancelled
+func removeCancelled(issues []models.EnrichedIssue) []models.EnrichedIssue {
+	kept := make([]models.EnrichedIssue, 0, len(issues))
+	for _, issue := range issues {
+		if !isCancelled(issue) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// isCancelled returns true if the issue has a cancelled status
+func isCancelled(issue models.EnrichedIssue) bool {
+	_, ok := cancelledStatuses[issue.Status]
+	return ok
 }
 
 // GetData returns the current cached data for metric calculation
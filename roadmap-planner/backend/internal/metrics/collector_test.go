@@ -0,0 +1,346 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	baseModels "github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeIssue is an issue served by fakeJira
+type fakeIssue struct {
+	ID       string
+	Key      string
+	Type     string
+	Status   string
+	Versions []string
+	// Milestones are the IDs of the milestones of an epic
+	Milestones []string
+}
+
+// fakeJira serves the Jira endpoints used by the collector
+type fakeJira struct {
+	mu       sync.Mutex
+	versions []map[string]any
+	// full and updated are the search results of full and incremental collections
+	full, updated []fakeIssue
+	// milestones maps the milestone IDs to the IDs of their pillar
+	milestones map[string]string
+	// failing changelogs return an error
+	failing map[string]bool
+
+	rateLimited bool
+	queries     []string
+	changelogs  []string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/rest/api/2/project/TEST":
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "1", "key": "TEST", "versions": f.versions})
+	case r.URL.Path == "/rest/api/2/search":
+		// the first search is rate limited to check the client retries it
+		if !f.rateLimited {
+			f.rateLimited = true
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		jql := r.URL.Query().Get("jql")
+		f.queries = append(f.queries, jql)
+		if strings.Contains(jql, "issuetype = Milestone") {
+			milestones := []map[string]any{}
+			for id, pillar := range f.milestones {
+				milestones = append(milestones, map[string]any{"id": id, "fields": map[string]any{"parent": map[string]any{"id": pillar}}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"startAt": 0, "maxResults": 100, "total": len(milestones), "issues": milestones})
+			return
+		}
+		source := f.full
+		if strings.Contains(jql, "updated >=") {
+			source = f.updated
+		}
+		issues := []map[string]any{}
+		for _, issue := range source {
+			if strings.Contains(jql, "issuetype = Epic") != (issue.Type == "Epic") {
+				continue
+			}
+			if issue.Status == "Cancelled" && strings.Contains(jql, "status not in") {
+				continue
+			}
+			versions := []map[string]any{}
+			for _, v := range issue.Versions {
+				versions = append(versions, map[string]any{"name": v})
+			}
+			links := []map[string]any{}
+			for _, id := range issue.Milestones {
+				links = append(links, map[string]any{
+					"type":         map[string]any{"name": "Blocks"},
+					"outwardIssue": map[string]any{"id": id, "fields": map[string]any{"issuetype": map[string]any{"name": "Milestone"}}},
+				})
+			}
+			issues = append(issues, map[string]any{"id": issue.ID, "key": issue.Key, "fields": map[string]any{
+				"summary":     issue.Key,
+				"issuetype":   map[string]any{"name": issue.Type},
+				"status":      map[string]any{"name": issue.Status},
+				"fixVersions": versions,
+				"issuelinks":  links,
+			}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"startAt": 0, "maxResults": 100, "total": len(issues), "issues": issues})
+	case strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/"):
+		id := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		f.changelogs = append(f.changelogs, id)
+		if f.failing[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "fields": map[string]any{}, "changelog": map[string]any{
+			"histories": []map[string]any{{
+				"id":      "1",
+				"created": "2025-06-02T10:00:00.000+0000",
+				"items":   []map[string]any{{"field": "status", "fromString": "To Do", "toString": "In Progress " + id}},
+			}},
+		}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// reset clears the recorded requests
+func (f *fakeJira) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = nil
+	f.changelogs = nil
+}
+
+func statusChanges(id string) []baseModels.StatusChange {
+	return []baseModels.StatusChange{{
+		FromStatus: "To Do",
+		ToStatus:   "In Progress " + id,
+		ChangedAt:  time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+	}}
+}
+
+// collectedIssue summarizes a collected issue
+type collectedIssue struct {
+	Key           string
+	ReleaseDate   string
+	PillarID      string
+	StatusChanges []baseModels.StatusChange
+}
+
+func summarize(issues []models.EnrichedIssue) []collectedIssue {
+	summary := []collectedIssue{}
+	for _, issue := range issues {
+		item := collectedIssue{Key: issue.Key, PillarID: issue.PillarID, StatusChanges: issue.StatusChanges}
+		if !issue.ReleaseDate.IsZero() {
+			item.ReleaseDate = issue.ReleaseDate.Format("2006-01-02")
+		}
+		summary = append(summary, item)
+	}
+	return summary
+}
+
+func TestCollector_IncrementalCollection(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeJira{
+		versions: []map[string]any{
+			{"id": "1", "name": "argo-cd-2.9.0", "released": true, "releaseDate": "2025-06-10"},
+			{"id": "2", "name": "argo-cd-2.10.0"},
+		},
+		full: []fakeIssue{
+			{ID: "10", Key: "TEST-1", Type: "Epic", Status: "Done", Versions: []string{"argo-cd-2.9.0"}},
+			{ID: "11", Key: "TEST-2", Type: "Epic", Status: "In Progress", Versions: []string{"argo-cd-2.10.0"}, Milestones: []string{"99", "100"}},
+			{ID: "20", Key: "TEST-3", Type: "Bug", Status: "Open"},
+			{ID: "30", Key: "TEST-6", Type: "Incident", Status: "Resolved"},
+		},
+		// milestone 99 has no pillar
+		milestones: map[string]string{"100": "1000"},
+		failing:    map[string]bool{},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := jira.NewClient(server.URL, "user", "token", "TEST")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	cfg := &config.Metrics{HistoricalDays: 365, FullSyncInterval: "24h", ChangelogConcurrency: 2}
+	collector := NewCollector(client, cfg, nil)
+
+	// the first collection fetches everything
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	data, _ := collector.GetData()
+	wantEpics := []collectedIssue{
+		{Key: "TEST-1", ReleaseDate: "2025-06-10", StatusChanges: statusChanges("10")},
+		{Key: "TEST-2", PillarID: "1000", StatusChanges: statusChanges("11")},
+	}
+	if diff := cmp.Diff(wantEpics, summarize(data.Epics)); diff != "" {
+		t.Errorf("full collection epics mismatch (-want +got):\n%s", diff)
+	}
+	// the status changes of incidents are fetched for the mean time to restore
+	wantIssues := []collectedIssue{{Key: "TEST-3"}, {Key: "TEST-6", StatusChanges: statusChanges("30")}}
+	if diff := cmp.Diff(wantIssues, summarize(data.Issues)); diff != "" {
+		t.Errorf("full collection issues mismatch (-want +got):\n%s", diff)
+	}
+	status := collector.Status()
+	if status.Mode != CollectionModeFull || status.Running || status.Error != "" || status.LastFullSync.IsZero() {
+		t.Errorf("Status() after full collection = %+v", status)
+	}
+
+	// the next collection only fetches the updated issues
+	fake.mu.Lock()
+	fake.versions[1]["released"] = true
+	fake.versions[1]["releaseDate"] = "2025-07-01"
+	fake.milestones["100"] = "1001"
+	fake.updated = []fakeIssue{
+		{ID: "10", Key: "TEST-1", Type: "Epic", Status: "Cancelled"},
+		{ID: "12", Key: "TEST-4", Type: "Epic", Status: "In Progress"},
+		{ID: "20", Key: "TEST-3", Type: "Bug", Status: "Cancelled"},
+		{ID: "21", Key: "TEST-5", Type: "Bug", Status: "Open", Versions: []string{"argo-cd-2.9.0"}},
+	}
+	fake.mu.Unlock()
+	fake.reset()
+
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	data, _ = collector.GetData()
+	wantEpics = []collectedIssue{
+		// the release date and the pillar of unchanged epics follow the releases and the milestones
+		{Key: "TEST-2", ReleaseDate: "2025-07-01", PillarID: "1001", StatusChanges: statusChanges("11")},
+		{Key: "TEST-4", StatusChanges: statusChanges("12")},
+	}
+	if diff := cmp.Diff(wantEpics, summarize(data.Epics)); diff != "" {
+		t.Errorf("incremental collection epics mismatch (-want +got):\n%s", diff)
+	}
+	wantIssues = []collectedIssue{{Key: "TEST-6", StatusChanges: statusChanges("30")}, {Key: "TEST-5", ReleaseDate: "2025-06-10"}}
+	if diff := cmp.Diff(wantIssues, summarize(data.Issues)); diff != "" {
+		t.Errorf("incremental collection issues mismatch (-want +got):\n%s", diff)
+	}
+	// only the changelogs of updated epics that are not cancelled are fetched
+	if diff := cmp.Diff([]string{"12"}, fake.changelogs); diff != "" {
+		t.Errorf("fetched changelogs mismatch (-want +got):\n%s", diff)
+	}
+	for _, query := range fake.queries {
+		// the pillars of all milestones are fetched on every collection
+		if !strings.Contains(query, "updated >= -") && !strings.Contains(query, "issuetype = Milestone") {
+			t.Errorf("incremental collection query %q does not filter updated issues", query)
+		}
+	}
+	status = collector.Status()
+	want := CollectionStatus{
+		Mode:          CollectionModeIncremental,
+		UpdatedEpics:  2,
+		UpdatedIssues: 2,
+	}
+	if diff := cmp.Diff(want, status, cmpCollectionStatus); diff != "" {
+		t.Errorf("Status() after incremental collection mismatch (-want +got):\n%s", diff)
+	}
+
+	// changelog failures are reported and keep the previous status changes
+	fake.mu.Lock()
+	fake.updated = []fakeIssue{{ID: "12", Key: "TEST-4", Type: "Epic", Status: "Done"}}
+	fake.failing["12"] = true
+	fake.mu.Unlock()
+
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	data, _ = collector.GetData()
+	if diff := cmp.Diff(statusChanges("12"), data.Epics[1].StatusChanges); diff != "" {
+		t.Errorf("status changes after a changelog failure mismatch (-want +got):\n%s", diff)
+	}
+	status = collector.Status()
+	if status.FailedChangelogs != 1 || len(status.ChangelogErrors) != 1 || !strings.HasPrefix(status.ChangelogErrors[0], "TEST-4: ") {
+		t.Errorf("Status() after a changelog failure = %+v", status)
+	}
+
+	// the full sync interval triggers a full collection, which also keeps the status changes it failed to fetch
+	fake.mu.Lock()
+	fake.failing["11"] = true
+	fake.mu.Unlock()
+	cfg.FullSyncInterval = "1ns"
+	if err := collector.Collect(ctx); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if mode := collector.Status().Mode; mode != CollectionModeFull {
+		t.Errorf("Status().Mode after the full sync interval = %q, want %q", mode, CollectionModeFull)
+	}
+	data, _ = collector.GetData()
+	wantEpics = []collectedIssue{
+		{Key: "TEST-1", ReleaseDate: "2025-06-10", StatusChanges: statusChanges("10")},
+		{Key: "TEST-2", ReleaseDate: "2025-07-01", PillarID: "1001", StatusChanges: statusChanges("11")},
+	}
+	if diff := cmp.Diff(wantEpics, summarize(data.Epics)); diff != "" {
+		t.Errorf("full collection epics after a changelog failure mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCollector_CollectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"errorMessages":["unauthorized"]}`)
+	}))
+	defer server.Close()
+
+	client, err := jira.NewClient(server.URL, "user", "token", "TEST")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	collector := NewCollector(client, &config.Metrics{}, nil)
+	if err := collector.Collect(context.Background()); err == nil {
+		t.Fatalf("Collect() expected error")
+	}
+
+	status := collector.Status()
+	if status.Running || status.Phase != "" || !strings.Contains(status.Error, "failed to fetch releases") {
+		t.Errorf("Status() after a failed collection = %+v", status)
+	}
+	if !collector.LastCollected().IsZero() {
+		t.Errorf("LastCollected() after a failed collection = %v, want zero", collector.LastCollected())
+	}
+}
+
+// cmpCollectionStatus ignores the timing fields of the collection status
+var cmpCollectionStatus = cmp.FilterPath(func(p cmp.Path) bool {
+	switch p.Last().String() {
+	case ".StartedAt", ".Duration", ".LastFullSync", ".Processed", ".Total":
+		return true
+	}
+	return false
+}, cmp.Ignore())
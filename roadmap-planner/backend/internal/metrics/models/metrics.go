@@ -113,7 +113,9 @@ type EnrichedIssue struct {
 	ResolvedDate  time.Time                 `json:"resolved_date,omitempty"`
 	ReleaseDate   time.Time                 `json:"release_date,omitempty"`
 	StatusChanges []baseModels.StatusChange `json:"status_changes,omitempty"`
-	PillarID      string                    `json:"pillar_id,omitempty"`
+	// MilestoneIDs are the milestones of an epic, PillarID is the parent pillar of the first milestone with one
+	MilestoneIDs []string `json:"milestone_ids,omitempty"`
+	PillarID     string   `json:"pillar_id,omitempty"`
 }
 
 // PrometheusMetricDesc describes a Prometheus metric
@@ -47,6 +47,13 @@ type PrometheusExporter struct {
 	patchRatio       *prometheus.GaugeVec
 	timeToPatch      *prometheus.GaugeVec
 
+	// Flow metrics
+	changeFailureRate *prometheus.GaugeVec
+	timeToRestore     *prometheus.GaugeVec
+	wipAge            *prometheus.GaugeVec
+	throughput        *prometheus.GaugeVec
+	flowEfficiency    *prometheus.GaugeVec
+
 	// Meta metrics
 	lastCollectionTime prometheus.Gauge
 	collectionErrors   prometheus.Counter
@@ -120,6 +127,56 @@ func NewPrometheusExporter(cfg *config.PrometheusConfig, service *Service) *Prom
 			[]string{"component"},
 		),
 
+		changeFailureRate: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "dora",
+				Name:      "change_failure_rate",
+				Help:      "Ratio of releases followed by a hotfix release or by bugs",
+			},
+			[]string{"component"},
+		),
+
+		timeToRestore: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "dora",
+				Name:      "mean_time_to_restore_hours",
+				Help:      "Mean time from incident report to restoration in hours",
+			},
+			[]string{"component"},
+		),
+
+		wipAge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "flow",
+				Name:      "wip_age_days",
+				Help:      "Age of the epics in progress in days",
+			},
+			[]string{"component"},
+		),
+
+		throughput: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "flow",
+				Name:      "throughput_epics_per_week",
+				Help:      "Number of epics completed per week",
+			},
+			[]string{"component"},
+		),
+
+		flowEfficiency: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "flow",
+				Name:      "flow_efficiency",
+				Help:      "Share of the cycle time of epics spent in active statuses",
+			},
+			[]string{"component"},
+		),
+
 		lastCollectionTime: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -173,6 +230,11 @@ func NewPrometheusExporter(cfg *config.PrometheusConfig, service *Service) *Prom
 		e.cycleTime,
 		e.patchRatio,
 		e.timeToPatch,
+		e.changeFailureRate,
+		e.timeToRestore,
+		e.wipAge,
+		e.throughput,
+		e.flowEfficiency,
 		e.lastCollectionTime,
 		e.collectionErrors,
 		e.releasesTotal,
@@ -216,80 +278,36 @@ func (e *PrometheusExporter) Update(ctx context.Context) error {
 	}
 
 	// Calculate and update each metric
-	filters := models.MetricFilters{}
-
-	// Update release frequency
-	if results, err := e.service.CalculateMetric(ctx, "release_frequency", filters, models.TimeRange{}); err == nil {
-		for _, r := range results {
-			component := r.Labels["component"]
-			if component == "" {
-				component = "unknown"
-			}
-			e.releaseFrequency.With(prometheus.Labels{"component": component}).Set(r.Value)
-		}
-	} else {
-		e.logger.Warn("Failed to calculate release_frequency", zap.Error(err))
-		e.collectionErrors.Inc()
-	}
+	e.updateGauge(ctx, "release_frequency", e.releaseFrequency)
+	e.updateGauge(ctx, "lead_time_to_release", e.leadTime)
+	e.updateGauge(ctx, "cycle_time", e.cycleTime)
+	e.updateGauge(ctx, "patch_ratio", e.patchRatio)
+	e.updateGauge(ctx, "time_to_patch", e.timeToPatch)
+	e.updateGauge(ctx, "change_failure_rate", e.changeFailureRate)
+	e.updateGauge(ctx, "mean_time_to_restore", e.timeToRestore)
+	e.updateGauge(ctx, "wip_age", e.wipAge)
+	e.updateGauge(ctx, "throughput", e.throughput)
+	e.updateGauge(ctx, "flow_efficiency", e.flowEfficiency)
 
-	// Update lead time
-	if results, err := e.service.CalculateMetric(ctx, "lead_time_to_release", filters, models.TimeRange{}); err == nil {
-		for _, r := range results {
-			component := r.Labels["component"]
-			if component == "" {
-				component = "unknown"
-			}
-			e.leadTime.With(prometheus.Labels{"component": component}).Set(r.Value)
-		}
-	} else {
-		e.logger.Warn("Failed to calculate lead_time_to_release", zap.Error(err))
-		e.collectionErrors.Inc()
-	}
-
-	// Update cycle time
-	if results, err := e.service.CalculateMetric(ctx, "cycle_time", filters, models.TimeRange{}); err == nil {
-		for _, r := range results {
-			component := r.Labels["component"]
-			if component == "" {
-				component = "unknown"
-			}
-			e.cycleTime.With(prometheus.Labels{"component": component}).Set(r.Value)
-		}
-	} else {
-		e.logger.Warn("Failed to calculate cycle_time", zap.Error(err))
-		e.collectionErrors.Inc()
-	}
+	e.logger.Debug("Prometheus metrics updated")
+	return nil
+}
 
-	// Update patch ratio
-	if results, err := e.service.CalculateMetric(ctx, "patch_ratio", filters, models.TimeRange{}); err == nil {
-		for _, r := range results {
-			component := r.Labels["component"]
-			if component == "" {
-				component = "unknown"
-			}
-			e.patchRatio.With(prometheus.Labels{"component": component}).Set(r.Value)
-		}
-	} else {
-		e.logger.Warn("Failed to calculate patch_ratio", zap.Error(err))
+// updateGauge sets the gauge of each component to the value calculated for the metric
+func (e *PrometheusExporter) updateGauge(ctx context.Context, metric string, gauge *prometheus.GaugeVec) {
+	results, err := e.service.CalculateMetric(ctx, metric, models.MetricFilters{}, models.TimeRange{})
+	if err != nil {
+		e.logger.Warn("Failed to calculate metric", zap.String("metric", metric), zap.Error(err))
 		e.collectionErrors.Inc()
+		return
 	}
-
-	// Update time to patch
-	if results, err := e.service.CalculateMetric(ctx, "time_to_patch", filters, models.TimeRange{}); err == nil {
-		for _, r := range results {
-			component := r.Labels["component"]
-			if component == "" {
-				component = "unknown"
-			}
-			e.timeToPatch.With(prometheus.Labels{"component": component}).Set(r.Value)
+	for _, r := range results {
+		component := r.Labels["component"]
+		if component == "" {
+			component = "unknown"
 		}
-	} else {
-		e.logger.Warn("Failed to calculate time_to_patch", zap.Error(err))
-		e.collectionErrors.Inc()
+		gauge.With(prometheus.Labels{"component": component}).Set(r.Value)
 	}
-
-	e.logger.Debug("Prometheus metrics updated")
-	return nil
 }
 
 // StartUpdater starts a background goroutine that periodically updates Prometheus metrics
@@ -18,31 +18,47 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/calculators"
 	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
 	"go.uber.org/zap"
 )
 
+// ErrStorageDisabled is returned by history queries when no storage is configured
+var ErrStorageDisabled = errors.New("metrics storage is not enabled")
+
+// defaultTrendWindow is how far back the summary trend compares against when not configured
+const defaultTrendWindow = 30 * 24 * time.Hour
+
 // Service orchestrates metric collection and calculation
 type Service struct {
 	config    *config.Metrics
 	registry  *Registry
 	collector *Collector
+	store     storage.Store
 	logger    *zap.Logger
+
+	// TrendWindow is how far back the summary trend compares against
+	TrendWindow time.Duration
 }
 
 // NewService creates a new metrics service
-func NewService(cfg *config.Metrics, collector *Collector) *Service {
+// store records metric snapshots used for trends and history, it can be nil
+func NewService(cfg *config.Metrics, collector *Collector, store storage.Store) *Service {
 	return &Service{
-		config:    cfg,
-		registry:  NewRegistry(),
-		collector: collector,
-		logger:    logger.WithComponent("metrics-service"),
+		config:      cfg,
+		registry:    NewRegistry(),
+		collector:   collector,
+		store:       store,
+		logger:      logger.WithComponent("metrics-service"),
+		TrendWindow: defaultTrendWindow,
 	}
 }
 
@@ -147,6 +163,7 @@ func (s *Service) CalculateAllMetrics(ctx context.Context, filters models.Metric
 			summary.Metrics[calc.Name()] = models.MetricSummaryItem{
 				Value: avgValue,
 				Unit:  calc.Unit(),
+				Trend: s.trend(ctx, calc.Name(), filters, avgValue, summary.Timestamp),
 			}
 		}
 	}
@@ -154,6 +171,166 @@ func (s *Service) CalculateAllMetrics(ctx context.Context, filters models.Metric
 	return summary, nil
 }
 
+// trend compares a summary value with the snapshot taken a trend window ago
+// Snapshots are only recorded for all components and for each component,
+// so there is no trend for other filters
+func (s *Service) trend(ctx context.Context, metric string, filters models.MetricFilters, value float64, now time.Time) string {
+	if s.store == nil || len(filters.Pillars) > 0 || len(filters.Quarters) > 0 || len(filters.Components) > 1 {
+		return ""
+	}
+	component := ""
+	if len(filters.Components) == 1 {
+		component = filters.Components[0]
+	}
+
+	previous, err := s.store.SnapshotBefore(ctx, metric, component, now.Add(-s.TrendWindow))
+	if err != nil {
+		s.logger.Warn("Failed to load previous snapshot", zap.String("metric", metric), zap.Error(err))
+		return ""
+	}
+	if previous == nil {
+		return ""
+	}
+	return formatTrend(previous.Value, value)
+}
+
+// formatTrend formats the relative change between two values, e.g. "+15%"
+func formatTrend(previous, current float64) string {
+	if previous == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.0f%%", (current-previous)/math.Abs(previous)*100)
+}
+
+// TakeSnapshot records the current value of all metrics, aggregated and for each component
+func (s *Service) TakeSnapshot(ctx context.Context) error {
+	if s.store == nil {
+		return ErrStorageDisabled
+	}
+	if s.collector.LastCollected().IsZero() {
+		return fmt.Errorf("no data collected yet")
+	}
+
+	data, err := s.collector.GetData()
+	if err != nil {
+		return fmt.Errorf("failed to get data from collector: %w", err)
+	}
+
+	takenAt := time.Now()
+	snapshots := []storage.Snapshot{}
+	for _, calc := range s.registry.All() {
+		results, err := calc.Calculate(ctx, data)
+		if err != nil {
+			s.logger.Warn("Failed to calculate metric for snapshot",
+				zap.String("metric", calc.Name()),
+				zap.Error(err))
+			continue
+		}
+		if len(results) == 0 {
+			continue
+		}
+
+		// Aggregate the same way as the summary
+		var totalValue float64
+		for _, r := range results {
+			totalValue += r.Value
+			if component := r.Labels["component"]; component != "" {
+				snapshots = append(snapshots, storage.Snapshot{
+					Metric:    calc.Name(),
+					Component: component,
+					Value:     r.Value,
+					Unit:      calc.Unit(),
+					TakenAt:   takenAt,
+				})
+			}
+		}
+		snapshots = append(snapshots, storage.Snapshot{
+			Metric:  calc.Name(),
+			Value:   totalValue / float64(len(results)),
+			Unit:    calc.Unit(),
+			TakenAt: takenAt,
+		})
+	}
+
+	if err := s.store.SaveSnapshots(ctx, snapshots); err != nil {
+		return err
+	}
+	s.logger.Info("Recorded metric snapshot", zap.Int("values", len(snapshots)))
+	return nil
+}
+
+// StartSnapshots records a snapshot every interval and deletes the snapshots older than retention
+// The first snapshot is taken as soon as data is available if the last one is older than the interval
+func (s *Service) StartSnapshots(ctx context.Context, interval, retention time.Duration) error {
+	if s.store == nil {
+		return ErrStorageDisabled
+	}
+
+	lastSnapshot, err := s.store.LastSnapshotAt(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Check often enough to take the first snapshot shortly after the initial collection
+	ticker := time.NewTicker(min(interval, time.Minute))
+	defer ticker.Stop()
+
+	s.logger.Info("Snapshot recorder started",
+		zap.Duration("interval", interval),
+		zap.Time("last_snapshot", lastSnapshot))
+
+	for {
+		if time.Since(lastSnapshot) >= interval && !s.collector.LastCollected().IsZero() {
+			if err := s.TakeSnapshot(ctx); err != nil {
+				s.logger.Error("Failed to record metric snapshot", zap.Error(err))
+			} else {
+				lastSnapshot = time.Now()
+				s.prune(ctx, lastSnapshot.Add(-retention), retention)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Snapshot recorder stopping")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes the snapshots taken before the given time, retention 0 keeps everything
+func (s *Service) prune(ctx context.Context, before time.Time, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	pruned, err := s.store.PruneSnapshots(ctx, before)
+	if err != nil {
+		s.logger.Warn("Failed to prune metric snapshots", zap.Error(err))
+		return
+	}
+	if pruned > 0 {
+		s.logger.Info("Pruned metric snapshots", zap.Int64("count", pruned))
+	}
+}
+
+// MetricHistory returns the recorded values of a metric in the time range
+// component selects the values of a component, empty selects the aggregated values
+func (s *Service) MetricHistory(ctx context.Context, name, component string, timeRange models.TimeRange) ([]storage.Snapshot, error) {
+	if s.store == nil {
+		return nil, ErrStorageDisabled
+	}
+	if _, exists := s.registry.Get(name); !exists {
+		return nil, fmt.Errorf("metric %s not found", name)
+	}
+
+	return s.store.ListSnapshots(ctx, storage.SnapshotQuery{
+		Metric:    name,
+		Component: component,
+		From:      timeRange.Start,
+		To:        timeRange.End,
+	})
+}
+
 // GetCalculatorOptions returns the options for a specific calculator from config
 func (s *Service) GetCalculatorOptions(name string) map[string]interface{} {
 	for _, calcCfg := range s.config.Calculators {
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+	"github.com/google/go-cmp/cmp"
+)
+
+// releaseCountCalculator counts the releases of each component
+type releaseCountCalculator struct{}
+
+func (releaseCountCalculator) Name() string               { return "release_count" }
+func (releaseCountCalculator) Description() string        { return "Number of releases" }
+func (releaseCountCalculator) Unit() string               { return "releases" }
+func (releaseCountCalculator) AvailableFilters() []string { return []string{"component"} }
+func (releaseCountCalculator) PrometheusMetrics() []models.PrometheusMetricDesc {
+	return nil
+}
+
+func (releaseCountCalculator) Calculate(ctx context.Context, data *models.CalculationContext) ([]models.MetricResult, error) {
+	counts := map[string]float64{}
+	for _, r := range data.Releases {
+		if len(data.Filters.Components) > 0 && data.Filters.Components[0] != r.Component {
+			continue
+		}
+		counts[r.Component]++
+	}
+	results := []models.MetricResult{}
+	for _, component := range []string{"argo-cd", "tekton"} {
+		if count, ok := counts[component]; ok {
+			results = append(results, models.MetricResult{Value: count, Labels: map[string]string{"component": component}})
+		}
+	}
+	return results, nil
+}
+
+func newTestService(t *testing.T, store storage.Store) *Service {
+	t.Helper()
+	cfg := &config.Metrics{HistoricalDays: 365}
+	svc := NewService(cfg, NewCollector(nil, cfg, store), store)
+	if err := svc.RegisterCalculator(releaseCountCalculator{}); err != nil {
+		t.Fatalf("RegisterCalculator() error = %v", err)
+	}
+	return svc
+}
+
+func TestService_SnapshotsAndTrend(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	err := store.SaveEntities(ctx, &storage.Entities{
+		Releases: []models.EnrichedRelease{
+			{ID: "1", Component: "argo-cd"},
+			{ID: "2", Component: "argo-cd"},
+			{ID: "3", Component: "argo-cd"},
+			{ID: "4", Component: "tekton"},
+		},
+		CollectedAt: time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("SaveEntities() error = %v", err)
+	}
+
+	svc := newTestService(t, store)
+	if err := svc.TakeSnapshot(ctx); err == nil {
+		t.Errorf("TakeSnapshot() before any data, want error")
+	}
+
+	// the collector restores the persisted data instead of crawling Jira
+	if err := svc.Collector().Restore(ctx); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if svc.Collector().ReleaseCount() != 4 {
+		t.Errorf("ReleaseCount() = %d, want 4", svc.Collector().ReleaseCount())
+	}
+
+	// previous values recorded a trend window ago
+	past := time.Now().Add(-svc.TrendWindow - time.Hour)
+	err = store.SaveSnapshots(ctx, []storage.Snapshot{
+		{Metric: "release_count", Value: 1, TakenAt: past},
+		{Metric: "release_count", Component: "argo-cd", Value: 2, TakenAt: past},
+	})
+	if err != nil {
+		t.Fatalf("SaveSnapshots() error = %v", err)
+	}
+
+	table := map[string]struct {
+		filters models.MetricFilters
+		trend   string
+	}{
+		"all components":  {trend: "+100%"},
+		"one component":   {filters: models.MetricFilters{Components: []string{"argo-cd"}}, trend: "+50%"},
+		"no snapshot":     {filters: models.MetricFilters{Components: []string{"tekton"}}},
+		"other dimension": {filters: models.MetricFilters{Pillars: []string{"devops"}}},
+	}
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			summary, err := svc.CalculateAllMetrics(ctx, tc.filters)
+			if err != nil {
+				t.Fatalf("CalculateAllMetrics() error = %v", err)
+			}
+			if trend := summary.Metrics["release_count"].Trend; trend != tc.trend {
+				t.Errorf("Trend = %q, want %q", trend, tc.trend)
+			}
+		})
+	}
+
+	if err := svc.TakeSnapshot(ctx); err != nil {
+		t.Fatalf("TakeSnapshot() error = %v", err)
+	}
+
+	history, err := svc.MetricHistory(ctx, "release_count", "", models.TimeRange{})
+	if err != nil {
+		t.Fatalf("MetricHistory() error = %v", err)
+	}
+	values := []float64{}
+	for _, s := range history {
+		values = append(values, s.Value)
+	}
+	if diff := cmp.Diff([]float64{1, 2}, values); diff != "" {
+		t.Errorf("MetricHistory() mismatch (-want +got):\n%s", diff)
+	}
+
+	history, err = svc.MetricHistory(ctx, "release_count", "tekton", models.TimeRange{})
+	if err != nil || len(history) != 1 || history[0].Value != 1 {
+		t.Errorf("MetricHistory() for tekton = %+v, %v", history, err)
+	}
+
+	if _, err := svc.MetricHistory(ctx, "unknown", "", models.TimeRange{}); err == nil {
+		t.Errorf("MetricHistory() of an unknown metric, want error")
+	}
+}
+
+func TestService_HistoryWithoutStorage(t *testing.T) {
+	svc := newTestService(t, nil)
+	if _, err := svc.MetricHistory(context.Background(), "release_count", "", models.TimeRange{}); !errors.Is(err, ErrStorageDisabled) {
+		t.Errorf("MetricHistory() error = %v, want %v", err, ErrStorageDisabled)
+	}
+}
+
+func TestFormatTrend(t *testing.T) {
+	table := map[string]struct {
+		previous, current float64
+		want              string
+	}{
+		"increase":      {previous: 10, current: 11.5, want: "+15%"},
+		"decrease":      {previous: 10, current: 9, want: "-10%"},
+		"unchanged":     {previous: 3, current: 3, want: "+0%"},
+		"negative base": {previous: -2, current: -1, want: "+50%"},
+		"zero base":     {previous: 0, current: 4, want: ""},
+	}
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			if got := formatTrend(tc.previous, tc.current); got != tc.want {
+				t.Errorf("formatTrend() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -63,6 +63,10 @@ type Epic struct {
 	Assignee       *User     `json:"assignee,omitempty"`
 	ResolutionDate time.Time `json:"resolution_date,omitempty"`
 	CreationDate   time.Time `json:"creation_date,omitempty"`
+	// BlockedBy are the keys of the epics blocking this epic
+	BlockedBy []string `json:"blocked_by,omitempty"`
+	// Blocks are the keys of the epics blocked by this epic
+	Blocks []string `json:"blocks,omitempty"`
 }
 
 // Issue represents a product requirement or set of stories
@@ -139,6 +143,7 @@ type AuthRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 	BaseURL  string `json:"base_url" binding:"required"`
+	Project  string `json:"project"`
 }
 
 // RoadmapData represents the complete roadmap data
@@ -247,6 +252,7 @@ func ConvertJiraIssueToEpic(issue *jira.Issue, milestoneID string) *Epic {
 		ResolutionDate: time.Time(issue.Fields.Resolutiondate),
 		CreationDate:   time.Time(issue.Fields.Created),
 	}
+	epic.BlockedBy, epic.Blocks = extractEpicDependenciesFromIssue(issue)
 
 	return epic
 }
@@ -408,6 +414,30 @@ func extractMilestoneIdsFromIssue(issue *jira.Issue) (milestoneIDs []string) {
 	return
 }
 
+// extractEpicDependenciesFromIssue extracts the keys of the epics linked by "Blocks" links
+// An outward issue is blocked by the issue, an inward issue blocks it
+func extractEpicDependenciesFromIssue(issue *jira.Issue) (blockedBy []string, blocks []string) {
+	if issue.Fields == nil {
+		return
+	}
+	for _, link := range issue.Fields.IssueLinks {
+		if link.Type.Name != "Blocks" {
+			continue
+		}
+		if link.InwardIssue != nil && isEpic(link.InwardIssue) {
+			blockedBy = append(blockedBy, link.InwardIssue.Key)
+		}
+		if link.OutwardIssue != nil && isEpic(link.OutwardIssue) {
+			blocks = append(blocks, link.OutwardIssue.Key)
+		}
+	}
+	return
+}
+
+func isEpic(issue *jira.Issue) bool {
+	return issue.Fields != nil && issue.Fields.Type.Name == "Epic"
+}
+
 // extractSequenceFromIssue extracts the sequence/rank from a Jira issue
 func extractSequenceFromIssue(issue *jira.Issue) int {
 	// Try to get from custom field first (common sequence field names)
@@ -568,6 +598,16 @@ func SortQuarters(quarters []string) {
 	})
 }
 
+// QuarterEnd returns the last day of a quarter (e.g., "2025Q1" -> 2025-03-31), false if the quarter is invalid
+func QuarterEnd(quarter string) (time.Time, bool) {
+	value := parseQuarter(quarter)
+	year, q := value/10, value%10
+	if len(quarter) != 6 || value == 0 || q < 1 || q > 4 {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(3*q+1), 0, 0, 0, 0, 0, time.UTC), true
+}
+
 // parseQuarter converts a quarter string (e.g., "2025Q1") to a comparable integer
 func parseQuarter(quarter string) int {
 	if len(quarter) < 6 {
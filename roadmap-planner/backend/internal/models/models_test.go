@@ -17,8 +17,11 @@ limitations under the License.
 package models
 
 import (
-	"github.com/google/go-cmp/cmp"
 	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestSortEpics(t *testing.T) {
@@ -77,3 +80,54 @@ func TestSortEpics(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertJiraIssueToEpic_Dependencies(t *testing.T) {
+	linked := func(key, issueType string) *jira.Issue {
+		return &jira.Issue{Key: key, Fields: &jira.IssueFields{Type: jira.IssueType{Name: issueType}}}
+	}
+	issue := &jira.Issue{
+		ID:  "100",
+		Key: "DEVOPS-100",
+		Fields: &jira.IssueFields{
+			IssueLinks: []*jira.IssueLink{
+				{Type: jira.IssueLinkType{Name: "Blocks"}, OutwardIssue: linked("DEVOPS-10", "Milestone")},
+				{Type: jira.IssueLinkType{Name: "Blocks"}, OutwardIssue: linked("DEVOPS-101", "Epic")},
+				{Type: jira.IssueLinkType{Name: "Blocks"}, InwardIssue: linked("DEVOPS-99", "Epic")},
+				{Type: jira.IssueLinkType{Name: "Blocks"}, InwardIssue: linked("DEVOPS-5", "Story")},
+				{Type: jira.IssueLinkType{Name: "Relates"}, InwardIssue: linked("DEVOPS-98", "Epic")},
+			},
+		},
+	}
+
+	epic := ConvertJiraIssueToEpic(issue, "")
+	if diff := cmp.Diff([]string{"DEVOPS-99"}, epic.BlockedBy); diff != "" {
+		t.Errorf("BlockedBy mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"DEVOPS-101"}, epic.Blocks); diff != "" {
+		t.Errorf("Blocks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQuarterEnd(t *testing.T) {
+	table := map[string]struct {
+		quarter string
+		want    time.Time
+		ok      bool
+	}{
+		"first quarter":  {quarter: "2025Q1", want: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), ok: true},
+		"second quarter": {quarter: "2024Q2", want: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), ok: true},
+		"last quarter":   {quarter: "2025Q4", want: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), ok: true},
+		"invalid":        {quarter: "2025Q5"},
+		"too long":       {quarter: "2025Q12"},
+		"empty":          {},
+	}
+
+	for name, tt := range table {
+		t.Run(name, func(t *testing.T) {
+			got, ok := QuarterEnd(tt.quarter)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("QuarterEnd(%q) = %v, %v, want %v, %v", tt.quarter, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roadmapio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// sheetName is the name of the XLSX sheet holding the roadmap
+const sheetName = "Roadmap"
+
+// Export writes the roadmap in the format
+func Export(w io.Writer, format Format, data models.RoadmapData) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, Flatten(data))
+	case FormatXLSX:
+		return writeXLSX(w, Flatten(data))
+	case FormatMarkdown:
+		return writeMarkdown(w, data)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+func writeCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row.values()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeXLSX(w io.Writer, rows []Row) error {
+	file := excelize.NewFile()
+	defer file.Close()
+	if err := file.SetSheetName(file.GetSheetName(0), sheetName); err != nil {
+		return err
+	}
+
+	stream, err := file.NewStreamWriter(sheetName)
+	if err != nil {
+		return err
+	}
+	if err := stream.SetRow("A1", cells(Columns)); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(cell, cells(row.values())); err != nil {
+			return err
+		}
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return file.Write(w)
+}
+
+func cells(values []string) []interface{} {
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return row
+}
+
+// writeMarkdown writes a heading per pillar and milestone and a table of epics per milestone
+func writeMarkdown(w io.Writer, data models.RoadmapData) error {
+	var b strings.Builder
+	b.WriteString("# Roadmap\n")
+	if len(data.Quarters) > 0 {
+		fmt.Fprintf(&b, "\nQuarters: %s\n", strings.Join(data.Quarters, ", "))
+	}
+
+	for _, pillar := range data.Pillars {
+		fmt.Fprintf(&b, "\n## %s\n", heading(pillar.Key, pillar.Name))
+		if len(pillar.Milestones) == 0 {
+			b.WriteString("\n_No milestones._\n")
+		}
+		for _, milestone := range pillar.Milestones {
+			fmt.Fprintf(&b, "\n### %s (%s)\n\n", heading(milestone.Key, milestone.Name), milestone.Quarter)
+			if len(milestone.Epics) == 0 {
+				b.WriteString("_No epics._\n")
+				continue
+			}
+			b.WriteString("| Epic | Components | Versions | Priority | Assignee | Status |\n")
+			b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+			for _, epic := range milestone.Epics {
+				assignee := ""
+				if epic.Assignee != nil {
+					assignee = epic.Assignee.DisplayName
+					if assignee == "" {
+						assignee = epic.Assignee.Name
+					}
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+					tableCell(heading(epic.Key, epic.Name)),
+					tableCell(strings.Join(epic.Components, ", ")),
+					tableCell(strings.Join(epic.Versions, ", ")),
+					tableCell(epic.Priority),
+					tableCell(assignee),
+					tableCell(epic.Status))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// heading returns "KEY Name", or the name alone without key
+func heading(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + " " + name
+}
+
+// tableCell escapes the characters breaking a Markdown table
+func tableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
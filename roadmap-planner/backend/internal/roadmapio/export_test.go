@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roadmapio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testRoadmap returns two pillars, the first with an epic moved between milestones
+func testRoadmap() models.RoadmapData {
+	return models.RoadmapData{
+		Quarters: []string{"2025Q1", "2025Q2"},
+		Pillars: []models.Pillar{
+			{
+				ID: "1", Key: "DEVOPS-1", Name: "Pipelines",
+				Milestones: []models.Milestone{
+					{
+						ID: "10", Key: "DEVOPS-10", Name: "Faster builds", Quarter: "2025Q1", PillarID: "1",
+						Epics: []models.Epic{
+							{ID: "100", Key: "DEVOPS-100", Name: "Build cache", Components: []string{"tekton"}, Versions: []string{"v1.0"}, MilestoneIDs: []string{"10"}, Priority: "High", Status: "Done", Assignee: &models.User{Name: "alice", DisplayName: "Alice"}},
+							{ID: "101", Key: "DEVOPS-101", Name: "Parallel | steps", Components: []string{"tekton", "ui"}, MilestoneIDs: []string{"10"}, Status: "Open"},
+						},
+					},
+					{ID: "11", Key: "DEVOPS-11", Name: "Release gates", Quarter: "2025Q2", PillarID: "1", Epics: []models.Epic{}},
+				},
+			},
+			{
+				ID: "2", Key: "DEVOPS-2", Name: "Security",
+				Milestones: []models.Milestone{
+					{
+						ID: "20", Key: "DEVOPS-20", Name: "Signed images", Quarter: "2025Q2", PillarID: "2",
+						Epics: []models.Epic{
+							{ID: "200", Key: "DEVOPS-200", Name: "Cosign", Components: []string{"chains"}, MilestoneIDs: []string{"20"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	tests := map[string]struct {
+		filter Filter
+		// want are the keys of the exported milestones and epics
+		want []string
+	}{
+		"no filter": {
+			want: []string{"DEVOPS-10", "DEVOPS-100", "DEVOPS-101", "DEVOPS-11", "DEVOPS-20", "DEVOPS-200"},
+		},
+		"quarter": {
+			filter: Filter{Quarters: []string{"2025Q2"}},
+			want:   []string{"DEVOPS-11", "DEVOPS-20", "DEVOPS-200"},
+		},
+		"pillar by id or key": {
+			filter: Filter{PillarIDs: []string{"DEVOPS-2"}},
+			want:   []string{"DEVOPS-20", "DEVOPS-200"},
+		},
+		"component drops milestones without matching epics": {
+			filter: Filter{Components: []string{"ui"}},
+			want:   []string{"DEVOPS-10", "DEVOPS-101"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := []string{}
+			for _, pillar := range tt.filter.Apply(testRoadmap()).Pillars {
+				for _, milestone := range pillar.Milestones {
+					got = append(got, milestone.Key)
+					for _, epic := range milestone.Epics {
+						got = append(got, epic.Key)
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatXLSX, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Export(&buf, format, testRoadmap()); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			rows, err := Read(&buf, format)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			want := Flatten(testRoadmap())
+			for i := range rows {
+				rows[i].Line = 0
+			}
+			if diff := cmp.Diff(want, rows); diff != "" {
+				t.Errorf("Read(Export()) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExport_ImportEpicOfSeveralMilestones(t *testing.T) {
+	data := testRoadmap()
+	// the build cache epic is linked to both milestones of the first pillar
+	epic := data.Pillars[0].Milestones[0].Epics[0]
+	epic.MilestoneIDs = []string{"10", "11"}
+	data.Pillars[0].Milestones[0].Epics[0] = epic
+	data.Pillars[0].Milestones[1].Epics = []models.Epic{epic}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatCSV, data); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	rows, err := Read(&buf, FormatCSV)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := &Plan{Changes: []Change{}, Errors: []RowError{}, Unchanged: 4}
+	if diff := cmp.Diff(want, NewPlan(rows, data, "")); diff != "" {
+		t.Errorf("NewPlan(Read(Export())) mismatch (-want +got):\n%s", diff)
+	}
+
+	// a row moving the epic conflicts with the rows keeping it
+	rows = append([]Row{{Line: 1, PillarKey: "DEVOPS-2", MilestoneKey: "DEVOPS-20", EpicKey: "DEVOPS-100"}}, rows...)
+	plan := NewPlan(rows, data, "")
+	if len(plan.Errors) != 2 || plan.Errors[0].Message != `epic "DEVOPS-100" is already imported by line 1` {
+		t.Errorf("NewPlan() errors = %+v, want the rows of the moved epic to be rejected", plan.Errors)
+	}
+}
+
+func TestExport_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatMarkdown, testRoadmap()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"## DEVOPS-1 Pipelines\n",
+		"### DEVOPS-10 Faster builds (2025Q1)\n",
+		"| DEVOPS-100 Build cache | tekton | v1.0 | High | Alice | Done |\n",
+		"| DEVOPS-101 Parallel \\| steps | tekton, ui |",
+		"### DEVOPS-11 Release gates (2025Q2)\n\n_No epics._\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Export() markdown does not contain %q:\n%s", want, got)
+		}
+	}
+
+	if _, err := Read(&buf, FormatMarkdown); err == nil {
+		t.Errorf("Read() markdown error = nil, want an error")
+	}
+}
+
+func TestRead_Headers(t *testing.T) {
+	tests := map[string]struct {
+		csv     string
+		want    []Row
+		wantErr bool
+	}{
+		"aliases, any order and blank lines": {
+			csv: "epic,Milestone,PILLAR,component\nCosign,Signed images,Security,chains\n,,,\n",
+			want: []Row{
+				{Line: 2, Pillar: "Security", Milestone: "Signed images", Epic: "Cosign", Components: "chains"},
+			},
+		},
+		"missing milestone column": {
+			csv:     "Pillar,Epic\nSecurity,Cosign\n",
+			wantErr: true,
+		},
+		"empty": {
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Read(strings.NewReader(tt.csv), FormatCSV)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); !tt.wantErr && diff != "" {
+				t.Errorf("Read() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roadmapio
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// Action is a change applied to Jira by an import
+type Action string
+
+const (
+	ActionCreateMilestone Action = "create_milestone"
+	ActionCreateEpic      Action = "create_epic"
+	ActionMoveEpic        Action = "move_epic"
+)
+
+// Change is a difference between an imported row and Jira
+type Change struct {
+	Action Action `json:"action"`
+	// Line is the line of the row in the sheet
+	Line     int    `json:"line"`
+	PillarID string `json:"pillar_id"`
+	// MilestoneID is the target milestone, empty when it is created by the same import
+	MilestoneID string `json:"milestone_id,omitempty"`
+	// MilestoneRef identifies a milestone created by the same import
+	MilestoneRef    string `json:"milestone_ref,omitempty"`
+	Milestone       string `json:"milestone"`
+	Quarter         string `json:"quarter,omitempty"`
+	FromMilestoneID string `json:"from_milestone_id,omitempty"`
+	EpicID          string `json:"epic_id,omitempty"`
+	EpicKey         string `json:"epic_key,omitempty"`
+	Epic            string `json:"epic,omitempty"`
+	Component       string `json:"component,omitempty"`
+	Version         string `json:"version,omitempty"`
+	Priority        string `json:"priority,omitempty"`
+	Assignee        string `json:"assignee,omitempty"`
+}
+
+// RowError is a row that cannot be imported
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Plan is the list of changes needed to make Jira match an imported sheet
+type Plan struct {
+	Changes []Change   `json:"changes"`
+	Errors  []RowError `json:"errors"`
+	// Unchanged is the number of rows already matching Jira
+	Unchanged int `json:"unchanged"`
+}
+
+// columnAliases maps the lower case headers accepted by Read to the columns
+var columnAliases = map[string]string{
+	"pillar key":    "Pillar Key",
+	"pillar":        "Pillar",
+	"milestone key": "Milestone Key",
+	"milestone":     "Milestone",
+	"quarter":       "Quarter",
+	"epic key":      "Epic Key",
+	"epic":          "Epic",
+	"components":    "Components",
+	"component":     "Components",
+	"versions":      "Versions",
+	"version":       "Versions",
+	"priority":      "Priority",
+	"assignee":      "Assignee",
+	"status":        "Status",
+}
+
+// Read parses the rows of a CSV, XLSX or JSON roadmap as written by Export
+// Markdown exports are meant to be read by humans and cannot be imported
+func Read(r io.Reader, format Format) ([]Row, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		return parseRecords(records)
+	case FormatXLSX:
+		file, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx: %w", err)
+		}
+		defer file.Close()
+		sheet := sheetName
+		if index, err := file.GetSheetIndex(sheet); err != nil || index < 0 {
+			sheet = file.GetSheetName(0)
+		}
+		records, err := file.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx sheet %s: %w", sheet, err)
+		}
+		return parseRecords(records)
+	case FormatJSON:
+		var data models.RoadmapData
+		if err := json.NewDecoder(r).Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to read json: %w", err)
+		}
+		rows := Flatten(data)
+		for i := range rows {
+			rows[i].Line = i + 1
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("format %q cannot be imported, use csv, xlsx or json", format)
+}
+
+// parseRecords maps the records of a sheet to rows using its header
+func parseRecords(records [][]string) ([]Row, error) {
+	if len(records) == 0 {
+		return nil, errors.New("the sheet is empty")
+	}
+
+	columns := map[string]int{}
+	for i, header := range records[0] {
+		if column, ok := columnAliases[strings.ToLower(strings.TrimSpace(header))]; ok {
+			columns[column] = i
+		}
+	}
+	_, hasPillar := columns["Pillar"]
+	_, hasPillarKey := columns["Pillar Key"]
+	_, hasMilestone := columns["Milestone"]
+	if !hasPillar && !hasPillarKey || !hasMilestone {
+		return nil, errors.New("the sheet needs a Pillar or Pillar Key column and a Milestone column")
+	}
+
+	rows := make([]Row, 0, len(records)-1)
+	for i, record := range records[1:] {
+		cell := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		row := Row{
+			Line:         i + 2,
+			PillarKey:    cell("Pillar Key"),
+			Pillar:       cell("Pillar"),
+			MilestoneKey: cell("Milestone Key"),
+			Milestone:    cell("Milestone"),
+			Quarter:      cell("Quarter"),
+			EpicKey:      cell("Epic Key"),
+			Epic:         cell("Epic"),
+			Components:   cell("Components"),
+			Versions:     cell("Versions"),
+			Priority:     cell("Priority"),
+			Assignee:     cell("Assignee"),
+			Status:       cell("Status"),
+		}
+		if !row.empty() {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// roadmapIndex looks up the pillars, milestones and epics of the current roadmap
+type roadmapIndex struct {
+	pillarsByKey  map[string]*models.Pillar
+	pillarsByName map[string]*models.Pillar
+	milestones    map[string]*models.Milestone
+	// milestonesByName are keyed by pillar ID and lower case name
+	milestonesByName map[string]*models.Milestone
+	epics            map[string]*models.Epic
+	epicsByName      map[string][]*models.Epic
+}
+
+func newRoadmapIndex(data models.RoadmapData) *roadmapIndex {
+	index := &roadmapIndex{
+		pillarsByKey:     map[string]*models.Pillar{},
+		pillarsByName:    map[string]*models.Pillar{},
+		milestones:       map[string]*models.Milestone{},
+		milestonesByName: map[string]*models.Milestone{},
+		epics:            map[string]*models.Epic{},
+		epicsByName:      map[string][]*models.Epic{},
+	}
+	for i := range data.Pillars {
+		pillar := &data.Pillars[i]
+		index.pillarsByKey[strings.ToUpper(pillar.Key)] = pillar
+		index.pillarsByName[strings.ToLower(pillar.Name)] = pillar
+		for j := range pillar.Milestones {
+			milestone := &pillar.Milestones[j]
+			index.milestones[strings.ToUpper(milestone.Key)] = milestone
+			index.milestonesByName[milestoneRef(pillar.ID, milestone.Name)] = milestone
+			for k := range milestone.Epics {
+				epic := &milestone.Epics[k]
+				// epics linked to several milestones are listed once per milestone
+				if _, ok := index.epics[strings.ToUpper(epic.Key)]; ok {
+					continue
+				}
+				index.epics[strings.ToUpper(epic.Key)] = epic
+				name := strings.ToLower(epic.Name)
+				index.epicsByName[name] = append(index.epicsByName[name], epic)
+			}
+		}
+	}
+	return index
+}
+
+// milestoneRef identifies a milestone by pillar and name
+func milestoneRef(pillarID, name string) string {
+	return pillarID + "/" + strings.ToLower(name)
+}
+
+// NewPlan compares the rows with the current roadmap and returns the changes to apply
+// Rows without epic key are matched to epics by name, unknown epics and milestones are created.
+// Created issues are assigned to the assignee of the row, or to defaultAssignee
+func NewPlan(rows []Row, current models.RoadmapData, defaultAssignee string) *Plan {
+	index := newRoadmapIndex(current)
+	plan := &Plan{Changes: []Change{}, Errors: []RowError{}}
+	created := map[string]bool{}
+	seenEpics := map[string]int{}
+	// movedEpics are the epics created or moved by a previous row
+	movedEpics := map[string]bool{}
+
+	// commit adds the changes of a row to the plan
+	commit := func(changes []Change) {
+		if len(changes) == 0 {
+			plan.Unchanged++
+		}
+		for _, change := range changes {
+			if change.Action == ActionCreateMilestone {
+				created[change.MilestoneRef] = true
+			}
+		}
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	for _, row := range rows {
+		fail := func(format string, args ...interface{}) {
+			plan.Errors = append(plan.Errors, RowError{Line: row.Line, Message: fmt.Sprintf(format, args...)})
+		}
+
+		pillar := index.pillarsByKey[strings.ToUpper(row.PillarKey)]
+		if pillar == nil && row.PillarKey == "" {
+			pillar = index.pillarsByName[strings.ToLower(row.Pillar)]
+		}
+		if pillar == nil {
+			fail("unknown pillar %q", firstNonEmpty(row.PillarKey, row.Pillar))
+			continue
+		}
+		if row.Milestone == "" && row.MilestoneKey == "" {
+			if row.Epic != "" || row.EpicKey != "" {
+				fail("epic %q has no milestone", firstNonEmpty(row.EpicKey, row.Epic))
+				continue
+			}
+			plan.Unchanged++
+			continue
+		}
+
+		assignee := firstNonEmpty(row.Assignee, defaultAssignee)
+		changes := []Change{}
+
+		// resolve the target milestone, creating it when unknown
+		target := Change{Line: row.Line, PillarID: pillar.ID, Milestone: row.Milestone, Quarter: row.Quarter}
+		if row.MilestoneKey != "" {
+			milestone := index.milestones[strings.ToUpper(row.MilestoneKey)]
+			if milestone == nil {
+				fail("unknown milestone %q", row.MilestoneKey)
+				continue
+			}
+			if milestone.PillarID != pillar.ID {
+				fail("milestone %s does not belong to pillar %s", milestone.Key, pillar.Key)
+				continue
+			}
+			target.MilestoneID, target.Milestone = milestone.ID, milestone.Name
+		} else if milestone := index.milestonesByName[milestoneRef(pillar.ID, row.Milestone)]; milestone != nil {
+			target.MilestoneID, target.Milestone = milestone.ID, milestone.Name
+		} else {
+			ref := milestoneRef(pillar.ID, row.Milestone)
+			target.MilestoneRef = ref
+			if !created[ref] {
+				if err := (&models.CreateMilestoneRequest{Quarter: row.Quarter}).Validate(); err != nil {
+					fail("milestone %q: %s", row.Milestone, err)
+					continue
+				}
+				if assignee == "" {
+					fail("milestone %q has no assignee", row.Milestone)
+					continue
+				}
+				create := target
+				create.Action = ActionCreateMilestone
+				create.Assignee = assignee
+				changes = append(changes, create)
+			}
+		}
+
+		if row.Epic == "" && row.EpicKey == "" {
+			commit(changes)
+			continue
+		}
+
+		// resolve the epic, matching by name when there is no key
+		epic := index.epics[strings.ToUpper(row.EpicKey)]
+		if row.EpicKey != "" && epic == nil {
+			fail("unknown epic %q", row.EpicKey)
+			continue
+		}
+		if epic == nil {
+			switch matches := index.epicsByName[strings.ToLower(row.Epic)]; len(matches) {
+			case 0:
+			case 1:
+				epic = matches[0]
+			default:
+				fail("epic name %q matches %d epics, set the epic key", row.Epic, len(matches))
+				continue
+			}
+		}
+		epicID := firstNonEmpty(row.EpicKey, strings.ToLower(row.Epic))
+		if epic != nil {
+			epicID = epic.ID
+		}
+		linked := epic != nil && target.MilestoneID != "" && slices.Contains(epic.MilestoneIDs, target.MilestoneID)
+		if line, ok := seenEpics[epicID]; ok {
+			// the export has a row per milestone of an epic, they are kept unless a previous row moves the epic
+			if linked && !movedEpics[epicID] {
+				commit(changes)
+				continue
+			}
+			fail("epic %q is already imported by line %d", firstNonEmpty(row.EpicKey, row.Epic), line)
+			continue
+		}
+		seenEpics[epicID] = row.Line
+		movedEpics[epicID] = !linked
+
+		switch {
+		case epic == nil:
+			if assignee == "" {
+				fail("epic %q has no assignee", row.Epic)
+				continue
+			}
+			change := target
+			change.Action = ActionCreateEpic
+			change.Epic = row.Epic
+			change.Component = firstOf(splitList(row.Components))
+			change.Version = firstOf(splitList(row.Versions))
+			change.Priority = row.Priority
+			change.Assignee = assignee
+			changes = append(changes, change)
+		case !linked:
+			change := target
+			change.Action = ActionMoveEpic
+			change.EpicID, change.EpicKey, change.Epic = epic.ID, epic.Key, epic.Name
+			change.FromMilestoneID = firstOf(epic.MilestoneIDs)
+			changes = append(changes, change)
+		}
+
+		commit(changes)
+	}
+	return plan
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Writer applies changes to Jira, implemented by the Jira client
+type Writer interface {
+	CreateMilestone(ctx context.Context, req models.CreateMilestoneRequest) (*models.Milestone, error)
+	CreateEpic(ctx context.Context, req models.CreateEpicRequest) (*models.Epic, error)
+	UpdateEpicMilestone(ctx context.Context, epicID, newMilestoneID string) error
+}
+
+// Result is the outcome of an applied change
+type Result struct {
+	Change
+	// Key is the key of the created milestone or epic
+	Key   string `json:"key,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Apply applies the changes of a plan in order and returns their results
+// Failed changes do not stop the import, changes depending on a milestone that failed to be created fail too
+func Apply(ctx context.Context, writer Writer, plan *Plan) []Result {
+	results := make([]Result, 0, len(plan.Changes))
+	createdMilestones := map[string]string{}
+
+	for _, change := range plan.Changes {
+		result := Result{Change: change}
+		milestoneID := change.MilestoneID
+		if milestoneID == "" && change.Action != ActionCreateMilestone {
+			milestoneID = createdMilestones[change.MilestoneRef]
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			result.Error = ctx.Err().Error()
+		case change.Action == ActionCreateMilestone:
+			milestone, err := writer.CreateMilestone(ctx, models.CreateMilestoneRequest{
+				Name:     change.Milestone,
+				Quarter:  change.Quarter,
+				PillarID: change.PillarID,
+				Assignee: models.User{Name: change.Assignee},
+			})
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+			createdMilestones[change.MilestoneRef] = milestone.ID
+			result.MilestoneID, result.Key = milestone.ID, milestone.Key
+		case milestoneID == "":
+			result.Error = fmt.Sprintf("milestone %q was not created", change.Milestone)
+		case change.Action == ActionCreateEpic:
+			epic, err := writer.CreateEpic(ctx, models.CreateEpicRequest{
+				Name:        change.Epic,
+				Component:   change.Component,
+				Version:     change.Version,
+				MilestoneID: milestoneID,
+				Priority:    change.Priority,
+				Assignee:    models.User{Name: change.Assignee},
+			})
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+			result.MilestoneID, result.EpicID, result.Key = milestoneID, epic.ID, epic.Key
+		case change.Action == ActionMoveEpic:
+			if err := writer.UpdateEpicMilestone(ctx, change.EpicID, milestoneID); err != nil {
+				result.Error = err.Error()
+				break
+			}
+			result.MilestoneID = milestoneID
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roadmapio
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewPlan(t *testing.T) {
+	tests := map[string]struct {
+		rows []Row
+		want *Plan
+	}{
+		"exported roadmap is unchanged": {
+			rows: Flatten(testRoadmap()),
+			want: &Plan{Changes: []Change{}, Errors: []RowError{}, Unchanged: 4},
+		},
+		"moves an epic by key": {
+			rows: []Row{{Line: 2, PillarKey: "DEVOPS-1", MilestoneKey: "DEVOPS-11", EpicKey: "devops-100"}},
+			want: &Plan{
+				Changes: []Change{
+					{Action: ActionMoveEpic, Line: 2, PillarID: "1", MilestoneID: "11", Milestone: "Release gates", FromMilestoneID: "10", EpicID: "100", EpicKey: "DEVOPS-100", Epic: "Build cache"},
+				},
+				Errors: []RowError{},
+			},
+		},
+		"creates a milestone once and its epics": {
+			rows: []Row{
+				{Line: 2, Pillar: "security", Milestone: "SBOM", Quarter: "2025Q3", Epic: "Syft scans", Components: "chains, ui", Versions: "v2.0", Assignee: "bob"},
+				{Line: 3, Pillar: "Security", Milestone: "sbom", Quarter: "2025Q3", Epic: "Cosign"},
+			},
+			want: &Plan{
+				Changes: []Change{
+					{Action: ActionCreateMilestone, Line: 2, PillarID: "2", MilestoneRef: "2/sbom", Milestone: "SBOM", Quarter: "2025Q3", Assignee: "bob"},
+					{Action: ActionCreateEpic, Line: 2, PillarID: "2", MilestoneRef: "2/sbom", Milestone: "SBOM", Quarter: "2025Q3", Epic: "Syft scans", Component: "chains", Version: "v2.0", Assignee: "bob"},
+					{Action: ActionMoveEpic, Line: 3, PillarID: "2", MilestoneRef: "2/sbom", Milestone: "sbom", Quarter: "2025Q3", FromMilestoneID: "20", EpicID: "200", EpicKey: "DEVOPS-200", Epic: "Cosign"},
+				},
+				Errors: []RowError{},
+			},
+		},
+		"reports invalid rows": {
+			rows: []Row{
+				{Line: 2, Pillar: "Unknown", Milestone: "Faster builds"},
+				{Line: 3, PillarKey: "DEVOPS-1", MilestoneKey: "DEVOPS-20", Epic: "Cosign"},
+				{Line: 4, PillarKey: "DEVOPS-1", Milestone: "New", Quarter: "Q3"},
+				{Line: 5, PillarKey: "DEVOPS-1", Milestone: "Faster builds", EpicKey: "DEVOPS-999"},
+				{Line: 6, PillarKey: "DEVOPS-1", Milestone: "Faster builds", Epic: "Build cache"},
+				{Line: 7, PillarKey: "DEVOPS-1", Milestone: "Release gates", Epic: "build cache"},
+				{Line: 8, PillarKey: "DEVOPS-1", Epic: "Orphan"},
+			},
+			want: &Plan{
+				Changes: []Change{},
+				Errors: []RowError{
+					{Line: 2, Message: `unknown pillar "Unknown"`},
+					{Line: 3, Message: "milestone DEVOPS-20 does not belong to pillar DEVOPS-1"},
+					{Line: 4, Message: `milestone "New": invalid quarter format, expected YYYYQX (e.g., 2025Q1)`},
+					{Line: 5, Message: `unknown epic "DEVOPS-999"`},
+					{Line: 7, Message: `epic "build cache" is already imported by line 6`},
+					{Line: 8, Message: `epic "Orphan" has no milestone`},
+				},
+				Unchanged: 1,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewPlan(tt.rows, testRoadmap(), "importer")
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NewPlan() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewPlan_DefaultAssignee(t *testing.T) {
+	rows := []Row{{Line: 2, PillarKey: "DEVOPS-1", MilestoneKey: "DEVOPS-11", Epic: "Dashboards"}}
+	plan := NewPlan(rows, testRoadmap(), "importer")
+	if len(plan.Changes) != 1 || plan.Changes[0].Assignee != "importer" {
+		t.Errorf("NewPlan() changes = %+v, want one epic assigned to the importer", plan.Changes)
+	}
+
+	plan = NewPlan(rows, testRoadmap(), "")
+	if diff := cmp.Diff([]RowError{{Line: 2, Message: `epic "Dashboards" has no assignee`}}, plan.Errors); diff != "" {
+		t.Errorf("NewPlan() errors mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// fakeWriter records the applied changes
+type fakeWriter struct {
+	calls          []string
+	failMilestones bool
+}
+
+func (w *fakeWriter) CreateMilestone(ctx context.Context, req models.CreateMilestoneRequest) (*models.Milestone, error) {
+	w.calls = append(w.calls, "milestone "+req.Name+" "+req.Quarter+" "+req.PillarID+" "+req.Assignee.Name)
+	if w.failMilestones {
+		return nil, errors.New("jira is down")
+	}
+	return &models.Milestone{ID: "30", Key: "DEVOPS-30", Name: req.Name}, nil
+}
+
+func (w *fakeWriter) CreateEpic(ctx context.Context, req models.CreateEpicRequest) (*models.Epic, error) {
+	w.calls = append(w.calls, "epic "+req.Name+" "+req.MilestoneID+" "+req.Component+" "+req.Version+" "+req.Assignee.Name)
+	return &models.Epic{ID: "300", Key: "DEVOPS-300", Name: req.Name}, nil
+}
+
+func (w *fakeWriter) UpdateEpicMilestone(ctx context.Context, epicID, newMilestoneID string) error {
+	w.calls = append(w.calls, "move "+epicID+" "+newMilestoneID)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	rows := []Row{
+		{Line: 2, PillarKey: "DEVOPS-2", Milestone: "SBOM", Quarter: "2025Q3", Epic: "Syft scans", Components: "chains", Assignee: "bob"},
+		{Line: 3, PillarKey: "DEVOPS-2", Milestone: "SBOM", Quarter: "2025Q3", EpicKey: "DEVOPS-200"},
+		{Line: 4, PillarKey: "DEVOPS-1", MilestoneKey: "DEVOPS-11", EpicKey: "DEVOPS-101"},
+	}
+
+	tests := map[string]struct {
+		failMilestones bool
+		wantCalls      []string
+		wantErrors     []string
+	}{
+		"applies in order with the created milestone": {
+			wantCalls:  []string{"milestone SBOM 2025Q3 2 bob", "epic Syft scans 30 chains  bob", "move 200 30", "move 101 11"},
+			wantErrors: []string{"", "", "", ""},
+		},
+		"changes depending on a failed milestone fail": {
+			failMilestones: true,
+			wantCalls:      []string{"milestone SBOM 2025Q3 2 bob", "move 101 11"},
+			wantErrors:     []string{"jira is down", `milestone "SBOM" was not created`, `milestone "SBOM" was not created`, ""},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			plan := NewPlan(rows, testRoadmap(), "importer")
+			writer := &fakeWriter{failMilestones: tt.failMilestones}
+			results := Apply(context.Background(), writer, plan)
+
+			if diff := cmp.Diff(tt.wantCalls, writer.calls); diff != "" {
+				t.Errorf("Apply() calls mismatch (-want +got):\n%s", diff)
+			}
+			gotErrors := []string{}
+			for _, result := range results {
+				gotErrors = append(gotErrors, result.Error)
+			}
+			if diff := cmp.Diff(tt.wantErrors, gotErrors); diff != "" {
+				t.Errorf("Apply() errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
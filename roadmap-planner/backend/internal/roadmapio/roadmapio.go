@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package roadmapio exports the roadmap to files and imports roadmap sheets back into Jira
+package roadmapio
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/models"
+)
+
+// Format is a file format of the roadmap
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatXLSX     Format = "xlsx"
+	FormatMarkdown Format = "md"
+	FormatJSON     Format = "json"
+)
+
+// ParseFormat returns the format of a name such as "csv" or "markdown"
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "csv":
+		return FormatCSV, nil
+	case "xlsx", "excel":
+		return FormatXLSX, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected csv, xlsx, md or json", name)
+}
+
+// FormatOfFile returns the format of a file name from its extension
+func FormatOfFile(filename string) (Format, error) {
+	return ParseFormat(filepath.Ext(filename))
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Columns are the header of the CSV and XLSX sheets, one row per epic
+var Columns = []string{"Pillar Key", "Pillar", "Milestone Key", "Milestone", "Quarter", "Epic Key", "Epic", "Components", "Versions", "Priority", "Assignee", "Status"}
+
+// Row is a line of a roadmap sheet
+// Milestones without epics have a row with empty epic columns
+type Row struct {
+	// Line is the line of the row in the sheet, the header is line 1
+	Line         int    `json:"line,omitempty"`
+	PillarKey    string `json:"pillar_key"`
+	Pillar       string `json:"pillar"`
+	MilestoneKey string `json:"milestone_key"`
+	Milestone    string `json:"milestone"`
+	Quarter      string `json:"quarter"`
+	EpicKey      string `json:"epic_key"`
+	Epic         string `json:"epic"`
+	Components   string `json:"components"`
+	Versions     string `json:"versions"`
+	Priority     string `json:"priority"`
+	Assignee     string `json:"assignee"`
+	Status       string `json:"status"`
+}
+
+// values returns the cells of the row in the order of Columns
+func (r Row) values() []string {
+	return []string{r.PillarKey, r.Pillar, r.MilestoneKey, r.Milestone, r.Quarter, r.EpicKey, r.Epic, r.Components, r.Versions, r.Priority, r.Assignee, r.Status}
+}
+
+// empty returns true if the row has no cell set
+func (r Row) empty() bool {
+	return strings.Join(r.values(), "") == ""
+}
+
+// Flatten returns the rows of a roadmap
+func Flatten(data models.RoadmapData) []Row {
+	rows := []Row{}
+	for _, pillar := range data.Pillars {
+		if len(pillar.Milestones) == 0 {
+			rows = append(rows, Row{PillarKey: pillar.Key, Pillar: pillar.Name})
+			continue
+		}
+		for _, milestone := range pillar.Milestones {
+			base := Row{
+				PillarKey:    pillar.Key,
+				Pillar:       pillar.Name,
+				MilestoneKey: milestone.Key,
+				Milestone:    milestone.Name,
+				Quarter:      milestone.Quarter,
+			}
+			if len(milestone.Epics) == 0 {
+				base.Status = milestone.Status
+				rows = append(rows, base)
+				continue
+			}
+			for _, epic := range milestone.Epics {
+				row := base
+				row.EpicKey = epic.Key
+				row.Epic = epic.Name
+				row.Components = strings.Join(epic.Components, ", ")
+				row.Versions = strings.Join(epic.Versions, ", ")
+				row.Priority = epic.Priority
+				row.Status = epic.Status
+				if epic.Assignee != nil {
+					row.Assignee = epic.Assignee.Name
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// Filter selects the part of the roadmap to export, empty fields select everything
+type Filter struct {
+	Quarters []string
+	// PillarIDs match the ID or the key of the pillars
+	PillarIDs  []string
+	Components []string
+}
+
+// Apply returns the pillars, milestones and epics of the roadmap selected by the filter
+// Milestones left without epics by the component filter are removed
+func (f Filter) Apply(data models.RoadmapData) models.RoadmapData {
+	filtered := models.RoadmapData{Quarters: data.Quarters, Pillars: []models.Pillar{}}
+	if len(f.Quarters) > 0 {
+		filtered.Quarters = f.Quarters
+	}
+
+	for _, pillar := range data.Pillars {
+		if len(f.PillarIDs) > 0 && !slices.Contains(f.PillarIDs, pillar.ID) && !slices.Contains(f.PillarIDs, pillar.Key) {
+			continue
+		}
+		milestones := []models.Milestone{}
+		for _, milestone := range pillar.Milestones {
+			if len(f.Quarters) > 0 && !slices.Contains(f.Quarters, milestone.Quarter) {
+				continue
+			}
+			if len(f.Components) > 0 {
+				epics := []models.Epic{}
+				for _, epic := range milestone.Epics {
+					if containsAny(epic.Components, f.Components) {
+						epics = append(epics, epic)
+					}
+				}
+				if len(epics) == 0 {
+					continue
+				}
+				milestone.Epics = epics
+			}
+			milestones = append(milestones, milestone)
+		}
+		if len(milestones) == 0 && (len(f.Quarters) > 0 || len(f.Components) > 0) {
+			continue
+		}
+		pillar.Milestones = milestones
+		filtered.Pillars = append(filtered.Pillars, pillar)
+	}
+	return filtered
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, value := range values {
+		if slices.Contains(wanted, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma separated cell
+func splitList(cell string) []string {
+	values := []string{}
+	for _, value := range strings.Split(cell, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
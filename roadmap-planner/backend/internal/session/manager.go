@@ -0,0 +1,360 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/logger"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidToken is returned for malformed, tampered or expired session tokens
+var ErrInvalidToken = errors.New("invalid session token")
+
+// ErrNoServiceAccount is returned when a delegated session is used without a configured service account
+var ErrNoServiceAccount = errors.New("no jira service account configured")
+
+const (
+	defaultTTL = 12 * time.Hour
+	// cleanupInterval is how often expired sessions are removed
+	cleanupInterval = 5 * time.Minute
+)
+
+// Manager creates and validates sessions, and pools a Jira client per session
+type Manager struct {
+	store   Store
+	ttl     time.Duration
+	signKey []byte
+	aead    cipher.AEAD
+	logger  *zap.Logger
+	now     func() time.Time
+	// newClient creates the Jira clients of the sessions
+	newClient func(baseURL, username, password, project string) (*jira.Client, error)
+
+	// serviceAccount is used by delegated sessions
+	serviceAccount *config.Jira
+
+	mu      sync.Mutex
+	clients map[clientKey]*jira.Client
+}
+
+// clientKey identifies a pooled client, sessions can switch between projects
+type clientKey struct {
+	sessionID string
+	project   string
+}
+
+// NewManager creates a session manager storing sessions in store
+// A random secret is generated when none is configured, sessions are then invalidated by restarts
+func NewManager(cfg *config.Auth, store Store) (*Manager, error) {
+	managerLogger := logger.WithComponent("session-manager")
+
+	secret := []byte(cfg.SessionSecret)
+	if len(secret) == 0 {
+		managerLogger.Warn("No session secret configured, sessions will not survive restarts")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate session secret: %w", err)
+		}
+	}
+
+	// derive separate keys to sign tokens and to encrypt Jira tokens
+	block, err := aes.NewCipher(deriveKey(secret, "encryption"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session cipher: %w", err)
+	}
+
+	ttl := defaultTTL
+	if cfg.SessionTTL != "" {
+		if ttl, err = time.ParseDuration(cfg.SessionTTL); err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid session ttl %q", cfg.SessionTTL)
+		}
+	}
+
+	return &Manager{
+		store:     store,
+		ttl:       ttl,
+		signKey:   deriveKey(secret, "signing"),
+		aead:      aead,
+		logger:    managerLogger,
+		now:       time.Now,
+		newClient: jira.NewClient,
+		clients:   map[clientKey]*jira.Client{},
+	}, nil
+}
+
+// deriveKey derives a 32 bytes key for a purpose from the secret
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("roadmap-planner session " + purpose))
+	return mac.Sum(nil)
+}
+
+// TTL returns the lifetime of the sessions
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+// UseServiceAccount sets the Jira account used by delegated sessions
+func (m *Manager) UseServiceAccount(account *config.Jira) {
+	m.serviceAccount = account
+}
+
+// Create stores a new session for the user and returns its signed token
+// jiraToken is the Jira password or API token, it is stored encrypted
+func (m *Manager) Create(ctx context.Context, username, jiraToken, baseURL, project string) (string, *Session, error) {
+	encrypted, err := m.encrypt(jiraToken)
+	if err != nil {
+		return "", nil, err
+	}
+	return m.save(ctx, &Session{
+		Username:       username,
+		BaseURL:        baseURL,
+		Project:        project,
+		EncryptedToken: encrypted,
+	})
+}
+
+// CreateDelegated stores a new single sign-on session for a Jira user and returns its signed token
+// The session uses the service account, its writes are attributed to username
+func (m *Manager) CreateDelegated(ctx context.Context, username, project string) (string, *Session, error) {
+	if m.serviceAccount == nil {
+		return "", nil, ErrNoServiceAccount
+	}
+	if project == "" {
+		project = m.serviceAccount.Project
+	}
+	return m.save(ctx, &Session{
+		Username:  username,
+		BaseURL:   m.serviceAccount.BaseURL,
+		Project:   project,
+		Delegated: true,
+	})
+}
+
+// save assigns an id and lifetime to a session, stores it and returns its signed token
+func (m *Manager) save(ctx context.Context, session *Session) (string, *Session, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	now := m.now()
+	session.ID = base64.RawURLEncoding.EncodeToString(id)
+	session.CreatedAt = now
+	session.ExpiresAt = now.Add(m.ttl)
+	if err := m.store.Save(ctx, session); err != nil {
+		return "", nil, fmt.Errorf("failed to save session: %w", err)
+	}
+
+	m.logger.Info("Session created",
+		zap.String("username", session.Username),
+		zap.Bool("delegated", session.Delegated),
+		zap.Time("expires_at", session.ExpiresAt))
+	return m.sign(session.ID, session.ExpiresAt), session, nil
+}
+
+// Authenticate returns the session of a signed token
+func (m *Manager) Authenticate(ctx context.Context, token string) (*Session, error) {
+	id, expiresAt, err := m.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if !m.now().Before(expiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	session, err := m.store.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.Expired(m.now()) {
+		return nil, ErrInvalidToken
+	}
+	return session, nil
+}
+
+// Revoke deletes the session of a signed token and its pooled clients
+func (m *Manager) Revoke(ctx context.Context, token string) error {
+	id, _, err := m.verify(token)
+	if err != nil {
+		return err
+	}
+	if err := m.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	m.dropClients(id)
+	m.logger.Info("Session revoked")
+	return nil
+}
+
+// Client returns the pooled Jira client of a session for a project
+// The client is created with the decrypted Jira token of the session on first use,
+// delegated sessions get a service account client acting on behalf of the user
+func (m *Manager) Client(session *Session, project string) (*jira.Client, error) {
+	if project == "" {
+		project = session.Project
+	}
+	key := clientKey{sessionID: session.ID, project: project}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if client, ok := m.clients[key]; ok {
+		return client, nil
+	}
+
+	client, err := m.createClient(session, project)
+	if err != nil {
+		return nil, err
+	}
+	m.clients[key] = client
+	return client, nil
+}
+
+// createClient creates the Jira client of a session for a project
+func (m *Manager) createClient(session *Session, project string) (*jira.Client, error) {
+	if session.Delegated {
+		if m.serviceAccount == nil {
+			return nil, ErrNoServiceAccount
+		}
+		client, err := m.newClient(m.serviceAccount.BaseURL, m.serviceAccount.Username, m.serviceAccount.Password, project)
+		if err != nil {
+			return nil, err
+		}
+		return client.WithActor(session.Username), nil
+	}
+
+	jiraToken, err := m.decrypt(session.EncryptedToken)
+	if err != nil {
+		return nil, err
+	}
+	return m.newClient(session.BaseURL, session.Username, jiraToken, project)
+}
+
+// Start removes expired sessions and their clients until the context is cancelled
+func (m *Manager) Start(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			m.Cleanup(ctx)
+		}
+	}
+}
+
+// Cleanup removes expired sessions and their clients
+func (m *Manager) Cleanup(ctx context.Context) {
+	expired, err := m.store.DeleteExpired(ctx, m.now())
+	if err != nil {
+		m.logger.Warn("Failed to delete expired sessions", zap.Error(err))
+		return
+	}
+	for _, id := range expired {
+		m.dropClients(id)
+	}
+	if len(expired) > 0 {
+		m.logger.Debug("Deleted expired sessions", zap.Int("count", len(expired)))
+	}
+}
+
+// dropClients removes the pooled clients of a session
+func (m *Manager) dropClients(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.clients {
+		if key.sessionID == sessionID {
+			delete(m.clients, key)
+		}
+	}
+}
+
+// sign returns the token of a session: id.expiry.signature
+func (m *Manager) sign(id string, expiresAt time.Time) string {
+	payload := id + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(m.mac(payload))
+}
+
+// verify checks the signature of a token and returns its session id and expiry
+func (m *Manager) verify(token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, m.mac(parts[0]+"."+parts[1])) {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	return parts[0], time.Unix(expiry, 0), nil
+}
+
+func (m *Manager) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, m.signKey)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// encrypt encrypts a Jira token, the nonce is prepended to the ciphertext
+func (m *Manager) encrypt(plaintext string) ([]byte, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return m.aead.Seal(nonce, nonce, []byte(plaintext), nil), nil
+}
+
+// decrypt decrypts a Jira token encrypted by encrypt
+func (m *Manager) decrypt(ciphertext []byte) (string, error) {
+	if len(ciphertext) < m.aead.NonceSize() {
+		return "", errors.New("invalid encrypted token")
+	}
+	nonce, sealed := ciphertext[:m.aead.NonceSize()], ciphertext[m.aead.NonceSize():]
+	plaintext, err := m.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt token: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/jira"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+)
+
+// newTestManager returns a manager with a controllable clock counting the created clients
+func newTestManager(t *testing.T, secret string) (*Manager, *time.Time, *[]string) {
+	t.Helper()
+	m, err := NewManager(&config.Auth{SessionSecret: secret, SessionTTL: "1h"}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	created := []string{}
+	m.newClient = func(baseURL, username, password, project string) (*jira.Client, error) {
+		created = append(created, username+":"+password+"@"+project)
+		return jira.NewClient(baseURL, username, password, project)
+	}
+	return m, &now, &created
+}
+
+func TestManager_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	m, _, created := newTestManager(t, "secret")
+
+	token, session, err := m.Create(ctx, "alice", "api-token", "https://jira.example.com", "DEVOPS")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if bytes.Contains(session.EncryptedToken, []byte("api-token")) {
+		t.Errorf("Create() stored the Jira token in clear text")
+	}
+	if strings.Contains(token, "api-token") || strings.Contains(token, "alice") {
+		t.Errorf("Create() token %q leaks credentials", token)
+	}
+
+	got, err := m.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got.Username != "alice" || got.Project != "DEVOPS" {
+		t.Errorf("Authenticate() = %+v", got)
+	}
+
+	// clients are pooled per session and project
+	first, err := m.Client(got, "")
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+	second, _ := m.Client(got, "DEVOPS")
+	other, _ := m.Client(got, "OTHER")
+	if first != second || first == other {
+		t.Errorf("Client() pooling mismatch: first=%p second=%p other=%p", first, second, other)
+	}
+	if want := []string{"alice:api-token@DEVOPS", "alice:api-token@OTHER"}; strings.Join(*created, ",") != strings.Join(want, ",") {
+		t.Errorf("created clients = %v, want %v", *created, want)
+	}
+
+	// revoked sessions are rejected and their clients dropped
+	if err := m.Revoke(ctx, token); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := m.Authenticate(ctx, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() after Revoke() error = %v, want %v", err, ErrInvalidToken)
+	}
+	if len(m.clients) != 0 {
+		t.Errorf("pooled clients after Revoke() = %d, want 0", len(m.clients))
+	}
+}
+
+func TestManager_InvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	m, _, _ := newTestManager(t, "secret")
+	token, _, err := m.Create(ctx, "alice", "api-token", "https://jira.example.com", "DEVOPS")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	other, _, _ := newTestManager(t, "another secret")
+	otherToken, _, _ := other.Create(ctx, "mallory", "token", "https://jira.example.com", "DEVOPS")
+
+	parts := strings.Split(token, ".")
+	tests := map[string]string{
+		"empty":                    "",
+		"malformed":                "not-a-token",
+		"tampered expiry":          parts[0] + ".99999999999." + parts[2],
+		"tampered id":              "x" + token,
+		"signed by another secret": otherToken,
+		"unknown session":          m.sign("unknown", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := m.Authenticate(ctx, token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Authenticate() error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestManager_Expiry(t *testing.T) {
+	ctx := context.Background()
+	m, now, _ := newTestManager(t, "")
+	token, session, err := m.Create(ctx, "alice", "api-token", "https://jira.example.com", "DEVOPS")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := m.Client(session, ""); err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+
+	*now = now.Add(59 * time.Minute)
+	if _, err := m.Authenticate(ctx, token); err != nil {
+		t.Errorf("Authenticate() before expiry error = %v", err)
+	}
+
+	*now = now.Add(time.Minute)
+	if _, err := m.Authenticate(ctx, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() after expiry error = %v, want %v", err, ErrInvalidToken)
+	}
+
+	m.Cleanup(ctx)
+	if _, err := m.store.Get(ctx, session.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("store.Get() after Cleanup() error = %v, want %v", err, ErrNotFound)
+	}
+	if len(m.clients) != 0 {
+		t.Errorf("pooled clients after Cleanup() = %d, want 0", len(m.clients))
+	}
+}
+
+func TestManager_StorageStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "roadmap.db")
+	cfg := &config.Auth{SessionSecret: "secret", SessionTTL: "1h"}
+
+	// open returns a manager backed by a new connection to the database, as after a restart
+	open := func() *Manager {
+		store, err := storage.NewSQLiteStore(path)
+		if err != nil {
+			t.Fatalf("NewSQLiteStore() error = %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close() })
+		m, err := NewManager(cfg, NewStorageStore(store))
+		if err != nil {
+			t.Fatalf("NewManager() error = %v", err)
+		}
+		return m
+	}
+
+	token, created, err := open().Create(ctx, "alice", "api-token", "https://jira.example.com", "DEVOPS")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	restarted := open()
+	session, err := restarted.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate() after restart error = %v", err)
+	}
+	if session.Username != created.Username || !bytes.Equal(session.EncryptedToken, created.EncryptedToken) {
+		t.Errorf("Authenticate() after restart = %+v, want %+v", session, created)
+	}
+	if jiraToken, err := restarted.decrypt(session.EncryptedToken); err != nil || jiraToken != "api-token" {
+		t.Errorf("decrypt() after restart = %q, %v", jiraToken, err)
+	}
+
+	if err := restarted.Revoke(ctx, token); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := open().Authenticate(ctx, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() of a revoked session error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestNewManager_InvalidTTL(t *testing.T) {
+	if _, err := NewManager(&config.Auth{SessionTTL: "forever"}, NewMemoryStore()); err == nil {
+		t.Errorf("NewManager() expected error for an invalid ttl")
+	}
+}
+
+func TestManager_Delegated(t *testing.T) {
+	ctx := context.Background()
+	m, _, created := newTestManager(t, "secret")
+
+	if _, _, err := m.CreateDelegated(ctx, "alice", ""); !errors.Is(err, ErrNoServiceAccount) {
+		t.Fatalf("CreateDelegated() without service account error = %v, want %v", err, ErrNoServiceAccount)
+	}
+
+	m.UseServiceAccount(&config.Jira{BaseURL: "https://jira.example.com", Username: "robot", Password: "robot-token", Project: "DEVOPS"})
+	token, session, err := m.CreateDelegated(ctx, "alice", "")
+	if err != nil {
+		t.Fatalf("CreateDelegated() error = %v", err)
+	}
+	if !session.Delegated || session.Project != "DEVOPS" || len(session.EncryptedToken) != 0 {
+		t.Errorf("CreateDelegated() session = %+v", session)
+	}
+
+	got, err := m.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	client, err := m.Client(got, "OTHER")
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+	if client.Actor() != "alice" {
+		t.Errorf("Client().Actor() = %q, want %q", client.Actor(), "alice")
+	}
+	if want := []string{"robot:robot-token@OTHER"}; strings.Join(*created, ",") != strings.Join(want, ",") {
+		t.Errorf("created clients = %v, want %v", *created, want)
+	}
+}
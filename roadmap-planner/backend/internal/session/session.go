@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package session manages authenticated user sessions
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/storage"
+)
+
+// ErrNotFound is returned when a session does not exist or expired
+var ErrNotFound = errors.New("session not found")
+
+// Session is an authenticated user session
+type Session struct {
+	ID       string
+	Username string
+	BaseURL  string
+	Project  string
+	// EncryptedToken is the Jira password or API token of the user, encrypted with the session key
+	EncryptedToken []byte
+	// Delegated sessions are created by single sign-on, they have no Jira token and
+	// use the service account with writes attributed to Username
+	Delegated bool
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Expired returns true if the session expired at the given time
+func (s *Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// Store persists sessions on the server side
+type Store interface {
+	// Save creates or replaces a session
+	Save(ctx context.Context, session *Session) error
+	// Get returns a session, ErrNotFound if it does not exist
+	Get(ctx context.Context, id string) (*Session, error)
+	// Delete removes a session, it does not fail if the session does not exist
+	Delete(ctx context.Context, id string) error
+	// DeleteExpired removes the sessions expired at the given time and returns their ids
+	DeleteExpired(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// MemoryStore keeps sessions in memory, they are lost on restart
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]Session
+}
+
+var _ Store = &MemoryStore{}
+
+// NewMemoryStore creates an empty in-memory session store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: map[string]Session{}}
+}
+
+// Save creates or replaces a session
+func (m *MemoryStore) Save(ctx context.Context, session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[session.ID] = *session
+	return nil
+}
+
+// Get returns a session, ErrNotFound if it does not exist
+func (m *MemoryStore) Get(ctx context.Context, id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &session, nil
+}
+
+// Delete removes a session
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// DeleteExpired removes the sessions expired at the given time
+func (m *MemoryStore) DeleteExpired(ctx context.Context, now time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var expired []string
+	for id, session := range m.sessions {
+		if session.Expired(now) {
+			delete(m.sessions, id)
+			expired = append(expired, id)
+		}
+	}
+	return expired, nil
+}
+
+// StorageStore keeps sessions in the storage so that they survive restarts
+// Sessions are stored as JSON documents, their Jira token stays encrypted with the session key:
+// a session secret must be configured for the sessions to be readable after a restart
+type StorageStore struct {
+	store storage.Store
+}
+
+var _ Store = &StorageStore{}
+
+// NewStorageStore creates a session store backed by the storage
+func NewStorageStore(store storage.Store) *StorageStore {
+	return &StorageStore{store: store}
+}
+
+// Save creates or replaces a session
+func (s *StorageStore) Save(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	return s.store.SaveSession(ctx, session.ID, data, session.ExpiresAt)
+}
+
+// Get returns a session, ErrNotFound if it does not exist
+func (s *StorageStore) Get(ctx context.Context, id string) (*Session, error) {
+	data, err := s.store.GetSession(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	return &session, nil
+}
+
+// Delete removes a session
+func (s *StorageStore) Delete(ctx context.Context, id string) error {
+	return s.store.DeleteSession(ctx, id)
+}
+
+// DeleteExpired removes the sessions expired at the given time
+func (s *StorageStore) DeleteExpired(ctx context.Context, now time.Time) ([]string, error) {
+	return s.store.DeleteExpiredSessions(ctx, now)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+// MemoryStore keeps entities, snapshots, audit entries and sessions in memory
+type MemoryStore struct {
+	mu        sync.RWMutex
+	entities  Entities
+	snapshots []Snapshot
+	audit     []AuditEntry
+	sessions  map[string]memorySession
+}
+
+// memorySession is an encoded session kept in memory
+type memorySession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+var _ Store = &MemoryStore{}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: map[string]memorySession{}}
+}
+
+// SaveEntities replaces the stored entities with the given ones
+func (m *MemoryStore) SaveEntities(ctx context.Context, entities *Entities) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entities = Entities{
+		Releases:     append([]models.EnrichedRelease{}, entities.Releases...),
+		Epics:        append([]models.EnrichedIssue{}, entities.Epics...),
+		Issues:       append([]models.EnrichedIssue{}, entities.Issues...),
+		CollectedAt:  entities.CollectedAt,
+		FullSyncedAt: entities.FullSyncedAt,
+	}
+	return nil
+}
+
+// LoadEntities returns the stored entities
+func (m *MemoryStore) LoadEntities(ctx context.Context) (*Entities, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return &Entities{
+		Releases:     append([]models.EnrichedRelease{}, m.entities.Releases...),
+		Epics:        append([]models.EnrichedIssue{}, m.entities.Epics...),
+		Issues:       append([]models.EnrichedIssue{}, m.entities.Issues...),
+		CollectedAt:  m.entities.CollectedAt,
+		FullSyncedAt: m.entities.FullSyncedAt,
+	}, nil
+}
+
+// SaveSnapshots records metric values
+func (m *MemoryStore) SaveSnapshots(ctx context.Context, snapshots []Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots = append(m.snapshots, snapshots...)
+	sort.SliceStable(m.snapshots, func(i, j int) bool {
+		return m.snapshots[i].TakenAt.Before(m.snapshots[j].TakenAt)
+	})
+	return nil
+}
+
+// ListSnapshots returns the snapshots matching the query, oldest first
+func (m *MemoryStore) ListSnapshots(ctx context.Context, query SnapshotQuery) ([]Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	snapshots := []Snapshot{}
+	for _, s := range m.snapshots {
+		if s.Metric != query.Metric || s.Component != query.Component {
+			continue
+		}
+		if !query.From.IsZero() && s.TakenAt.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && s.TakenAt.After(query.To) {
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
+
+// SnapshotBefore returns the latest snapshot of a metric taken at or before the given time
+func (m *MemoryStore) SnapshotBefore(ctx context.Context, metric, component string, at time.Time) (*Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for i := len(m.snapshots) - 1; i >= 0; i-- {
+		s := m.snapshots[i]
+		if s.Metric == metric && s.Component == component && !s.TakenAt.After(at) {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+// LastSnapshotAt returns the time of the latest snapshot
+func (m *MemoryStore) LastSnapshotAt(ctx context.Context) (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.snapshots) == 0 {
+		return time.Time{}, nil
+	}
+	return m.snapshots[len(m.snapshots)-1].TakenAt, nil
+}
+
+// PruneSnapshots deletes the snapshots taken before the given time
+func (m *MemoryStore) PruneSnapshots(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.snapshots[:0]
+	for _, s := range m.snapshots {
+		if !s.TakenAt.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	pruned := int64(len(m.snapshots) - len(kept))
+	m.snapshots = kept
+	return pruned, nil
+}
+
+// SaveAuditEntry records a change of the roadmap and sets the ID of the entry
+func (m *MemoryStore) SaveAuditEntry(ctx context.Context, entry *AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry.ID = int64(len(m.audit) + 1)
+	m.audit = append(m.audit, *entry)
+	return nil
+}
+
+// ListAuditEntries returns the audit entries matching the query, most recent first
+func (m *MemoryStore) ListAuditEntries(ctx context.Context, query AuditQuery) ([]AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entries := []AuditEntry{}
+	for i := len(m.audit) - 1; i >= 0; i-- {
+		if query.Match(&m.audit[i]) {
+			entries = append(entries, m.audit[i])
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
+
+// SaveSession creates or replaces an encoded session
+func (m *MemoryStore) SaveSession(ctx context.Context, id string, data []byte, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = memorySession{data: append([]byte{}, data...), expiresAt: expiresAt}
+	return nil
+}
+
+// GetSession returns an encoded session, ErrNotFound if it does not exist
+func (m *MemoryStore) GetSession(ctx context.Context, id string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, session.data...), nil
+}
+
+// DeleteSession removes a session
+func (m *MemoryStore) DeleteSession(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// DeleteExpiredSessions removes the sessions expired at the given time
+func (m *MemoryStore) DeleteExpiredSessions(ctx context.Context, now time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var expired []string
+	for id, session := range m.sessions {
+		if !now.Before(session.expiresAt) {
+			delete(m.sessions, id)
+			expired = append(expired, id)
+		}
+	}
+	return expired, nil
+}
+
+// Close does nothing for the in-memory store
+func (m *MemoryStore) Close() error {
+	return nil
+}
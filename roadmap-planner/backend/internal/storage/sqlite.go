@@ -0,0 +1,508 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	_ "modernc.org/sqlite" // pure Go SQLite driver, the images are built without cgo
+)
+
+// migrations are applied in order, the index + 1 is stored as the schema version
+var migrations = []string{
+	`CREATE TABLE releases (
+		id TEXT PRIMARY KEY,
+		data TEXT NOT NULL
+	);
+	CREATE TABLE issues (
+		kind TEXT NOT NULL,
+		key TEXT NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (kind, key)
+	);
+	CREATE TABLE collections (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		collected_at INTEGER NOT NULL
+	);
+	CREATE TABLE metric_snapshots (
+		metric TEXT NOT NULL,
+		component TEXT NOT NULL DEFAULT '',
+		taken_at INTEGER NOT NULL,
+		value REAL NOT NULL,
+		unit TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (metric, component, taken_at)
+	);
+	CREATE INDEX metric_snapshots_taken_at ON metric_snapshots (taken_at);`,
+	`ALTER TABLE collections ADD COLUMN full_synced_at INTEGER NOT NULL DEFAULT 0;`,
+	`CREATE TABLE audit_entries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		project TEXT NOT NULL DEFAULT '',
+		actor TEXT NOT NULL DEFAULT '',
+		action TEXT NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		entity_key TEXT NOT NULL DEFAULT '',
+		before_value TEXT,
+		after_value TEXT,
+		recorded_at INTEGER NOT NULL
+	);
+	CREATE INDEX audit_entries_recorded_at ON audit_entries (recorded_at);
+	CREATE INDEX audit_entries_entity ON audit_entries (entity_id);`,
+	`CREATE TABLE sessions (
+		id TEXT PRIMARY KEY,
+		data BLOB NOT NULL,
+		expires_at INTEGER NOT NULL
+	);
+	CREATE INDEX sessions_expires_at ON sessions (expires_at);`,
+}
+
+// issue kinds stored in the issues table
+const (
+	kindEpic  = "epic"
+	kindIssue = "issue"
+)
+
+// SQLiteStore stores entities, snapshots, audit entries and sessions in a SQLite database
+// entities are stored as JSON documents so that new fields do not need a migration
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+var _ Store = &SQLiteStore{}
+
+// NewSQLiteStore opens the database file and applies pending migrations
+// path: the database file, ":memory:" opens a private in-memory database
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := path
+	if path != ":memory:" {
+		dsn = "file:" + (&url.URL{Path: path}).EscapedPath()
+	}
+	dsn += "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	// SQLite allows a single writer, serializing the connections avoids busy errors
+	db.SetMaxOpenConns(1)
+
+	store := &SQLiteStore{db: db}
+	if err := store.migrate(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// migrate applies the migrations newer than the schema version of the database
+func (s *SQLiteStore) migrate(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to set schema version %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveEntities replaces the stored entities with the given ones
+func (s *SQLiteStore) SaveEntities(ctx context.Context, entities *Entities) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range []string{"DELETE FROM releases", "DELETE FROM issues"} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to clear entities: %w", err)
+		}
+	}
+
+	for _, release := range entities.Releases {
+		data, err := json.Marshal(release)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO releases (id, data) VALUES (?, ?)", release.ID, string(data)); err != nil {
+			return fmt.Errorf("failed to save release %s: %w", release.Name, err)
+		}
+	}
+	if err := saveIssues(ctx, tx, kindEpic, entities.Epics); err != nil {
+		return err
+	}
+	if err := saveIssues(ctx, tx, kindIssue, entities.Issues); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO collections (id, collected_at, full_synced_at) VALUES (1, ?, ?) "+
+			"ON CONFLICT (id) DO UPDATE SET collected_at = excluded.collected_at, full_synced_at = excluded.full_synced_at",
+		entities.CollectedAt.UnixMilli(), unixMilli(entities.FullSyncedAt)); err != nil {
+		return fmt.Errorf("failed to save collection time: %w", err)
+	}
+	return tx.Commit()
+}
+
+// unixMilli converts t to milliseconds, keeping the zero time as 0
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// saveIssues inserts issues of a kind
+func saveIssues(ctx context.Context, tx *sql.Tx, kind string, issues []models.EnrichedIssue) error {
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO issues (kind, key, data) VALUES (?, ?, ?)", kind, issue.Key, string(data)); err != nil {
+			return fmt.Errorf("failed to save %s %s: %w", kind, issue.Key, err)
+		}
+	}
+	return nil
+}
+
+// LoadEntities returns the stored entities
+func (s *SQLiteStore) LoadEntities(ctx context.Context) (*Entities, error) {
+	entities := &Entities{
+		Releases: []models.EnrichedRelease{},
+		Epics:    []models.EnrichedIssue{},
+		Issues:   []models.EnrichedIssue{},
+	}
+
+	var collectedAt, fullSyncedAt int64
+	err := s.db.QueryRowContext(ctx, "SELECT collected_at, full_synced_at FROM collections WHERE id = 1").Scan(&collectedAt, &fullSyncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load collection time: %w", err)
+	}
+	entities.CollectedAt = time.UnixMilli(collectedAt)
+	if fullSyncedAt > 0 {
+		entities.FullSyncedAt = time.UnixMilli(fullSyncedAt)
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT data FROM releases ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load releases: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		var release models.EnrichedRelease
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &release); err != nil {
+			return nil, fmt.Errorf("failed to decode release: %w", err)
+		}
+		entities.Releases = append(entities.Releases, release)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, "SELECT kind, data FROM issues ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issues: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var kind, data string
+		var issue models.EnrichedIssue
+		if err := rows.Scan(&kind, &data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &issue); err != nil {
+			return nil, fmt.Errorf("failed to decode issue: %w", err)
+		}
+		if kind == kindEpic {
+			entities.Epics = append(entities.Epics, issue)
+		} else {
+			entities.Issues = append(entities.Issues, issue)
+		}
+	}
+	return entities, rows.Err()
+}
+
+// SaveSnapshots records metric values
+func (s *SQLiteStore) SaveSnapshots(ctx context.Context, snapshots []Snapshot) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, snapshot := range snapshots {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT OR REPLACE INTO metric_snapshots (metric, component, taken_at, value, unit) VALUES (?, ?, ?, ?, ?)",
+			snapshot.Metric, snapshot.Component, snapshot.TakenAt.UnixMilli(), snapshot.Value, snapshot.Unit); err != nil {
+			return fmt.Errorf("failed to save snapshot of %s: %w", snapshot.Metric, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListSnapshots returns the snapshots matching the query, oldest first
+func (s *SQLiteStore) ListSnapshots(ctx context.Context, query SnapshotQuery) ([]Snapshot, error) {
+	to := int64(math.MaxInt64)
+	if !query.To.IsZero() {
+		to = query.To.UnixMilli()
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT metric, component, taken_at, value, unit FROM metric_snapshots
+		WHERE metric = ? AND component = ? AND taken_at >= ? AND taken_at <= ?
+		ORDER BY taken_at`,
+		query.Metric, query.Component, query.From.UnixMilli(), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots of %s: %w", query.Metric, err)
+	}
+	defer rows.Close()
+
+	snapshots := []Snapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// SnapshotBefore returns the latest snapshot of a metric taken at or before the given time
+func (s *SQLiteStore) SnapshotBefore(ctx context.Context, metric, component string, at time.Time) (*Snapshot, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT metric, component, taken_at, value, unit FROM metric_snapshots
+		WHERE metric = ? AND component = ? AND taken_at <= ?
+		ORDER BY taken_at DESC LIMIT 1`,
+		metric, component, at.UnixMilli())
+	snapshot, err := scanSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// LastSnapshotAt returns the time of the latest snapshot
+func (s *SQLiteStore) LastSnapshotAt(ctx context.Context) (time.Time, error) {
+	var takenAt sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, "SELECT MAX(taken_at) FROM metric_snapshots").Scan(&takenAt); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read last snapshot time: %w", err)
+	}
+	if !takenAt.Valid {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(takenAt.Int64), nil
+}
+
+// PruneSnapshots deletes the snapshots taken before the given time
+func (s *SQLiteStore) PruneSnapshots(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM metric_snapshots WHERE taken_at < ?", before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune snapshots: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// SaveAuditEntry records a change of the roadmap and sets the ID of the entry
+func (s *SQLiteStore) SaveAuditEntry(ctx context.Context, entry *AuditEntry) error {
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_entries (project, actor, action, entity_type, entity_id, entity_key, before_value, after_value, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Project, entry.Actor, entry.Action, entry.EntityType, entry.EntityID, entry.EntityKey,
+		nullJSON(entry.Before), nullJSON(entry.After), entry.Timestamp.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry of %s %s: %w", entry.EntityType, entry.EntityID, err)
+	}
+	entry.ID, err = result.LastInsertId()
+	return err
+}
+
+// nullJSON stores an empty JSON value as NULL
+func nullJSON(value json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(value), Valid: len(value) > 0}
+}
+
+// ListAuditEntries returns the audit entries matching the query, most recent first
+func (s *SQLiteStore) ListAuditEntries(ctx context.Context, query AuditQuery) ([]AuditEntry, error) {
+	var conditions []string
+	var args []any
+	for _, filter := range []struct {
+		condition string
+		value     string
+	}{
+		{"project = ?", query.Project},
+		{"entity_type = ?", query.EntityType},
+		{"actor = ?", query.Actor},
+	} {
+		if filter.value != "" {
+			conditions = append(conditions, filter.condition)
+			args = append(args, filter.value)
+		}
+	}
+	if query.Entity != "" {
+		conditions = append(conditions, "(entity_id = ? OR entity_key = ?)")
+		args = append(args, query.Entity, query.Entity)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, "recorded_at >= ?")
+		args = append(args, query.From.UnixMilli())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "recorded_at <= ?")
+		args = append(args, query.To.UnixMilli())
+	}
+
+	stmt := `SELECT id, project, actor, action, entity_type, entity_id, entity_key, before_value, after_value, recorded_at
+		FROM audit_entries`
+	if len(conditions) > 0 {
+		stmt += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	stmt += " ORDER BY recorded_at DESC, id DESC"
+	if query.Limit > 0 {
+		stmt += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var entry AuditEntry
+		var before, after sql.NullString
+		var recordedAt int64
+		if err := rows.Scan(&entry.ID, &entry.Project, &entry.Actor, &entry.Action, &entry.EntityType,
+			&entry.EntityID, &entry.EntityKey, &before, &after, &recordedAt); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			entry.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			entry.After = json.RawMessage(after.String)
+		}
+		entry.Timestamp = time.UnixMilli(recordedAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// SaveSession creates or replaces an encoded session
+func (s *SQLiteStore) SaveSession(ctx context.Context, id string, data []byte, expiresAt time.Time) error {
+	if _, err := s.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO sessions (id, data, expires_at) VALUES (?, ?, ?)",
+		id, data, expiresAt.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns an encoded session, ErrNotFound if it does not exist
+func (s *SQLiteStore) GetSession(ctx context.Context, id string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, "SELECT data FROM sessions WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	return data, nil
+}
+
+// DeleteSession removes a session
+func (s *SQLiteStore) DeleteSession(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions removes the sessions expired at the given time
+func (s *SQLiteStore) DeleteExpiredSessions(ctx context.Context, now time.Time) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "DELETE FROM sessions WHERE expires_at <= ? RETURNING id", now.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var expired []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		expired = append(expired, id)
+	}
+	return expired, rows.Err()
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// scanner is implemented by sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSnapshot reads a snapshot row
+func scanSnapshot(row scanner) (*Snapshot, error) {
+	var snapshot Snapshot
+	var takenAt int64
+	if err := row.Scan(&snapshot.Metric, &snapshot.Component, &takenAt, &snapshot.Value, &snapshot.Unit); err != nil {
+		return nil, err
+	}
+	snapshot.TakenAt = time.UnixMilli(takenAt)
+	return &snapshot, nil
+}
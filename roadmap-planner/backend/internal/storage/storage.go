@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage persists collected Jira entities, metric snapshots and the audit log
+// so that the metrics survive restarts and can be charted over time
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+)
+
+const (
+	// DriverSQLite stores data in a SQLite database file
+	DriverSQLite = "sqlite"
+	// DriverMemory keeps data in memory, mostly useful for development and tests
+	DriverMemory = "memory"
+	// DriverNone disables the storage
+	DriverNone = "none"
+)
+
+// ErrNotFound is returned when a stored session does not exist
+var ErrNotFound = errors.New("not found")
+
+// Store persists collected entities, metric snapshots, the audit log and the user sessions
+type Store interface {
+	// SaveEntities replaces the stored entities with the given ones
+	SaveEntities(ctx context.Context, entities *Entities) error
+	// LoadEntities returns the stored entities, empty if nothing was collected yet
+	LoadEntities(ctx context.Context) (*Entities, error)
+
+	// SaveSnapshots records metric values
+	SaveSnapshots(ctx context.Context, snapshots []Snapshot) error
+	// ListSnapshots returns the snapshots matching the query, oldest first
+	ListSnapshots(ctx context.Context, query SnapshotQuery) ([]Snapshot, error)
+	// SnapshotBefore returns the latest snapshot of a metric taken at or before the given time, nil if none
+	SnapshotBefore(ctx context.Context, metric, component string, at time.Time) (*Snapshot, error)
+	// LastSnapshotAt returns the time of the latest snapshot, zero if none
+	LastSnapshotAt(ctx context.Context) (time.Time, error)
+	// PruneSnapshots deletes the snapshots taken before the given time
+	PruneSnapshots(ctx context.Context, before time.Time) (int64, error)
+
+	// SaveAuditEntry records a change of the roadmap and sets the ID of the entry
+	SaveAuditEntry(ctx context.Context, entry *AuditEntry) error
+	// ListAuditEntries returns the audit entries matching the query, most recent first
+	ListAuditEntries(ctx context.Context, query AuditQuery) ([]AuditEntry, error)
+
+	// SaveSession creates or replaces an encoded session expiring at the given time
+	SaveSession(ctx context.Context, id string, data []byte, expiresAt time.Time) error
+	// GetSession returns an encoded session, ErrNotFound if it does not exist
+	GetSession(ctx context.Context, id string) ([]byte, error)
+	// DeleteSession removes a session, it does not fail if the session does not exist
+	DeleteSession(ctx context.Context, id string) error
+	// DeleteExpiredSessions removes the sessions expired at the given time and returns their ids
+	DeleteExpiredSessions(ctx context.Context, now time.Time) ([]string, error)
+
+	// Close releases the resources of the store
+	Close() error
+}
+
+// Entities are the data collected from Jira
+type Entities struct {
+	Releases    []models.EnrichedRelease
+	Epics       []models.EnrichedIssue
+	Issues      []models.EnrichedIssue
+	CollectedAt time.Time
+	// FullSyncedAt is the time of the last collection that refetched all the data
+	FullSyncedAt time.Time
+}
+
+// Snapshot is the value of a metric at a point in time
+type Snapshot struct {
+	Metric string `json:"metric"`
+	// Component is empty for the value aggregated over all components
+	Component string    `json:"component,omitempty"`
+	Value     float64   `json:"value"`
+	Unit      string    `json:"unit"`
+	TakenAt   time.Time `json:"timestamp"`
+}
+
+// SnapshotQuery selects the snapshots of a metric
+type SnapshotQuery struct {
+	Metric string
+	// Component selects a component, empty selects the aggregated values
+	Component string
+	From      time.Time
+	To        time.Time
+}
+
+// AuditEntry is a change of the roadmap made through the planner
+type AuditEntry struct {
+	ID      int64  `json:"id"`
+	Project string `json:"project"`
+	// Actor is the user who made the change
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	EntityKey  string `json:"entity_key,omitempty"`
+	// Before and After are the JSON values of the entity, Before is empty for a creation
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// AuditQuery selects audit entries, empty fields match everything
+type AuditQuery struct {
+	Project    string
+	EntityType string
+	// Entity matches the ID or the key of the entity
+	Entity string
+	Actor  string
+	From   time.Time
+	To     time.Time
+	// Limit is the maximum number of entries returned, 0 returns all of them
+	Limit int
+}
+
+// Match reports whether the entry is selected by the query
+func (q AuditQuery) Match(entry *AuditEntry) bool {
+	switch {
+	case q.Project != "" && entry.Project != q.Project:
+		return false
+	case q.EntityType != "" && entry.EntityType != q.EntityType:
+		return false
+	case q.Entity != "" && entry.EntityID != q.Entity && entry.EntityKey != q.Entity:
+		return false
+	case q.Actor != "" && entry.Actor != q.Actor:
+		return false
+	case !q.From.IsZero() && entry.Timestamp.Before(q.From):
+		return false
+	case !q.To.IsZero() && entry.Timestamp.After(q.To):
+		return false
+	}
+	return true
+}
+
+// New creates the store configured by the driver
+// Returns nil when the storage is disabled
+func New(cfg *config.Storage) (Store, error) {
+	switch cfg.Driver {
+	case "", DriverNone:
+		return nil, nil
+	case DriverMemory:
+		return NewMemoryStore(), nil
+	case DriverSQLite:
+		return NewSQLiteStore(cfg.Path)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2024 The AlaudaDevops Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/config"
+	"github.com/AlaudaDevops/toolbox/roadmap-planner/backend/internal/metrics/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// stores returns a fresh store of each implementation
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "roadmap.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = sqlite.Close() })
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqlite,
+	}
+}
+
+func TestStore_Entities(t *testing.T) {
+	ctx := context.Background()
+	collectedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	entities := &Entities{
+		Releases: []models.EnrichedRelease{
+			{ID: "1", Name: "argo-cd-2.9.0", Released: true, ReleaseDate: collectedAt, Component: "argo-cd", Major: 2, Minor: 9, Type: "minor"},
+		},
+		Epics: []models.EnrichedIssue{
+			{ID: "10", Key: "DEVOPS-1", Name: "Epic", IssueType: "Epic", Components: []string{"argo-cd"}, CreatedDate: collectedAt},
+		},
+		Issues: []models.EnrichedIssue{
+			{ID: "11", Key: "DEVOPS-2", Name: "Bug", IssueType: "Bug", Versions: []string{"argo-cd-2.9.0"}, CreatedDate: collectedAt},
+		},
+		CollectedAt:  collectedAt,
+		FullSyncedAt: collectedAt.Add(-time.Hour),
+	}
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			empty, err := store.LoadEntities(ctx)
+			if err != nil {
+				t.Fatalf("LoadEntities() error = %v", err)
+			}
+			if !empty.CollectedAt.IsZero() || len(empty.Releases)+len(empty.Epics)+len(empty.Issues) != 0 {
+				t.Errorf("LoadEntities() on an empty store = %+v", empty)
+			}
+
+			if err := store.SaveEntities(ctx, entities); err != nil {
+				t.Fatalf("SaveEntities() error = %v", err)
+			}
+			// saving again replaces the previous entities
+			if err := store.SaveEntities(ctx, entities); err != nil {
+				t.Fatalf("SaveEntities() error = %v", err)
+			}
+
+			loaded, err := store.LoadEntities(ctx)
+			if err != nil {
+				t.Fatalf("LoadEntities() error = %v", err)
+			}
+			if diff := cmp.Diff(entities, loaded); diff != "" {
+				t.Errorf("LoadEntities() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStore_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []Snapshot{
+		{Metric: "cycle_time", Value: 10, Unit: "days", TakenAt: day(1)},
+		{Metric: "cycle_time", Component: "argo-cd", Value: 8, Unit: "days", TakenAt: day(1)},
+		{Metric: "cycle_time", Value: 12, Unit: "days", TakenAt: day(2)},
+		{Metric: "cycle_time", Value: 9, Unit: "days", TakenAt: day(3)},
+		{Metric: "patch_ratio", Value: 0.5, TakenAt: day(3)},
+	}
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			last, err := store.LastSnapshotAt(ctx)
+			if err != nil || !last.IsZero() {
+				t.Fatalf("LastSnapshotAt() on an empty store = %v, %v", last, err)
+			}
+
+			if err := store.SaveSnapshots(ctx, snapshots); err != nil {
+				t.Fatalf("SaveSnapshots() error = %v", err)
+			}
+
+			listed, err := store.ListSnapshots(ctx, SnapshotQuery{Metric: "cycle_time", From: day(2)})
+			if err != nil {
+				t.Fatalf("ListSnapshots() error = %v", err)
+			}
+			if diff := cmp.Diff(snapshots[2:4], listed); diff != "" {
+				t.Errorf("ListSnapshots() mismatch (-want +got):\n%s", diff)
+			}
+
+			listed, err = store.ListSnapshots(ctx, SnapshotQuery{Metric: "cycle_time", Component: "argo-cd"})
+			if err != nil {
+				t.Fatalf("ListSnapshots() error = %v", err)
+			}
+			if diff := cmp.Diff(snapshots[1:2], listed); diff != "" {
+				t.Errorf("ListSnapshots() by component mismatch (-want +got):\n%s", diff)
+			}
+
+			before, err := store.SnapshotBefore(ctx, "cycle_time", "", day(2).Add(time.Hour))
+			if err != nil {
+				t.Fatalf("SnapshotBefore() error = %v", err)
+			}
+			if diff := cmp.Diff(&snapshots[2], before); diff != "" {
+				t.Errorf("SnapshotBefore() mismatch (-want +got):\n%s", diff)
+			}
+			if before, _ := store.SnapshotBefore(ctx, "cycle_time", "", day(1).Add(-time.Hour)); before != nil {
+				t.Errorf("SnapshotBefore() = %+v, want nil", before)
+			}
+
+			if last, _ := store.LastSnapshotAt(ctx); !last.Equal(day(3)) {
+				t.Errorf("LastSnapshotAt() = %v, want %v", last, day(3))
+			}
+
+			pruned, err := store.PruneSnapshots(ctx, day(2))
+			if err != nil || pruned != 2 {
+				t.Errorf("PruneSnapshots() = %d, %v, want 2", pruned, err)
+			}
+		})
+	}
+}
+
+func TestStore_AuditEntries(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	entries := []AuditEntry{
+		{Project: "DEVOPS", Actor: "alice", Action: "create", EntityType: "milestone", EntityID: "1", EntityKey: "DEVOPS-1",
+			After: []byte(`{"name":"Milestone"}`), Timestamp: day(1)},
+		{Project: "DEVOPS", Actor: "bob", Action: "update", EntityType: "milestone", EntityID: "1", EntityKey: "DEVOPS-1",
+			Before: []byte(`{"name":"Milestone"}`), After: []byte(`{"name":"Renamed"}`), Timestamp: day(2)},
+		{Project: "DEVOPS", Actor: "alice", Action: "move", EntityType: "epic", EntityID: "2", EntityKey: "DEVOPS-2",
+			Before: []byte(`{"milestone_ids":["1"]}`), After: []byte(`{"milestone_ids":["3"]}`), Timestamp: day(3)},
+		{Project: "OTHER", Actor: "alice", Action: "create", EntityType: "epic", EntityID: "4", EntityKey: "OTHER-4",
+			After: []byte(`{"name":"Epic"}`), Timestamp: day(3)},
+	}
+	withIDs := make([]AuditEntry, len(entries))
+	for i := range entries {
+		withIDs[i] = entries[i]
+		withIDs[i].ID = int64(i + 1)
+	}
+
+	table := map[string]struct {
+		query AuditQuery
+		want  []AuditEntry
+	}{
+		"all":         {query: AuditQuery{}, want: []AuditEntry{withIDs[3], withIDs[2], withIDs[1], withIDs[0]}},
+		"project":     {query: AuditQuery{Project: "DEVOPS"}, want: []AuditEntry{withIDs[2], withIDs[1], withIDs[0]}},
+		"entity id":   {query: AuditQuery{Entity: "1"}, want: []AuditEntry{withIDs[1], withIDs[0]}},
+		"entity key":  {query: AuditQuery{Entity: "DEVOPS-2"}, want: []AuditEntry{withIDs[2]}},
+		"entity type": {query: AuditQuery{EntityType: "epic"}, want: []AuditEntry{withIDs[3], withIDs[2]}},
+		"actor":       {query: AuditQuery{Actor: "bob"}, want: []AuditEntry{withIDs[1]}},
+		"date range":  {query: AuditQuery{From: day(2), To: day(2)}, want: []AuditEntry{withIDs[1]}},
+		"limit":       {query: AuditQuery{Project: "DEVOPS", Limit: 2}, want: []AuditEntry{withIDs[2], withIDs[1]}},
+	}
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			for i := range entries {
+				entry := entries[i]
+				if err := store.SaveAuditEntry(ctx, &entry); err != nil {
+					t.Fatalf("SaveAuditEntry() error = %v", err)
+				}
+				if entry.ID != withIDs[i].ID {
+					t.Errorf("SaveAuditEntry() ID = %d, want %d", entry.ID, withIDs[i].ID)
+				}
+			}
+
+			for query, tc := range table {
+				listed, err := store.ListAuditEntries(ctx, tc.query)
+				if err != nil {
+					t.Fatalf("ListAuditEntries(%s) error = %v", query, err)
+				}
+				if diff := cmp.Diff(tc.want, listed); diff != "" {
+					t.Errorf("ListAuditEntries(%s) mismatch (-want +got):\n%s", query, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Sessions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := store.GetSession(ctx, "missing"); err != ErrNotFound {
+				t.Errorf("GetSession() of a missing session error = %v, want ErrNotFound", err)
+			}
+
+			if err := store.SaveSession(ctx, "expired", []byte(`{"user":"alice"}`), now); err != nil {
+				t.Fatalf("SaveSession() error = %v", err)
+			}
+			if err := store.SaveSession(ctx, "active", []byte(`{"user":"bob"}`), now.Add(time.Hour)); err != nil {
+				t.Fatalf("SaveSession() error = %v", err)
+			}
+			// saving again replaces the session
+			if err := store.SaveSession(ctx, "active", []byte(`{"user":"carol"}`), now.Add(time.Hour)); err != nil {
+				t.Fatalf("SaveSession() error = %v", err)
+			}
+			data, err := store.GetSession(ctx, "active")
+			if err != nil || string(data) != `{"user":"carol"}` {
+				t.Errorf("GetSession() = %s, %v", data, err)
+			}
+
+			expired, err := store.DeleteExpiredSessions(ctx, now)
+			if err != nil {
+				t.Fatalf("DeleteExpiredSessions() error = %v", err)
+			}
+			if diff := cmp.Diff([]string{"expired"}, expired); diff != "" {
+				t.Errorf("DeleteExpiredSessions() mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := store.DeleteSession(ctx, "active"); err != nil {
+				t.Fatalf("DeleteSession() error = %v", err)
+			}
+			if err := store.DeleteSession(ctx, "active"); err != nil {
+				t.Errorf("DeleteSession() of a deleted session error = %v", err)
+			}
+			if _, err := store.GetSession(ctx, "active"); err != ErrNotFound {
+				t.Errorf("GetSession() of a deleted session error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	table := map[string]struct {
+		driver  string
+		want    string
+		wantErr bool
+	}{
+		"disabled": {driver: DriverNone},
+		"memory":   {driver: DriverMemory, want: "*storage.MemoryStore"},
+		"sqlite":   {driver: DriverSQLite, want: "*storage.SQLiteStore"},
+		"unknown":  {driver: "postgres", wantErr: true},
+	}
+
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			store, err := New(&config.Storage{Driver: tc.driver, Path: filepath.Join(t.TempDir(), "roadmap.db")})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			got := ""
+			if store != nil {
+				got = fmt.Sprintf("%T", store)
+				_ = store.Close()
+			}
+			if got != tc.want {
+				t.Errorf("New() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
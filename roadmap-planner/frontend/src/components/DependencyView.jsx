@@ -0,0 +1,149 @@
+import React, { useCallback, useEffect, useMemo, useState } from 'react';
+import toast from 'react-hot-toast';
+import { RefreshCw, AlertTriangle, Repeat } from 'lucide-react';
+import { useRoadmap } from '../hooks/useRoadmap';
+import { roadmapAPI, handleAPIError } from '../services/api';
+import { loadSelectedQuarters, getDefaultQuarters } from '../utils/quarterStorage';
+import './DependencyView.css';
+
+const REASON_LABELS = {
+  scheduled_before_dependency: 'released before its dependency',
+  dependency_unscheduled: 'dependency has no release date',
+};
+
+const formatDate = (value) => (value ? new Date(value).toISOString().slice(0, 10) : '—');
+
+// Epic box of the layered graph
+const DependencyNode = ({ node }) => {
+  const classes = ['dependency-node'];
+  if (node.critical) classes.push('dependency-node--critical');
+  if (node.in_cycle) classes.push('dependency-node--cycle');
+  if (node.external) classes.push('dependency-node--external');
+
+  return (
+    <div className={classes.join(' ')} title={node.name}>
+      <div className="dependency-node__header">
+        <span className="dependency-node__key mono">{node.key}</span>
+        <span className="dependency-node__status">{node.status}</span>
+      </div>
+      <div className="dependency-node__name">{node.name}</div>
+      <div className="dependency-node__meta mono">
+        {(node.versions || []).join(', ') || 'No version'} · {formatDate(node.release_date)}
+      </div>
+    </div>
+  );
+};
+
+const DependencyView = () => {
+  const { roadmapData } = useRoadmap();
+  const [quarters, setQuarters] = useState([]);
+  const [graph, setGraph] = useState(null);
+  const [loading, setLoading] = useState(false);
+
+  useEffect(() => {
+    if (!roadmapData?.quarters || quarters.length > 0) return;
+    const stored = loadSelectedQuarters(roadmapData.quarters);
+    setQuarters(stored.length > 0 ? stored : getDefaultQuarters(roadmapData.quarters));
+  }, [roadmapData?.quarters, quarters.length]);
+
+  const loadGraph = useCallback(async () => {
+    if (quarters.length === 0) return;
+    setLoading(true);
+    try {
+      setGraph(await roadmapAPI.getDependencies({ quarters }));
+    } catch (error) {
+      toast.error(handleAPIError(error).message);
+    } finally {
+      setLoading(false);
+    }
+  }, [quarters]);
+
+  useEffect(() => {
+    loadGraph();
+  }, [loadGraph]);
+
+  const toggleQuarter = (quarter) => {
+    setQuarters(prev => (prev.includes(quarter) ? prev.filter(q => q !== quarter) : [...prev, quarter]));
+  };
+
+  // nodes grouped by depth, dependencies first
+  const layers = useMemo(() => {
+    const byDepth = [];
+    (graph?.nodes || []).forEach(node => {
+      (byDepth[node.depth] = byDepth[node.depth] || []).push(node);
+    });
+    return byDepth.filter(Boolean);
+  }, [graph]);
+
+  return (
+    <div className="dependency-view">
+      <div className="dependency-view__controls">
+        <div className="dependency-view__quarters">
+          {(roadmapData?.quarters || []).map(quarter => (
+            <button
+              key={quarter}
+              type="button"
+              className={`dependency-view__quarter mono ${quarters.includes(quarter) ? 'is-active' : ''}`}
+              onClick={() => toggleQuarter(quarter)}
+            >
+              {quarter}
+            </button>
+          ))}
+        </div>
+        <button type="button" className="btn btn-ghost" onClick={loadGraph} disabled={loading}>
+          <RefreshCw size={14} strokeWidth={1.75} className={loading ? 'dependency-view__spinning' : ''} />
+          <span>Refresh</span>
+        </button>
+      </div>
+
+      {graph && (
+        <>
+          <section className="dependency-view__section">
+            <h3 className="dependency-view__heading">Critical path</h3>
+            {graph.critical_path?.length ? (
+              <ol className="dependency-view__path mono">
+                {graph.critical_path.map(key => <li key={key}>{key}</li>)}
+              </ol>
+            ) : (
+              <p className="dependency-view__empty">No dependencies between these epics.</p>
+            )}
+          </section>
+
+          {graph.cycles?.length > 0 && (
+            <section className="dependency-view__section dependency-view__section--alert">
+              <h3 className="dependency-view__heading"><Repeat size={14} /> Cycles</h3>
+              <ul className="dependency-view__list mono">
+                {graph.cycles.map(cycle => <li key={cycle.join()}>{cycle.join(' ↔ ')}</li>)}
+              </ul>
+            </section>
+          )}
+
+          {graph.violations?.length > 0 && (
+            <section className="dependency-view__section dependency-view__section--alert">
+              <h3 className="dependency-view__heading"><AlertTriangle size={14} /> Scheduling conflicts</h3>
+              <ul className="dependency-view__list">
+                {graph.violations.map(violation => (
+                  <li key={`${violation.epic}-${violation.depends_on}`}>
+                    <span className="mono">{violation.epic}</span> ({formatDate(violation.release_date)}) depends on{' '}
+                    <span className="mono">{violation.depends_on}</span> ({formatDate(violation.dependency_release_date)}):{' '}
+                    {REASON_LABELS[violation.reason] || violation.reason}
+                  </li>
+                ))}
+              </ul>
+            </section>
+          )}
+
+          <section className="dependency-view__layers">
+            {layers.map((nodes, depth) => (
+              <div key={depth} className="dependency-view__layer">
+                {nodes.map(node => <DependencyNode key={node.key} node={node} />)}
+              </div>
+            ))}
+          </section>
+        </>
+      )}
+    </div>
+  );
+};
+
+export default DependencyView;
@@ -9,7 +9,10 @@ import CreateEpicModal from './modals/CreateEpicModal';
 import UpdateMilestoneModal from './modals/UpdateMilestoneModal';
 import EpicMoveModal from './modals/EpicMoveModal';
 import UpdateEpicModal from './modals/UpdateEpicModal';
-import { Plus, RefreshCw, GripVertical, Compass } from 'lucide-react';
+import ImportRoadmapModal from './modals/ImportRoadmapModal';
+import { roadmapAPI, handleAPIError } from '../services/api';
+import toast from 'react-hot-toast';
+import { Plus, RefreshCw, GripVertical, Compass, Download, Upload } from 'lucide-react';
 import {
   saveSelectedQuarters,
   loadSelectedQuarters,
@@ -21,6 +24,13 @@ import './KanbanBoard.css';
 
 const MAX_QUARTERS = 4;
 
+const EXPORT_FORMATS = [
+  { value: 'csv', label: 'CSV' },
+  { value: 'xlsx', label: 'Excel' },
+  { value: 'md', label: 'Markdown' },
+  { value: 'json', label: 'JSON' },
+];
+
 const KanbanBoard = () => {
   const { onProjectChange } = useAuth();
   const { roadmapData, isLoading, error, loadRoadmap, moveEpic } = useRoadmap();
@@ -34,6 +44,27 @@ const KanbanBoard = () => {
   const [selectedEpic, setSelectedEpic] = useState(null);
   const [selectedQuarter, setSelectedQuarter] = useState(null);
   const [selectedQuarters, setSelectedQuarters] = useState([]);
+  const [showImport, setShowImport] = useState(false);
+  const [isExporting, setIsExporting] = useState(false);
+  const [forecasts, setForecasts] = useState({});
+
+  // exports the quarters in view
+  const handleExport = async (format) => {
+    setIsExporting(true);
+    try {
+      const { blob, filename } = await roadmapAPI.exportRoadmap(format, { quarters: selectedQuarters });
+      const url = window.URL.createObjectURL(blob);
+      const link = document.createElement('a');
+      link.href = url;
+      link.download = filename;
+      link.click();
+      window.URL.revokeObjectURL(url);
+    } catch (error) {
+      toast.error(handleAPIError(error).message);
+    } finally {
+      setIsExporting(false);
+    }
+  };
 
   useEffect(() => {
     if (roadmapData?.quarters && selectedQuarters.length === 0) {
@@ -58,6 +89,23 @@ const KanbanBoard = () => {
     }
   }, [roadmapData?.quarters, selectedQuarters]);
 
+  // forecasts are only available when the metrics are enabled, the board works without them
+  useEffect(() => {
+    if (selectedQuarters.length === 0) return undefined;
+    let cancelled = false;
+    roadmapAPI.getForecast({ quarters: selectedQuarters })
+      .then(data => {
+        if (cancelled) return;
+        const byMilestone = {};
+        (data.forecasts || []).forEach(forecast => { byMilestone[forecast.id] = forecast; });
+        setForecasts(byMilestone);
+      })
+      .catch(() => {
+        if (!cancelled) setForecasts({});
+      });
+    return () => { cancelled = true; };
+  }, [selectedQuarters]);
+
   useEffect(() => {
     const unsubscribe = onProjectChange((newProject, prevProject) => {
       if (newProject !== prevProject) loadRoadmap();
@@ -228,10 +276,30 @@ const KanbanBoard = () => {
           </div>
           <span className="controls-bar__hint">{selectedQuarters.length}/{MAX_QUARTERS}</span>
         </div>
-        <button onClick={loadRoadmap} className="btn btn-sm btn-ghost" title="Refresh data">
-          <RefreshCw size={13} strokeWidth={1.75} />
-          Refresh
-        </button>
+        <div className="controls-bar__actions">
+          <label className="controls-bar__export" title="Export the quarters in view">
+            <Download size={13} strokeWidth={1.75} />
+            <select
+              value=""
+              onChange={(e) => e.target.value && handleExport(e.target.value)}
+              disabled={isExporting}
+              aria-label="Export roadmap"
+            >
+              <option value="">{isExporting ? 'Exporting…' : 'Export'}</option>
+              {EXPORT_FORMATS.map((format) => (
+                <option key={format.value} value={format.value}>{format.label}</option>
+              ))}
+            </select>
+          </label>
+          <button onClick={() => setShowImport(true)} className="btn btn-sm btn-ghost" title="Import a roadmap sheet">
+            <Upload size={13} strokeWidth={1.75} />
+            Import
+          </button>
+          <button onClick={loadRoadmap} className="btn btn-sm btn-ghost" title="Refresh data">
+            <RefreshCw size={13} strokeWidth={1.75} />
+            Refresh
+          </button>
+        </div>
       </div>
 
       {/* Kanban Board */}
@@ -283,6 +351,7 @@ const KanbanBoard = () => {
                           <div key={milestone.id} className="milestone-wrapper">
                             <MilestoneCard
                               milestone={milestone}
+                              forecast={forecasts[milestone.id]}
                               onUpdateMilestone={handleUpdateMilestone}
                             />
 
@@ -410,6 +479,10 @@ const KanbanBoard = () => {
         />
       )}
 
+      {showImport && (
+        <ImportRoadmapModal onClose={() => setShowImport(false)} />
+      )}
+
       {showUpdateEpic && (
         <UpdateEpicModal
           epic={selectedEpic}
@@ -5,6 +5,7 @@ import { useMetrics, MetricsProvider } from '../hooks/useMetrics';
 import { useRoadmap } from '../hooks/useRoadmap';
 import MetricCard, { METRIC_CONFIG } from './metrics/MetricCard';
 import MetricBreakdown from './metrics/MetricBreakdown';
+import MetricTrend from './metrics/MetricTrend';
 import './MetricsDashboard.css';
 
 // Format relative time
@@ -29,6 +30,41 @@ const formatRelativeTime = (timestamp) => {
   return `${diffDays} days ago`;
 };
 
+// Collection progress and errors reported by the collector
+const CollectionProgress = ({ collection }) => {
+  if (!collection) return null;
+
+  if (collection.running) {
+    const progress = collection.total > 0 ? ` ${collection.processed}/${collection.total}` : '';
+    return (
+      <span className="metrics-dashboard__collection mono">
+        Collecting {collection.phase}{progress} · {collection.mode}
+      </span>
+    );
+  }
+
+  if (collection.error) {
+    return (
+      <span className="metrics-dashboard__collection metrics-dashboard__collection--error" title={collection.error}>
+        Last collection failed
+      </span>
+    );
+  }
+
+  if (collection.failed_changelogs > 0) {
+    return (
+      <span
+        className="metrics-dashboard__collection metrics-dashboard__collection--warning"
+        title={(collection.changelog_errors || []).join('\n')}
+      >
+        {collection.failed_changelogs} changelogs not fetched
+      </span>
+    );
+  }
+
+  return null;
+};
+
 // Status badge component
 const StatusBadge = ({ status }) => {
   const statusConfig = {
@@ -109,6 +145,11 @@ const METRIC_ORDER = [
   'cycle_time',
   'patch_ratio',
   'time_to_patch',
+  'change_failure_rate',
+  'mean_time_to_restore',
+  'wip_age',
+  'throughput',
+  'flow_efficiency',
 ];
 
 const MetricsDashboardContent = () => {
@@ -192,6 +233,7 @@ const MetricsDashboardContent = () => {
                   {status.releases_count} releases · {status.epics_count} epics · {status.issues_count || 0} issues
                 </span>
               )}
+              <CollectionProgress collection={status.collection} />
             </div>
           )}
         </div>
@@ -266,9 +308,12 @@ const MetricsDashboardContent = () => {
             })}
           </div>
 
-          {/* Expanded Breakdown */}
+          {/* Expanded Trend and Breakdown */}
           {expandedMetric && (
-            <MetricBreakdown metricName={expandedMetric} />
+            <>
+              <MetricTrend metricName={expandedMetric} />
+              <MetricBreakdown metricName={expandedMetric} />
+            </>
           )}
         </>
       )}
@@ -1,9 +1,41 @@
 import React from 'react';
-import { ExternalLink, Pencil } from 'lucide-react';
+import { AlertTriangle, ExternalLink, Pencil } from 'lucide-react';
 import { getJiraIssueUrl } from '../utils/jiraUtils';
 import './MilestoneCard.css';
 
-const MilestoneCard = ({ milestone, onUpdateMilestone }) => {
+const formatForecastDate = (value) =>
+  new Date(value).toLocaleDateString(undefined, { month: 'short', day: 'numeric', timeZone: 'UTC' });
+
+// Completion forecast of the milestone, P50 and P85 with details in the tooltip
+const MilestoneForecast = ({ forecast }) => {
+  const remaining = `${forecast.remaining.epics} epics, ${forecast.remaining.issues} issues remaining`;
+
+  if (forecast.missing_history?.length) {
+    return (
+      <span
+        className="milestone-forecast milestone-forecast--at-risk mono"
+        title={`${remaining}\nNo throughput history for ${forecast.missing_history.join(', ')}`}
+      >
+        No forecast
+      </span>
+    );
+  }
+
+  const title = [
+    remaining,
+    `P50 ${formatForecastDate(forecast.p50)} · P85 ${formatForecastDate(forecast.p85)} · P95 ${formatForecastDate(forecast.p95)}`,
+    forecast.deadline && `Quarter ends ${formatForecastDate(forecast.deadline)}`,
+  ].filter(Boolean).join('\n');
+
+  return (
+    <span className={`milestone-forecast mono${forecast.at_risk ? ' milestone-forecast--at-risk' : ''}`} title={title}>
+      {forecast.at_risk && <AlertTriangle size={10} strokeWidth={2} />}
+      P50 {formatForecastDate(forecast.p50)} · P85 {formatForecastDate(forecast.p85)}
+    </span>
+  );
+};
+
+const MilestoneCard = ({ milestone, forecast, onUpdateMilestone }) => {
   const jiraUrl = getJiraIssueUrl(milestone.key);
   const epicCount = milestone.epics?.length ?? 0;
 
@@ -45,7 +77,10 @@ const MilestoneCard = ({ milestone, onUpdateMilestone }) => {
           </button>
         </div>
       </div>
-      <span className="milestone-key mono">{milestone.key}</span>
+      <div className="milestone-footer">
+        <span className="milestone-key mono">{milestone.key}</span>
+        {forecast && <MilestoneForecast forecast={forecast} />}
+      </div>
     </div>
   );
 };
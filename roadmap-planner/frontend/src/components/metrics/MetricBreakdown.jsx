@@ -61,6 +61,27 @@ const formatBreakdownData = (results, metricName) => {
           max: metadata.max || 0,
           count: metadata.count || 0,
         };
+      case 'change_failure_rate':
+        return {
+          ...base,
+          totalReleases: metadata.total_releases || 0,
+          failedReleases: metadata.failed_releases || 0,
+          hotfixedReleases: metadata.hotfixed_releases || 0,
+        };
+      case 'mean_time_to_restore':
+      case 'wip_age':
+      case 'flow_efficiency':
+        return {
+          ...base,
+          min: metadata.min || 0,
+          max: metadata.max || 0,
+          count: metadata.sample_size || 0,
+        };
+      case 'throughput':
+        return {
+          ...base,
+          count: metadata.completed_epics || 0,
+        };
       default:
         return base;
     }
@@ -104,6 +125,39 @@ const TABLE_COLUMNS = {
     { key: 'max', label: 'Max', format: (v) => Math.round(v) },
     { key: 'count', label: 'Issues' },
   ],
+  change_failure_rate: [
+    { key: 'component', label: 'Component' },
+    { key: 'value', label: 'Rate', format: (v) => v.toFixed(2) },
+    { key: 'totalReleases', label: 'Total' },
+    { key: 'failedReleases', label: 'Failed' },
+    { key: 'hotfixedReleases', label: 'Hotfixed' },
+  ],
+  mean_time_to_restore: [
+    { key: 'component', label: 'Component' },
+    { key: 'value', label: 'Mean (hours)', format: (v) => Math.round(v) },
+    { key: 'min', label: 'Min', format: (v) => Math.round(v) },
+    { key: 'max', label: 'Max', format: (v) => Math.round(v) },
+    { key: 'count', label: 'Incidents' },
+  ],
+  wip_age: [
+    { key: 'component', label: 'Component' },
+    { key: 'value', label: 'P50 (days)', format: (v) => Math.round(v) },
+    { key: 'min', label: 'Min', format: (v) => Math.round(v) },
+    { key: 'max', label: 'Max', format: (v) => Math.round(v) },
+    { key: 'count', label: 'Epics' },
+  ],
+  throughput: [
+    { key: 'component', label: 'Component' },
+    { key: 'value', label: 'Epics/Week', format: (v) => v.toFixed(2) },
+    { key: 'count', label: 'Completed' },
+  ],
+  flow_efficiency: [
+    { key: 'component', label: 'Component' },
+    { key: 'value', label: 'Efficiency', format: (v) => v.toFixed(2) },
+    { key: 'min', label: 'Min', format: (v) => v.toFixed(2) },
+    { key: 'max', label: 'Max', format: (v) => v.toFixed(2) },
+    { key: 'count', label: 'Epics' },
+  ],
 };
 
 const MetricBreakdown = ({ metricName }) => {
@@ -1,5 +1,5 @@
 import React from 'react';
-import { ChevronDown, ChevronUp, TrendingUp, Clock, Repeat, AlertTriangle, Zap } from 'lucide-react';
+import { ChevronDown, ChevronUp, TrendingUp, Clock, Repeat, AlertTriangle, Zap, ShieldAlert, LifeBuoy, Hourglass, Gauge, Activity } from 'lucide-react';
 import './MetricCard.css';
 
 // Metric configuration with thresholds and icons
@@ -50,6 +50,46 @@ const METRIC_CONFIG = {
     higherIsBetter: false,
     description: 'Time to release bug/security fixes',
   },
+  change_failure_rate: {
+    displayName: 'Change Failure Rate',
+    icon: ShieldAlert,
+    unit: '',
+    thresholds: { excellent: 0.15, good: 0.3, warning: 0.45 },
+    higherIsBetter: false,
+    description: 'Ratio of releases followed by a hotfix or bugs',
+  },
+  mean_time_to_restore: {
+    displayName: 'Time to Restore',
+    icon: LifeBuoy,
+    unit: 'hours (mean)',
+    thresholds: { excellent: 24, good: 72, warning: 168 },
+    higherIsBetter: false,
+    description: 'Time from incident report to restoration',
+  },
+  wip_age: {
+    displayName: 'WIP Age',
+    icon: Hourglass,
+    unit: 'days (P50)',
+    thresholds: { excellent: 14, good: 30, warning: 60 },
+    higherIsBetter: false,
+    description: 'Time since the epics in progress were started',
+  },
+  throughput: {
+    displayName: 'Throughput',
+    icon: Activity,
+    unit: 'epics/week',
+    thresholds: { excellent: 2, good: 1, warning: 0.5 },
+    higherIsBetter: true,
+    description: 'Epics completed per week',
+  },
+  flow_efficiency: {
+    displayName: 'Flow Efficiency',
+    icon: Gauge,
+    unit: '',
+    thresholds: { excellent: 0.4, good: 0.25, warning: 0.15 },
+    higherIsBetter: true,
+    description: 'Share of the cycle time spent in active statuses',
+  },
 };
 
 // Determine status based on value and thresholds
@@ -76,12 +116,12 @@ const formatValue = (value, metricName) => {
   if (value === null || value === undefined) return 'N/A';
   if (isNaN(value)) return 'N/A';
 
-  if (metricName === 'patch_ratio') {
+  if (['patch_ratio', 'change_failure_rate', 'flow_efficiency'].includes(metricName)) {
     return value.toFixed(2);
   }
 
   // For days-based metrics, show whole numbers
-  if (metricName.includes('time') || metricName.includes('lead') || metricName.includes('cycle')) {
+  if (metricName.includes('time') || metricName.includes('lead') || metricName.includes('cycle') || metricName.includes('age')) {
     return Math.round(value);
   }
 
@@ -89,6 +129,13 @@ const formatValue = (value, metricName) => {
   return value.toFixed(1);
 };
 
+// Classify a trend such as "+15%" as an improvement or a regression
+const getTrendDirection = (trend, config) => {
+  const change = parseFloat(trend);
+  if (!change) return 'flat';
+  return (change > 0) === Boolean(config.higherIsBetter) ? 'better' : 'worse';
+};
+
 const MetricCard = ({ metricName, data, expanded, onToggle }) => {
   const config = METRIC_CONFIG[metricName] || {
     displayName: metricName,
@@ -122,6 +169,15 @@ const MetricCard = ({ metricName, data, expanded, onToggle }) => {
           {config.unit && <span className="metric-card__unit">{config.unit}</span>}
         </div>
 
+        {data?.trend && (
+          <span
+            className={`metric-card__trend metric-card__trend--${getTrendDirection(data.trend, config)}`}
+            title="Change over the trend window"
+          >
+            {data.trend}
+          </span>
+        )}
+
         {statusLabel && (
           <span className={`metric-card__status-label metric-card__status-label--${status}`}>
             {statusLabel}
@@ -154,4 +210,4 @@ const MetricCard = ({ metricName, data, expanded, onToggle }) => {
 };
 
 export default MetricCard;
-export { METRIC_CONFIG, getStatus, formatValue };
+export { METRIC_CONFIG, getStatus, formatValue, getTrendDirection };
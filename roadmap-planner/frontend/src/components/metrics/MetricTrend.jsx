@@ -0,0 +1,120 @@
+import React, { useEffect, useState } from 'react';
+import { LineChart, Line, XAxis, YAxis, CartesianGrid, Tooltip, ResponsiveContainer } from 'recharts';
+import { useMetrics } from '../../hooks/useMetrics';
+import { METRIC_CONFIG, formatValue } from './MetricCard';
+import './MetricTrend.css';
+
+// Selectable history ranges in days
+const RANGES = [
+  { days: 30, label: '30D' },
+  { days: 90, label: '90D' },
+  { days: 180, label: '180D' },
+  { days: 365, label: '1Y' },
+];
+
+// Format a snapshot timestamp for the axis
+const formatDate = (timestamp) =>
+  new Date(timestamp).toLocaleDateString(undefined, { month: 'short', day: 'numeric' });
+
+const MetricTrend = ({ metricName }) => {
+  const { loadHistory, filters } = useMetrics();
+  const [range, setRange] = useState(90);
+  const [points, setPoints] = useState([]);
+  const [isLoading, setIsLoading] = useState(true);
+  const [error, setError] = useState(null);
+
+  useEffect(() => {
+    const fetchHistory = async () => {
+      setIsLoading(true);
+      setError(null);
+
+      const from = new Date(Date.now() - range * 24 * 60 * 60 * 1000).toISOString();
+      const result = await loadHistory(metricName, from);
+      if (result.success) {
+        setPoints((result.data.points || []).map(point => ({
+          timestamp: new Date(point.timestamp).getTime(),
+          value: point.value,
+        })));
+      } else if (result.status === 503) {
+        // Storage disabled on the server, nothing to chart
+        setPoints([]);
+      } else {
+        setError(result.error);
+      }
+
+      setIsLoading(false);
+    };
+
+    fetchHistory();
+  }, [metricName, range, loadHistory]);
+
+  const config = METRIC_CONFIG[metricName];
+  const scope = filters.components.length === 1 ? filters.components[0] : 'All components';
+
+  return (
+    <div className="metric-trend">
+      <div className="metric-trend__header">
+        <h4 className="metric-trend__title">
+          {config?.displayName || metricName} - Trend · {scope}
+        </h4>
+        <div className="metric-trend__ranges" role="group" aria-label="History range">
+          {RANGES.map(r => (
+            <button
+              key={r.days}
+              className={`metric-trend__range ${range === r.days ? 'metric-trend__range--active' : ''}`}
+              onClick={() => setRange(r.days)}
+            >
+              {r.label}
+            </button>
+          ))}
+        </div>
+      </div>
+
+      {isLoading && (
+        <div className="metric-trend__message">
+          <div className="loading-spinner" />
+          <span>Loading history...</span>
+        </div>
+      )}
+
+      {!isLoading && error && (
+        <div className="metric-trend__message metric-trend__message--error">
+          Failed to load history: {error}
+        </div>
+      )}
+
+      {!isLoading && !error && points.length < 2 && (
+        <div className="metric-trend__message">
+          Not enough history yet — snapshots are recorded periodically.
+        </div>
+      )}
+
+      {!isLoading && !error && points.length >= 2 && (
+        <ResponsiveContainer width="100%" height={220}>
+          <LineChart data={points} margin={{ top: 5, right: 30, left: 0, bottom: 5 }}>
+            <CartesianGrid strokeDasharray="3 3" vertical={false} />
+            <XAxis
+              dataKey="timestamp"
+              type="number"
+              scale="time"
+              domain={['dataMin', 'dataMax']}
+              tickFormatter={formatDate}
+              tick={{ fontSize: 12 }}
+            />
+            <YAxis tick={{ fontSize: 12 }} width={50} />
+            <Tooltip
+              labelFormatter={(label) => new Date(label).toLocaleString()}
+              formatter={(value) => [
+                `${formatValue(value, metricName)} ${config?.unit || ''}`.trim(),
+                config?.displayName || metricName,
+              ]}
+            />
+            <Line type="monotone" dataKey="value" stroke="var(--accent)" strokeWidth={2} dot={false} />
+          </LineChart>
+        </ResponsiveContainer>
+      )}
+    </div>
+  );
+};
+
+export default MetricTrend;
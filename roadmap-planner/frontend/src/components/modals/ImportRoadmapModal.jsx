@@ -0,0 +1,166 @@
+import React, { useState } from 'react';
+import toast from 'react-hot-toast';
+import { X, Upload } from 'lucide-react';
+import { roadmapAPI, handleAPIError } from '../../services/api';
+import { useRoadmap } from '../../hooks/useRoadmap';
+import './Modal.css';
+import './ImportRoadmapModal.css';
+
+const ACTION_LABELS = {
+  create_milestone: 'Create milestone',
+  create_epic: 'Create epic',
+  move_epic: 'Move epic',
+};
+
+const ImportRoadmapModal = ({ onClose }) => {
+  const { loadRoadmap } = useRoadmap();
+  const [file, setFile] = useState(null);
+  const [plan, setPlan] = useState(null);
+  const [results, setResults] = useState(null);
+  const [error, setError] = useState('');
+  const [isSubmitting, setIsSubmitting] = useState(false);
+
+  const onFileChange = (event) => {
+    setFile(event.target.files?.[0] || null);
+    setPlan(null);
+    setResults(null);
+    setError('');
+  };
+
+  // preview runs a dry run, apply imports the previewed sheet
+  const submit = async (dryRun) => {
+    if (!file) return;
+    setIsSubmitting(true);
+    setError('');
+    try {
+      const response = await roadmapAPI.importRoadmap(file, dryRun);
+      setPlan(response.plan);
+      if (!dryRun) {
+        setResults(response.results);
+        if (response.failed > 0) {
+          toast.error(`${response.failed} change(s) failed`);
+        } else {
+          toast.success(`Imported ${response.results.length} change(s)`);
+        }
+        await loadRoadmap();
+      }
+    } catch (err) {
+      const errorInfo = handleAPIError(err);
+      setError(errorInfo.message);
+      if (errorInfo.data?.plan) setPlan(errorInfo.data.plan);
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  const canApply = plan && !results && plan.errors.length === 0 && plan.changes.length > 0;
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content import-modal" onClick={(e) => e.stopPropagation()}>
+        <div className="modal-header">
+          <div>
+            <h2>Import Roadmap</h2>
+            <p>Preview the differences of a CSV, XLSX or JSON sheet with Jira, then apply them</p>
+          </div>
+          <button onClick={onClose} className="btn btn-secondary" aria-label="Close modal">
+            <X size={16} />
+          </button>
+        </div>
+
+        <div className="modal-body">
+          <div className="form-group">
+            <label htmlFor="import_file" className="form-label">Sheet</label>
+            <input
+              id="import_file"
+              type="file"
+              accept=".csv,.xlsx,.json"
+              className="form-input"
+              onChange={onFileChange}
+              disabled={isSubmitting}
+            />
+          </div>
+
+          {error && <div className="form-error mb-4">{error}</div>}
+
+          {plan && (
+            <div className="import-plan">
+              <p className="import-plan__summary mono">
+                {plan.changes.length} change(s) · {plan.unchanged} unchanged · {plan.errors.length} error(s)
+              </p>
+              {plan.errors.length > 0 && (
+                <ul className="import-plan__errors">
+                  {plan.errors.map((rowError) => (
+                    <li key={`${rowError.line}-${rowError.message}`}>
+                      <span className="mono">Line {rowError.line}</span> {rowError.message}
+                    </li>
+                  ))}
+                </ul>
+              )}
+              {plan.changes.length > 0 && (
+                <table className="import-plan__table">
+                  <thead>
+                    <tr>
+                      <th>Line</th>
+                      <th>Action</th>
+                      <th>Milestone</th>
+                      <th>Epic</th>
+                      {results && <th>Result</th>}
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {(results || plan.changes).map((change, index) => (
+                      <tr key={`${change.line}-${change.action}-${index}`}>
+                        <td className="mono">{change.line}</td>
+                        <td>{ACTION_LABELS[change.action] || change.action}</td>
+                        <td>{change.milestone}{change.quarter ? ` (${change.quarter})` : ''}</td>
+                        <td>{change.epic_key ? `${change.epic_key} ` : ''}{change.epic}</td>
+                        {results && (
+                          <td className={change.error ? 'import-plan__failed' : 'import-plan__applied'}>
+                            {change.error || change.key || 'Applied'}
+                          </td>
+                        )}
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              )}
+            </div>
+          )}
+        </div>
+
+        <div className="modal-footer">
+          <button type="button" onClick={onClose} className="btn btn-secondary" disabled={isSubmitting}>
+            {results ? 'Close' : 'Cancel'}
+          </button>
+          <button
+            type="button"
+            onClick={() => submit(true)}
+            className="btn btn-secondary"
+            disabled={!file || isSubmitting}
+          >
+            <Upload size={14} strokeWidth={1.75} />
+            Preview
+          </button>
+          <button
+            type="button"
+            onClick={() => submit(false)}
+            className="btn btn-primary"
+            disabled={!canApply || isSubmitting}
+          >
+            {isSubmitting ? (
+              <>
+                <div className="loading-spinner-sm"></div>
+                Importing...
+              </>
+            ) : (
+              'Apply changes'
+            )}
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ImportRoadmapModal;
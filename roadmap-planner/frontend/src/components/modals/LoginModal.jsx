@@ -1,6 +1,7 @@
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
 import { useForm } from 'react-hook-form';
 import { useAuth } from '../../hooks/useAuth';
+import { authAPI } from '../../services/api';
 import { Eye, EyeOff, Compass } from 'lucide-react';
 import './Modal.css';
 import './LoginModal.css';
@@ -8,6 +9,13 @@ import './LoginModal.css';
 const LoginModal = () => {
   const { login, isLoading } = useAuth();
   const [showPassword, setShowPassword] = useState(false);
+  const [ssoEnabled, setSsoEnabled] = useState(false);
+
+  useEffect(() => {
+    authAPI.config()
+      .then((config) => setSsoEnabled(Boolean(config.oidc)))
+      .catch(() => setSsoEnabled(false));
+  }, []);
 
   const {
     register,
@@ -69,6 +77,15 @@ const LoginModal = () => {
             </p>
           </header>
 
+          {ssoEnabled && (
+            <div className="login-form__sso">
+              <a href={authAPI.oidcLoginURL()} className="btn btn-secondary btn-lg w-full">
+                Sign in with SSO
+              </a>
+              <span className="login-form__divider mono">or with Jira credentials</span>
+            </div>
+          )}
+
           <form onSubmit={handleSubmit(onSubmit)} className="login-form">
             <div className="form-group">
               <label htmlFor="base_url" className="form-label">Jira Base URL</label>
@@ -25,23 +25,38 @@ export const AuthProvider = ({ children }) => {
   }, []);
 
   const checkAuthStatus = async () => {
+    // Single sign-on redirects back with auth_error when it failed
+    const params = new URLSearchParams(window.location.search);
+    const authError = params.get('auth_error');
+    if (authError) {
+      toast.error(authError);
+      params.delete('auth_error');
+      const query = params.toString();
+      window.history.replaceState(null, '', `${window.location.pathname}${query ? `?${query}` : ''}`);
+    }
+
     try {
       const storedAuth = getStoredAuth();
-      if (!storedAuth) {
-        setIsLoading(false);
-        return;
-      }
 
-      // Verify with server
+      // Verify the session cookie with server, single sign-on sessions have no stored details yet
       const response = await authAPI.status();
       if (response.authenticated) {
+        const currentProject = storedAuth?.project || response.project;
+        setStoredAuth({
+          username: response.user,
+          baseURL: response.base_url,
+          project: currentProject,
+        });
         setIsAuthenticated(true);
         setUser(response.user);
+        setProject(currentProject);
       } else {
         clearStoredAuth();
       }
     } catch (error) {
-      console.error('Auth check failed:', error);
+      if (error.response?.status !== 401) {
+        console.error('Auth check failed:', error);
+      }
       clearStoredAuth();
     } finally {
       setIsLoading(false);
@@ -68,10 +83,10 @@ export const AuthProvider = ({ children }) => {
     setProject(prevProject => {
       if (prevProject !== newProject) {
         // Update auth data
-        setStoredAuth(prevAuth => ({
-          ...prevAuth,
+        setStoredAuth({
+          ...getStoredAuth(),
           project: newProject,
-        }));
+        });
 
         // Trigger all registered callbacks
         projectCallbacks.forEach(callback => {
@@ -90,18 +105,17 @@ export const AuthProvider = ({ children }) => {
     try {
       setIsLoading(true);
 
-      // Validate credentials with server
+      // Validate credentials with server, which starts a session cookie
       const response = await authAPI.login(credentials);
 
-      // Store credentials for API requests
+      // Store the session details, the Jira token stays on the server
       const authData = {
-        username: credentials.username,
-        password: credentials.password,
-        baseURL: credentials.base_url,
-        project: credentials.project,
+        username: response.user,
+        baseURL: response.base_url,
+        project: response.project,
       };
 
-      setProject(credentials.project);
+      setProject(response.project);
       setStoredAuth(authData);
       setIsAuthenticated(true);
       setUser(response.user);
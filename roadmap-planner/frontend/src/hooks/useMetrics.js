@@ -64,6 +64,18 @@ export const MetricsProvider = ({ children }) => {
     }
   }, [filters]);
 
+  // Load the recorded history of a metric, scoped to the component filter when exactly one is selected
+  const loadHistory = useCallback(async (name, from) => {
+    try {
+      const component = filters.components.length === 1 ? filters.components[0] : undefined;
+      const data = await metricsAPI.getHistory(name, { component, from });
+      return { success: true, data };
+    } catch (err) {
+      const errorInfo = handleAPIError(err);
+      return { success: false, error: errorInfo.message, status: errorInfo.status };
+    }
+  }, [filters]);
+
   // Update filters
   const updateFilters = useCallback((newFilters) => {
     setFilters(prev => ({
@@ -104,6 +116,7 @@ export const MetricsProvider = ({ children }) => {
     clearFilters,
     refresh,
     loadMetric,
+    loadHistory,
   };
 
   return (
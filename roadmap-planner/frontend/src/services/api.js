@@ -4,23 +4,22 @@ import axios from 'axios';
 // In development with Vite, leave baseURL empty so the dev-server proxy handles /api → http://localhost:8080
 const apiBaseURL = import.meta.env?.VITE_API_URL ?? '';
 
+// The session is kept in an HTTP-only cookie set by /api/auth/login
 const api = axios.create({
   baseURL: apiBaseURL,
   timeout: 30000,
+  withCredentials: true,
   headers: {
     'Content-Type': 'application/json',
   },
 });
 
-// Request interceptor to add authentication headers
+// Request interceptor to select the Jira project of the session
 api.interceptors.request.use(
   (config) => {
     const auth = getStoredAuth();
-    if (auth) {
-      config.headers['X-Jira-Username'] = auth.username;
-      config.headers['X-Jira-Password'] = auth.password;
-      config.headers['X-Jira-BaseURL'] = auth.baseURL;
-      config.headers['X-Jira-Project'] = auth.project || 'DEVOPS';
+    if (auth?.project) {
+      config.headers['X-Jira-Project'] = auth.project;
     }
     return config;
   },
@@ -33,7 +32,8 @@ api.interceptors.request.use(
 api.interceptors.response.use(
   (response) => response,
   (error) => {
-    if (error.response?.status === 401) {
+    // The status check answers 401 before signing in, it must not reload the page
+    if (error.response?.status === 401 && !error.config?.url?.endsWith('/api/auth/status')) {
       // Clear stored auth on unauthorized
       clearStoredAuth();
       window.location.reload();
@@ -43,6 +43,7 @@ api.interceptors.response.use(
 );
 
 // Auth storage helpers
+// Only non-secret session details are stored: username, baseURL and project
 const AUTH_STORAGE_KEY = 'roadmap_planner_auth';
 
 export const getStoredAuth = () => {
@@ -79,9 +80,40 @@ export const authAPI = {
     const response = await api.get('/api/auth/status');
     return response.data;
   },
+
+  // config returns the login methods offered by the server
+  config: async () => {
+    const response = await api.get('/api/auth/config');
+    return response.data;
+  },
+
+  // oidcLoginURL starts single sign-on, the browser is redirected back with a session cookie
+  oidcLoginURL: () => `${apiBaseURL}/api/auth/oidc/login`,
 };
 
 export const roadmapAPI = {
+  // exportRoadmap downloads the roadmap as csv, xlsx, md or json
+  exportRoadmap: async (format, filters = {}) => {
+    const params = new URLSearchParams({ format });
+    (filters.quarters || []).forEach(quarter => params.append('quarter', quarter));
+    (filters.pillarIds || []).forEach(id => params.append('pillar_id', id));
+    (filters.components || []).forEach(component => params.append('component', component));
+    const response = await api.get(`/api/export?${params.toString()}`, { responseType: 'blob' });
+    const disposition = response.headers['content-disposition'] || '';
+    const filename = disposition.match(/filename="?([^"]+)"?/)?.[1] || `roadmap.${format}`;
+    return { blob: response.data, filename };
+  },
+
+  // importRoadmap previews the changes of a sheet, or applies them when dryRun is false
+  importRoadmap: async (file, dryRun = true) => {
+    const formData = new FormData();
+    formData.append('file', file);
+    const response = await api.post(`/api/import?dry_run=${dryRun}`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  },
+
   getBasicData: async () => {
     const response = await api.get('/api/basic');
     return response.data;
@@ -118,6 +150,42 @@ export const roadmapAPI = {
     return response.data;
   },
 
+  // getDependencies returns the dependency graph of the epics of milestones or quarters
+  getDependencies: async (filters = {}) => {
+    const params = new URLSearchParams();
+    (filters.milestoneIds || []).forEach(id => params.append('milestone_id', id));
+    (filters.quarters || []).forEach(quarter => params.append('quarter', quarter));
+    const response = await api.get(`/api/dependencies?${params.toString()}`);
+    return response.data;
+  },
+
+  // getForecast returns the completion forecasts of the milestones of quarters
+  getForecast: async (filters = {}) => {
+    const params = new URLSearchParams();
+    (filters.milestoneIds || []).forEach(id => params.append('milestone_id', id));
+    (filters.quarters || []).forEach(quarter => params.append('quarter', quarter));
+    const response = await api.get(`/api/forecast?${params.toString()}`);
+    return response.data;
+  },
+
+  // getAuditLog returns the changes of milestones and epics, most recent first
+  getAuditLog: async (filters = {}) => {
+    const params = new URLSearchParams();
+    ['entity', 'entity_type', 'user', 'from', 'to', 'limit'].forEach(name => {
+      if (filters[name]) params.append(name, filters[name]);
+    });
+    const response = await api.get(`/api/audit?${params.toString()}`);
+    return response.data;
+  },
+
+  // getAuditSnapshot returns the milestones and epics as they were at a past time
+  getAuditSnapshot: async (at, quarters = []) => {
+    const params = new URLSearchParams({ at });
+    quarters.forEach(quarter => params.append('quarter', quarter));
+    const response = await api.get(`/api/audit/snapshot?${params.toString()}`);
+    return response.data;
+  },
+
   createMilestone: async (milestoneData) => {
     const response = await api.post('/api/milestones', milestoneData);
     return response.data;
@@ -196,6 +264,15 @@ export const metricsAPI = {
     const response = await api.get('/api/metrics/status');
     return response.data;
   },
+
+  // Get recorded values of a metric over time
+  getHistory: async (name, { component, from } = {}) => {
+    const params = new URLSearchParams();
+    if (component) params.append('component', component);
+    if (from) params.append('from', from);
+    const response = await api.get(`/api/metrics/${name}/history?${params.toString()}`);
+    return response.data;
+  },
 };
 
 // Error handling helper
@@ -18,6 +18,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -25,46 +26,63 @@ import (
 	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fs"
 	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
 	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/repository"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/transform"
 	"github.com/spf13/cobra"
 )
 
+// CopyOptions options for the copy command
+type CopyOptions struct {
+	// ConfigFile path to the configuration file
+	ConfigFile string
+	// CleanTarget removes the target folder of each source before copying
+	// all files are copied again instead of only the changed ones
+	CleanTarget bool
+	// SkipLink skips creating symbolic links
+	SkipLink bool
+	// CacheDir directory where repository sources are cloned
+	CacheDir string
+	// DryRun prints the files to copy and the links to create without changing the target
+	DryRun bool
+}
+
 func NewCopyCommand(ctx context.Context) *cobra.Command {
 	var (
-		configFile   string
-		cleanTarget  bool
+		opts         CopyOptions
 		copyDirectly bool
-		skipLink     bool
 	)
 	cmd := &cobra.Command{
 		Use:   "copy",
 		Short: "Copy files from multiple sources to a target based on a configuration file",
 		Long:  copyLongDescription,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if configFile == "" {
+			if opts.ConfigFile == "" {
 				return fmt.Errorf("--config is required")
 			}
 			if copyDirectly {
 				return fmt.Errorf("--copy-directly is not supported yet")
 			}
-			return RunCopy(ctx, cmd, args, configFile, cleanTarget, skipLink)
+			return RunCopy(ctx, cmd, args, opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to the configuration file")
-	cmd.Flags().BoolVarP(&cleanTarget, "clean", "x", true, "Clean the target before copying. Defaults to true")
+	cmd.Flags().StringVarP(&opts.ConfigFile, "config", "c", "", "Path to the configuration file")
+	cmd.Flags().BoolVarP(&opts.CleanTarget, "clean", "x", true, "Clean the target before copying. Use --clean=false to sync only changed files. Defaults to true")
 	cmd.Flags().BoolVarP(&copyDirectly, "copy-directly", "d", false, "Copy files directly from source to target. Defaults to false")
-	cmd.Flags().BoolVarP(&skipLink, "skip-link", "s", false, "Skip creating symbolic links. Defaults to false")
+	cmd.Flags().BoolVarP(&opts.SkipLink, "skip-link", "s", false, "Skip creating symbolic links. Defaults to false")
+	cmd.Flags().StringVar(&opts.CacheDir, "cache-dir", repository.DefaultCacheDir(), "Directory where repository sources are cloned and reused across runs")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the files to copy and the links to create, replace or remove without changing the target. Repository sources are still fetched into --cache-dir. Defaults to false")
 	return cmd
 }
 
-// RunCopy runs copy command logic to copy files from multiple sources to a target based on a configuration file
-func RunCopy(ctx context.Context, cmd *cobra.Command, args []string, configFile string, cleanTarget bool, skipLink bool) error {
+// loadConfig loads, defaults and validates the configuration file
+func loadConfig(ctx context.Context, configFile string) (*config.CopyConfig, error) {
 	log := logger.GetLogger(ctx)
 
 	config, err := config.Load(ctx, configFile)
 	if err != nil {
 		log.Error("error loading config: ", err)
-		return err
+		return nil, err
 	}
 	if config.Target == nil {
 		config.Target = config.Target.Default()
@@ -72,48 +90,232 @@ func RunCopy(ctx context.Context, cmd *cobra.Command, args []string, configFile
 
 	if err := config.Validate(ctx); err != nil {
 		log.Error("error validating config: ", err)
+		return nil, err
+	}
+	return config, nil
+}
+
+// RunCopy runs copy command logic to copy files from multiple sources to a target based on a configuration file
+func RunCopy(ctx context.Context, cmd *cobra.Command, args []string, opts CopyOptions) error {
+	log := logger.GetLogger(ctx)
+
+	config, err := loadConfig(ctx, opts.ConfigFile)
+	if err != nil {
 		return err
 	}
 
-	copier := fscopy.FileSystemCopier{}
-	selector := fscopy.FileSystemSelector{}
+	checkouter := &repository.GitCheckouter{CacheDir: opts.CacheDir}
 	for _, source := range config.Sources {
-		log.Info("Listing files in source: ", source.Name)
-		files, err := selector.ListFiles(ctx, source.Dir.Path)
+		sourceFolder, err := SourcePath(ctx, checkouter, source)
 		if err != nil {
-			log.Error("error listing files in source ", source.Name, " error: ", err)
+			log.Error("error preparing source ", source.Name, " error: ", err)
 			return err
 		}
-		log.Debug("files in source ", source.Name, " are: ", files)
-		links := config.Target.Parse(source)
-		log.Debug("links in source ", source.Name, " are: ", links)
 
-		// TODO: skip real logic in dry-run mode later
-		targetFolderForSource := filepath.Join(config.Target.CopyTo, source.Name)
-		sourceFolder := source.Dir.Path
-
-		if cleanTarget {
-			log.Info("Cleaning target folder: ", targetFolderForSource)
-			if err := os.RemoveAll(targetFolderForSource); err != nil {
-				log.Error("error cleaning target folder ", targetFolderForSource, " error: ", err)
+		if opts.DryRun {
+			if err := dryRunSource(ctx, cmd, config, source, sourceFolder, opts); err != nil {
+				log.Error("error planning source ", source.Name, " error: ", err)
+				return err
 			}
+			continue
 		}
-		log.Info("Copying files from source: ", sourceFolder, " to ", targetFolderForSource)
-		if err := copier.Copy(ctx, sourceFolder, targetFolderForSource, files...); err != nil {
-			log.Error("error copying files from source ", sourceFolder, " error: ", err)
+
+		plan, err := syncSource(ctx, config, source, sourceFolder, opts)
+		if err != nil {
 			return err
 		}
-		if !skipLink {
-			log.Info("Linking files from source: ", sourceFolder, " to ", config.Target.LinkTo)
-			if err := copier.Link(ctx, targetFolderForSource, config.Target.LinkTo, links...); err != nil {
-				log.Error("error linking files from source ", sourceFolder, " error: ", err)
-				return err
-			}
+		printSyncPlan(cmd, source.Name, plan)
+	}
+	return nil
+}
+
+// listSourceFiles lists the files of a source folder respecting .syncignore files
+func listSourceFiles(ctx context.Context, source config.CopySource, sourceFolder string) ([]fs.FileInfo, error) {
+	log := logger.GetLogger(ctx)
+	selector := fscopy.FileSystemSelector{}
+
+	log.Info("Listing files in source: ", source.Name)
+	files, err := selector.ListFiles(ctx, sourceFolder)
+	if err != nil {
+		log.Error("error listing files in source ", source.Name, " error: ", err)
+		return nil, err
+	}
+	log.Debug("files in source ", source.Name, " are: ", files)
+	return files, nil
+}
+
+// newSourceCopier returns a copier applying the transforms of the configuration followed by the transforms of the source
+func newSourceCopier(copyConfig *config.CopyConfig, source config.CopySource) (*fscopy.FileSystemCopier, *transform.Pipeline, error) {
+	transforms := append(append([]config.Transform{}, copyConfig.Transforms...), source.Transforms...)
+	pipeline, err := transform.New(source.Name, transforms...)
+	if err != nil {
+		return nil, nil, err
+	}
+	copier := &fscopy.FileSystemCopier{}
+	if !pipeline.Empty() {
+		copier.Transformer = pipeline
+	}
+	return copier, pipeline, nil
+}
+
+// reportBrokenLinks logs the links of the planned files that do not point to a copied file
+func reportBrokenLinks(ctx context.Context, source string, pipeline *transform.Pipeline, plan *fscopy.SyncPlan) {
+	log := logger.GetLogger(ctx)
+	files := []string{}
+	for _, item := range plan.Items {
+		if item.Action != fscopy.SyncDeleted {
+			files = append(files, item.Path)
 		}
 	}
+	for _, broken := range pipeline.BrokenLinks(files...) {
+		log.Warn("broken link in source ", source, ": ", broken)
+	}
+}
+
+// syncSource incrementally syncs the files of a source folder to its target folder and creates the links
+func syncSource(ctx context.Context, copyConfig *config.CopyConfig, source config.CopySource, sourceFolder string, opts CopyOptions) (*fscopy.SyncPlan, error) {
+	log := logger.GetLogger(ctx)
+	target := copyConfig.Target
+
+	copier, pipeline, err := newSourceCopier(copyConfig, source)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listSourceFiles(ctx, source, sourceFolder)
+	if err != nil {
+		return nil, err
+	}
+	links := target.Parse(source)
+	log.Debug("links in source ", source.Name, " are: ", links)
+
+	targetFolderForSource := filepath.Join(target.CopyTo, source.Name)
+	manifestPath := fscopy.ManifestPath(target.CopyTo, source.Name)
+
+	var plan *fscopy.SyncPlan
+	if opts.CleanTarget {
+		plan, err = cleanSync(ctx, copier, source.Name, sourceFolder, targetFolderForSource, manifestPath, files...)
+	} else {
+		log.Info("Syncing files from source: ", sourceFolder, " to ", targetFolderForSource)
+		plan, err = copier.Sync(ctx, source.Name, sourceFolder, targetFolderForSource, manifestPath, files...)
+	}
+	if err != nil {
+		log.Error("error copying files from source ", sourceFolder, " error: ", err)
+		return nil, err
+	}
+	reportBrokenLinks(ctx, source.Name, pipeline, plan)
+	if !opts.SkipLink {
+		log.Info("Linking files from source: ", sourceFolder, " to ", target.LinkTo)
+		if err := copier.Link(ctx, targetFolderForSource, target.LinkTo, links...); err != nil {
+			log.Error("error linking files from source ", sourceFolder, " error: ", err)
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// cleanSync removes the target folder and copies all the files again
+// the plan is made before cleaning so that it reports the same deletes and adds as the dry run
+func cleanSync(ctx context.Context, copier *fscopy.FileSystemCopier, source, sourceFolder, targetFolder, manifestPath string, files ...fs.FileInfo) (*fscopy.SyncPlan, error) {
+	log := logger.GetLogger(ctx)
+
+	manifest, err := fscopy.LoadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := copier.Plan(ctx, sourceFolder, targetFolder, manifest, files...)
+	if err != nil {
+		return nil, err
+	}
+	if err := fscopy.CleanPlan(plan); err != nil {
+		return nil, err
+	}
+
+	log.Info("Cleaning target folder: ", targetFolder)
+	if err := os.RemoveAll(targetFolder); err != nil {
+		log.Error("error cleaning target folder ", targetFolder, " error: ", err)
+	}
+	log.Info("Copying files from source: ", sourceFolder, " to ", targetFolder)
+	if err := copier.Apply(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, plan.Manifest(source).Save(manifestPath)
+}
+
+// dryRunSource prints the sync and link plans of a source without changing the target
+func dryRunSource(ctx context.Context, cmd *cobra.Command, copyConfig *config.CopyConfig, source config.CopySource, sourceFolder string, opts CopyOptions) error {
+	out := outputWriter(cmd)
+	target := copyConfig.Target
+	targetFolder := filepath.Join(target.CopyTo, source.Name)
+
+	copier, pipeline, err := newSourceCopier(copyConfig, source)
+	if err != nil {
+		return err
+	}
+
+	files, err := listSourceFiles(ctx, source, sourceFolder)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := fscopy.LoadManifest(fscopy.ManifestPath(target.CopyTo, source.Name))
+	if err != nil {
+		return err
+	}
+	plan, err := copier.Plan(ctx, sourceFolder, targetFolder, manifest, files...)
+	if err != nil {
+		return err
+	}
+	if opts.CleanTarget {
+		fmt.Fprintf(out, "x %s (clean)\n", targetFolder)
+		// all files are removed and copied again after cleaning
+		if err := fscopy.CleanPlan(plan); err != nil {
+			return err
+		}
+	}
+	printSyncPlan(cmd, source.Name, plan)
+	reportBrokenLinks(ctx, source.Name, pipeline, plan)
+
+	if opts.SkipLink {
+		return nil
+	}
+	// links are planned against the source folder as the files are not copied yet
+	for _, link := range copier.PlanLinksFrom(ctx, targetFolder, target.LinkTo, sourceFolder, target.Parse(source)...) {
+		fmt.Fprintf(out, "%s link %s -> %s\n", link.Action, link.TargetPath, link.SourcePath)
+	}
 	return nil
 }
 
+// outputWriter returns the output of the command, stdout if there is no command
+func outputWriter(cmd *cobra.Command) io.Writer {
+	if cmd != nil {
+		return cmd.OutOrStdout()
+	}
+	return os.Stdout
+}
+
+// printSyncPlan prints the changed files and a summary of the sync of a source
+func printSyncPlan(cmd *cobra.Command, source string, plan *fscopy.SyncPlan) {
+	out := outputWriter(cmd)
+
+	symbols := map[fscopy.SyncAction]string{fscopy.SyncAdded: "+", fscopy.SyncUpdated: "~", fscopy.SyncDeleted: "-"}
+	for _, item := range plan.Items {
+		if symbol, ok := symbols[item.Action]; ok {
+			fmt.Fprintf(out, "%s %s\n", symbol, filepath.Join(plan.Destination, item.Path))
+		}
+	}
+	fmt.Fprintf(out, "%s: %s\n", source, plan.Summary())
+}
+
+// SourcePath returns the local folder of a source
+// repository sources are checked out first
+func SourcePath(ctx context.Context, checkouter repository.Checkouter, source config.CopySource) (string, error) {
+	if source.Repository != nil {
+		return checkouter.Checkout(ctx, *source.Repository)
+	}
+	return source.Dir.Path, nil
+}
+
 func RunCopyDirectly(ctx context.Context, cmd *cobra.Command, args []string, configFile string) error {
 	log := logger.GetLogger(ctx)
 	config, err := config.Load(ctx, configFile)
@@ -171,6 +373,28 @@ sources:
 - name: <name> # provided name for the source
   dir: # directory information, either dir or repository is required
     path: ../tektoncd-pipeline
+- name: <name>
+  repository: # git repository, cloned into --cache-dir and reused across runs
+    url: https://github.com/tektoncd/pipeline.git
+    ref: v0.60.0 # branch, tag or commit SHA, defaults to the remote HEAD
+    path: docs # optional subpath, checked out using sparse checkout
+    auth: # optional credentials for HTTP(S) repositories
+      tokenEnv: GITHUB_TOKEN # environment variable with the token
+      tokenFile: /var/run/secrets/git-token # or a file with the token
+  transforms: # optional content transforms for the files of this source, applied after the top level transforms
+  - files: ["*.md"] # globs relative to the source folder
+    replace: # regular expression replacements
+    - pattern: /docs/
+      replacement: /<name>/
+    frontMatter:
+      set: # replaces front matter fields
+        source: <name>
+      merge: {} # deep merges front matter fields
+    links:
+      rewrite: # link prefix rewrites
+      - from: /images/
+        to: /images/<name>/
+      outside: https://github.com/tektoncd/pipeline/blob/main/docs/ # base URL for relative links outside of the copied tree
 
 target:
   copyTo: imported-docs # destination directory for copied files
@@ -88,6 +88,57 @@ func Test_RunCopyDirectly(t *testing.T) {
 	}
 }
 
+func Test_RunCopyWithTransforms(t *testing.T) {
+	ctx, _ := testLoggerContext()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	if err := os.MkdirAll(filepath.Join(source, "en"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "en", "index.md"), []byte("# Index\n\nSee [readme](../../README.md) and /docs/install.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	content := `sources:
+- name: my-source
+  dir:
+    path: ` + source + `
+  transforms:
+  - files: ["*.md"]
+    replace:
+    - pattern: /docs/
+      replacement: /<name>/
+    links:
+      outside: https://github.com/org/repo/blob/main/docs/
+target:
+  copyTo: ` + filepath.Join(dir, "imported") + `
+  linkTo: ` + filepath.Join(dir, "docs") + `
+  links:
+  - from: en
+    target: en/<name>
+transforms:
+- files: ["**/*.md"]
+  frontMatter:
+    set:
+      source: <name>
+`
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.RunCopy(ctx, nil, nil, cmd.CopyOptions{ConfigFile: configFile, SkipLink: true}); err != nil {
+		t.Fatalf("error running copy: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "imported", "my-source", "en", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "---\nsource: my-source\n---\n# Index\n\nSee [readme](https://github.com/org/repo/blob/main/README.md) and /my-source/install.\n"
+	if diff := cmp.Diff(expected, string(got)); diff != "" {
+		t.Errorf("content mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func testLoggerContext() (context.Context, *zap.SugaredLogger) {
 	ctx := context.Background()
 	log := logger.NewLoggerFromContext(ctx, logger.LogLeveler{Level: "debug"})
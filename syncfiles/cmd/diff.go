@@ -0,0 +1,128 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/repository"
+	"github.com/spf13/cobra"
+)
+
+// ErrTargetOutdated is returned by diff when the target differs from the sources
+var ErrTargetOutdated = errors.New("target is not up to date with the sources")
+
+// DiffOptions options for the diff command
+type DiffOptions struct {
+	// ConfigFile path to the configuration file
+	ConfigFile string
+	// CacheDir directory where repository sources are cloned
+	CacheDir string
+	// NameOnly prints only the paths of the different files
+	NameOnly bool
+}
+
+func NewDiffCommand(ctx context.Context) *cobra.Command {
+	var opts DiffOptions
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the differences between the sources and the copied files",
+		Long:  diffLongDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.ConfigFile == "" {
+				return fmt.Errorf("--config is required")
+			}
+			// differences are an expected result, not a usage error
+			cmd.SilenceUsage = true
+			return RunDiff(ctx, cmd, args, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ConfigFile, "config", "c", "", "Path to the configuration file")
+	cmd.Flags().StringVar(&opts.CacheDir, "cache-dir", repository.DefaultCacheDir(), "Directory where repository sources are cloned and reused across runs")
+	cmd.Flags().BoolVar(&opts.NameOnly, "name-only", false, "Print only the paths of the files that differ. Defaults to false")
+	return cmd
+}
+
+// RunDiff prints a unified diff between the selected files of each source and its target folder
+// returns ErrTargetOutdated if there is any difference
+func RunDiff(ctx context.Context, cmd *cobra.Command, args []string, opts DiffOptions) error {
+	log := logger.GetLogger(ctx)
+	out := outputWriter(cmd)
+
+	config, err := loadConfig(ctx, opts.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	selector := fscopy.FileSystemSelector{}
+	checkouter := &repository.GitCheckouter{CacheDir: opts.CacheDir}
+	total := 0
+	for _, source := range config.Sources {
+		sourceFolder, err := SourcePath(ctx, checkouter, source)
+		if err != nil {
+			log.Error("error preparing source ", source.Name, " error: ", err)
+			return err
+		}
+
+		files, err := selector.ListFiles(ctx, sourceFolder)
+		if err != nil {
+			log.Error("error listing files in source ", source.Name, " error: ", err)
+			return err
+		}
+
+		copier, _, err := newSourceCopier(config, source)
+		if err != nil {
+			return err
+		}
+		targetFolderForSource := filepath.Join(config.Target.CopyTo, source.Name)
+		diffs, err := copier.Diff(ctx, sourceFolder, targetFolderForSource, files...)
+		if err != nil {
+			log.Error("error comparing source ", source.Name, " error: ", err)
+			return err
+		}
+
+		for _, diff := range diffs {
+			if opts.NameOnly {
+				fmt.Fprintf(out, "%s %s\n", diff.Action, filepath.Join(targetFolderForSource, diff.Path))
+				continue
+			}
+			fmt.Fprint(out, diff.Diff)
+		}
+		log.Info(source.Name, ": ", len(diffs), " files differ")
+		total += len(diffs)
+	}
+
+	if total > 0 {
+		return fmt.Errorf("%w: %d files differ", ErrTargetOutdated, total)
+	}
+	return nil
+}
+
+const (
+	diffLongDescription = `Show the differences between the files selected in each source and the files copied in the target.
+The command exits with a non-zero code if there is any difference, so CI can verify that synced files are up to date.
+Example usage:
+
+$ syncfiles diff --config syncfiles.yaml
+$ syncfiles diff --config syncfiles.yaml --name-only
+`
+)
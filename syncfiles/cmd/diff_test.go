@@ -0,0 +1,123 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/cmd"
+	"github.com/spf13/cobra"
+)
+
+func writeConfig(t *testing.T, dir string) string {
+	t.Helper()
+	source := filepath.Join(dir, "source")
+	if err := os.MkdirAll(filepath.Join(source, "en"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "en", "index.md"), []byte("# index\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	content := `sources:
+- name: my-source
+  dir:
+    path: ` + source + `
+target:
+  copyTo: ` + filepath.Join(dir, "imported") + `
+  linkTo: ` + filepath.Join(dir, "docs") + `
+  links:
+  - from: en
+    target: en/<name>
+`
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return configFile
+}
+
+func Test_RunCopyDryRunAndDiff(t *testing.T) {
+	ctx, _ := testLoggerContext()
+	dir := t.TempDir()
+	configFile := writeConfig(t, dir)
+	out := &bytes.Buffer{}
+	command := &cobra.Command{}
+	command.SetOut(out)
+
+	// dry run lists the files and links without touching the target
+	if err := cmd.RunCopy(ctx, command, nil, cmd.CopyOptions{ConfigFile: configFile, DryRun: true}); err != nil {
+		t.Fatalf("error running dry run: %v", err)
+	}
+	for _, expected := range []string{
+		"+ " + filepath.Join(dir, "imported", "my-source", "en", "index.md"),
+		"my-source: 1 added, 0 updated, 0 deleted, 0 unchanged",
+		"create link " + filepath.Join(dir, "docs", "en", "my-source"),
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected dry run output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "imported")); !os.IsNotExist(err) {
+		t.Errorf("expected dry run not to create the target, err: %v", err)
+	}
+
+	// diff reports the missing file
+	out.Reset()
+	err := cmd.RunDiff(ctx, command, nil, cmd.DiffOptions{ConfigFile: configFile})
+	if !errors.Is(err, cmd.ErrTargetOutdated) {
+		t.Fatalf("expected target to be outdated, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "+# index") {
+		t.Errorf("expected diff to contain the new content, got:\n%s", out.String())
+	}
+
+	// no difference after copying
+	if err := cmd.RunCopy(ctx, command, nil, cmd.CopyOptions{ConfigFile: configFile, SkipLink: true}); err != nil {
+		t.Fatalf("error copying: %v", err)
+	}
+	out.Reset()
+	if err := cmd.RunDiff(ctx, command, nil, cmd.DiffOptions{ConfigFile: configFile}); err != nil {
+		t.Errorf("expected no difference after copying, got: %v, output:\n%s", err, out.String())
+	}
+
+	// a clean dry run removes the copied files and adds them again
+	out.Reset()
+	if err := cmd.RunCopy(ctx, command, nil, cmd.CopyOptions{ConfigFile: configFile, DryRun: true, CleanTarget: true, SkipLink: true}); err != nil {
+		t.Fatalf("error running clean dry run: %v", err)
+	}
+	copied := filepath.Join(dir, "imported", "my-source", "en", "index.md")
+	expected := "- " + copied + "\n+ " + copied + "\nmy-source: 1 added, 0 updated, 1 deleted, 0 unchanged\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("expected clean dry run output to end with %q, got:\n%s", expected, out.String())
+	}
+
+	// the clean copy reports the same plan as its dry run
+	out.Reset()
+	if err := cmd.RunCopy(ctx, command, nil, cmd.CopyOptions{ConfigFile: configFile, CleanTarget: true, SkipLink: true}); err != nil {
+		t.Fatalf("error running clean copy: %v", err)
+	}
+	if out.String() != expected {
+		t.Errorf("expected clean copy output %q, got:\n%s", expected, out.String())
+	}
+	if _, err := os.Stat(copied); err != nil {
+		t.Errorf("expected clean copy to copy the file again, err: %v", err)
+	}
+}
@@ -45,6 +45,8 @@ func NewRootCommand(ctx context.Context, name string) *cobra.Command {
 
 	// Add subcommands
 	cmd.AddCommand(NewCopyCommand(ctx))
+	cmd.AddCommand(NewDiffCommand(ctx))
+	cmd.AddCommand(NewWatchCommand(ctx))
 
 	return cmd
 }
@@ -0,0 +1,134 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/repository"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/watch"
+	"github.com/spf13/cobra"
+)
+
+// WatchOptions options for the watch command
+type WatchOptions struct {
+	CopyOptions
+	// Debounce quiet period before changed files are synced
+	Debounce time.Duration
+}
+
+func NewWatchCommand(ctx context.Context) *cobra.Command {
+	var opts WatchOptions
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Sync files on every change in the sources",
+		Long:  watchLongDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.ConfigFile == "" {
+				return fmt.Errorf("--config is required")
+			}
+			ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			return RunWatch(ctx, cmd, args, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ConfigFile, "config", "c", "", "Path to the configuration file")
+	cmd.Flags().BoolVarP(&opts.SkipLink, "skip-link", "s", false, "Skip creating symbolic links. Defaults to false")
+	cmd.Flags().StringVar(&opts.CacheDir, "cache-dir", repository.DefaultCacheDir(), "Directory where repository sources are cloned and reused across runs")
+	cmd.Flags().DurationVar(&opts.Debounce, "debounce", watch.DefaultDebounce, "Quiet period before changed files are synced")
+	return cmd
+}
+
+// RunWatch syncs all sources once and then syncs each source again when its files change
+// repository sources are only synced once
+func RunWatch(ctx context.Context, cmd *cobra.Command, args []string, opts WatchOptions) error {
+	log := logger.GetLogger(ctx)
+
+	copyConfig, err := loadConfig(ctx, opts.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	checkouter := &repository.GitCheckouter{CacheDir: opts.CacheDir}
+	sources := map[string]config.CopySource{}
+	folders := map[string]string{}
+	for _, source := range copyConfig.Sources {
+		sourceFolder, err := SourcePath(ctx, checkouter, source)
+		if err != nil {
+			log.Error("error preparing source ", source.Name, " error: ", err)
+			return err
+		}
+
+		plan, err := syncSource(ctx, copyConfig, source, sourceFolder, opts.CopyOptions)
+		if err != nil {
+			return err
+		}
+		printSyncPlan(cmd, source.Name, plan)
+
+		if source.Repository != nil {
+			log.Info("Repository source ", source.Name, " is not watched")
+			continue
+		}
+		if sourceFolder, err = filepath.Abs(sourceFolder); err != nil {
+			return err
+		}
+		sources[source.Name] = source
+		folders[source.Name] = sourceFolder
+	}
+	if len(folders) == 0 {
+		return fmt.Errorf("no dir source to watch")
+	}
+
+	watcher := &watch.Watcher{
+		Sources:  folders,
+		Debounce: opts.Debounce,
+		OnChange: func(ctx context.Context, changed []string) error {
+			for _, name := range changed {
+				plan, err := syncSource(ctx, copyConfig, sources[name], folders[name], opts.CopyOptions)
+				if err != nil {
+					return err
+				}
+				if plan.Count(fscopy.SyncUnchanged) != len(plan.Items) {
+					printSyncPlan(cmd, name, plan)
+				}
+			}
+			return nil
+		},
+	}
+	log.Info("Watching sources for changes, press Ctrl+C to stop")
+	return watcher.Run(ctx)
+}
+
+const (
+	watchLongDescription = `Sync files from multiple sources to a target and keep syncing them on every change.
+All sources are synced once, then every dir source is watched and changed files are synced incrementally,
+respecting .syncignore files. Deleted and renamed files are pruned from the target.
+Example usage:
+
+$ syncfiles watch --config syncfiles.yaml
+$ syncfiles watch --config syncfiles.yaml --debounce 1s
+`
+)
@@ -18,6 +18,10 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	ifs "github.com/AlaudaDevops/toolbox/syncfiles/pkg/fs"
@@ -29,13 +33,16 @@ type CopyConfig struct {
 	Sources []CopySource `json:"sources"`
 	// Target configuration for copying
 	Target *CopyTarget `json:"target,omitempty"`
+	// Transforms applied to the files of every source, before the transforms of the source
+	Transforms []Transform `json:"transforms,omitempty"`
 }
 
 // CopySource defines the source configuration for copying files.
 type CopySource struct {
-	Name string `json:"name"` // Custom name for the source
-	// Repository is currently not supported
-	Dir *Directory `json:"dir"` // Directory information (mutually exclusive with Repository)
+	Name       string      `json:"name"`                 // Custom name for the source
+	Dir        *Directory  `json:"dir,omitempty"`        // Directory information (mutually exclusive with Repository)
+	Repository *Repository `json:"repository,omitempty"` // Git repository information (mutually exclusive with Dir)
+	Transforms []Transform `json:"transforms,omitempty"` // Content transforms applied to the files of this source
 }
 
 // Directory contains information about a local directory.
@@ -43,6 +50,57 @@ type Directory struct {
 	Path string `json:"path"` // Path to the local directory
 }
 
+// Repository contains information about a git repository
+// the repository is cloned into a cache directory and reused across runs
+type Repository struct {
+	URL  string          `json:"url"`            // URL of the repository, any URL supported by git
+	Ref  string          `json:"ref"`            // Branch, tag or commit SHA to checkout. Defaults to the remote HEAD
+	Path string          `json:"path"`           // Subpath of the repository to checkout using sparse checkout. Defaults to the whole repository
+	Auth *RepositoryAuth `json:"auth,omitempty"` // Optional credentials for HTTP(S) repositories
+}
+
+// RepositoryAuth contains the credentials used to clone a repository over HTTP(S)
+// the token is read from the environment variable or the file, the environment variable takes precedence
+type RepositoryAuth struct {
+	Username  string `json:"username"`  // Username for basic auth. Defaults to x-access-token
+	TokenEnv  string `json:"tokenEnv"`  // Name of the environment variable containing the token
+	TokenFile string `json:"tokenFile"` // Path to a file containing the token
+}
+
+// Transform defines content transformations applied to the files matching a list of globs
+// the transformations are applied in order: replace, frontMatter and links
+// string values support the <name> placeholder
+type Transform struct {
+	Files       []string              `json:"files"`                 // Glob patterns relative to the source folder, "**" matches any folder. Patterns without "/" match the file name. Defaults to markdown files (*.md)
+	Replace     []Replacement         `json:"replace,omitempty"`     // Regular expression replacements
+	FrontMatter *FrontMatterTransform `json:"frontMatter,omitempty"` // YAML front matter changes
+	Links       *LinkTransform        `json:"links,omitempty"`       // Markdown and HTML link rewriting, broken links are reported
+}
+
+// Replacement replaces all the matches of a regular expression
+type Replacement struct {
+	Pattern     string `json:"pattern"`     // Regular expression, see https://pkg.go.dev/regexp/syntax
+	Replacement string `json:"replacement"` // Replacement, supports $1 style references to the groups
+}
+
+// FrontMatterTransform changes the YAML front matter of a file, a front matter is added if missing
+type FrontMatterTransform struct {
+	Set   map[string]any `json:"set,omitempty"`   // Fields replaced as a whole
+	Merge map[string]any `json:"merge,omitempty"` // Fields deep merged with the existing values
+}
+
+// LinkTransform rewrites link targets
+type LinkTransform struct {
+	Rewrite []LinkRewrite `json:"rewrite,omitempty"` // Prefix rewrites, the first matching rule is applied
+	Outside string        `json:"outside,omitempty"` // Base URL of the copied tree used for relative links pointing outside of it
+}
+
+// LinkRewrite replaces the prefix of link targets
+type LinkRewrite struct {
+	From string `json:"from"` // Prefix to replace
+	To   string `json:"to"`   // New prefix
+}
+
 // CopyTarget defines the target configuration for copying files.
 type CopyTarget struct {
 	CopyTo string      `json:"copyTo"` // Destination directory for copied files
@@ -70,10 +128,23 @@ func (c *CopyConfig) Validate(ctx context.Context) error {
 		if source.Name == "" {
 			errs = append(errs, errors.New("config.sources.name should not be empty"))
 		}
-		if source.Dir == nil {
-			errs = append(errs, errors.New("config.sources.dir should not be nil"))
+		if (source.Dir == nil) == (source.Repository == nil) {
+			errs = append(errs, errors.New("config.sources should have exactly one of dir or repository"))
+		}
+		errs = append(errs, validateTransforms(source.Transforms)...)
+		if source.Repository != nil {
+			if source.Repository.URL == "" {
+				errs = append(errs, errors.New("config.sources.repository.url should not be empty"))
+			}
+			if path := strings.TrimLeft(filepath.ToSlash(source.Repository.Path), "/"); path != "" && !filepath.IsLocal(filepath.FromSlash(path)) {
+				errs = append(errs, fmt.Errorf("config.sources.repository.path %q should be inside the repository", source.Repository.Path))
+			}
+			if auth := source.Repository.Auth; auth != nil && auth.TokenEnv == "" && auth.TokenFile == "" {
+				errs = append(errs, errors.New("config.sources.repository.auth should have tokenEnv or tokenFile"))
+			}
 		}
 	}
+	errs = append(errs, validateTransforms(c.Transforms)...)
 	if c.Target.CopyTo == "" || c.Target.LinkTo == "" || c.Target.CopyTo == c.Target.LinkTo {
 		errs = append(errs, errors.New("config.target.copyTo and base should point to different folders"))
 	}
@@ -88,6 +159,23 @@ func (c *CopyConfig) Validate(ctx context.Context) error {
 	return errors.Join(errs...)
 }
 
+// validateTransforms validates the regular expressions and URLs of the transforms
+func validateTransforms(transforms []Transform) (errs []error) {
+	for _, transform := range transforms {
+		for _, replace := range transform.Replace {
+			if _, err := regexp.Compile(replace.Pattern); err != nil {
+				errs = append(errs, fmt.Errorf("config.transforms.replace.pattern %q is invalid: %w", replace.Pattern, err))
+			}
+		}
+		if transform.Links != nil && transform.Links.Outside != "" {
+			if _, err := url.Parse(transform.Links.Outside); err != nil {
+				errs = append(errs, fmt.Errorf("config.transforms.links.outside %q is invalid: %w", transform.Links.Outside, err))
+			}
+		}
+	}
+	return
+}
+
 // Default configuration for CopyTarget
 func (CopyTarget) Default() *CopyTarget {
 	return &CopyTarget{
@@ -42,3 +42,35 @@ func TestCopyConfig_Parse(t *testing.T) {
 		t.Errorf("Link requests mismatch (-want +got):\n%v", diff)
 	}
 }
+
+func TestCopyConfig_Validate(t *testing.T) {
+	target := config.CopyTarget{}.Default()
+	tests := []struct {
+		name        string
+		source      config.CopySource
+		expectError bool
+	}{
+		{name: "dir", source: config.CopySource{Name: "dir", Dir: &config.Directory{Path: "../source"}}},
+		{name: "repository", source: config.CopySource{Name: "repo", Repository: &config.Repository{URL: "https://github.com/tektoncd/pipeline.git", Auth: &config.RepositoryAuth{TokenEnv: "GITHUB_TOKEN"}}}},
+		{name: "no source", source: config.CopySource{Name: "none"}, expectError: true},
+		{name: "repository path", source: config.CopySource{Name: "repo", Repository: &config.Repository{URL: "https://github.com/tektoncd/pipeline.git", Path: "/docs"}}},
+		{name: "repository path outside", source: config.CopySource{Name: "repo", Repository: &config.Repository{URL: "https://github.com/tektoncd/pipeline.git", Path: "docs/../.."}}, expectError: true},
+		{name: "dir and repository", source: config.CopySource{Name: "both", Dir: &config.Directory{Path: "../source"}, Repository: &config.Repository{URL: "https://github.com/tektoncd/pipeline.git"}}, expectError: true},
+		{name: "repository without url", source: config.CopySource{Name: "repo", Repository: &config.Repository{Ref: "main"}}, expectError: true},
+		{name: "transforms", source: config.CopySource{Name: "dir", Dir: &config.Directory{Path: "../source"}, Transforms: []config.Transform{{Replace: []config.Replacement{{Pattern: `(\w+)\.md`, Replacement: "$1/"}}, Links: &config.LinkTransform{Outside: "https://github.com/tektoncd/pipeline/blob/main/docs/"}}}}},
+		{name: "invalid replace pattern", source: config.CopySource{Name: "dir", Dir: &config.Directory{Path: "../source"}, Transforms: []config.Transform{{Replace: []config.Replacement{{Pattern: "(unclosed"}}}}}, expectError: true},
+		{name: "auth without token", source: config.CopySource{Name: "repo", Repository: &config.Repository{URL: "https://github.com/tektoncd/pipeline.git", Auth: &config.RepositoryAuth{Username: "user"}}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copyConfig := &config.CopyConfig{Sources: []config.CopySource{tt.source}, Target: target}
+			err := copyConfig.Validate(context.Background())
+			if tt.expectError && err == nil {
+				t.Error("expected validation error")
+			} else if !tt.expectError && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
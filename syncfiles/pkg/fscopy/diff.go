@@ -0,0 +1,139 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fscopy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	ifs "github.com/AlaudaDevops/toolbox/syncfiles/pkg/fs"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileDiff is the difference of a file between the source and the target
+type FileDiff struct {
+	// Path relative to the target folder
+	Path string
+	// Action the action a sync would take, added, updated or deleted
+	Action SyncAction
+	// Diff unified diff from the target to the source content
+	Diff string
+}
+
+// Diff compares the selected files with the content of the destination folder
+// returns the files that are missing, different or stale in the destination, sorted by path
+func (s *FileSystemCopier) Diff(ctx context.Context, base, destination string, files ...ifs.FileInfo) ([]FileDiff, error) {
+	log := logger.GetLogger(ctx)
+	diffs := []FileDiff{}
+	selected := map[string]bool{}
+	for _, file := range files {
+		relativeFilePath := relativePath(base, file.GetPath())
+		selected[relativeFilePath] = true
+
+		sourceContent, err := s.readContent(ctx, base, file)
+		if err != nil {
+			return nil, err
+		}
+		targetPath := filepath.Join(destination, relativeFilePath)
+		targetContent, err := os.ReadFile(targetPath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			diffs = append(diffs, FileDiff{Path: relativeFilePath, Action: SyncAdded, Diff: unifiedDiff(targetPath, nil, sourceContent, false, true)})
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(sourceContent, targetContent):
+			diffs = append(diffs, FileDiff{Path: relativeFilePath, Action: SyncUpdated, Diff: unifiedDiff(targetPath, targetContent, sourceContent, true, true)})
+		}
+	}
+
+	// files in the target that are not selected anymore
+	walkErr := filepath.WalkDir(destination, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relativeFilePath := relativePath(destination, path)
+		if selected[relativeFilePath] {
+			return nil
+		}
+		targetContent, err := os.ReadFile(path)
+		if err != nil {
+			log.Warn("error reading file ", path, " error: ", err)
+			return nil
+		}
+		diffs = append(diffs, FileDiff{Path: relativeFilePath, Action: SyncDeleted, Diff: unifiedDiff(path, targetContent, nil, true, false)})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs, nil
+}
+
+// unifiedDiff returns a unified diff between the target and source content of a file in the target path
+// a missing side is shown as /dev/null like git does
+func unifiedDiff(path string, target, source []byte, targetExists, sourceExists bool) string {
+	path = filepath.ToSlash(path)
+	fromFile, toFile := "a/"+path, "b/"+path
+	if !targetExists {
+		fromFile = "/dev/null"
+	}
+	if !sourceExists {
+		toFile = "/dev/null"
+	}
+	if bytes.IndexByte(target, 0) >= 0 || bytes.IndexByte(source, 0) >= 0 {
+		return "Binary files " + fromFile + " and " + toFile + " differ\n"
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(target),
+		B:        splitLines(source),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return "Files " + fromFile + " and " + toFile + " differ\n"
+	}
+	return diff
+}
+
+// splitLines splits content into lines keeping the line endings
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := difflib.SplitLines(string(content))
+	// SplitLines adds a line ending to the last line
+	// which results in an extra line when the content already ends with one
+	if bytes.HasSuffix(content, []byte("\n")) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
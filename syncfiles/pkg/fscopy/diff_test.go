@@ -0,0 +1,142 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fscopy_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ifs "github.com/AlaudaDevops/toolbox/syncfiles/pkg/fs"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileSystemCopier_Diff(t *testing.T) {
+	ctx, _ := testLoggerContext()
+	base := t.TempDir()
+	source := filepath.Join(base, "source")
+	destination := filepath.Join(base, "target")
+
+	writeTestFile(t, filepath.Join(source, "same.md"), "same\n")
+	writeTestFile(t, filepath.Join(source, "changed.md"), "line 1\nline 2\n")
+	writeTestFile(t, filepath.Join(source, "new.md"), "new\n")
+	writeTestFile(t, filepath.Join(destination, "same.md"), "same\n")
+	writeTestFile(t, filepath.Join(destination, "changed.md"), "line 1\nold line 2\n")
+	writeTestFile(t, filepath.Join(destination, "stale", "old.md"), "old\n")
+
+	files, err := (&fscopy.FileSystemSelector{}).ListFiles(ctx, source)
+	if err != nil {
+		t.Fatalf("error listing files: %v", err)
+	}
+
+	diffs, err := (&fscopy.FileSystemCopier{}).Diff(ctx, source, destination, files...)
+	if err != nil {
+		t.Fatalf("error comparing files: %v", err)
+	}
+
+	actions := map[string]fscopy.SyncAction{}
+	for _, diff := range diffs {
+		actions[diff.Path] = diff.Action
+	}
+	expected := map[string]fscopy.SyncAction{
+		"changed.md":   fscopy.SyncUpdated,
+		"new.md":       fscopy.SyncAdded,
+		"stale/old.md": fscopy.SyncDeleted,
+	}
+	if diff := cmp.Diff(expected, actions); diff != "" {
+		t.Errorf("diff actions mismatch (-want +got):\n%s", diff)
+	}
+
+	changed := diffs[0].Diff
+	for _, line := range []string{"--- a/" + filepath.ToSlash(filepath.Join(destination, "changed.md")), "-old line 2\n", "+line 2\n", " line 1\n"} {
+		if !strings.Contains(changed, line) {
+			t.Errorf("expected diff to contain %q, got:\n%s", line, changed)
+		}
+	}
+	if !strings.HasPrefix(diffs[1].Diff, "--- /dev/null") || !strings.Contains(diffs[2].Diff, "+++ /dev/null") {
+		t.Errorf("expected added and deleted files to be compared with /dev/null, got:\n%s%s", diffs[1].Diff, diffs[2].Diff)
+	}
+}
+
+func TestFileSystemCopier_PlanLinks(t *testing.T) {
+	ctx, _ := testLoggerContext()
+	base := t.TempDir()
+	current, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// links are relative to the working directory
+	if err := os.Chdir(base); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(current)
+
+	writeTestFile(t, "imported/src/en/index.md", "index")
+	writeTestFile(t, "imported/src/zh/index.md", "index")
+	writeTestFile(t, "docs/file.md", "not a link")
+	if err := os.MkdirAll("docs/en", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../imported/src/en", "docs/en/src"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("docs/zh", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../imported/other/zh", "docs/zh/src"); err != nil {
+		t.Fatal(err)
+	}
+	// link to a folder pruned by a previous sync
+	if err := os.MkdirAll("docs/ko", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../imported/src/ko", "docs/ko/src"); err != nil {
+		t.Fatal(err)
+	}
+
+	copier := &fscopy.FileSystemCopier{}
+	links := []ifs.LinkRequest{
+		{Source: "en", Destination: "en/src"},
+		{Source: "zh", Destination: "zh/src"},
+		{Source: "ja", Destination: "ja/src"},
+		{Source: "en/index.md", Destination: "file.md"},
+		{Source: "en/index.md", Destination: "public/index.md"},
+		{Source: "ko", Destination: "ko/src"},
+	}
+	actions := []fscopy.LinkAction{}
+	for _, item := range copier.PlanLinks(ctx, "imported/src", "docs", links...) {
+		actions = append(actions, item.Action)
+	}
+	expected := []fscopy.LinkAction{fscopy.LinkUnchanged, fscopy.LinkReplace, fscopy.LinkSkip, fscopy.LinkExists, fscopy.LinkCreate, fscopy.LinkRemove}
+	if diff := cmp.Diff(expected, actions); diff != "" {
+		t.Errorf("link actions mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := os.Lstat("docs/public"); !os.IsNotExist(err) {
+		t.Errorf("expected planning not to change the file system, err: %v", err)
+	}
+
+	if err := copier.Link(ctx, "imported/src", "docs", links...); err != nil {
+		t.Fatalf("error linking files: %v", err)
+	}
+	if target, err := os.Readlink("docs/zh/src"); err != nil || target != "../../imported/src/zh" {
+		t.Errorf("expected link to be replaced, got %q, err: %v", target, err)
+	}
+	if _, err := os.Lstat("docs/ko/src"); !os.IsNotExist(err) {
+		t.Errorf("expected dangling link to be removed, err: %v", err)
+	}
+}
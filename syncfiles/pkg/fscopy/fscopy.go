@@ -17,7 +17,9 @@ package fscopy
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,6 +29,8 @@ import (
 )
 
 type FileSystemCopier struct {
+	// Transformer optional transformer applied to the content of the copied files
+	Transformer ContentTransformer
 }
 
 var _ FileCopier = &FileSystemCopier{}
@@ -57,6 +61,17 @@ func (s *FileSystemCopier) CopyFile(ctx context.Context, base, destination strin
 		return err
 	}
 
+	if s.Transformer != nil {
+		content, err := s.readContent(ctx, base, file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(desiredFilePath, content, file.Mode()); err != nil {
+			return err
+		}
+		return os.Chmod(desiredFilePath, file.Mode())
+	}
+
 	// Open source file
 	sourceFile, err := os.Open(file.GetPath())
 	if err != nil {
@@ -80,14 +95,61 @@ func (s *FileSystemCopier) CopyFile(ctx context.Context, base, destination strin
 	return os.Chmod(desiredFilePath, file.Mode())
 }
 
+// readContent reads the content of a file applying the transformer if any
+func (s *FileSystemCopier) readContent(ctx context.Context, base string, file ifs.FileInfo) ([]byte, error) {
+	content, err := os.ReadFile(file.GetPath())
+	if err != nil || s.Transformer == nil {
+		return content, err
+	}
+	content, err = s.Transformer.Transform(ctx, relativePath(base, file.GetPath()), content)
+	if err != nil {
+		return nil, fmt.Errorf("transforming %s: %w", file.GetPath(), err)
+	}
+	return content, nil
+}
+
 const upperDir = ".."
 
-// Link will create symlinks based on the base and destination folders given the list links
-// it will ignore if the destination folder was already created and the link already exists
-func (s *FileSystemCopier) Link(ctx context.Context, base, destination string, links ...ifs.LinkRequest) error {
-	log := logger.GetLogger(ctx)
-	log.Debug("linking files from ", base, " to ", destination)
+// LinkAction is the action taken for a link request
+type LinkAction string
+
+const (
+	// LinkCreate the link does not exist and will be created
+	LinkCreate LinkAction = "create"
+	// LinkReplace a link with a different target exists and will be replaced
+	LinkReplace LinkAction = "replace"
+	// LinkUnchanged the link already exists with the same target
+	LinkUnchanged LinkAction = "unchanged"
+	// LinkExists a file or folder that is not a link exists in the target path and is left untouched
+	LinkExists LinkAction = "exists"
+	// LinkSkip the link source does not exist
+	LinkSkip LinkAction = "skip"
+	// LinkRemove the link source does not exist anymore, e.g. it was pruned by a sync, and the dangling link will be removed
+	LinkRemove LinkAction = "remove"
+)
 
+// LinkPlanItem is the planned action for a link request
+type LinkPlanItem struct {
+	// Request the original link request
+	Request ifs.LinkRequest
+	// SourcePath relative path the link will point to
+	SourcePath string
+	// TargetPath path of the link
+	TargetPath string
+	// Action to take
+	Action LinkAction
+}
+
+// PlanLinks resolves the source and target paths of the links and checks their current state
+// without changing the file system
+func (s *FileSystemCopier) PlanLinks(ctx context.Context, base, destination string, links ...ifs.LinkRequest) []LinkPlanItem {
+	return s.PlanLinksFrom(ctx, base, destination, base, links...)
+}
+
+// PlanLinksFrom works as PlanLinks but checks if the link sources exist in sourceBase instead of base
+// useful to plan links before the files are copied into base
+func (s *FileSystemCopier) PlanLinksFrom(ctx context.Context, base, destination, sourceBase string, links ...ifs.LinkRequest) []LinkPlanItem {
+	items := make([]LinkPlanItem, 0, len(links))
 	for _, link := range links {
 		targetPath := filepath.Join(destination, link.Destination)
 
@@ -100,10 +162,58 @@ func (s *FileSystemCopier) Link(ctx context.Context, base, destination string, l
 		}
 		// join upperList with destination
 		sourcePath := filepath.Join(append(upperList, base, link.Source)...)
-		sourceExternalPath := filepath.Join(base, link.Source)
+		item := LinkPlanItem{Request: link, SourcePath: sourcePath, TargetPath: targetPath, Action: LinkCreate}
+
+		sourceExternalPath := filepath.Join(sourceBase, link.Source)
 		if _, err := os.Lstat(sourceExternalPath); err != nil {
-			log.Warn("skip linking ", sourcePath, " error getting source file info, err: ", err)
+			item.Action = LinkSkip
+			if current, err := os.Readlink(targetPath); err == nil && current == sourcePath {
+				item.Action = LinkRemove
+			}
+		} else if info, err := os.Lstat(targetPath); err == nil {
+			item.Action = LinkExists
+			if info.Mode()&fs.ModeSymlink != 0 {
+				item.Action = LinkReplace
+				if current, err := os.Readlink(targetPath); err == nil && current == sourcePath {
+					item.Action = LinkUnchanged
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Link will create symlinks based on the base and destination folders given the list links
+// it will ignore if the destination folder was already created and the link already exists
+// links pointing to a different source are replaced
+func (s *FileSystemCopier) Link(ctx context.Context, base, destination string, links ...ifs.LinkRequest) error {
+	log := logger.GetLogger(ctx)
+	log.Debug("linking files from ", base, " to ", destination)
+
+	for _, item := range s.PlanLinks(ctx, base, destination, links...) {
+		targetPath := item.TargetPath
+		sourcePath := item.SourcePath
+		switch item.Action {
+		case LinkSkip:
+			log.Warn("skip linking ", sourcePath, " source file does not exist: ", filepath.Join(base, item.Request.Source))
+			continue
+		case LinkRemove:
+			log.Debug("removing dangling link ", targetPath)
+			if err := os.Remove(targetPath); err != nil {
+				log.Error("error removing link ", targetPath, " err: ", err)
+				return err
+			}
+			continue
+		case LinkUnchanged, LinkExists:
+			log.Debug("skip linking ", sourcePath, " target already exists: ", targetPath)
 			continue
+		case LinkReplace:
+			log.Debug("replacing link ", targetPath)
+			if err := os.Remove(targetPath); err != nil {
+				log.Error("error removing link ", targetPath, " err: ", err)
+				return err
+			}
 		}
 
 		// creating base dir for target
@@ -111,7 +221,7 @@ func (s *FileSystemCopier) Link(ctx context.Context, base, destination string, l
 			log.Warn("error creating parent folder for ", targetPath, " err: ", err)
 		}
 
-		log.Debug("linking file ", sourceExternalPath, " to ", targetPath)
+		log.Debug("linking file ", filepath.Join(base, item.Request.Source), " to ", targetPath)
 		err := os.Symlink(sourcePath, targetPath)
 		if err != nil && !os.IsExist(err) {
 			log.Error("error linking file from ", sourcePath, " to ", targetPath, " err: ", err)
@@ -38,6 +38,30 @@ type IgnoreNode struct {
 
 var _ FileFilter = &IgnoreNode{}
 
+// NewIgnoreTree walks the given path loading every .syncignore file
+// returns the root node that can be used to check if a path is ignored
+func NewIgnoreTree(ctx context.Context, path string) (*IgnoreNode, error) {
+	root := &IgnoreNode{path: path, matcher: goignore.DummyIgnoreMatcher(false), matcherConstructorFunc: goignore.NewGitIgnore}
+	err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return root.WalkDirFunc(ctx, path, d, err)
+	})
+	return root, err
+}
+
+// IsIgnored returns true if the path matches any .syncignore file of the tree
+// unlike IsFileAllowed it works for paths that do not exist anymore
+func (n *IgnoreNode) IsIgnored(path string, isDir bool) bool {
+	for _, matcher := range n.ListMatchers(path) {
+		if matcher.Match(path, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsFileAllowed implements the FileFilter interface returning true if the file is allowed
 // and false if it should be ignored
 func (n *IgnoreNode) IsFileAllowed(ctx context.Context, file ifs.FileInfo) (bool, error) {
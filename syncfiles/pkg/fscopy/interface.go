@@ -47,6 +47,19 @@ type FileCopier interface {
 	Link(ctx context.Context, base, dst string, links ...ifs.LinkRequest) error
 }
 
+// FileSyncer incrementally syncs files from a given path to a destination
+// source is the name used to record the synced files in the manifest
+// manifestPath is the manifest of the previous sync, used to skip unchanged files and prune stale ones
+type FileSyncer interface {
+	Sync(ctx context.Context, source, base, dst, manifestPath string, files ...ifs.FileInfo) (*SyncPlan, error)
+}
+
+// ContentTransformer transforms the content of a file before it is copied
+// path is the path of the file relative to the base folder, using "/" as separator
+type ContentTransformer interface {
+	Transform(ctx context.Context, path string, content []byte) ([]byte, error)
+}
+
 // fileInfoImp private implementation of the FileInfo interface
 type fileInfoImp struct {
 	fs.FileInfo
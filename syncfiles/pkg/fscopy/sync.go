@@ -0,0 +1,316 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fscopy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ifs "github.com/AlaudaDevops/toolbox/syncfiles/pkg/fs"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+)
+
+// SyncAction is the action taken for a file during a sync
+type SyncAction string
+
+const (
+	// SyncAdded the file was not synced before
+	SyncAdded SyncAction = "added"
+	// SyncUpdated the file was synced before and its content changed
+	SyncUpdated SyncAction = "updated"
+	// SyncDeleted the file was synced before and is no longer selected
+	SyncDeleted SyncAction = "deleted"
+	// SyncUnchanged the file was synced before and its content is the same
+	SyncUnchanged SyncAction = "unchanged"
+)
+
+// Manifest records the files synced from a source with their content hash
+// it is stored next to the target folder of the source
+type Manifest struct {
+	// Source name of the source
+	Source string `json:"source"`
+	// Files maps the path relative to the target folder to the sha256 of its content
+	Files map[string]string `json:"files"`
+}
+
+// ManifestPath returns the path of the manifest for a source
+// e.g. imported-docs/.my-source.syncfiles.json for imported-docs/my-source
+func ManifestPath(copyTo, source string) string {
+	return filepath.Join(copyTo, "."+source+".syncfiles.json")
+}
+
+// LoadManifest loads a manifest from a path
+// returns an empty manifest if the file does not exist
+func LoadManifest(path string) (*Manifest, error) {
+	manifest := &Manifest{Files: map[string]string{}}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing manifest %s: %w", path, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]string{}
+	}
+	return manifest, nil
+}
+
+// Save writes the manifest to a path
+func (m *Manifest) Save(path string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// SyncItem is a planned action for a file
+type SyncItem struct {
+	// Path relative to the target folder
+	Path string
+	// Action to take
+	Action SyncAction
+	// File source file, nil for deleted files
+	File ifs.FileInfo
+	// Hash sha256 of the source content, empty for deleted files
+	Hash string
+}
+
+// SyncPlan lists the actions needed to sync a target folder
+type SyncPlan struct {
+	// Base source folder
+	Base string
+	// Destination target folder
+	Destination string
+	// Items actions sorted by path
+	Items []SyncItem
+}
+
+// Count returns the number of items with the given action
+func (p *SyncPlan) Count(action SyncAction) int {
+	count := 0
+	for _, item := range p.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Summary returns a one line summary of the plan
+func (p *SyncPlan) Summary() string {
+	return fmt.Sprintf("%d added, %d updated, %d deleted, %d unchanged",
+		p.Count(SyncAdded), p.Count(SyncUpdated), p.Count(SyncDeleted), p.Count(SyncUnchanged))
+}
+
+// Manifest returns the manifest describing the target folder after the plan is applied
+func (p *SyncPlan) Manifest(source string) *Manifest {
+	manifest := &Manifest{Source: source, Files: map[string]string{}}
+	for _, item := range p.Items {
+		if item.Action != SyncDeleted {
+			manifest.Files[item.Path] = item.Hash
+		}
+	}
+	return manifest
+}
+
+var _ FileSyncer = &FileSystemCopier{}
+
+// Plan compares the files with the manifest of the previous sync and the target folder
+// files with the same content in the source and the target are unchanged
+// files in the manifest that are no longer selected are deleted
+func (s *FileSystemCopier) Plan(ctx context.Context, base, destination string, manifest *Manifest, files ...ifs.FileInfo) (*SyncPlan, error) {
+	plan := &SyncPlan{Base: base, Destination: destination}
+	selected := map[string]bool{}
+	for _, file := range files {
+		relativeFilePath := relativePath(base, file.GetPath())
+		if selected[relativeFilePath] {
+			continue
+		}
+		selected[relativeFilePath] = true
+
+		hash, err := s.hashContent(ctx, base, file)
+		if err != nil {
+			return nil, err
+		}
+
+		item := SyncItem{Path: relativeFilePath, File: file, Hash: hash}
+		previousHash, synced := manifest.Files[relativeFilePath]
+		targetHash, targetErr := hashFile(filepath.Join(destination, relativeFilePath))
+		switch {
+		case synced && previousHash == hash && targetErr == nil && targetHash == hash:
+			item.Action = SyncUnchanged
+		case synced || targetErr == nil:
+			item.Action = SyncUpdated
+		default:
+			item.Action = SyncAdded
+		}
+		plan.Items = append(plan.Items, item)
+	}
+
+	for relativeFilePath := range manifest.Files {
+		if !selected[relativeFilePath] {
+			plan.Items = append(plan.Items, SyncItem{Path: relativeFilePath, Action: SyncDeleted})
+		}
+	}
+
+	sort.Slice(plan.Items, func(i, j int) bool { return plan.Items[i].Path < plan.Items[j].Path })
+	return plan, nil
+}
+
+// Apply copies added and updated files and removes deleted files
+// folders left empty by deleted files are removed as well
+func (s *FileSystemCopier) Apply(ctx context.Context, plan *SyncPlan) error {
+	log := logger.GetLogger(ctx)
+	for _, item := range plan.Items {
+		switch item.Action {
+		case SyncAdded, SyncUpdated:
+			log.Debug(item.Action, " file ", item.Path)
+			if err := s.CopyFile(ctx, plan.Base, plan.Destination, item.File); err != nil {
+				return err
+			}
+		case SyncDeleted:
+			log.Debug("deleting file ", item.Path)
+			targetPath := filepath.Join(plan.Destination, item.Path)
+			if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			removeEmptyParents(filepath.Dir(targetPath), plan.Destination)
+		}
+	}
+	return nil
+}
+
+// CleanPlan rewrites a plan for a destination that is removed before syncing
+// every file of the destination is deleted and every selected file is added again
+func CleanPlan(plan *SyncPlan) error {
+	items := make([]SyncItem, 0, len(plan.Items))
+	for _, item := range plan.Items {
+		if item.Action == SyncDeleted {
+			continue
+		}
+		item.Action = SyncAdded
+		items = append(items, item)
+	}
+
+	if _, err := os.Stat(plan.Destination); err == nil {
+		err := filepath.WalkDir(plan.Destination, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				items = append(items, SyncItem{Path: relativePath(plan.Destination, path), Action: SyncDeleted})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	// files copied again are listed after their deletion
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Path == items[j].Path {
+			return items[i].Action == SyncDeleted && items[j].Action != SyncDeleted
+		}
+		return items[i].Path < items[j].Path
+	})
+	plan.Items = items
+	return nil
+}
+
+// Sync incrementally syncs the files to the destination using the manifest in manifestPath
+// and updates the manifest once the files are synced
+// implements the FileSyncer interface
+func (s *FileSystemCopier) Sync(ctx context.Context, source, base, destination, manifestPath string, files ...ifs.FileInfo) (*SyncPlan, error) {
+	manifest, err := LoadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.Plan(ctx, base, destination, manifest, files...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Apply(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, plan.Manifest(source).Save(manifestPath)
+}
+
+// relativePath returns the path of a file relative to base, using the same logic as CopyFile
+func relativePath(base, path string) string {
+	relativeFilePath, _ := strings.CutPrefix(path, base)
+	return strings.TrimPrefix(filepath.ToSlash(relativeFilePath), "/")
+}
+
+// hashContent returns the sha256 of the content the file will have once copied
+func (s *FileSystemCopier) hashContent(ctx context.Context, base string, file ifs.FileInfo) (string, error) {
+	if s.Transformer == nil {
+		return hashFile(file.GetPath())
+	}
+	content, err := s.readContent(ctx, base, file)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// hashFile returns the sha256 of the file content
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// removeEmptyParents removes empty folders from dir up to, but not including, root
+func removeEmptyParents(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fscopy_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileSystemCopier_Sync(t *testing.T) {
+	ctx, _ := testLoggerContext()
+	base := t.TempDir()
+	source := filepath.Join(base, "source")
+	copyTo := filepath.Join(base, "imported")
+	destination := filepath.Join(copyTo, "my-source")
+	manifestPath := fscopy.ManifestPath(copyTo, "my-source")
+
+	writeTestFile(t, filepath.Join(source, "keep.md"), "keep")
+	writeTestFile(t, filepath.Join(source, "change.md"), "v1")
+	writeTestFile(t, filepath.Join(source, "old", "renamed.md"), "renamed")
+
+	copier := &fscopy.FileSystemCopier{}
+	selector := &fscopy.FileSystemSelector{}
+
+	sync := func() *fscopy.SyncPlan {
+		files, err := selector.ListFiles(ctx, source)
+		if err != nil {
+			t.Fatalf("error listing files: %v", err)
+		}
+		plan, err := copier.Sync(ctx, "my-source", source, destination, manifestPath, files...)
+		if err != nil {
+			t.Fatalf("error syncing files: %v", err)
+		}
+		return plan
+	}
+	actions := func(plan *fscopy.SyncPlan) map[string]fscopy.SyncAction {
+		result := map[string]fscopy.SyncAction{}
+		for _, item := range plan.Items {
+			result[item.Path] = item.Action
+		}
+		return result
+	}
+
+	plan := sync()
+	expected := map[string]fscopy.SyncAction{
+		"change.md":      fscopy.SyncAdded,
+		"keep.md":        fscopy.SyncAdded,
+		"old/renamed.md": fscopy.SyncAdded,
+	}
+	if diff := cmp.Diff(expected, actions(plan)); diff != "" {
+		t.Errorf("first sync mismatch (-want +got):\n%s", diff)
+	}
+
+	// change one file, rename another and leave the rest untouched
+	writeTestFile(t, filepath.Join(source, "change.md"), "v2")
+	if err := os.Rename(filepath.Join(source, "old"), filepath.Join(source, "new")); err != nil {
+		t.Fatal(err)
+	}
+
+	plan = sync()
+	expected = map[string]fscopy.SyncAction{
+		"change.md":      fscopy.SyncUpdated,
+		"keep.md":        fscopy.SyncUnchanged,
+		"new/renamed.md": fscopy.SyncAdded,
+		"old/renamed.md": fscopy.SyncDeleted,
+	}
+	if diff := cmp.Diff(expected, actions(plan)); diff != "" {
+		t.Errorf("second sync mismatch (-want +got):\n%s", diff)
+	}
+	if plan.Summary() != "1 added, 1 updated, 1 deleted, 1 unchanged" {
+		t.Errorf("unexpected summary: %s", plan.Summary())
+	}
+
+	content, err := os.ReadFile(filepath.Join(destination, "change.md"))
+	if err != nil || string(content) != "v2" {
+		t.Errorf("expected updated content, got %q, err: %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(destination, "old")); !os.IsNotExist(err) {
+		t.Errorf("expected stale folder to be pruned, err: %v", err)
+	}
+
+	manifest, err := fscopy.LoadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("error loading manifest: %v", err)
+	}
+	if manifest.Source != "my-source" || len(manifest.Files) != 3 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	// files modified in the target are copied again
+	writeTestFile(t, filepath.Join(destination, "keep.md"), "modified in target")
+	plan = sync()
+	if action := actions(plan)["keep.md"]; action != fscopy.SyncUpdated {
+		t.Errorf("expected file modified in target to be updated, got %s", action)
+	}
+}
+
+func TestLoadManifest_NotExist(t *testing.T) {
+	manifest, err := fscopy.LoadManifest(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.Files == nil || len(manifest.Files) != 0 {
+		t.Errorf("expected empty manifest, got %+v", manifest)
+	}
+}
+
+// upperTransformer upper cases the content of markdown files
+type upperTransformer struct{}
+
+func (upperTransformer) Transform(ctx context.Context, path string, content []byte) ([]byte, error) {
+	if filepath.Ext(path) != ".md" {
+		return content, nil
+	}
+	return bytes.ToUpper(content), nil
+}
+
+func TestFileSystemCopier_SyncWithTransformer(t *testing.T) {
+	ctx, _ := testLoggerContext()
+	base := t.TempDir()
+	source := filepath.Join(base, "source")
+	destination := filepath.Join(base, "imported", "my-source")
+	manifestPath := fscopy.ManifestPath(filepath.Join(base, "imported"), "my-source")
+
+	writeTestFile(t, filepath.Join(source, "docs", "index.md"), "hello")
+	writeTestFile(t, filepath.Join(source, "image.svg"), "<svg/>")
+
+	copier := &fscopy.FileSystemCopier{Transformer: upperTransformer{}}
+	files, err := (&fscopy.FileSystemSelector{}).ListFiles(ctx, source)
+	if err != nil {
+		t.Fatalf("error listing files: %v", err)
+	}
+	if _, err := copier.Sync(ctx, "my-source", source, destination, manifestPath, files...); err != nil {
+		t.Fatalf("error syncing files: %v", err)
+	}
+
+	expected := map[string]string{"docs/index.md": "HELLO", "image.svg": "<svg/>"}
+	for path, content := range expected {
+		got, err := os.ReadFile(filepath.Join(destination, path))
+		if err != nil {
+			t.Fatalf("error reading %s: %v", path, err)
+		}
+		if string(got) != content {
+			t.Errorf("expected %s content %q, got %q", path, content, got)
+		}
+	}
+
+	// transformed files are unchanged on the next sync and have no diff
+	plan, err := copier.Sync(ctx, "my-source", source, destination, manifestPath, files...)
+	if err != nil {
+		t.Fatalf("error syncing files: %v", err)
+	}
+	if plan.Count(fscopy.SyncUnchanged) != 2 {
+		t.Errorf("expected all files unchanged, got %s", plan.Summary())
+	}
+	diffs, err := copier.Diff(ctx, source, destination, files...)
+	if err != nil {
+		t.Fatalf("error comparing files: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %+v", diffs)
+	}
+}
@@ -0,0 +1,219 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package repository checks out git repositories used as copy sources
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+)
+
+// defaultUsername is used for basic auth when only a token is configured
+// it is accepted by GitHub and GitLab for personal access tokens
+const defaultUsername = "x-access-token"
+
+// commitSHAPattern matches full or abbreviated commit SHAs
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// Checkouter checks out a repository and returns the local path of the requested subpath
+type Checkouter interface {
+	Checkout(ctx context.Context, repo config.Repository) (string, error)
+}
+
+// GitCheckouter checks out repositories using the git cli
+// clones are kept in CacheDir and reused across runs, only the requested ref is fetched
+type GitCheckouter struct {
+	// CacheDir is the directory where repositories are cloned
+	CacheDir string
+}
+
+var _ Checkouter = &GitCheckouter{}
+
+// DefaultCacheDir returns the default directory to cache repositories
+func DefaultCacheDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "syncfiles", "repositories")
+	}
+	return filepath.Join(os.TempDir(), "syncfiles", "repositories")
+}
+
+// Checkout clones or updates the repository in the cache directory and checks out the configured ref
+// returns the path to the configured subpath of the checkout
+func (g *GitCheckouter) Checkout(ctx context.Context, repo config.Repository) (string, error) {
+	log := logger.GetLogger(ctx)
+
+	subpath, err := localSubpath(repo.Path)
+	if err != nil {
+		return "", err
+	}
+
+	env, err := authEnv(repo.Auth)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(g.CacheDir, cacheKey(repo.URL))
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		log.Info("Cloning repository ", repo.URL, " into ", dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+		if err := g.git(ctx, dir, nil, "init", "--quiet"); err != nil {
+			return "", err
+		}
+		if err := g.git(ctx, dir, nil, "remote", "add", "origin", repo.URL); err != nil {
+			return "", err
+		}
+	} else {
+		log.Info("Reusing cached repository ", repo.URL, " in ", dir)
+		if err := g.git(ctx, dir, nil, "remote", "set-url", "origin", repo.URL); err != nil {
+			return "", err
+		}
+	}
+
+	if err := g.sparseCheckout(ctx, dir, subpath); err != nil {
+		return "", err
+	}
+
+	ref := repo.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	log.Debug("fetching ref ", ref, " from ", repo.URL)
+	if err := g.git(ctx, dir, env, "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+		// servers may refuse to serve commits that are not a ref tip
+		// so fall back to a full fetch for commit SHAs
+		if !commitSHAPattern.MatchString(ref) {
+			return "", err
+		}
+		log.Debug("fetching commit ", ref, " failed, fetching the whole repository")
+		if err := g.git(ctx, dir, env, "fetch", "--quiet", "--unshallow", "origin"); err != nil {
+			if err := g.git(ctx, dir, env, "fetch", "--quiet", "origin"); err != nil {
+				return "", err
+			}
+		}
+		if err := g.git(ctx, dir, nil, "checkout", "--quiet", "--force", "--detach", ref); err != nil {
+			return "", err
+		}
+	} else if err := g.git(ctx, dir, nil, "checkout", "--quiet", "--force", "--detach", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+
+	// remove leftovers of previous checkouts
+	if err := g.git(ctx, dir, nil, "clean", "--quiet", "-ffdx"); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, subpath)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("path %q not found in repository %s at %s: %w", repo.Path, repo.URL, ref, err)
+	}
+	return path, nil
+}
+
+// localSubpath cleans the configured subpath of a repository
+// leading slashes are relative to the repository root, paths escaping the checkout are rejected
+func localSubpath(path string) (string, error) {
+	path = strings.TrimLeft(filepath.ToSlash(path), "/")
+	if path == "" {
+		return ".", nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(path)) {
+		return "", fmt.Errorf("path %q is outside of the repository", path)
+	}
+	return filepath.Clean(filepath.FromSlash(path)), nil
+}
+
+// sparseCheckout restricts the checkout to the given subpath, or disables sparse checkout if empty
+func (g *GitCheckouter) sparseCheckout(ctx context.Context, dir, path string) error {
+	path = strings.Trim(filepath.ToSlash(path), "/")
+	if path == "" || path == "." {
+		return g.git(ctx, dir, nil, "sparse-checkout", "disable")
+	}
+	return g.git(ctx, dir, nil, "sparse-checkout", "set", "--no-cone", "/"+path+"/")
+}
+
+// git runs a git command in the given directory
+func (g *GitCheckouter) git(ctx context.Context, dir string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, env...)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w, output: %s", args[0], err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+// authEnv returns the environment variables to authenticate git over HTTP(S)
+// the credentials are passed as an extra header through the environment
+// so they are never written to the cached repository configuration
+func authEnv(auth *config.RepositoryAuth) ([]string, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	token := ""
+	if auth.TokenEnv != "" {
+		token = os.Getenv(auth.TokenEnv)
+	}
+	if token == "" && auth.TokenFile != "" {
+		content, err := os.ReadFile(auth.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading token file: %w", err)
+		}
+		token = strings.TrimSpace(string(content))
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no token found in environment variable %q or file %q", auth.TokenEnv, auth.TokenFile)
+	}
+
+	username := auth.Username
+	if username == "" {
+		username = defaultUsername
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + token))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + credentials,
+	}, nil
+}
+
+// cacheKey returns a readable and unique directory name for a repository URL
+func cacheKey(url string) string {
+	name := strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if index := strings.LastIndexAny(name, "/:"); index >= 0 {
+		name = name[index+1:]
+	}
+	hash := sha256.Sum256([]byte(url))
+	return name + "-" + hex.EncodeToString(hash[:])[:12]
+}
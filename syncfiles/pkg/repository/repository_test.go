@@ -0,0 +1,182 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newBareRepository creates a bare repository with two commits
+// v1 contains docs/en/index.md and README.md
+// the second commit on main adds docs/en/new.md and removes README.md
+// returns the bare repository path and the SHA of the first commit
+func newBareRepository(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	bare := filepath.Join(base, "origin.git")
+
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v, output: %s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.MkdirAll(work, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "init", "--quiet", "--initial-branch", "main")
+	write(filepath.Join(work, "README.md"), "readme")
+	write(filepath.Join(work, "docs", "en", "index.md"), "index v1")
+	run(work, "add", "-A")
+	run(work, "commit", "--quiet", "-m", "v1")
+	run(work, "tag", "v1")
+	first := run(work, "rev-parse", "HEAD")
+
+	write(filepath.Join(work, "docs", "en", "new.md"), "new")
+	run(work, "rm", "--quiet", "README.md")
+	run(work, "add", "-A")
+	run(work, "commit", "--quiet", "-m", "v2")
+
+	run(base, "clone", "--quiet", "--bare", work, bare)
+	return bare, first
+}
+
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestGitCheckouter_Checkout(t *testing.T) {
+	bare, firstCommit := newBareRepository(t)
+	ctx := logger.WithLogger(context.Background(), logger.NewLoggerFromContext(context.Background(), logger.LogLeveler{Level: "debug"}))
+	checkouter := &repository.GitCheckouter{CacheDir: t.TempDir()}
+
+	tests := []struct {
+		name     string
+		repo     config.Repository
+		expected []string
+	}{
+		{
+			name:     "default branch",
+			repo:     config.Repository{URL: bare},
+			expected: []string{"docs/en/index.md", "docs/en/new.md"},
+		},
+		{
+			name:     "tag",
+			repo:     config.Repository{URL: bare, Ref: "v1"},
+			expected: []string{"README.md", "docs/en/index.md"},
+		},
+		{
+			name:     "sparse path on branch",
+			repo:     config.Repository{URL: bare, Ref: "main", Path: "docs"},
+			expected: []string{"en/index.md", "en/new.md"},
+		},
+		{
+			name:     "sparse path on commit",
+			repo:     config.Repository{URL: bare, Ref: firstCommit, Path: "docs/en"},
+			expected: []string{"index.md"},
+		},
+	}
+
+	// all cases share the same cache directory to verify the clone is reused
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := checkouter.Checkout(ctx, tt.repo)
+			if err != nil {
+				t.Fatalf("error checking out repository: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, listFiles(t, path)); diff != "" {
+				t.Errorf("checked out files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(checkouter.CacheDir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected a single cached clone, got %v, err: %v", entries, err)
+	}
+}
+
+func TestGitCheckouter_CheckoutErrors(t *testing.T) {
+	bare, _ := newBareRepository(t)
+	ctx := logger.WithLogger(context.Background(), logger.NewLoggerFromContext(context.Background(), logger.LogLeveler{Level: "debug"}))
+	checkouter := &repository.GitCheckouter{CacheDir: t.TempDir()}
+
+	if _, err := checkouter.Checkout(ctx, config.Repository{URL: bare, Ref: "does-not-exist"}); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+	if _, err := checkouter.Checkout(ctx, config.Repository{URL: bare, Path: "missing"}); err == nil {
+		t.Error("expected error for unknown path")
+	}
+	for _, path := range []string{"..", "../..", "docs/../../outside", "/../outside"} {
+		if _, err := checkouter.Checkout(ctx, config.Repository{URL: bare, Path: path}); err == nil {
+			t.Errorf("expected error for path %q outside of the repository", path)
+		}
+	}
+	auth := &config.RepositoryAuth{TokenEnv: "SYNCFILES_TEST_TOKEN_NOT_SET"}
+	if _, err := checkouter.Checkout(ctx, config.Repository{URL: bare, Auth: auth}); err == nil {
+		t.Error("expected error for missing token")
+	}
+}
@@ -0,0 +1,145 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// applyFrontMatter sets and merges fields into the YAML front matter of the content
+// a front matter is added when the content has none
+func applyFrontMatter(content []byte, transform *config.FrontMatterTransform) ([]byte, error) {
+	frontMatter, body, found := splitFrontMatter(content)
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if found && len(bytes.TrimSpace(frontMatter)) > 0 {
+		document := &yaml.Node{}
+		if err := yaml.Unmarshal(frontMatter, document); err != nil {
+			return nil, fmt.Errorf("parsing front matter: %w", err)
+		}
+		if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("front matter is not a mapping")
+		}
+		mapping = document.Content[0]
+	}
+
+	for _, key := range sortedKeys(transform.Set) {
+		value, err := encodeNode(transform.Set[key])
+		if err != nil {
+			return nil, err
+		}
+		setField(mapping, key, value)
+	}
+	for _, key := range sortedKeys(transform.Merge) {
+		value, err := encodeNode(transform.Merge[key])
+		if err != nil {
+			return nil, err
+		}
+		mergeField(mapping, key, value)
+	}
+
+	result := &bytes.Buffer{}
+	result.WriteString(frontMatterDelimiter + "\n")
+	encoder := yaml.NewEncoder(result)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(mapping); err != nil {
+		return nil, fmt.Errorf("encoding front matter: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	result.WriteString(frontMatterDelimiter + "\n")
+	result.Write(body)
+	return result.Bytes(), nil
+}
+
+// splitFrontMatter splits the content into the front matter, without delimiters, and the body
+func splitFrontMatter(content []byte) (frontMatter, body []byte, found bool) {
+	firstLine, rest, ok := bytes.Cut(content, []byte("\n"))
+	if !ok || string(bytes.TrimRight(firstLine, "\r")) != frontMatterDelimiter {
+		return nil, content, false
+	}
+	for offset := 0; ; {
+		line, _, hasNext := bytes.Cut(rest[offset:], []byte("\n"))
+		if string(bytes.TrimRight(line, "\r")) == frontMatterDelimiter {
+			end := offset + len(line)
+			if hasNext {
+				end++
+			}
+			return rest[:offset], rest[end:], true
+		}
+		if !hasNext {
+			return nil, content, false
+		}
+		offset += len(line) + 1
+	}
+}
+
+// setField replaces the value of a field, adding it if missing
+func setField(mapping *yaml.Node, key string, value *yaml.Node) {
+	if index := fieldIndex(mapping, key); index >= 0 {
+		mapping.Content[index+1] = value
+		return
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// mergeField deep merges the value of a field, mappings are merged recursively and other values replaced
+func mergeField(mapping *yaml.Node, key string, value *yaml.Node) {
+	index := fieldIndex(mapping, key)
+	if index < 0 || mapping.Content[index+1].Kind != yaml.MappingNode || value.Kind != yaml.MappingNode {
+		setField(mapping, key, value)
+		return
+	}
+	existing := mapping.Content[index+1]
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		mergeField(existing, value.Content[i].Value, value.Content[i+1])
+	}
+}
+
+// fieldIndex returns the index of the key node of a field in a mapping, -1 if missing
+func fieldIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func encodeNode(value any) (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, fmt.Errorf("encoding front matter value: %w", err)
+	}
+	return node, nil
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
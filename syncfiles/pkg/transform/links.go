@@ -0,0 +1,169 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+)
+
+// linkPatterns match link targets in markdown and HTML, the second group is the target
+var linkPatterns = []*regexp.Regexp{
+	// inline links and images: [text](target "title")
+	regexp.MustCompile(`(!?\[[^\]]*\]\()([^)\s]+)((?:\s+"[^"]*")?\))`),
+	// reference definitions: [id]: target
+	regexp.MustCompile(`(?m)(^ {0,3}\[[^\]]+\]:[ \t]*)(\S+)()`),
+	// HTML attributes: <img src="target">, <a href="target">
+	regexp.MustCompile(`(\b(?:src|href)=")([^"]+)(")`),
+}
+
+// linkRewriter rewrites the link targets of a file
+type linkRewriter struct {
+	rewrite []config.LinkRewrite
+	outside *url.URL
+}
+
+func newLinkRewriter(links config.LinkTransform, source string) (*linkRewriter, error) {
+	rewriter := &linkRewriter{}
+	for _, rewrite := range links.Rewrite {
+		rewriter.rewrite = append(rewriter.rewrite, config.LinkRewrite{From: expand(rewrite.From, source), To: expand(rewrite.To, source)})
+	}
+	if links.Outside != "" {
+		outside, err := url.Parse(expand(links.Outside, source))
+		if err != nil {
+			return nil, fmt.Errorf("invalid links outside URL %q: %w", links.Outside, err)
+		}
+		if !strings.HasSuffix(outside.Path, "/") {
+			outside.Path += "/"
+		}
+		rewriter.outside = outside
+	}
+	return rewriter, nil
+}
+
+// apply rewrites the links of the content of filePath
+func (r *linkRewriter) apply(filePath string, content []byte) []byte {
+	return mapOutsideCode(content, func(text []byte) []byte {
+		for _, pattern := range linkPatterns {
+			text = pattern.ReplaceAllFunc(text, func(match []byte) []byte {
+				groups := pattern.FindSubmatch(match)
+				target := r.target(filePath, string(groups[2]))
+				return bytes.Join([][]byte{groups[1], []byte(target), groups[3]}, nil)
+			})
+		}
+		return text
+	})
+}
+
+// target returns the rewritten target of a link
+// the first matching prefix rewrite is applied, otherwise relative links pointing
+// outside of the copied tree are resolved against the outside URL
+func (r *linkRewriter) target(filePath, target string) string {
+	if isExternal(target) {
+		return target
+	}
+	linkPath, suffix := splitLink(target)
+	for _, rewrite := range r.rewrite {
+		if rest, ok := strings.CutPrefix(linkPath, rewrite.From); ok {
+			return rewrite.To + rest + suffix
+		}
+	}
+	if r.outside != nil && linkPath != "" && !strings.HasPrefix(linkPath, "/") {
+		resolved := path.Join(path.Dir(filePath), linkPath)
+		if isOutside(resolved) {
+			return r.outside.ResolveReference(&url.URL{Path: resolved}).String() + suffix
+		}
+	}
+	return target
+}
+
+// localLinks returns the relative link targets of the content
+// external links, absolute paths and anchors are ignored
+func localLinks(content []byte) []string {
+	links := []string{}
+	mapOutsideCode(content, func(text []byte) []byte {
+		for _, pattern := range linkPatterns {
+			for _, groups := range pattern.FindAllSubmatch(text, -1) {
+				target := string(groups[2])
+				linkPath, _ := splitLink(target)
+				if !isExternal(target) && linkPath != "" && !strings.HasPrefix(linkPath, "/") {
+					links = append(links, target)
+				}
+			}
+		}
+		return text
+	})
+	return links
+}
+
+// mapOutsideCode applies fn to the parts of the content outside of fenced code blocks
+func mapOutsideCode(content []byte, fn func([]byte) []byte) []byte {
+	result := make([]byte, 0, len(content))
+	chunk := []byte{}
+	fence := ""
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		trimmed := strings.TrimLeft(string(line), " ")
+		marker := ""
+		if strings.HasPrefix(trimmed, "```") {
+			marker = "```"
+		} else if strings.HasPrefix(trimmed, "~~~") {
+			marker = "~~~"
+		}
+		switch {
+		case fence == "" && marker != "":
+			result = append(result, fn(chunk)...)
+			chunk = []byte{}
+			fence = marker
+			result = append(result, line...)
+		case fence != "":
+			if marker == fence {
+				fence = ""
+			}
+			result = append(result, line...)
+		default:
+			chunk = append(chunk, line...)
+		}
+	}
+	return append(result, fn(chunk)...)
+}
+
+// splitLink splits a link target into its path and its query or fragment
+func splitLink(target string) (string, string) {
+	if index := strings.IndexAny(target, "?#"); index >= 0 {
+		return target[:index], target[index:]
+	}
+	return target, ""
+}
+
+// isExternal returns true for links with a scheme, like https: or mailto:, and protocol relative links
+func isExternal(target string) bool {
+	if strings.HasPrefix(target, "//") {
+		return true
+	}
+	parsed, err := url.Parse(target)
+	return err == nil && parsed.Scheme != ""
+}
+
+// isOutside returns true if a cleaned relative path points outside of its root
+func isOutside(resolved string) bool {
+	return resolved == ".." || strings.HasPrefix(resolved, "../")
+}
@@ -0,0 +1,267 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package transform applies the content transforms configured for copy sources
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
+)
+
+// namePlaceholder is replaced with the source name in the configured values
+const namePlaceholder = "<name>"
+
+// defaultFiles are the files matched by a transform without files, transforms are meant for markdown
+const defaultFiles = "*.md"
+
+// Pipeline applies a list of transforms to the files of a source
+// it records the links of the files matched by a links transform to report broken links
+type Pipeline struct {
+	steps []step
+
+	lock  sync.Mutex
+	links map[string][]string
+}
+
+var _ fscopy.ContentTransformer = &Pipeline{}
+
+// step is a compiled transform
+type step struct {
+	files       []*regexp.Regexp
+	replace     []replacement
+	frontMatter *config.FrontMatterTransform
+	links       *linkRewriter
+}
+
+// replacement is a compiled regular expression replacement
+type replacement struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// BrokenLink is a relative link that does not point to a copied file
+type BrokenLink struct {
+	// File path of the file containing the link, relative to the source folder
+	File string
+	// Link target as written in the file
+	Link string
+	// Reason why the link is broken
+	Reason string
+}
+
+func (b BrokenLink) String() string {
+	return fmt.Sprintf("%s: %s (%s)", b.File, b.Link, b.Reason)
+}
+
+// New compiles the transforms of a source
+// the <name> placeholder is replaced with the source name
+func New(source string, transforms ...config.Transform) (*Pipeline, error) {
+	pipeline := &Pipeline{links: map[string][]string{}}
+	for _, transform := range transforms {
+		compiled := step{}
+		patterns := transform.Files
+		if len(patterns) == 0 {
+			patterns = []string{defaultFiles}
+		}
+		for _, pattern := range patterns {
+			compiled.files = append(compiled.files, compileGlob(pattern))
+		}
+		for _, replace := range transform.Replace {
+			pattern, err := regexp.Compile(replace.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid replace pattern %q: %w", replace.Pattern, err)
+			}
+			compiled.replace = append(compiled.replace, replacement{pattern: pattern, replacement: expand(replace.Replacement, source)})
+		}
+		if transform.FrontMatter != nil {
+			compiled.frontMatter = &config.FrontMatterTransform{
+				Set:   expandMap(transform.FrontMatter.Set, source),
+				Merge: expandMap(transform.FrontMatter.Merge, source),
+			}
+		}
+		if transform.Links != nil {
+			links, err := newLinkRewriter(*transform.Links, source)
+			if err != nil {
+				return nil, err
+			}
+			compiled.links = links
+		}
+		pipeline.steps = append(pipeline.steps, compiled)
+	}
+	return pipeline, nil
+}
+
+// Empty returns true if the pipeline has no transforms
+func (p *Pipeline) Empty() bool {
+	return p == nil || len(p.steps) == 0
+}
+
+// Transform applies the transforms matching the path to the content
+// binary content is returned unchanged, even if its path matches
+// implements the fscopy.ContentTransformer interface
+func (p *Pipeline) Transform(ctx context.Context, filePath string, content []byte) ([]byte, error) {
+	if isBinary(content) {
+		return content, nil
+	}
+	checkLinks := false
+	for _, step := range p.steps {
+		if !step.matches(filePath) {
+			continue
+		}
+		for _, replace := range step.replace {
+			content = replace.pattern.ReplaceAll(content, []byte(replace.replacement))
+		}
+		if step.frontMatter != nil {
+			var err error
+			if content, err = applyFrontMatter(content, step.frontMatter); err != nil {
+				return nil, err
+			}
+		}
+		if step.links != nil {
+			content = step.links.apply(filePath, content)
+			checkLinks = true
+		}
+	}
+	if checkLinks {
+		p.lock.Lock()
+		p.links[filePath] = localLinks(content)
+		p.lock.Unlock()
+	}
+	return content, nil
+}
+
+// BrokenLinks returns the relative links recorded by Transform that do not point to one of the copied files or folders
+// files are the paths of the copied files relative to the source folder
+func (p *Pipeline) BrokenLinks(files ...string) []BrokenLink {
+	copied := map[string]bool{".": true}
+	for _, file := range files {
+		for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			copied[dir] = true
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	broken := []BrokenLink{}
+	sorted := append([]string{}, files...)
+	sort.Strings(sorted)
+	for _, file := range sorted {
+		for _, link := range p.links[file] {
+			linkPath, _ := splitLink(link)
+			if unescaped, err := url.PathUnescape(linkPath); err == nil {
+				linkPath = unescaped
+			}
+			resolved := path.Join(path.Dir(file), linkPath)
+			switch {
+			case isOutside(resolved):
+				broken = append(broken, BrokenLink{File: file, Link: link, Reason: "points outside of the copied files"})
+			case !copied[resolved]:
+				broken = append(broken, BrokenLink{File: file, Link: link, Reason: "target is not copied"})
+			}
+		}
+	}
+	return broken
+}
+
+// matches returns true if the path matches one of the file globs of the step
+func (s step) matches(filePath string) bool {
+	for _, pattern := range s.files {
+		if pattern.MatchString(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileGlob converts a glob to a regular expression
+// "*" matches any character except "/", "**" matches any number of folders and "?" matches one character
+// patterns without "/" match the file name in any folder
+func compileGlob(pattern string) *regexp.Regexp {
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	expr := strings.Builder{}
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// isBinary returns true if the content is not text: it contains a NUL byte or is not valid UTF-8
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content)
+}
+
+// expand replaces the <name> placeholder with the source name
+func expand(value, source string) string {
+	return strings.ReplaceAll(value, namePlaceholder, source)
+}
+
+// expandMap replaces the <name> placeholder in all the string values of a map
+func expandMap(values map[string]any, source string) map[string]any {
+	if values == nil {
+		return nil
+	}
+	expanded := make(map[string]any, len(values))
+	for key, value := range values {
+		expanded[key] = expandValue(value, source)
+	}
+	return expanded
+}
+
+func expandValue(value any, source string) any {
+	switch typed := value.(type) {
+	case string:
+		return expand(typed, source)
+	case map[string]any:
+		return expandMap(typed, source)
+	case []any:
+		expanded := make([]any, len(typed))
+		for i, item := range typed {
+			expanded[i] = expandValue(item, source)
+		}
+		return expanded
+	default:
+		return value
+	}
+}
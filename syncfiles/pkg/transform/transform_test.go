@@ -0,0 +1,161 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transform_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/config"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/transform"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPipeline_Transform(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms []config.Transform
+		path       string
+		content    string
+		expected   string
+	}{
+		{
+			name:       "replace",
+			transforms: []config.Transform{{Files: []string{"*.md"}, Replace: []config.Replacement{{Pattern: `/docs/(\w+)`, Replacement: "/<name>/$1"}}}},
+			path:       "en/index.md",
+			content:    "see /docs/install and /docs/upgrade",
+			expected:   "see /pipeline/install and /pipeline/upgrade",
+		},
+		{
+			name:       "files not matching",
+			transforms: []config.Transform{{Files: []string{"en/**/*.md"}, Replace: []config.Replacement{{Pattern: "a", Replacement: "b"}}}},
+			path:       "zh/index.md",
+			content:    "a",
+			expected:   "a",
+		},
+		{
+			name: "front matter set and merge",
+			transforms: []config.Transform{{FrontMatter: &config.FrontMatterTransform{
+				Set:   map[string]any{"source": "<name>", "weight": 10},
+				Merge: map[string]any{"i18n": map[string]any{"title": map[string]any{"en": "Pipeline"}}},
+			}}},
+			path: "index.md",
+			content: `---
+# keep comments
+weight: 1
+i18n:
+  title:
+    zh: 流水线
+---
+# Title
+`,
+			expected: `---
+# keep comments
+weight: 10
+i18n:
+  title:
+    zh: 流水线
+    en: Pipeline
+source: pipeline
+---
+# Title
+`,
+		},
+		{
+			name:       "front matter added",
+			transforms: []config.Transform{{FrontMatter: &config.FrontMatterTransform{Set: map[string]any{"source": "<name>"}}}},
+			path:       "index.md",
+			content:    "# Title\n",
+			expected:   "---\nsource: pipeline\n---\n# Title\n",
+		},
+		{
+			name:       "files default to markdown",
+			transforms: []config.Transform{{FrontMatter: &config.FrontMatterTransform{Set: map[string]any{"source": "<name>"}}}},
+			path:       "en/config.yaml",
+			content:    "key: value\n",
+			expected:   "key: value\n",
+		},
+		{
+			name:       "binary content",
+			transforms: []config.Transform{{Files: []string{"**"}, Replace: []config.Replacement{{Pattern: "PNG", Replacement: "JPG"}}, FrontMatter: &config.FrontMatterTransform{Set: map[string]any{"source": "<name>"}}}},
+			path:       "images/logo.png",
+			content:    "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+			expected:   "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+		},
+		{
+			name: "links",
+			transforms: []config.Transform{{Files: []string{"*.md"}, Links: &config.LinkTransform{
+				Rewrite: []config.LinkRewrite{{From: "/images/", To: "/images/<name>/"}},
+				Outside: "https://github.com/tektoncd/pipeline/blob/main/docs",
+			}}},
+			path: "en/index.md",
+			content: "[install](./install.md#steps) ![logo](/images/logo.png \"Logo\")\n" +
+				"[readme](../../README.md) [site](https://tekton.dev) <a href=\"../../CONTRIBUTING.md\">contributing</a>\n" +
+				"[ref]: ../../LICENSE\n" +
+				"```\n[code](../../README.md)\n```\n",
+			expected: "[install](./install.md#steps) ![logo](/images/pipeline/logo.png \"Logo\")\n" +
+				"[readme](https://github.com/tektoncd/pipeline/blob/main/README.md) [site](https://tekton.dev) <a href=\"https://github.com/tektoncd/pipeline/blob/main/CONTRIBUTING.md\">contributing</a>\n" +
+				"[ref]: https://github.com/tektoncd/pipeline/blob/main/LICENSE\n" +
+				"```\n[code](../../README.md)\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := transform.New("pipeline", tt.transforms...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := pipeline.Transform(context.Background(), tt.path, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, string(got)); diff != "" {
+				t.Errorf("content mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPipeline_BrokenLinks(t *testing.T) {
+	pipeline, err := transform.New("pipeline", config.Transform{Files: []string{"*.md"}, Links: &config.LinkTransform{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := "[install](install.md) [images](../images) [ignored](ignored.md#anchor) [outside](../../README.md) [anchor](#top) [abs](/docs/index.md)"
+	if _, err := pipeline.Transform(context.Background(), "en/index.md", []byte(content)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := pipeline.BrokenLinks("en/index.md", "en/install.md", "images/logo.png")
+	expected := []transform.BrokenLink{
+		{File: "en/index.md", Link: "ignored.md#anchor", Reason: "target is not copied"},
+		{File: "en/index.md", Link: "../../README.md", Reason: "points outside of the copied files"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("broken links mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPipeline_Empty(t *testing.T) {
+	pipeline, err := transform.New("pipeline")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !pipeline.Empty() {
+		t.Error("expected pipeline without transforms to be empty")
+	}
+}
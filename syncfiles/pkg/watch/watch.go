@@ -0,0 +1,207 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package watch watches source folders for changes
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/fscopy"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce default quiet period before changes are handled
+const DefaultDebounce = 300 * time.Millisecond
+
+const syncIgnoreFile = ".syncignore"
+
+// ChangeHandler handles the sources changed during a quiet period
+// sources are the sorted names of the changed sources
+type ChangeHandler func(ctx context.Context, sources []string) error
+
+// Watcher watches source folders recursively
+// events are grouped until no event happens during the debounce period
+// changes to files ignored by .syncignore files are discarded
+type Watcher struct {
+	// Sources maps the name of each source to its folder
+	Sources map[string]string
+	// Debounce quiet period before changes are handled. Defaults to DefaultDebounce
+	Debounce time.Duration
+	// OnChange called with the changed sources
+	OnChange ChangeHandler
+
+	watcher *fsnotify.Watcher
+	ignores map[string]*fscopy.IgnoreNode
+}
+
+// Run watches the sources until the context is done
+// errors returned by OnChange are logged and do not stop the watcher
+func (w *Watcher) Run(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	w.watcher = watcher
+	w.ignores = map[string]*fscopy.IgnoreNode{}
+
+	for name, folder := range w.Sources {
+		if err := w.addRecursive(ctx, folder); err != nil {
+			return err
+		}
+		if err := w.loadIgnores(ctx, name); err != nil {
+			return err
+		}
+		log.Info("Watching source ", name, " in ", folder)
+	}
+
+	pending := map[string]bool{}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn("error watching sources: ", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if source := w.handleEvent(ctx, event); source != "" {
+				pending[source] = true
+				timer.Reset(debounce)
+			}
+		case <-timer.C:
+			sources := make([]string, 0, len(pending))
+			for source := range pending {
+				sources = append(sources, source)
+			}
+			sort.Strings(sources)
+			pending = map[string]bool{}
+
+			log.Debug("changes detected in sources: ", sources)
+			if err := w.OnChange(ctx, sources); err != nil {
+				log.Error("error handling changes in sources ", sources, " error: ", err)
+			}
+		}
+	}
+}
+
+// handleEvent updates the watched folders and the ignore rules
+// returns the name of the changed source, or empty if the event should be discarded
+func (w *Watcher) handleEvent(ctx context.Context, event fsnotify.Event) string {
+	log := logger.GetLogger(ctx)
+	if event.Op == fsnotify.Chmod {
+		return ""
+	}
+	source := w.sourceOf(event.Name)
+	if source == "" {
+		return ""
+	}
+	log.Debug("event ", event.Op, " on ", event.Name)
+
+	if filepath.Base(event.Name) == syncIgnoreFile {
+		if err := w.loadIgnores(ctx, source); err != nil {
+			log.Warn("error loading ignore files of source ", source, " error: ", err)
+		}
+		return source
+	}
+
+	info, statErr := os.Lstat(event.Name)
+	isDir := statErr == nil && info.IsDir()
+	// new folders or folders moved into the source are watched as well
+	if isDir && event.Has(fsnotify.Create) {
+		if err := w.addRecursive(ctx, event.Name); err != nil {
+			log.Warn("error watching folder ", event.Name, " error: ", err)
+		}
+		if err := w.loadIgnores(ctx, source); err != nil {
+			log.Warn("error loading ignore files of source ", source, " error: ", err)
+		}
+		return source
+	}
+
+	// removed or renamed paths can't be checked for being a folder
+	// so they always trigger a sync to prune the target
+	if statErr != nil {
+		return source
+	}
+
+	if ignore := w.ignores[source]; ignore != nil && ignore.IsIgnored(event.Name, isDir) {
+		log.Debug("ignoring event on ", event.Name)
+		return ""
+	}
+	return source
+}
+
+// sourceOf returns the name of the source containing the path
+func (w *Watcher) sourceOf(path string) string {
+	for name, folder := range w.Sources {
+		folder = filepath.Clean(folder)
+		if path == folder || strings.HasPrefix(path, folder+string(filepath.Separator)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// addRecursive watches a folder and all its subfolders
+// fsnotify does not watch folders recursively
+func (w *Watcher) addRecursive(ctx context.Context, folder string) error {
+	log := logger.GetLogger(ctx)
+	return filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the folder may be removed while walking
+			log.Debug("error walking ", path, " error: ", err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		log.Debug("watching folder ", path)
+		return w.watcher.Add(path)
+	})
+}
+
+// loadIgnores loads the .syncignore files of a source
+func (w *Watcher) loadIgnores(ctx context.Context, source string) error {
+	ignore, err := fscopy.NewIgnoreTree(ctx, filepath.Clean(w.Sources[source]))
+	if err != nil {
+		return err
+	}
+	w.ignores[source] = ignore
+	return nil
+}
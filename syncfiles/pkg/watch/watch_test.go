@@ -0,0 +1,120 @@
+/*    Copyright 2025 AlaudaDevops authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/logger"
+	"github.com/AlaudaDevops/toolbox/syncfiles/pkg/watch"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWatcher_Run(t *testing.T) {
+	ctx := logger.WithLogger(context.Background(), logger.NewLoggerFromContext(context.Background(), logger.LogLeveler{Level: "debug"}))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	first := t.TempDir()
+	second := t.TempDir()
+	if err := os.WriteFile(filepath.Join(first, ".syncignore"), []byte("*.tmp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(first, "old.md"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan []string, 10)
+	watcher := &watch.Watcher{
+		Sources:  map[string]string{"first": first, "second": second},
+		Debounce: 100 * time.Millisecond,
+		OnChange: func(ctx context.Context, sources []string) error {
+			changes <- sources
+			return nil
+		},
+	}
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+	// give the watcher time to register the folders
+	time.Sleep(200 * time.Millisecond)
+
+	expectChange := func(name string, expected []string) {
+		t.Helper()
+		select {
+		case sources := <-changes:
+			if diff := cmp.Diff(expected, sources); diff != "" {
+				t.Errorf("%s: changed sources mismatch (-want +got):\n%s", name, diff)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s: expected a change", name)
+		}
+	}
+	expectNoChange := func(name string) {
+		t.Helper()
+		select {
+		case sources := <-changes:
+			t.Errorf("%s: expected no change, got %v", name, sources)
+		case <-time.After(400 * time.Millisecond):
+		}
+	}
+
+	// a burst of events in multiple sources is handled once
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(filepath.Join(first, "burst.md"), []byte{byte(i)}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(second, "file.md"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("burst", []string{"first", "second"})
+	expectNoChange("after burst")
+
+	// files ignored by .syncignore do not trigger a sync
+	if err := os.WriteFile(filepath.Join(first, "draft.tmp"), []byte("draft"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectNoChange("ignored file")
+
+	// files in new folders are watched
+	if err := os.MkdirAll(filepath.Join(first, "new"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("new folder", []string{"first"})
+	if err := os.WriteFile(filepath.Join(first, "new", "page.md"), []byte("page"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("file in new folder", []string{"first"})
+
+	// renames and deletes trigger a sync
+	if err := os.Rename(filepath.Join(first, "old.md"), filepath.Join(first, "new", "old.md")); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("rename", []string{"first"})
+	if err := os.Remove(filepath.Join(second, "file.md")); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("delete", []string{"second"})
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}